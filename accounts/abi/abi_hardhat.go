@@ -4,6 +4,7 @@ package abi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -15,39 +16,46 @@ func LoadHardhatContract(abiFileName string) (*ABI, string, string) {
 		fmt.Printf("Error reading file: %v", err)
 		return nil, "", ""
 	}
-	// Using map to store content
-	var contract map[string]interface{}
-	err = json.Unmarshal(data, &contract)
+	contractABI, contractBytecode, deployedBytecode, err := ParseHardhatContract(data)
 	if err != nil {
-		fmt.Println("Error parsing JSON:", err)
+		fmt.Printf("Err in load file %s:%s", abiFileName, err)
 		return nil, "", ""
 	}
+	return contractABI, contractBytecode, deployedBytecode
+}
+
+// ParseHardhatContract parses the abi, bytecode and deployed bytecode from the
+// content of a hardhat artifact, e.g. one embedded into the binary.
+func ParseHardhatContract(data []byte) (*ABI, string, string, error) {
+	// Using map to store content
+	var contract map[string]interface{}
+	if err := json.Unmarshal(data, &contract); err != nil {
+		return nil, "", "", err
+	}
 	// Parse abi
 	contractABI, err := loadContractJSON(contract)
 	if err != nil {
-		fmt.Printf("Err in load file %s:%s", abiFileName, err)
-		return nil, "", ""
+		return nil, "", "", err
 	}
 	// Parse bytecode, convert to string
-	contractBytecode := contract["bytecode"].(string)
-	deployedBytecode := contract["deployedBytecode"].(string)
-	return contractABI, contractBytecode, deployedBytecode
+	contractBytecode, _ := contract["bytecode"].(string)
+	deployedBytecode, _ := contract["deployedBytecode"].(string)
+	return contractABI, contractBytecode, deployedBytecode, nil
 }
 
 func loadContractJSON(contract map[string]interface{}) (*ABI, error) {
 	// Parse abi from data.The abi field in json is an array, So convert it to []interface{}
 	abidata, ok := contract["abi"].([]interface{})
 	if !ok {
-		fmt.Println("ABI is not a string")
+		return nil, errors.New("abi is not an array")
 	}
 	abiJSON, err := json.Marshal(abidata)
 	if err != nil {
-		fmt.Println("Error Marshal json:", err)
+		return nil, err
 	}
-
 	parsedABI, err := JSON(strings.NewReader(string(abiJSON)))
 	if err != nil {
-		fmt.Printf("Error parsing ABI: %v", err)
+		return nil, err
 	}
 	return &parsedABI, nil
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/cryptoupgrade"
 	"github.com/ethereum/go-ethereum/eth/catalyst"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -179,6 +180,13 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	}
 	backend, eth := utils.RegisterEthService(stack, &cfg.Eth)
 
+	// The registry of upgraded algorithms lives in the chain database and is kept
+	// up to date with the versions announced in codestorage
+	if config := backend.ChainConfig(); config.CryptoUpgrade != nil {
+		cryptoupgrade.SetRegistry(backend.ChainDb())
+		stack.RegisterLifecycle(&cuWatcher{stack: stack, config: config, dir: cfg.Eth.CryptoUpgradeDir})
+	}
+
 	// Create gauge with geth system and build information
 	if eth != nil { // The 'eth' backend may be nil in light mode
 		var protos []string
@@ -0,0 +1,486 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/cryptoupgrade"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	cuNameFlag = &cli.StringFlag{
+		Name:     "name",
+		Usage:    "Name of the algorithm, also the name of its exported entry point",
+		Required: true,
+	}
+	cuGasFlag = &cli.Uint64Flag{
+		Name:  "gas",
+		Usage: "Base gas charged for every call of the algorithm",
+	}
+	cuWordGasFlag = &cli.Uint64Flag{
+		Name:  "wordgas",
+		Usage: "Gas charged per 32 byte word of input on top of the base gas",
+	}
+	cuITypeFlag = &cli.StringFlag{
+		Name:  "itype",
+		Usage: "Comma separated abi types of the algorithm input",
+		Value: "bytes",
+	}
+	cuOTypeFlag = &cli.StringFlag{
+		Name:  "otype",
+		Usage: "Comma separated abi types of the algorithm output",
+		Value: "bytes",
+	}
+	cuActivationFlag = &cli.Uint64Flag{
+		Name:  "activation",
+		Usage: "Block number the algorithm becomes callable at",
+	}
+	cuAuthorFlag = &cli.StringFlag{
+		Name:  "author",
+		Usage: "Address of the author of the algorithm",
+	}
+	cuOutFlag = &cli.StringFlag{
+		Name:  "out",
+		Usage: "File to write the package to (default <name>.json)",
+	}
+	cuKeyFlag = &cli.StringFlag{
+		Name:     "key",
+		Usage:    "File holding the hex encoded private key to sign with",
+		Required: true,
+	}
+	cuGenesisFlag = &cli.StringFlag{
+		Name:  "genesis",
+		Usage: "Genesis file holding the codestorage address and governance to verify approvals against",
+	}
+	cuVectorsFlag = &cli.StringFlag{
+		Name:     "vectors",
+		Usage:    "Json file with the abi encoded inputs and expected outputs to run",
+		Required: true,
+	}
+	cuRPCFlag = &cli.StringFlag{
+		Name:  "rpc",
+		Usage: "Endpoint of the node to publish through",
+		Value: "http://127.0.0.1:8545",
+	}
+
+	cryptoupgradeCommand = &cli.Command{
+		Name:  "cryptoupgrade",
+		Usage: "Build, check and publish upgraded crypto algorithms",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "pack",
+				Usage:     "Package a wasm module for codestorage",
+				ArgsUsage: "<module.wasm>",
+				Action:    cuPack,
+				Flags:     []cli.Flag{cuNameFlag, cuGasFlag, cuWordGasFlag, cuITypeFlag, cuOTypeFlag, cuActivationFlag, cuAuthorFlag, cuGenesisFlag, cuOutFlag},
+				Description: `
+geth cryptoupgrade pack --name <name> --genesis <genesis.json> [flags] <module.wasm>
+Compresses the module and writes a package with its manifest, bound to the chain
+and codestorage of the genesis. The package still has to be signed by the
+governors before nodes accept it.`,
+			},
+			{
+				Name:      "sign",
+				Usage:     "Approve a package with a governor key",
+				ArgsUsage: "<package.json>",
+				Action:    cuSign,
+				Flags:     []cli.Flag{cuKeyFlag},
+			},
+			{
+				Name:      "inspect",
+				Usage:     "Show the manifest, module and approvals of a package",
+				ArgsUsage: "<package.json>",
+				Action:    cuInspect,
+				Flags:     []cli.Flag{cuGenesisFlag},
+			},
+			{
+				Name:      "simulate",
+				Usage:     "Run a package against test vectors on a simulated chain",
+				ArgsUsage: "<package.json>",
+				Action:    cuSimulate,
+				Flags:     []cli.Flag{cuVectorsFlag},
+				Description: `
+geth cryptoupgrade simulate --vectors <vectors.json> <package.json>
+Calls the algorithm through codestorage on a simulated chain for every vector
+and reports the gas used and the outputs that don't match. The vector file is a
+list of {"input": "0x..", "output": "0x.."} objects, both abi encoded.`,
+			},
+			{
+				Name:      "publish",
+				Usage:     "Upload a package to codestorage and announce it",
+				ArgsUsage: "<package.json>",
+				Action:    cuPublish,
+				Flags:     []cli.Flag{cuKeyFlag, cuRPCFlag, cuGenesisFlag},
+				Description: `
+geth cryptoupgrade publish --key <keyfile> [flags] <package.json>
+Uploads the package to codestorage and appends it to the versions of the
+algorithm. The key must be the publisher of codestorage, and the activation of
+the package has to come after the current block and the last version.`,
+			},
+		},
+	}
+)
+
+// cuVector is a test case of the simulate command.
+type cuVector struct {
+	Input  hexutil.Bytes `json:"input"`
+	Output hexutil.Bytes `json:"output"`
+}
+
+func cuLoadPackage(ctx *cli.Context) *cryptoupgrade.Package {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires the package file as argument")
+	}
+	p, err := cryptoupgrade.LoadPackage(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Failed to load package: %v", err)
+	}
+	return p
+}
+
+// cuChainConfig reads the chain configuration, holding the codestorage address
+// and governance, from the genesis file given by the genesis flag, nil if unset.
+func cuChainConfig(ctx *cli.Context) *params.ChainConfig {
+	if !ctx.IsSet(cuGenesisFlag.Name) {
+		return nil
+	}
+	data, err := os.ReadFile(ctx.String(cuGenesisFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to read genesis file: %v", err)
+	}
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(data, genesis); err != nil {
+		utils.Fatalf("Invalid genesis file: %v", err)
+	}
+	if genesis.Config == nil || genesis.Config.CryptoUpgrade == nil || genesis.Config.ChainID == nil {
+		utils.Fatalf("Genesis has no cryptoupgrade governance")
+	}
+	return genesis.Config
+}
+
+func cuPack(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires the wasm module as argument")
+	}
+	// Approvals are bound to the chain and its codestorage
+	config := cuChainConfig(ctx)
+	if config == nil {
+		utils.Fatalf("The --%s flag is required to pack a package", cuGenesisFlag.Name)
+	}
+	manifest := cryptoupgrade.Manifest{
+		Name:        ctx.String(cuNameFlag.Name),
+		IType:       ctx.String(cuITypeFlag.Name),
+		OType:       ctx.String(cuOTypeFlag.Name),
+		Gas:         ctx.Uint64(cuGasFlag.Name),
+		WordGas:     ctx.Uint64(cuWordGasFlag.Name),
+		Activation:  ctx.Uint64(cuActivationFlag.Name),
+		ChainID:     config.ChainID.Uint64(),
+		CodeStorage: config.CryptoUpgrade.CodeStorage(),
+	}
+	if author := ctx.String(cuAuthorFlag.Name); author != "" {
+		if !common.IsHexAddress(author) {
+			utils.Fatalf("Invalid author address %q", author)
+		}
+		manifest.Author = common.HexToAddress(author)
+	}
+	for _, typ := range strings.Split(manifest.IType+","+manifest.OType, ",") {
+		if _, err := abi.NewType(typ, "", nil); err != nil {
+			utils.Fatalf("Invalid abi type %q: %v", typ, err)
+		}
+	}
+	p, err := cryptoupgrade.NewPackage(ctx.Args().First(), manifest)
+	if err != nil {
+		utils.Fatalf("Failed to build package: %v", err)
+	}
+	out := ctx.String(cuOutFlag.Name)
+	if out == "" {
+		out = manifest.Name + ".json"
+	}
+	if err := p.Save(out); err != nil {
+		utils.Fatalf("Failed to write package: %v", err)
+	}
+	fmt.Printf("Packed %s into %s\nDigest:        %s\nManifest hash: %s\n", manifest.Name, out, p.Digest, p.Hash())
+	return nil
+}
+
+func cuSign(ctx *cli.Context) error {
+	p := cuLoadPackage(ctx)
+	key, err := crypto.LoadECDSA(ctx.String(cuKeyFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to load key: %v", err)
+	}
+	if err := p.Sign(key); err != nil {
+		utils.Fatalf("Failed to sign package: %v", err)
+	}
+	if err := p.Save(ctx.Args().First()); err != nil {
+		utils.Fatalf("Failed to write package: %v", err)
+	}
+	fmt.Printf("Signed %s as %s, %d signatures\n", p.Name, crypto.PubkeyToAddress(key.PublicKey), len(p.Signatures))
+	return nil
+}
+
+func cuInspect(ctx *cli.Context) error {
+	p := cuLoadPackage(ctx)
+	fmt.Printf("Name:          %s\n", p.Name)
+	fmt.Printf("Author:        %s\n", p.Author)
+	fmt.Printf("Input types:   %s\n", p.IType)
+	fmt.Printf("Output types:  %s\n", p.OType)
+	fmt.Printf("Gas:           %d + %d per word\n", p.Gas, p.WordGas)
+	fmt.Printf("Activation:    %d\n", p.Activation)
+	fmt.Printf("Chain:         %d, codestorage %s\n", p.ChainID, p.CodeStorage)
+	fmt.Printf("Digest:        %s\n", p.Digest)
+	fmt.Printf("Manifest hash: %s\n", p.Hash())
+	fmt.Printf("Code size:     %d bytes compressed\n", len(p.Code))
+
+	module, err := p.Module()
+	if err != nil {
+		fmt.Printf("Module:        INVALID (%v)\n", err)
+	} else {
+		var exports []string
+		for name := range module.Exports {
+			exports = append(exports, name)
+		}
+		sort.Strings(exports)
+		fmt.Printf("Module:        %d functions, exports %s\n", len(module.Funcs), strings.Join(exports, ", "))
+	}
+	signers, err := p.Approvers()
+	if err != nil {
+		utils.Fatalf("Invalid signatures: %v", err)
+	}
+	for _, signer := range signers {
+		fmt.Printf("Signed by:     %s\n", signer)
+	}
+	if config := cuChainConfig(ctx); config != nil {
+		if err := p.Verify(config.ChainID, config.CryptoUpgrade); err != nil {
+			fmt.Printf("Approval:      REJECTED (%v)\n", err)
+		} else {
+			fmt.Printf("Approval:      accepted (%d of %d governors required)\n", config.CryptoUpgrade.Threshold, len(config.CryptoUpgrade.Governors))
+		}
+	}
+	return nil
+}
+
+func cuSimulate(ctx *cli.Context) error {
+	p := cuLoadPackage(ctx)
+	data, err := os.ReadFile(ctx.String(cuVectorsFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to read vectors: %v", err)
+	}
+	var vectors []cuVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		utils.Fatalf("Invalid vector file: %v", err)
+	}
+	if _, err := p.Module(); err != nil {
+		utils.Fatalf("Invalid package: %v", err)
+	}
+	// Predeploy codestorage with the package active from genesis, approved by a
+	// throwaway governor
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	codeStorage := params.DefaultCodeStorageAddress
+	local := *p
+	local.Activation = 0
+	local.ChainID = params.AllDevChainProtocolChanges.ChainID.Uint64()
+	local.CodeStorage = codeStorage
+	local.Signatures = nil
+	if err := local.Sign(key); err != nil {
+		utils.Fatalf("Failed to approve package: %v", err)
+	}
+	sim := simulated.NewBackend(core.GenesisAlloc{
+		from:        {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+		codeStorage: {Code: cryptoupgrade.CodeStorageCode, Storage: cryptoupgrade.CodeStorageGenesis(from, &local), Balance: new(big.Int)},
+	}, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		config := *ethConf.Genesis.Config
+		config.CryptoUpgrade = &params.CryptoUpgradeConfig{Address: &codeStorage, Governors: []common.Address{from}, Threshold: 1}
+		ethConf.Genesis.Config = &config
+	})
+	defer sim.Close()
+	client := sim.Client()
+
+	otypes := strings.Split(p.OType, ",")
+	mismatches := 0
+	for i, v := range vectors {
+		input, err := cryptoupgrade.CodeStorageABI.Pack("callFunc", p.Name, []byte(v.Input))
+		if err != nil {
+			utils.Fatalf("Failed to pack vector %d: %v", i, err)
+		}
+		msg := ethereum.CallMsg{From: from, To: &codeStorage, Data: input}
+		output, err := client.CallContract(context.Background(), msg, nil)
+		if err != nil {
+			fmt.Printf("vector %d: call failed: %v\n", i, err)
+			mismatches++
+			continue
+		}
+		gas, err := client.EstimateGas(context.Background(), msg)
+		if err != nil {
+			fmt.Printf("vector %d: gas estimation failed: %v\n", i, err)
+		}
+		if bytes.Equal(output, v.Output) {
+			fmt.Printf("vector %d: ok, gas %d\n", i, gas)
+			continue
+		}
+		mismatches++
+		have, _ := cryptoupgrade.UnpackInput(output, otypes)
+		want, _ := cryptoupgrade.UnpackInput(v.Output, otypes)
+		fmt.Printf("vector %d: MISMATCH, gas %d\n  have %x %v\n  want %x %v\n", i, gas, output, have, []byte(v.Output), want)
+	}
+	if mismatches > 0 {
+		return fmt.Errorf("%d of %d vectors failed", mismatches, len(vectors))
+	}
+	fmt.Printf("All %d vectors passed\n", len(vectors))
+	return nil
+}
+
+func cuPublish(ctx *cli.Context) error {
+	p := cuLoadPackage(ctx)
+	if _, err := p.Module(); err != nil {
+		utils.Fatalf("Invalid package: %v", err)
+	}
+	codeStorage := p.CodeStorage
+	if config := cuChainConfig(ctx); config != nil {
+		if err := p.Verify(config.ChainID, config.CryptoUpgrade); err != nil {
+			utils.Fatalf("Package would be rejected by the nodes: %v", err)
+		}
+	}
+	key, err := crypto.LoadECDSA(ctx.String(cuKeyFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to load key: %v", err)
+	}
+	client, err := ethclient.Dial(ctx.String(cuRPCFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to connect to node: %v", err)
+	}
+	defer client.Close()
+
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		utils.Fatalf("Failed to retrieve chain id: %v", err)
+	}
+	if !chainID.IsUint64() || chainID.Uint64() != p.ChainID {
+		utils.Fatalf("Package is bound to chain %d, node runs chain %d", p.ChainID, chainID)
+	}
+	upload, err := p.UploadData()
+	if err != nil {
+		utils.Fatalf("Failed to pack upload: %v", err)
+	}
+	pull, err := p.PullData()
+	if err != nil {
+		utils.Fatalf("Failed to pack pullcode: %v", err)
+	}
+	// Upload first, the pullcode event makes nodes fetch the uploaded version
+	for _, step := range []struct {
+		name string
+		data []byte
+	}{{"uploadCode", upload}, {"pullCode", pull}} {
+		receipt, err := cuTransact(client, key, codeStorage, step.data)
+		if err != nil {
+			utils.Fatalf("%s failed: %v", step.name, err)
+		}
+		fmt.Printf("%s included in block %d, tx %s\n", step.name, receipt.BlockNumber, receipt.TxHash.Hex())
+	}
+	return nil
+}
+
+// cuWatcher indexes the modules announced in codestorage into the registry once
+// the node is running.
+type cuWatcher struct {
+	stack  *node.Node
+	config *params.ChainConfig
+	dir    string
+	client *rpc.Client
+}
+
+func (w *cuWatcher) Start() error {
+	w.client = w.stack.Attach()
+	go cryptoupgrade.BindPullcode(ethclient.NewClient(w.client), w.config, w.dir)
+	return nil
+}
+
+// Stop ends the subscription of the watcher.
+func (w *cuWatcher) Stop() error {
+	w.client.Close()
+	return nil
+}
+
+// cuTransact sends a transaction calling codestorage and waits for its receipt.
+func cuTransact(client *ethclient.Client, key *ecdsa.PrivateKey, codeStorage common.Address, data []byte) (*types.Receipt, error) {
+	c := context.Background()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainID, err := client.ChainID(c)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := client.PendingNonceAt(c, from)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := client.SuggestGasPrice(c)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := client.EstimateGas(c, ethereum.CallMsg{From: from, To: &codeStorage, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &codeStorage,
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Data:     data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SendTransaction(c, tx); err != nil {
+		return nil, err
+	}
+	receipt, err := bind.WaitMined(c, client, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, errors.New("transaction reverted")
+	}
+	return receipt, nil
+}
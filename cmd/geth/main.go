@@ -32,7 +32,6 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/console/prompt"
-	"github.com/ethereum/go-ethereum/cryptoupgrade"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -63,6 +62,7 @@ var (
 		utils.PasswordFileFlag,
 		utils.BootnodesFlag,
 		utils.MinFreeDiskSpaceFlag,
+		utils.CryptoUpgradeDirFlag,
 		utils.KeyStoreDirFlag,
 		utils.ExternalSignerFlag,
 		utils.NoUSBFlag, // deprecated
@@ -126,6 +126,19 @@ var (
 		utils.MinerExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNewPayloadTimeout,
+		utils.MinerGRPCConsensusFlag,
+		utils.MinerGRPCPoTFlag,
+		utils.MinerGRPCTransferFlag,
+		utils.MinerGRPCDCIFlag,
+		utils.MinerGRPCExecutorFlag,
+		utils.MinerGRPCPoterFlag,
+		utils.MinerGRPCCoinMixerFlag,
+		utils.MinerGRPCTLSCertFlag,
+		utils.MinerGRPCTLSKeyFlag,
+		utils.MinerGRPCTLSCAFlag,
+		utils.MinerGRPCKeepaliveFlag,
+		utils.MinerGRPCMaxBackoffFlag,
+		utils.MinerShardsFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV4Flag,
@@ -236,6 +249,8 @@ func init() {
 		snapshotCommand,
 		// See verkle.go
 		verkleCommand,
+		// See cryptoupgradecmd.go
+		cryptoupgradeCommand,
 	}
 	if logTestCommand != nil {
 		app.Commands = append(app.Commands, logTestCommand)
@@ -366,9 +381,6 @@ func startNode(ctx *cli.Context, stack *node.Node, backend ethapi.Backend, isCon
 	rpcClient := stack.Attach()
 	ethClient := ethclient.NewClient(rpcClient)
 
-	// * Bind cryptoupgrade  pull code event
-	go cryptoupgrade.BindPullcode(ethClient)
-
 	go func() {
 		// Open any wallets already attached
 		for _, wallet := range stack.AccountManager().Wallets() {
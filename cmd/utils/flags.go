@@ -113,6 +113,11 @@ var (
 		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
 		Category: flags.EthCategory,
 	}
+	CryptoUpgradeDirFlag = &flags.DirectoryFlag{
+		Name:     "datadir.cryptoupgrade",
+		Usage:    "Directory keeping a copy of the upgraded crypto algorithms (default = inside the datadir)",
+		Category: flags.EthCategory,
+	}
 	KeyStoreDirFlag = &flags.DirectoryFlag{
 		Name:     "keystore",
 		Usage:    "Directory for the keystore (default = inside the datadir)",
@@ -464,6 +469,80 @@ var (
 		Value:    ethconfig.Defaults.Miner.NewPayloadTimeout,
 		Category: flags.MinerCategory,
 	}
+	MinerGRPCConsensusFlag = &cli.StringFlag{
+		Name:     "miner.grpc.consensus",
+		Usage:    "gRPC endpoint of the consensus layer",
+		Value:    ethconfig.Defaults.Miner.GRPC.Consensus,
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCPoTFlag = &cli.StringFlag{
+		Name:     "miner.grpc.pot",
+		Usage:    "gRPC endpoint of the PoT layer",
+		Value:    ethconfig.Defaults.Miner.GRPC.PoT,
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCTransferFlag = &cli.StringFlag{
+		Name:     "miner.grpc.transfer",
+		Usage:    "gRPC endpoint of the transfer service",
+		Value:    ethconfig.Defaults.Miner.GRPC.Transfer,
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCDCIFlag = &cli.StringFlag{
+		Name:     "miner.grpc.dci",
+		Usage:    "gRPC endpoint of the DCI layer",
+		Value:    ethconfig.Defaults.Miner.GRPC.DCI,
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCExecutorFlag = &cli.StringFlag{
+		Name:     "miner.grpc.executor",
+		Usage:    "gRPC listen address of the executor server",
+		Value:    ethconfig.Defaults.Miner.GRPC.Executor,
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCPoterFlag = &cli.StringFlag{
+		Name:     "miner.grpc.poter",
+		Usage:    "gRPC listen address of the PoT executor server",
+		Value:    ethconfig.Defaults.Miner.GRPC.Poter,
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCCoinMixerFlag = &cli.StringFlag{
+		Name:     "miner.grpc.coinmixer",
+		Usage:    "gRPC listen address of the coin mixer monitor server",
+		Value:    ethconfig.Defaults.Miner.GRPC.CoinMixer,
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCTLSCertFlag = &cli.StringFlag{
+		Name:     "miner.grpc.tls.cert",
+		Usage:    "Certificate file enabling TLS on the gRPC links",
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCTLSKeyFlag = &cli.StringFlag{
+		Name:     "miner.grpc.tls.key",
+		Usage:    "Key file of the gRPC TLS certificate",
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCTLSCAFlag = &cli.StringFlag{
+		Name:     "miner.grpc.tls.ca",
+		Usage:    "CA file enabling mutual TLS on the gRPC links",
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCKeepaliveFlag = &cli.DurationFlag{
+		Name:     "miner.grpc.keepalive",
+		Usage:    "Interval of the gRPC keepalive pings (0 = disabled)",
+		Value:    ethconfig.Defaults.Miner.GRPC.Keepalive,
+		Category: flags.MinerCategory,
+	}
+	MinerGRPCMaxBackoffFlag = &cli.DurationFlag{
+		Name:     "miner.grpc.maxbackoff",
+		Usage:    "Maximum delay between gRPC reconnection attempts",
+		Value:    ethconfig.Defaults.Miner.GRPC.MaxBackoff,
+		Category: flags.MinerCategory,
+	}
+	MinerShardsFlag = &cli.StringSliceFlag{
+		Name:     "miner.shards",
+		Usage:    "Shards of the sender address ranges, as <shard>:<from>-<to>[@<executor endpoint>] (accounts out of every range are local). This flag can be given multiple times.",
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -1511,6 +1590,52 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(MinerNewPayloadTimeout.Name) {
 		cfg.NewPayloadTimeout = ctx.Duration(MinerNewPayloadTimeout.Name)
 	}
+	if ctx.IsSet(MinerGRPCConsensusFlag.Name) {
+		cfg.GRPC.Consensus = ctx.String(MinerGRPCConsensusFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCPoTFlag.Name) {
+		cfg.GRPC.PoT = ctx.String(MinerGRPCPoTFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCTransferFlag.Name) {
+		cfg.GRPC.Transfer = ctx.String(MinerGRPCTransferFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCDCIFlag.Name) {
+		cfg.GRPC.DCI = ctx.String(MinerGRPCDCIFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCExecutorFlag.Name) {
+		cfg.GRPC.Executor = ctx.String(MinerGRPCExecutorFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCPoterFlag.Name) {
+		cfg.GRPC.Poter = ctx.String(MinerGRPCPoterFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCCoinMixerFlag.Name) {
+		cfg.GRPC.CoinMixer = ctx.String(MinerGRPCCoinMixerFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCTLSCertFlag.Name) {
+		cfg.GRPC.CertFile = ctx.String(MinerGRPCTLSCertFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCTLSKeyFlag.Name) {
+		cfg.GRPC.KeyFile = ctx.String(MinerGRPCTLSKeyFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCTLSCAFlag.Name) {
+		cfg.GRPC.CAFile = ctx.String(MinerGRPCTLSCAFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCKeepaliveFlag.Name) {
+		cfg.GRPC.Keepalive = ctx.Duration(MinerGRPCKeepaliveFlag.Name)
+	}
+	if ctx.IsSet(MinerGRPCMaxBackoffFlag.Name) {
+		cfg.GRPC.MaxBackoff = ctx.Duration(MinerGRPCMaxBackoffFlag.Name)
+	}
+	if ctx.IsSet(MinerShardsFlag.Name) {
+		cfg.Shards = nil
+		for _, entry := range ctx.StringSlice(MinerShardsFlag.Name) {
+			route, err := miner.ParseShardRoute(entry)
+			if err != nil {
+				Fatalf("Invalid --%s: %v", MinerShardsFlag.Name, err)
+			}
+			cfg.Shards = append(cfg.Shards, route)
+		}
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1631,6 +1756,11 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(AncientFlag.Name) {
 		cfg.DatabaseFreezer = ctx.String(AncientFlag.Name)
 	}
+	if ctx.IsSet(CryptoUpgradeDirFlag.Name) {
+		cfg.CryptoUpgradeDir = ctx.String(CryptoUpgradeDirFlag.Name)
+	} else if cfg.CryptoUpgradeDir == "" {
+		cfg.CryptoUpgradeDir = stack.ResolvePath("cryptoupgrade")
+	}
 
 	if gcmode := ctx.String(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
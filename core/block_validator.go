@@ -17,6 +17,7 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 
@@ -116,12 +117,24 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 		}
 		return consensus.ErrPrunedAncestor
 	}
-	return nil
+	// The PoW economics fields must follow from the parent, the plans recorded
+	// in its state and the transactions. The parent state is only opened once
+	// the fork is active.
+	if !v.config.IsPowEconomics(header.Number) {
+		return nil
+	}
+	parent := v.bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	statedb, err := v.bc.StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+	return VerifyPowEconomics(v.bc, statedb, parent, header, block.Transactions())
 }
 
 // ValidateState validates the various changes that happen after a state transition,
-// such as amount of used gas, the receipt roots and the state root itself.
-func (v *BlockValidator) ValidateState(block *types.Block, statedb *state.StateDB, receipts types.Receipts, usedGas uint64) error {
+// such as amount of used gas, the receipt roots, the tainted root and the state
+// root itself.
+func (v *BlockValidator) ValidateState(block *types.Block, statedb *state.StateDB, receipts types.Receipts, usedGas uint64, tainted []byte) error {
 	header := block.Header()
 	if block.GasUsed() != usedGas {
 		return fmt.Errorf("invalid gas used (remote: %d local: %d)", block.GasUsed(), usedGas)
@@ -137,6 +150,10 @@ func (v *BlockValidator) ValidateState(block *types.Block, statedb *state.StateD
 	if receiptSha != header.ReceiptHash {
 		return fmt.Errorf("invalid receipt root hash (remote: %x local: %x)", header.ReceiptHash, receiptSha)
 	}
+	// Validate the tainted-address DAG root the block commits to
+	if !bytes.Equal(tainted, header.Tainted) {
+		return fmt.Errorf("invalid tainted root (remote: %x local: %x)", header.Tainted, tainted)
+	}
 	// Validate the state root against the received state root and throw
 	// an error if they don't match.
 	if root := statedb.IntermediateRoot(v.config.IsEIP158(header.Number)); header.Root != root {
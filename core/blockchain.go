@@ -41,9 +41,11 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/governance"
 	"github.com/ethereum/go-ethereum/internal/syncx"
 	"github.com/ethereum/go-ethereum/internal/version"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/merkeldag"
 	"github.com/ethereum/go-ethereum/merkeldag/mdagdb"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
@@ -1347,8 +1349,8 @@ func (bc *BlockChain) writeKnownBlock(block *types.Block) error {
 }
 
 // writeBlockWithState writes block, metadata and corresponding state data to the
-// database.
-func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, state *state.StateDB) error {
+// database, along with the tainted-address DAG of the block if it changed it.
+func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, state *state.StateDB, taintDAG *merkeldag.MerkelDAG) error {
 	// Calculate the total difficulty of the block
 	ptd := bc.GetTd(block.ParentHash(), block.NumberU64()-1)
 	if ptd == nil {
@@ -1365,10 +1367,22 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteTd(blockBatch, block.Hash(), block.NumberU64(), externTd)
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
+	if rates := bc.voucherRateSnapshot(block, receipts, state); len(rates) > 0 {
+		rawdb.WriteVoucherRates(blockBatch, block.Hash(), block.NumberU64(), rates)
+	}
+	if changes := bc.securityLevelChanges(block, receipts); len(changes) > 0 {
+		rawdb.WriteSecurityLevelChanges(blockBatch, block.Hash(), block.NumberU64(), changes)
+	}
 	rawdb.WritePreimages(blockBatch, state.Preimages())
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
 	}
+	// Persist the tainted-address DAG of the block, if it changed
+	if taintDAG != nil {
+		if err := bc.mdagdb.SaveVersion(taintDAG); err != nil {
+			return err
+		}
+	}
 	// Commit all cached state changes into underlying memory database.
 	root, err := state.Commit(block.NumberU64(), bc.chainConfig.IsEIP158(block.Number()))
 	if err != nil {
@@ -1435,20 +1449,71 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 }
 
 // WriteBlockAndSetHead writes the given block and all associated state to the database,
-// and applies the block as the new chain head.
-func (bc *BlockChain) WriteBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, emitHeadEvent bool) (status WriteStatus, err error) {
+// and applies the block as the new chain head. The tainted-address DAG is the one
+// returned by TaintedRoot for the block, nil if the block didn't change it.
+func (bc *BlockChain) WriteBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, taintDAG *merkeldag.MerkelDAG, emitHeadEvent bool) (status WriteStatus, err error) {
 	if !bc.chainmu.TryLock() {
 		return NonStatTy, errChainStopped
 	}
 	defer bc.chainmu.Unlock()
 
-	return bc.writeBlockAndSetHead(block, receipts, logs, state, emitHeadEvent)
+	return bc.writeBlockAndSetHead(block, receipts, logs, state, taintDAG, emitHeadEvent)
+}
+
+// securityLevelChanges returns the security level changes the transactions of
+// a block made through the governance module, in order of execution.
+func (bc *BlockChain) securityLevelChanges(block *types.Block, receipts []*types.Receipt) []types.SecurityLevelChange {
+	var changes []types.SecurityLevelChange
+	for _, receipt := range receipts {
+		for _, entry := range receipt.Logs {
+			if change := governance.ParseLevelChange(bc.chainConfig.Security, entry); change != nil {
+				change.BlockNumber, change.BlockHash, change.TxHash = block.NumberU64(), block.Hash(), receipt.TxHash
+				changes = append(changes, *change)
+			}
+		}
+	}
+	return changes
+}
+
+// TaintedRoot returns the root of the tainted-address DAG the block with the
+// given header and receipts commits to, together with the DAG if the block
+// changed it.
+func (bc *BlockChain) TaintedRoot(header *types.Header, receipts []*types.Receipt) ([]byte, *merkeldag.MerkelDAG, error) {
+	parent := bc.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, nil, consensus.ErrUnknownAncestor
+	}
+	return UpdateTaintDAG(bc.mdagdb, parent.Tainted, TaintedAddresses(receipts))
+}
+
+// TaintDAG returns the tainted-address DAG with the given root, the empty root
+// being the empty DAG. Its nodes are read from the database as they're accessed.
+func (bc *BlockChain) TaintDAG(root []byte) (*merkeldag.MerkelDAG, error) {
+	return bc.mdagdb.LoadVersion(root)
+}
+
+// voucherRateSnapshot returns the voucher rate snapshot to store with a block:
+// the rates its transactions converted their fees at, of the vouchers they paid
+// with only. Rates are quoted on the parent's state the block started from, the
+// original root of state, which must thus be the block's post state not
+// committed yet.
+func (bc *BlockChain) voucherRateSnapshot(block *types.Block, receipts []*types.Receipt, state *state.StateDB) []types.VoucherRate {
+	var vouchers []types.VoucherRate
+	for _, receipt := range receipts {
+		if settlement := receipt.Settlement; settlement != nil {
+			vouchers = append(vouchers, types.VoucherRate{Currency: settlement.Currency, Voucher: settlement.Voucher})
+		}
+	}
+	if len(vouchers) == 0 {
+		return nil
+	}
+	return NewVoucherRates(bc.chainConfig, NewEVMBlockContext(block.Header(), bc, nil), state).Snapshot(vouchers)
 }
 
 // writeBlockAndSetHead is the internal implementation of WriteBlockAndSetHead.
 // This function expects the chain mutex to be held.
-func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, emitHeadEvent bool) (status WriteStatus, err error) {
-	if err := bc.writeBlockWithState(block, receipts, state); err != nil {
+func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, taintDAG *merkeldag.MerkelDAG, emitHeadEvent bool) (status WriteStatus, err error) {
+	if err := bc.writeBlockWithState(block, receipts, state, taintDAG); err != nil {
 		return NonStatTy, err
 	}
 	currentBlock := bc.CurrentBlock()
@@ -1785,8 +1850,16 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 		}
 		ptime := time.Since(pstart)
 
+		// The tainted-address DAG is updated once, its root validated and the DAG
+		// written with the block
 		vstart := time.Now()
-		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
+		tainted, taintDAG, err := bc.TaintedRoot(block.Header(), receipts)
+		if err != nil {
+			bc.reportBlock(block, receipts, err)
+			followupInterrupt.Store(true)
+			return it.index, err
+		}
+		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas, tainted); err != nil {
 			log.Info("report 4")
 			bc.reportBlock(block, receipts, err)
 			followupInterrupt.Store(true)
@@ -1818,9 +1891,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 		)
 		if !setHead {
 			// Don't set the head, only insert the block
-			err = bc.writeBlockWithState(block, receipts, statedb)
+			err = bc.writeBlockWithState(block, receipts, statedb, taintDAG)
 		} else {
-			status, err = bc.writeBlockAndSetHead(block, receipts, logs, statedb, false)
+			status, err = bc.writeBlockAndSetHead(block, receipts, logs, statedb, taintDAG, false)
 		}
 		followupInterrupt.Store(true)
 		if err != nil {
@@ -168,7 +168,11 @@ func testBlockChainImport(chain types.Blocks, blockchain *BlockChain) error {
 			blockchain.reportBlock(block, receipts, err)
 			return err
 		}
-		err = blockchain.validator.ValidateState(block, statedb, receipts, usedGas)
+		tainted, _, err := blockchain.TaintedRoot(block.Header(), receipts)
+		if err != nil {
+			return err
+		}
+		err = blockchain.validator.ValidateState(block, statedb, receipts, usedGas, tainted)
 		if err != nil {
 			blockchain.reportBlock(block, receipts, err)
 			return err
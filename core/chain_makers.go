@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/merkeldag/mdagdb"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
@@ -311,6 +312,7 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		panic("nil consensus engine")
 	}
 	cm := newChainMaker(parent, config, engine)
+	taintdb := mdagdb.NewMerkleDAGDB(db)
 
 	genblock := func(i int, parent *types.Block, triedb *trie.Database, statedb *state.StateDB) (*types.Block, types.Receipts) {
 		b := &BlockGen{i: i, cm: cm, parent: parent, statedb: statedb, engine: engine}
@@ -328,6 +330,8 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 				b.header.Difficulty = big.NewInt(0)
 			}
 		}
+		PrepareState(config, statedb, b.header.Number)
+
 		// Mutate the state and block according to any hard-fork specs
 		if daoBlock := config.DAOForkBlock; daoBlock != nil {
 			limit := new(big.Int).Add(daoBlock, params.DAOForkExtraRange)
@@ -344,6 +348,16 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		if gen != nil {
 			gen(i, b)
 		}
+		tainted, dag, err := UpdateTaintDAG(taintdb, parent.Header().Tainted, TaintedAddresses(b.receipts))
+		if err != nil {
+			panic(fmt.Sprintf("tainted DAG error: %v", err))
+		}
+		if dag != nil {
+			if err := taintdb.SaveVersion(dag); err != nil {
+				panic(fmt.Sprintf("tainted DAG write error: %v", err))
+			}
+		}
+		b.header.Tainted = tainted
 
 		block, err := b.engine.FinalizeAndAssemble(cm, b.header, statedb, b.txs, b.uncles, b.receipts, b.withdrawals)
 		if err != nil {
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// crossShardPayoutPrefix starts the data of the transactions paying out a
+// cross-shard transfer, followed by the source shard and the hash of the
+// transfer in the source shard.
+var crossShardPayoutPrefix = []byte{0x0D, 0x06}
+
+// ErrCrossShardPaid is returned by the payout transactions of a cross-shard
+// transfer the payer paid out already.
+var ErrCrossShardPaid = errors.New("cross-shard transfer paid out already")
+
+// CrossShardPayoutData returns the data of a transaction paying out the transfer
+// with the given hash of the source shard.
+func CrossShardPayoutData(shard uint64, hash common.Hash) []byte {
+	data := append(common.CopyBytes(crossShardPayoutPrefix), make([]byte, 8)...)
+	binary.BigEndian.PutUint64(data[len(crossShardPayoutPrefix):], shard)
+	return append(data, hash.Bytes()...)
+}
+
+// parseCrossShardPayout returns the source shard and transfer paid out by a
+// transaction with the given data, false if it isn't a payout.
+func parseCrossShardPayout(data []byte) (uint64, common.Hash, bool) {
+	if len(data) != len(crossShardPayoutPrefix)+8+common.HashLength || !bytes.HasPrefix(data, crossShardPayoutPrefix) {
+		return 0, common.Hash{}, false
+	}
+	data = data[len(crossShardPayoutPrefix):]
+	return binary.BigEndian.Uint64(data), common.BytesToHash(data[8:]), true
+}
+
+// crossShardPayoutSlot returns the slot of the cross-shard address recording the
+// payout of a transfer by payer. Payouts are recorded per payer, so that nobody
+// can claim the payout of a transfer in place of the relaying executor.
+func crossShardPayoutSlot(payer common.Address, shard uint64, hash common.Hash) common.Hash {
+	return crypto.Keccak256Hash(payer.Bytes(), binary.BigEndian.AppendUint64(nil, shard), hash.Bytes())
+}
+
+// CrossShardPaid reports whether payer paid out the transfer with the given hash
+// of the source shard.
+func CrossShardPaid(config *params.ChainConfig, statedb vm.StateDB, payer common.Address, shard uint64, hash common.Hash) bool {
+	if config.Sharding == nil {
+		return false
+	}
+	return statedb.GetState(config.Sharding.CrossShard(), crossShardPayoutSlot(payer, shard, hash)) != (common.Hash{})
+}
+
+// call runs the call of a message. On sharded chains, the value of calls of
+// accounts of other shards is locked at the cross-shard address, and payouts of
+// cross-shard transfers are recorded there, once per payer.
+func (st *StateTransition) call(sender vm.AccountRef, value *uint256.Int) ([]byte, uint64, error) {
+	sharding := st.evm.ChainConfig().Sharding
+	if sharding.IsForeign(st.to()) {
+		return st.evm.Call(sender, sharding.CrossShard(), st.msg.Data, st.gasRemaining, value)
+	}
+	shard, hash, ok := parseCrossShardPayout(st.msg.Data)
+	if sharding == nil || !ok {
+		return st.evm.Call(sender, st.to(), st.msg.Data, st.gasRemaining, value)
+	}
+	if st.gasRemaining < params.SstoreSetGasEIP2200 {
+		return nil, 0, vm.ErrOutOfGas
+	}
+	var (
+		gas     = st.gasRemaining - params.SstoreSetGasEIP2200
+		records = sharding.CrossShard()
+		slot    = crossShardPayoutSlot(st.msg.From, shard, hash)
+	)
+	if st.state.GetState(records, slot) != (common.Hash{}) {
+		return nil, gas, ErrCrossShardPaid
+	}
+	ret, gas, err := st.evm.Call(sender, st.to(), st.msg.Data, gas, value)
+	if err == nil {
+		// The nonce keeps the account from being cleared as empty
+		if st.state.GetNonce(records) == 0 {
+			st.state.SetNonce(records, 1)
+		}
+		st.state.SetState(records, slot, common.BytesToHash([]byte{1}))
+	}
+	return ret, gas, err
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that transfers to accounts of other shards are locked at the cross-shard
+// address, and that a transfer is only paid out once per payer, as recorded in
+// the state of the importing node too.
+func TestCrossShardTransfers(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		foreign = common.Address{0xbb, 0x01}
+		local   = common.Address{0xaa}
+		source  = common.Hash{0x01}
+		config  = *params.TestChainConfig
+		genesis = &Genesis{
+			Config:  &config,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Alloc:   GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(&config)
+	)
+	config.Sharding = &params.ShardingConfig{
+		Shard:  1,
+		Ranges: []params.ShardRange{{Shard: 2, From: common.Address{0xbb}, To: common.Address{0xbc}}},
+	}
+	_, blocks, receipts := GenerateChainWithGenesis(genesis, ethash.NewFaker(), 2, func(i int, b *BlockGen) {
+		transfer := func(to common.Address, value int64, data []byte) {
+			tx, _ := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   config.ChainID,
+				Nonce:     b.TxNonce(sender),
+				GasTipCap: big.NewInt(1),
+				GasFeeCap: b.header.BaseFee,
+				Gas:       100_000,
+				To:        &to,
+				Value:     big.NewInt(value),
+				Data:      data,
+			})
+			b.AddTx(tx)
+		}
+		if i == 0 {
+			transfer(foreign, 1000, nil)
+			transfer(local, 500, CrossShardPayoutData(2, source))
+		} else {
+			transfer(local, 500, CrossShardPayoutData(2, source))
+		}
+	})
+	// The second payout of the transfer fails
+	for i, want := range [][]uint64{
+		{types.ReceiptStatusSuccessful, types.ReceiptStatusSuccessful},
+		{types.ReceiptStatusFailed},
+	} {
+		for j, receipt := range receipts[i] {
+			if receipt.Status != want[j] {
+				t.Errorf("block %d tx %d: wrong status: have %d, want %d", i+1, j, receipt.Status, want[j])
+			}
+		}
+	}
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	statedb, _ := chain.State()
+	if have := statedb.GetBalance(foreign); !have.IsZero() {
+		t.Errorf("foreign account credited: %v", have)
+	}
+	if have := statedb.GetBalance(config.Sharding.CrossShard()); have.Uint64() != 1000 {
+		t.Errorf("wrong locked value: have %v, want 1000", have)
+	}
+	if have := statedb.GetBalance(local); have.Uint64() != 500 {
+		t.Errorf("wrong paid out value: have %v, want 500", have)
+	}
+	if !CrossShardPaid(&config, statedb, sender, 2, source) {
+		t.Error("payout not recorded")
+	}
+	if CrossShardPaid(&config, statedb, local, 2, source) {
+		t.Error("payout recorded for another payer")
+	}
+}
@@ -85,6 +85,18 @@ var (
 	// current network configuration.
 	ErrTxTypeNotSupported = types.ErrTxTypeNotSupported
 
+	// ErrFeeCurrency is returned if a transaction pays its fees with the vouchers
+	// of a contract that isn't a fee currency of the chain.
+	ErrFeeCurrency = errors.New("fee currency not allowed")
+
+	// ErrVoucherRate is returned if the voucher a transaction pays its fees with
+	// has no exchange rate to convert them at.
+	ErrVoucherRate = errors.New("voucher has no exchange rate")
+
+	// ErrVoucherSettlement is returned if the fee of a voucher transaction can't
+	// be settled with its fee currency.
+	ErrVoucherSettlement = errors.New("voucher fee settlement failed")
+
 	// ErrTipAboveFeeCap is a sanity error to ensure no one is able to specify a
 	// transaction with a tip higher than the total fee cap.
 	ErrTipAboveFeeCap = errors.New("max priority fee per gas higher than max fee per gas")
@@ -27,6 +27,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 		Mixhash       common.Hash                                 `json:"mixHash"`
 		Coinbase      common.Address                              `json:"coinbase"`
 		Alloc         map[common.UnprefixedAddress]GenesisAccount `json:"alloc"      gencodec:"required"`
+		Voucher       *GenesisVoucher                             `json:"voucher,omitempty"`
 		Number        math.HexOrDecimal64                         `json:"number"`
 		GasUsed       math.HexOrDecimal64                         `json:"gasUsed"`
 		ParentHash    common.Hash                                 `json:"parentHash"`
@@ -49,6 +50,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 			enc.Alloc[common.UnprefixedAddress(k)] = v
 		}
 	}
+	enc.Voucher = g.Voucher
 	enc.Number = math.HexOrDecimal64(g.Number)
 	enc.GasUsed = math.HexOrDecimal64(g.GasUsed)
 	enc.ParentHash = g.ParentHash
@@ -70,6 +72,7 @@ func (g *Genesis) UnmarshalJSON(input []byte) error {
 		Mixhash       *common.Hash                                `json:"mixHash"`
 		Coinbase      *common.Address                             `json:"coinbase"`
 		Alloc         map[common.UnprefixedAddress]GenesisAccount `json:"alloc"      gencodec:"required"`
+		Voucher       *GenesisVoucher                             `json:"voucher,omitempty"`
 		Number        *math.HexOrDecimal64                        `json:"number"`
 		GasUsed       *math.HexOrDecimal64                        `json:"gasUsed"`
 		ParentHash    *common.Hash                                `json:"parentHash"`
@@ -114,6 +117,9 @@ func (g *Genesis) UnmarshalJSON(input []byte) error {
 	for k, v := range dec.Alloc {
 		g.Alloc[common.Address(k)] = v
 	}
+	if dec.Voucher != nil {
+		g.Voucher = dec.Voucher
+	}
 	if dec.Number != nil {
 		g.Number = uint64(*dec.Number)
 	}
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -38,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/trie/triedb/pathdb"
+	"github.com/ethereum/go-ethereum/voucher"
 	"github.com/holiman/uint256"
 )
 
@@ -59,6 +61,9 @@ type Genesis struct {
 	Coinbase   common.Address      `json:"coinbase"`
 	Alloc      GenesisAlloc        `json:"alloc"      gencodec:"required"`
 
+	// Predeploy of the voucher contract at voucher.VoucherAddress (nil = not deployed)
+	Voucher *GenesisVoucher `json:"voucher,omitempty"`
+
 	// These fields are used for consensus tests. Please don't use them
 	// in actual genesis blocks.
 	Number        uint64      `json:"number"`
@@ -107,6 +112,80 @@ func ReadGenesis(db ethdb.Database) (*Genesis, error) {
 	return &genesis, nil
 }
 
+// GenesisVoucher predeploys the multi voucher contract paying for transaction
+// fees, optionally with some vouchers already created. The initial supply of
+// every voucher is credited to the owner.
+type GenesisVoucher struct {
+	Owner    *common.Address         `json:"owner,omitempty"`
+	Vouchers []GenesisVoucherBalance `json:"vouchers,omitempty"`
+}
+
+// GenesisVoucherBalance is a voucher created in the genesis block.
+type GenesisVoucherBalance struct {
+	Name           string   `json:"name"`
+	ConversionRate *big.Int `json:"conversionRate"`   // Voucher units bought by one unit of the native token
+	Supply         *big.Int `json:"supply,omitempty"` // Balance of the owner
+}
+
+// account returns the genesis account of the voucher contract.
+func (gv *GenesisVoucher) account() (GenesisAccount, error) {
+	storage := map[common.Hash]common.Hash{
+		voucher.DecimalsSlot(): common.BigToHash(voucher.DefaultDecimals),
+	}
+	for _, v := range gv.Vouchers {
+		if v.ConversionRate == nil || v.ConversionRate.Sign() <= 0 {
+			return GenesisAccount{}, fmt.Errorf("genesis voucher %q has no conversion rate", v.Name)
+		}
+		if _, ok := storage[voucher.RateSlot(v.Name)]; ok {
+			return GenesisAccount{}, fmt.Errorf("duplicate genesis voucher %q", v.Name)
+		}
+		storage[voucher.RateSlot(v.Name)] = common.BigToHash(v.ConversionRate)
+
+		if v.Supply != nil && v.Supply.Sign() > 0 {
+			if gv.Owner == nil {
+				return GenesisAccount{}, fmt.Errorf("genesis voucher %q has a supply but no owner", v.Name)
+			}
+			storage[voucher.BalanceSlot(v.Name, *gv.Owner)] = common.BigToHash(v.Supply)
+		}
+	}
+	return GenesisAccount{
+		Code:    voucher.VoucherCode,
+		Storage: storage,
+		Balance: new(big.Int),
+	}, nil
+}
+
+// rates returns the voucher rate snapshot of the genesis block, the conversion
+// rates the genesis vouchers are created with.
+func (gv *GenesisVoucher) rates() []types.VoucherRate {
+	rates := make([]types.VoucherRate, 0, len(gv.Vouchers))
+	for _, v := range gv.Vouchers {
+		rates = append(rates, types.VoucherRate{Currency: voucher.VoucherAddress, Voucher: v.Name, Rate: v.ConversionRate})
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Voucher < rates[j].Voucher })
+	return rates
+}
+
+// alloc returns the genesis allocation, including the predeployed contracts.
+func (g *Genesis) alloc() (GenesisAlloc, error) {
+	if g.Voucher == nil {
+		return g.Alloc, nil
+	}
+	if _, ok := g.Alloc[voucher.VoucherAddress]; ok {
+		return nil, fmt.Errorf("genesis allocation conflicts with the voucher contract at %v", voucher.VoucherAddress)
+	}
+	account, err := g.Voucher.account()
+	if err != nil {
+		return nil, err
+	}
+	alloc := make(GenesisAlloc, len(g.Alloc)+1)
+	for addr, acc := range g.Alloc {
+		alloc[addr] = acc
+	}
+	alloc[voucher.VoucherAddress] = account
+	return alloc, nil
+}
+
 // GenesisAlloc specifies the initial state that is part of the genesis block.
 type GenesisAlloc map[common.Address]GenesisAccount
 
@@ -435,7 +514,11 @@ func (g *Genesis) IsVerkle() bool {
 
 // ToBlock returns the genesis block according to genesis specification.
 func (g *Genesis) ToBlock() *types.Block {
-	root, err := g.Alloc.hash(g.IsVerkle())
+	alloc, err := g.alloc()
+	if err != nil {
+		panic(err)
+	}
+	root, err := alloc.hash(g.IsVerkle())
 	if err != nil {
 		panic(err)
 	}
@@ -497,6 +580,10 @@ func (g *Genesis) ToBlock() *types.Block {
 // Commit writes the block and state of a genesis specification to the database.
 // The block is committed as the canonical head block.
 func (g *Genesis) Commit(db ethdb.Database, triedb *trie.Database) (*types.Block, error) {
+	alloc, err := g.alloc()
+	if err != nil {
+		return nil, err
+	}
 	block := g.ToBlock()
 	if block.Number().Sign() != 0 {
 		return nil, errors.New("can't commit genesis block with number > 0")
@@ -514,23 +601,17 @@ func (g *Genesis) Commit(db ethdb.Database, triedb *trie.Database) (*types.Block
 	// All the checks has passed, flush the states derived from the genesis
 	// specification as well as the specification itself into the provided
 	// database.
-	if err := g.Alloc.flush(db, triedb, block.Hash()); err != nil {
+	if err := alloc.flush(db, triedb, block.Hash()); err != nil {
 		return nil, err
 	}
-	// generate merkel dag
-	// mdag, err := merkeldag.NewMerkelDAG()
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// mdag_root := mdag.GetRoot().GetHash()
-	// block.Header().Tainted = mdag_root
-
-	// mdagdb := mdagdb.NewMerkleDAGDB(db)
-	// mdagdb.SaveDAG(mdag)
-
+	// The genesis block carries no tainted root: chains start with the empty
+	// tainted-address DAG, see UpdateTaintDAG.
 	rawdb.WriteTd(db, block.Hash(), block.NumberU64(), block.Difficulty())
 	rawdb.WriteBlock(db, block)
 	rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), nil)
+	if g.Voucher != nil {
+		rawdb.WriteVoucherRates(db, block.Hash(), block.NumberU64(), g.Voucher.rates())
+	}
 	rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
 	rawdb.WriteHeadBlockHash(db, block.Hash())
 	rawdb.WriteHeadFastBlockHash(db, block.Hash())
@@ -27,11 +27,13 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/trie/triedb/pathdb"
+	"github.com/ethereum/go-ethereum/voucher"
 )
 
 func TestInvalidCliqueConfig(t *testing.T) {
@@ -256,6 +258,84 @@ func TestReadWriteGenesisAlloc(t *testing.T) {
 	}
 }
 
+// Tests that the voucher contract is predeployed with the genesis vouchers
+// created and their supply credited to the owner.
+func TestGenesisVoucherPredeploy(t *testing.T) {
+	owner := common.Address{0xaa}
+	genesis := &Genesis{
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Config:  params.TestChainConfig,
+		Alloc:   GenesisAlloc{owner: {Balance: big.NewInt(params.Ether)}},
+		Voucher: &GenesisVoucher{
+			Owner: &owner,
+			Vouchers: []GenesisVoucherBalance{
+				{Name: "BitCoin", ConversionRate: big.NewInt(2), Supply: big.NewInt(1000)},
+				{Name: "Gold", ConversionRate: big.NewInt(5)},
+			},
+		},
+	}
+	db := rawdb.NewMemoryDatabase()
+	triedb := trie.NewDatabase(db, trie.HashDefaults)
+	block := genesis.MustCommit(db, triedb)
+
+	statedb, err := state.New(block.Root(), state.NewDatabaseWithNodeDB(db, triedb), nil)
+	if err != nil {
+		t.Fatalf("failed to open genesis state: %v", err)
+	}
+	blockCtx := vm.BlockContext{
+		CanTransfer: CanTransfer,
+		Transfer:    Transfer,
+		BlockNumber: new(big.Int),
+		Difficulty:  new(big.Int),
+		BaseFee:     new(big.Int),
+	}
+	evm := vm.NewEVM(blockCtx, vm.TxContext{}, statedb, genesis.Config, vm.Config{})
+	backend := voucher.NewEVMBackend(evm, owner, 1_000_000)
+	currency, err := voucher.NewMultiVoucher(voucher.VoucherAddress, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decimals, err := currency.Decimals(backend.CallOpts()); err != nil || decimals.Cmp(voucher.DefaultDecimals) != 0 {
+		t.Errorf("decimals mismatch: have %v, %v, want %v", decimals, err, voucher.DefaultDecimals)
+	}
+	for name, want := range map[string]int64{"BitCoin": 2, "Gold": 5} {
+		if rate, err := currency.GetVoucherInfo(backend.CallOpts(), name); err != nil || rate.Int64() != want {
+			t.Errorf("voucher %q rate mismatch: have %v, %v, want %d", name, rate, err, want)
+		}
+	}
+	if balance, err := currency.BalanceOf(backend.CallOpts(), "BitCoin", owner); err != nil || balance.Int64() != 1000 {
+		t.Errorf("owner balance mismatch: have %v, %v, want %d", balance, err, 1000)
+	}
+	// The predeployed contract must keep working as if it was deployed normally
+	if _, err := currency.Use(backend.TransactOpts(nil), "BitCoin", big.NewInt(400)); err != nil {
+		t.Fatalf("failed to use voucher: %v", err)
+	}
+	if balance, err := currency.BalanceOf(backend.CallOpts(), "BitCoin", owner); err != nil || balance.Int64() != 600 {
+		t.Errorf("owner balance mismatch: have %v, %v, want %d", balance, err, 600)
+	}
+}
+
+// Tests that invalid voucher predeploys are rejected.
+func TestGenesisVoucherPredeployInvalid(t *testing.T) {
+	tests := []*Genesis{
+		// Voucher without a conversion rate
+		{Voucher: &GenesisVoucher{Vouchers: []GenesisVoucherBalance{{Name: "BitCoin"}}}},
+		// Supply without an owner
+		{Voucher: &GenesisVoucher{Vouchers: []GenesisVoucherBalance{{Name: "BitCoin", ConversionRate: common.Big1, Supply: common.Big1}}}},
+		// Duplicate voucher
+		{Voucher: &GenesisVoucher{Vouchers: []GenesisVoucherBalance{{Name: "BitCoin", ConversionRate: common.Big1}, {Name: "BitCoin", ConversionRate: common.Big2}}}},
+		// Allocation at the contract address
+		{Alloc: GenesisAlloc{voucher.VoucherAddress: {Balance: common.Big1}}, Voucher: &GenesisVoucher{}},
+	}
+	for i, genesis := range tests {
+		genesis.Config = params.TestChainConfig
+		db := rawdb.NewMemoryDatabase()
+		if _, err := genesis.Commit(db, trie.NewDatabase(db, trie.HashDefaults)); err == nil {
+			t.Errorf("test %d: invalid voucher predeploy accepted", i)
+		}
+	}
+}
+
 func newDbConfig(scheme string) *trie.Config {
 	if scheme == rawdb.HashScheme {
 		return trie.HashDefaults
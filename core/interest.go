@@ -0,0 +1,142 @@
+package core
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// SetInterestAccrual makes statedb accrue the interest of an account before each
+// change of its balance in block @number, and start new accounts accruing at
+// that block. It's a no-op on chains without an interest model.
+func SetInterestAccrual(config *params.ChainConfig, statedb *state.StateDB, number *big.Int) {
+	if config.Interest == nil {
+		return
+	}
+	statedb.SetInterestAccrual(func(s *state.StateDB, addr common.Address, number uint64) {
+		AccrueInterest(config, s, addr, new(big.Int).SetUint64(number))
+	}, number.Uint64())
+}
+
+// AccrueInterest credits addr the interest its balance earned since it was last
+// accrued, compounding once per period of the chain's interest model, and marks
+// it accrued as of the given block. Accounts accrue before every change of their
+// balance, so the balance is constant since the last accrual.
+// It's a no-op on chains without an interest model and for unknown accounts.
+func AccrueInterest(config *params.ChainConfig, statedb *state.StateDB, addr common.Address, number *big.Int) {
+	model := config.Interest
+	if model == nil || !statedb.Exist(addr) {
+		return
+	}
+	var (
+		last = statedb.GetInterestBlock(addr)
+		now  = number.Uint64()
+	)
+	if now <= last {
+		return
+	}
+	var (
+		balance  = statedb.GetBalance(addr)
+		interest = statedb.GetInterest(addr)
+	)
+	if !balance.IsZero() {
+		// Compound the balance and the interest it already earned, in fixed point
+		scale := model.Scale()
+		principal := new(big.Int).Mul(balance.ToBig(), scale)
+		principal.Add(principal, interest.ToBig())
+
+		compounded := compoundInterest(model, principal, last, now)
+		if earned := compounded.Sub(compounded, principal); earned.Sign() > 0 {
+			if total, overflow := uint256.FromBig(earned.Add(earned, interest.ToBig())); overflow {
+				interest.SetAllOne()
+			} else {
+				interest = total
+			}
+		}
+	}
+	statedb.SetInterest(addr, interest, now)
+}
+
+// ClaimableInterest returns how much native token addr could claim from the
+// interest accrued up to the given block, accruing it on statedb first.
+func ClaimableInterest(config *params.ChainConfig, statedb *state.StateDB, addr common.Address, number *big.Int) *uint256.Int {
+	AccrueInterest(config, statedb, addr, number)
+	return NativeInterest(config, statedb.GetInterest(addr))
+}
+
+// compoundInterest compounds the fixed point principal over the compounding
+// periods that ended in the blocks (last, now], each period at the rate in effect
+// at its last block.
+func compoundInterest(model *params.InterestConfig, principal *big.Int, last, now uint64) *big.Int {
+	var (
+		scale  = model.Scale()
+		result = new(big.Int).Set(principal)
+	)
+	// Periods end at the multiples of the period length, compound the ones with
+	// the same rate at once
+	for period, end := last/model.Period+1, now/model.Period; period <= end; {
+		rate, next := model.RateAt(period * model.Period)
+
+		until := end
+		if next != math.MaxUint64 {
+			// Last period ending before the next rate comes into effect
+			if changed := (next - 1) / model.Period; changed < until {
+				until = changed
+			}
+		}
+		if rate > 0 {
+			factor := new(big.Int).Add(scale, new(big.Int).SetUint64(rate))
+			result = mulPow(result, factor, scale, until-period+1)
+		}
+		period = until + 1
+	}
+	return result
+}
+
+// mulPow multiplies x by the n-th power of the fixed point factor, truncating to
+// the fixed point scale after every multiplication.
+func mulPow(x, factor, scale *big.Int, n uint64) *big.Int {
+	var (
+		result = new(big.Int).Set(x)
+		pow    = new(big.Int).Set(factor)
+	)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result.Mul(result, pow)
+			result.Quo(result, scale)
+		}
+		if n > 1 {
+			pow.Mul(pow, pow)
+			pow.Quo(pow, scale)
+		}
+	}
+	return result
+}
+
+// interestUnits converts a native amount into the fixed point unit interest is
+// accrued in.
+func interestUnits(config *params.ChainConfig, amount *uint256.Int) *uint256.Int {
+	if config.Interest == nil {
+		return new(uint256.Int).Set(amount)
+	}
+	scale, _ := uint256.FromBig(config.Interest.Scale())
+	units, overflow := new(uint256.Int).MulOverflow(amount, scale)
+	if overflow {
+		units.SetAllOne()
+	}
+	return units
+}
+
+// NativeInterest converts an amount of accrued interest into whole units of the
+// native token, the inverse of interestUnits.
+func NativeInterest(config *params.ChainConfig, units *uint256.Int) *uint256.Int {
+	if config.Interest == nil {
+		return new(uint256.Int).Set(units)
+	}
+	scale, _ := uint256.FromBig(config.Interest.Scale())
+	return new(uint256.Int).Div(units, scale)
+}
@@ -0,0 +1,265 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// Tests that interest compounds once per period, at the rate of the schedule in
+// effect at the end of each period.
+func TestAccrueInterest(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Interest = &params.InterestConfig{
+		Schedule:  []params.InterestRate{{Block: 0, Rate: 100}, {Block: 45, Rate: 0}}, // 1% per period until block 45
+		Period:    10,
+		Precision: 4,
+	}
+	addr := common.Address{0xaa}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetBalance(addr, uint256.NewInt(1000))
+
+	for i, tt := range []struct {
+		number   int64
+		interest uint64 // in units of 10^-4
+	}{
+		{25, 201000},  // two periods: 1000 * 1.01^2 - 1000 = 20.1
+		{29, 201000},  // no period ended
+		{30, 303010},  // one period, compounding the interest earned
+		{100, 406040}, // one period at 1%, the rest after the rate dropped to zero
+	} {
+		AccrueInterest(&config, statedb, addr, big.NewInt(tt.number))
+		if have := statedb.GetInterest(addr); have.Uint64() != tt.interest {
+			t.Errorf("test %d: interest mismatch: have %v, want %v", i, have, tt.interest)
+		}
+		if have := statedb.GetInterestBlock(addr); have != uint64(tt.number) {
+			t.Errorf("test %d: accrual block mismatch: have %d, want %d", i, have, tt.number)
+		}
+	}
+}
+
+// Tests that the blocks produced with interest accrual are accepted by an
+// importing node, i.e. that both compute the same state, and that every account
+// whose balance changes accrues first, not only the sender.
+func TestInterestAccrualImport(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		to      = common.Address{0xaa}
+		miner   = common.Address{0xbb}
+		config  = *params.TestChainConfig
+		genesis = &Genesis{
+			Config:  &config,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Alloc:   GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(&config)
+	)
+	config.Interest = &params.InterestConfig{
+		Schedule:  []params.InterestRate{{Block: 0, Rate: 1}},
+		Period:    1,
+		Precision: 2,
+	}
+	_, blocks, _ := GenerateChainWithGenesis(genesis, ethash.NewFaker(), 4, func(i int, b *BlockGen) {
+		b.SetCoinbase(miner)
+		tx, _ := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     b.TxNonce(sender),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: b.header.BaseFee,
+			Gas:       params.TxGas,
+			To:        &to,
+			Value:     big.NewInt(100),
+		})
+		b.AddTx(tx)
+	})
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to open head state: %v", err)
+	}
+	for _, addr := range []common.Address{sender, to, miner} {
+		if statedb.GetInterest(addr).IsZero() {
+			t.Errorf("%x: no interest accrued", addr)
+		}
+		if have, want := statedb.GetInterestBlock(addr), uint64(len(blocks)); have != want {
+			t.Errorf("%x: accrual block mismatch: have %d, want %d", addr, have, want)
+		}
+	}
+	// The recipient accrued from the block that created it, before each deposit:
+	// 1.00 wei in block 2, 2.01 in block 3 and 3.03 in block 4
+	if have, want := statedb.GetInterest(to).Uint64(), uint64(604); have != want {
+		t.Errorf("recipient interest mismatch: have %d, want %d", have, want)
+	}
+}
+
+// Tests that calling the claim address moves accrued interest into the balance
+// of the caller, either all whole wei of it or the requested amount.
+func TestInterestClaim(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Interest = &params.InterestConfig{
+		Schedule:  []params.InterestRate{{Block: 0, Rate: 1}},
+		Period:    1,
+		Precision: 2,
+	}
+	var (
+		addr       = common.Address{0xaa}
+		claim      = config.Interest.ClaimAddress()
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		context    = vm.BlockContext{CanTransfer: CanTransfer, Transfer: Transfer, BlockNumber: big.NewInt(1)}
+		evm        = vm.NewEVM(context, vm.TxContext{}, statedb, &config, vm.Config{})
+		gas        = params.InterestClaimGas
+	)
+	statedb.SetBalance(addr, uint256.NewInt(10))
+	statedb.SetInterest(addr, uint256.NewInt(1234567), 1) // 12345.67 wei
+	SetInterestAccrual(&config, statedb, context.BlockNumber)
+
+	for i, tt := range []struct {
+		input    []byte
+		value    uint64
+		err      error
+		claimed  uint64
+		interest uint64
+	}{
+		{input: common.LeftPadBytes([]byte{0x03, 0xe8}, 32), claimed: 1000, interest: 1134567},
+		{input: common.LeftPadBytes([]byte{0x30, 0x39}, 32), err: vm.ErrExecutionReverted, interest: 1134567}, // more than accrued
+		{input: []byte{0x01}, err: vm.ErrExecutionReverted, interest: 1134567},
+		{value: 1, err: vm.ErrExecutionReverted, interest: 1134567},
+		{claimed: 11345, interest: 67},
+		{claimed: 0, interest: 67},
+	} {
+		balance := statedb.GetBalance(addr).Uint64()
+
+		ret, left, err := evm.Call(vm.AccountRef(addr), claim, tt.input, gas, uint256.NewInt(tt.value))
+		if err != tt.err {
+			t.Fatalf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if left != 0 {
+			t.Errorf("test %d: gas left: %d", i, left)
+		}
+		if have := statedb.GetBalance(addr).Uint64() - balance; have != tt.claimed {
+			t.Errorf("test %d: balance change mismatch: have %d, want %d", i, have, tt.claimed)
+		}
+		if err == nil {
+			if have := new(uint256.Int).SetBytes(ret).Uint64(); have != tt.claimed {
+				t.Errorf("test %d: returned amount mismatch: have %d, want %d", i, have, tt.claimed)
+			}
+		}
+		if have := statedb.GetInterest(addr).Uint64(); have != tt.interest {
+			t.Errorf("test %d: interest mismatch: have %d, want %d", i, have, tt.interest)
+		}
+	}
+	if _, _, err := evm.Call(vm.AccountRef(addr), claim, nil, gas-1, new(uint256.Int)); err != vm.ErrOutOfGas {
+		t.Errorf("claim with insufficient gas: have %v, want %v", err, vm.ErrOutOfGas)
+	}
+}
+
+// Tests that claiming all interest in a block yields the amount the API reports
+// as claimable at that block, including the interest accrued since the last
+// accrual of the account.
+func TestInterestClaimMatchesClaimable(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Interest = &params.InterestConfig{
+		Schedule:  []params.InterestRate{{Block: 0, Rate: 1}},
+		Period:    1,
+		Precision: 2,
+	}
+	var (
+		addr       = common.Address{0xaa}
+		number     = big.NewInt(10)
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		context    = vm.BlockContext{CanTransfer: CanTransfer, Transfer: Transfer, BlockNumber: number}
+		evm        = vm.NewEVM(context, vm.TxContext{}, statedb, &config, vm.Config{})
+	)
+	statedb.SetBalance(addr, uint256.NewInt(100000))
+	statedb.SetInterest(addr, uint256.NewInt(50), 1)
+
+	claimable := ClaimableInterest(&config, statedb.Copy(), addr, number)
+	if claimable.IsZero() {
+		t.Fatal("no interest claimable")
+	}
+	SetInterestAccrual(&config, statedb, number)
+	ret, _, err := evm.Call(vm.AccountRef(addr), config.Interest.ClaimAddress(), nil, params.InterestClaimGas, new(uint256.Int))
+	if err != nil {
+		t.Fatalf("claim failed: %v", err)
+	}
+	if have := new(uint256.Int).SetBytes(ret); !have.Eq(claimable) {
+		t.Errorf("claimed amount mismatch: have %v, want %v", have, claimable)
+	}
+}
+
+// Tests that native fees are paid from the accrued interest first and from the
+// balance for the rest, and that the unused gas is refunded to the balance first.
+func TestInterestFeePayment(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Interest = &params.InterestConfig{
+		Schedule:  []params.InterestRate{{Block: 0, Rate: 1}},
+		Period:    1,
+		Precision: 2,
+	}
+	for i, tt := range []struct {
+		balance, interest         uint64 // interest in wei
+		err                       error
+		interestFee, balanceFee   uint64
+		wantBalance, wantInterest uint64
+	}{
+		// Upfront 50000 wei, 21000 wei used
+		{balance: 100000, interest: 10000, interestFee: 10000, balanceFee: 11000, wantBalance: 89000},
+		{balance: 100000, interest: 60000, interestFee: 21000, wantBalance: 100000, wantInterest: 39000},
+		{balance: 40000, interest: 10000, interestFee: 10000, balanceFee: 11000, wantBalance: 29000},
+		{balance: 39999, interest: 10000, err: ErrInsufficientFunds},
+	} {
+		var (
+			from       = common.Address{0xaa}
+			statedb, _ = state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+			context    = vm.BlockContext{CanTransfer: CanTransfer, Transfer: Transfer, BlockNumber: big.NewInt(1), BaseFee: big.NewInt(1)}
+			evm        = vm.NewEVM(context, vm.TxContext{GasPrice: big.NewInt(1)}, statedb, &config, vm.Config{})
+		)
+		statedb.SetBalance(from, uint256.NewInt(tt.balance))
+		statedb.SetInterest(from, uint256.NewInt(tt.interest*100), 1)
+
+		result, err := ApplyMessage(evm, &Message{
+			From:              from,
+			To:                &common.Address{0xbb},
+			Value:             new(big.Int),
+			GasLimit:          50000,
+			GasPrice:          big.NewInt(1),
+			GasFeeCap:         big.NewInt(1),
+			GasTipCap:         new(big.Int),
+			SkipAccountChecks: true,
+		}, new(GasPool).AddGas(50000))
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if err != nil {
+			continue
+		}
+		if result.InterestFee.Uint64() != tt.interestFee || result.BalanceFee.Uint64() != tt.balanceFee {
+			t.Errorf("test %d: fee split mismatch: have %v/%v, want %d/%d", i, result.InterestFee, result.BalanceFee, tt.interestFee, tt.balanceFee)
+		}
+		if have := statedb.GetBalance(from).Uint64(); have != tt.wantBalance {
+			t.Errorf("test %d: balance mismatch: have %d, want %d", i, have, tt.wantBalance)
+		}
+		if have := statedb.GetInterest(from).Uint64(); have != tt.wantInterest*100 {
+			t.Errorf("test %d: interest mismatch: have %d, want %d", i, have, tt.wantInterest*100)
+		}
+	}
+}
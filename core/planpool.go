@@ -6,8 +6,6 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/params"
 )
 
 // Plan represents a parameter update plan that will take effect at specified height
@@ -15,19 +13,19 @@ type Plan struct {
 	ID     uint64 // Unique identifier for the plan
 	Height uint64 // Block height when this plan takes effect
 
-	// Parameters that can be updated
-	Difficulty     *big.Int
-	TargetPowRatio *common.Rational
+	// Parameters that can be updated, see params.PowParams
+	Difficulty     *big.Int         `rlp:"nil"`
+	TargetPowRatio *common.Rational `rlp:"nil"`
 	MinPowGas      uint64
 	MaxPowGas      uint64
 	InitialGas     uint64
-	MinPrice       *big.Int
-	MaxPrice       *big.Int
-	Alpha          *common.Rational
-	Fmin           *common.Rational
-	Fmax           *common.Rational
-	Kp             *common.Rational
-	Ki             *common.Rational
+	MinPrice       *big.Int         `rlp:"nil"`
+	MaxPrice       *big.Int         `rlp:"nil"`
+	Alpha          *common.Rational `rlp:"nil"`
+	Fmin           *common.Rational `rlp:"nil"`
+	Fmax           *common.Rational `rlp:"nil"`
+	Kp             *common.Rational `rlp:"nil"`
+	Ki             *common.Rational `rlp:"nil"`
 }
 
 // PlanPool manages all parameter update plans
@@ -151,11 +149,6 @@ func (pp *PlanPool) GetPlanByID(id uint64) *Plan {
 	return nil
 }
 
-type ParamUpdater struct {
-	update func(interface{})
-	value  func(p *Plan) interface{}
-}
-
 // MergePlans merges multiple plans and returns a new plan with the latest values
 func (pp *PlanPool) MergePlans(height uint64) *Plan {
 	plans := pp.GetPlansByHeight(height)
@@ -212,68 +205,3 @@ func (pp *PlanPool) MergePlans(height uint64) *Plan {
 
 	return mergedPlan
 }
-
-// UpdateParams updates all parameters based on the given plan
-func (pp *PlanPool) UpdateParams(plan *Plan) {
-	if plan == nil {
-		return
-	}
-
-	paramUpdaters := map[string]ParamUpdater{
-		"Difficulty": {
-			update: func(v interface{}) { params.InitialDifficulty = v.(*big.Int) },
-			value:  func(p *Plan) interface{} { return p.Difficulty },
-		},
-		"TargetPowRatio": {
-			update: func(v interface{}) { params.TargetPowRatio = v.(*common.Rational) },
-			value:  func(p *Plan) interface{} { return p.TargetPowRatio },
-		},
-		"MinPowGas": {
-			update: func(v interface{}) { params.MinPowGas = v.(uint64) },
-			value:  func(p *Plan) interface{} { return p.MinPowGas },
-		},
-		"MaxPowGas": {
-			update: func(v interface{}) { params.MaxPowGas = v.(uint64) },
-			value:  func(p *Plan) interface{} { return p.MaxPowGas },
-		},
-		"InitialGas": {
-			update: func(v interface{}) { params.InitialGas = v.(uint64) },
-			value:  func(p *Plan) interface{} { return p.InitialGas },
-		},
-		"MinPrice": {
-			update: func(v interface{}) { params.MinPrice = v.(*big.Int) },
-			value:  func(p *Plan) interface{} { return p.MinPrice },
-		},
-		"MaxPrice": {
-			update: func(v interface{}) { params.MaxPrice = v.(*big.Int) },
-			value:  func(p *Plan) interface{} { return p.MaxPrice },
-		},
-		"Alpha": {
-			update: func(v interface{}) { params.Alpha = v.(*common.Rational) },
-			value:  func(p *Plan) interface{} { return p.Alpha },
-		},
-		"Fmin": {
-			update: func(v interface{}) { params.Fmin = v.(*common.Rational) },
-			value:  func(p *Plan) interface{} { return p.Fmin },
-		},
-		"Fmax": {
-			update: func(v interface{}) { params.Fmax = v.(*common.Rational) },
-			value:  func(p *Plan) interface{} { return p.Fmax },
-		},
-		"Kp": {
-			update: func(v interface{}) { params.Kp = v.(*common.Rational) },
-			value:  func(p *Plan) interface{} { return p.Kp },
-		},
-		"Ki": {
-			update: func(v interface{}) { params.Ki = v.(*common.Rational) },
-			value:  func(p *Plan) interface{} { return p.Ki },
-		},
-	}
-
-	for name, updater := range paramUpdaters {
-		if value := updater.value(plan); value != nil && value != 0 {
-			updater.update(value)
-			log.Info("Parameter updated", "name", name, "value", value)
-		}
-	}
-}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// The PoW economics fields of a header are derived from the parent header, the
+// transactions of the block and the parameters of the active plan only, so that
+// every node computes the same values:
+//
+//   - PowDifficulty, PowPrice and PowGas apply to the PoW transactions of the
+//     block, and are derived from the parent header before executing it.
+//   - AvgRatio and AvgGas are the moving averages of the PoW transaction ratio
+//     and of the gas usage, updated with the transactions of the block.
+//
+// The parameters are those in effect at the block, from the plans recorded in
+// the state of the parent, see PowParamsAt.
+
+// CalcPowParams calculates the PoW difficulty, price and gas of the block after
+// parent. The price follows a PI controller on the distance of the average PoW
+// ratio to its target, the proportional term acting on the change of distance
+// since the grandparent.
+func CalcPowParams(chain consensus.ChainHeaderReader, pow *params.PowParams, parent *types.Header) (*big.Int, *big.Int, uint64) {
+	var (
+		target = ratToBig(pow.TargetPowRatio)
+		diff   = new(big.Rat).Sub(target, avgRatio(parent))
+		delta  = new(big.Rat)
+	)
+	if parent.Number.Sign() > 0 {
+		if grandparent := chain.GetHeader(parent.ParentHash, parent.Number.Uint64()-1); grandparent != nil {
+			delta.Sub(avgRatio(grandparent), avgRatio(parent))
+		}
+	}
+	// The difficulty eases when PoW transactions are below target, and rises
+	// when they are above
+	difficulty := new(big.Rat).SetInt(pow.Difficulty)
+	if parent.PowDifficulty != nil && parent.PowDifficulty.Sign() > 0 {
+		difficulty.SetInt(parent.PowDifficulty)
+	}
+	switch diff.Sign() {
+	case 1:
+		difficulty.Mul(difficulty, ratToBig(pow.Fmin))
+	case -1:
+		difficulty.Mul(difficulty, ratToBig(pow.Fmax))
+	}
+	powDifficulty := ratFloor(difficulty)
+	if powDifficulty.Sign() <= 0 {
+		powDifficulty.SetUint64(1)
+	}
+	// The price moves relatively to the parent's, by kp * delta + ki * diff, and
+	// by at least a wei not to stall on small prices
+	powPrice := new(big.Int).Set(pow.MinPrice)
+	if parent.PowPrice != nil && parent.PowPrice.Sign() > 0 {
+		powPrice.Set(parent.PowPrice)
+	}
+	adjustment := new(big.Rat).Mul(ratToBig(pow.Kp), delta)
+	adjustment.Add(adjustment, new(big.Rat).Mul(ratToBig(pow.Ki), diff))
+	adjustment.Mul(adjustment, new(big.Rat).SetInt(powPrice))
+
+	step := ratFloor(new(big.Rat).Abs(adjustment))
+	if step.Sign() == 0 && adjustment.Sign() != 0 {
+		step.SetUint64(1)
+	}
+	if adjustment.Sign() < 0 {
+		step.Neg(step)
+	}
+	powPrice.Add(powPrice, step)
+	if powPrice.Cmp(pow.MinPrice) < 0 {
+		powPrice.Set(pow.MinPrice)
+	} else if powPrice.Cmp(pow.MaxPrice) > 0 {
+		powPrice.Set(pow.MaxPrice)
+	}
+	// The gas grows by a tenth when blocks are mostly full, and shrinks by a
+	// tenth when they are mostly empty
+	gas := pow.InitialGas
+	if parent.PowGas != 0 {
+		gas = parent.PowGas
+	}
+	usage := common.NewRational(parent.AvgGasNumerator, parent.AvgGasDenominator)
+	if usage.Compare(common.NewRational(8, 10)) > 0 {
+		gas += gas / 10
+	} else if usage.Compare(common.NewRational(5, 10)) < 0 {
+		gas -= gas / 10
+	}
+	gas = min(max(gas, pow.MinPowGas), pow.MaxPowGas)
+
+	return powDifficulty, powPrice, gas
+}
+
+// CalcPowAverages updates the moving averages of the parent with the PoW
+// transaction ratio and the gas usage of the transactions of a block. Blocks
+// without transactions leave the average PoW ratio unchanged.
+func CalcPowAverages(pow *params.PowParams, parent *types.Header, header *types.Header, txs types.Transactions) (ratio *common.Rational, usage *common.Rational) {
+	var pows, gas uint64
+	for _, tx := range txs {
+		if tx.Type() == types.PowTxType {
+			pows++
+		}
+		gas += tx.Gas()
+	}
+	ratio = common.NewRational(parent.AvgRatioNumerator, parent.AvgRatioDenominator)
+	if len(txs) > 0 {
+		ratio = ema(pow.Alpha, common.NewRational(pows, uint64(len(txs))), ratio)
+	}
+	usage = ema(pow.Alpha, common.NewRational(gas, header.GasLimit), common.NewRational(parent.AvgGasNumerator, parent.AvgGasDenominator))
+	return ratio, usage
+}
+
+// VerifyPowEconomics checks the PoW economics fields of a header against the
+// values derived from its parent, the state of the parent and its transactions.
+// The fields are checked from the PoW economics fork block on, whether the
+// header carries them or not.
+func VerifyPowEconomics(chain consensus.ChainHeaderReader, statedb vm.StateDB, parent *types.Header, header *types.Header, txs types.Transactions) error {
+	if !chain.Config().IsPowEconomics(header.Number) {
+		return nil
+	}
+	pow := PowParamsAt(statedb, header.Number.Uint64())
+	difficulty, price, gas := CalcPowParams(chain, pow, parent)
+	if header.PowDifficulty == nil || header.PowDifficulty.Cmp(difficulty) != 0 {
+		return fmt.Errorf("invalid pow difficulty: have %v, want %v", header.PowDifficulty, difficulty)
+	}
+	if header.PowPrice == nil || header.PowPrice.Cmp(price) != 0 {
+		return fmt.Errorf("invalid pow price: have %v, want %v", header.PowPrice, price)
+	}
+	if header.PowGas != gas {
+		return fmt.Errorf("invalid pow gas: have %d, want %d", header.PowGas, gas)
+	}
+	ratio, usage := CalcPowAverages(pow, parent, header, txs)
+	if header.AvgRatioNumerator != ratio.Numerator || header.AvgRatioDenominator != ratio.Denominator {
+		return fmt.Errorf("invalid average pow ratio: have %d/%d, want %d/%d", header.AvgRatioNumerator, header.AvgRatioDenominator, ratio.Numerator, ratio.Denominator)
+	}
+	if header.AvgGasNumerator != usage.Numerator || header.AvgGasDenominator != usage.Denominator {
+		return fmt.Errorf("invalid average gas usage: have %d/%d, want %d/%d", header.AvgGasNumerator, header.AvgGasDenominator, usage.Numerator, usage.Denominator)
+	}
+	return nil
+}
+
+// ema mixes an observation into a moving average with the smoothing factor.
+func ema(alpha *common.Rational, observed *common.Rational, avg *common.Rational) *common.Rational {
+	oneMinusAlpha := common.NewRational(1, 1).Sub(alpha)
+	return observed.Mul(alpha).Add(avg.Mul(oneMinusAlpha))
+}
+
+// avgRatio returns the average PoW ratio of a header.
+func avgRatio(header *types.Header) *big.Rat {
+	return ratToBig(common.NewRational(header.AvgRatioNumerator, header.AvgRatioDenominator))
+}
+
+func ratToBig(r *common.Rational) *big.Rat {
+	return new(big.Rat).SetFrac(new(big.Int).SetUint64(r.Numerator), new(big.Int).SetUint64(r.Denominator))
+}
+
+func ratFloor(r *big.Rat) *big.Int {
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestCalcPowParams(t *testing.T) {
+	pow := params.DefaultPowParams()
+
+	// Parents at genesis height, so that no grandparent is looked up
+	newParent := func(ratio *common.Rational, usage *common.Rational) *types.Header {
+		return &types.Header{
+			Number:              new(big.Int),
+			PowDifficulty:       big.NewInt(1000),
+			PowPrice:            big.NewInt(1000),
+			PowGas:              pow.InitialGas,
+			AvgRatioNumerator:   ratio.Numerator,
+			AvgRatioDenominator: ratio.Denominator,
+			AvgGasNumerator:     usage.Numerator,
+			AvgGasDenominator:   usage.Denominator,
+		}
+	}
+	// Few PoW transactions and mostly full blocks: easier and better paid, more gas
+	difficulty, price, gas := CalcPowParams(nil, pow, newParent(common.NewRational(1, 10), common.NewRational(9, 10)))
+	if difficulty.Cmp(big.NewInt(1000)) >= 0 || price.Cmp(big.NewInt(1000)) <= 0 || gas <= pow.InitialGas {
+		t.Errorf("wrong params below target: difficulty %v, price %v, gas %d", difficulty, price, gas)
+	}
+	// Many PoW transactions and mostly empty blocks: harder and worse paid, less gas
+	difficulty, price, gas = CalcPowParams(nil, pow, newParent(common.NewRational(9, 10), common.NewRational(1, 10)))
+	if difficulty.Cmp(big.NewInt(1000)) <= 0 || price.Cmp(big.NewInt(1000)) >= 0 || gas >= pow.InitialGas {
+		t.Errorf("wrong params above target: difficulty %v, price %v, gas %d", difficulty, price, gas)
+	}
+	// On target, nothing moves
+	difficulty, price, gas = CalcPowParams(nil, pow, newParent(pow.TargetPowRatio, common.NewRational(6, 10)))
+	if difficulty.Cmp(big.NewInt(1000)) != 0 || price.Cmp(big.NewInt(1000)) != 0 || gas != pow.InitialGas {
+		t.Errorf("wrong params on target: difficulty %v, price %v, gas %d", difficulty, price, gas)
+	}
+	// Parents without the fields start from the initial values, within bounds
+	difficulty, price, gas = CalcPowParams(nil, pow, &types.Header{Number: new(big.Int)})
+	if difficulty.Sign() <= 0 || price.Cmp(pow.MinPrice) < 0 || price.Cmp(pow.MaxPrice) > 0 || gas < pow.MinPowGas || gas > pow.MaxPowGas {
+		t.Errorf("wrong initial params: difficulty %v, price %v, gas %d", difficulty, price, gas)
+	}
+}
+
+func TestCalcPowParamsBounds(t *testing.T) {
+	pow := params.DefaultPowParams()
+	parent := &types.Header{Number: new(big.Int)}
+	for i := 0; i < 2000; i++ {
+		// Alternate long runs of full and empty blocks without PoW transactions
+		var usage *common.Rational
+		if (i/100)%2 == 0 {
+			usage = common.NewRational(1, 1)
+		} else {
+			usage = common.NewRational(0, 1)
+		}
+		difficulty, price, gas := CalcPowParams(nil, pow, parent)
+		if difficulty.Sign() <= 0 {
+			t.Fatalf("block %d: non-positive difficulty %v", i, difficulty)
+		}
+		if price.Cmp(pow.MinPrice) < 0 || price.Cmp(pow.MaxPrice) > 0 {
+			t.Fatalf("block %d: price %v out of bounds", i, price)
+		}
+		if gas < pow.MinPowGas || gas > pow.MaxPowGas {
+			t.Fatalf("block %d: gas %d out of bounds", i, gas)
+		}
+		parent = &types.Header{
+			Number:            new(big.Int),
+			PowDifficulty:     difficulty,
+			PowPrice:          price,
+			PowGas:            gas,
+			AvgGasNumerator:   usage.Numerator,
+			AvgGasDenominator: usage.Denominator,
+		}
+	}
+	// Prices keep rising while PoW transactions stay below target
+	if parent.PowPrice.Cmp(pow.MaxPrice) != 0 {
+		t.Errorf("price didn't reach the maximum: %v", parent.PowPrice)
+	}
+}
+
+func TestCalcPowAverages(t *testing.T) {
+	pow := params.DefaultPowParams()
+	parent := &types.Header{AvgRatioNumerator: 1, AvgRatioDenominator: 2}
+	header := &types.Header{GasLimit: 84000}
+
+	// Blocks without transactions keep the ratio and pull the usage down
+	ratio, usage := CalcPowAverages(pow, parent, header, nil)
+	if ratio.Numerator != 1 || ratio.Denominator != 2 || usage.Numerator != 0 {
+		t.Errorf("wrong averages of empty block: ratio %v, usage %v", ratio, usage)
+	}
+	txs := types.Transactions{
+		types.NewTx(&types.LegacyTx{Gas: 21000}),
+		types.NewTx(&types.LegacyTx{Gas: 21000}),
+	}
+	// Half-full block of non-PoW transactions: both averages move half way
+	ratio, usage = CalcPowAverages(pow, parent, header, txs)
+	want := common.NewRational(1, 1).Sub(pow.Alpha).Mul(common.NewRational(1, 2))
+	if *ratio != *want {
+		t.Errorf("wrong average ratio: have %v, want %v", ratio, want)
+	}
+	if want := pow.Alpha.Mul(common.NewRational(1, 2)); *usage != *want {
+		t.Errorf("wrong average usage: have %v, want %v", usage, want)
+	}
+}
+
+// setPowEconomics fills the PoW economics fields of a generated block.
+func setPowEconomics(b *BlockGen) {
+	var (
+		parent = b.parent.Header()
+		pow    = PowParamsAt(b.statedb, b.header.Number.Uint64())
+	)
+	b.header.PowDifficulty, b.header.PowPrice, b.header.PowGas = CalcPowParams(b.cm, pow, parent)
+
+	ratio, usage := CalcPowAverages(pow, parent, b.header, b.txs)
+	b.header.AvgRatioNumerator, b.header.AvgRatioDenominator = ratio.Numerator, ratio.Denominator
+	b.header.AvgGasNumerator, b.header.AvgGasDenominator = usage.Numerator, usage.Denominator
+}
+
+func TestPowEconomicsImport(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		config = *params.TestChainConfig
+		gspec  = &Genesis{
+			Config: &config,
+			Alloc:  GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	config.PowEconomicsBlock = big.NewInt(0)
+
+	generate := func(tamper func(i int, b *BlockGen)) []*types.Block {
+		_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 6, func(i int, b *BlockGen) {
+			for j := 0; j < i; j++ {
+				tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addr), common.Address{1}, big.NewInt(1), params.TxGas, b.BaseFee(), nil), signer, key)
+				b.AddTx(tx)
+			}
+			setPowEconomics(b)
+			if tamper != nil {
+				tamper(i, b)
+			}
+		})
+		return blocks
+	}
+	insert := func(blocks []*types.Block) (int, error) {
+		chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer chain.Stop()
+		return chain.InsertChain(blocks)
+	}
+	if _, err := insert(generate(nil)); err != nil {
+		t.Fatalf("failed to import chain with valid pow economics: %v", err)
+	}
+	tests := []struct {
+		name   string
+		tamper func(b *BlockGen)
+		err    string
+	}{
+		{"difficulty", func(b *BlockGen) { b.header.PowDifficulty = new(big.Int).Add(b.header.PowDifficulty, common.Big1) }, "invalid pow difficulty"},
+		{"price", func(b *BlockGen) { b.header.PowPrice = new(big.Int).Add(b.header.PowPrice, common.Big1) }, "invalid pow price"},
+		{"gas", func(b *BlockGen) { b.header.PowGas++ }, "invalid pow gas"},
+		{"ratio", func(b *BlockGen) { b.header.AvgRatioNumerator++ }, "invalid average pow ratio"},
+		{"usage", func(b *BlockGen) { b.header.AvgGasDenominator++ }, "invalid average gas usage"},
+		{"dropped", func(b *BlockGen) { b.header.PowPrice = nil }, "invalid pow price"},
+	}
+	for _, tt := range tests {
+		blocks := generate(func(i int, b *BlockGen) {
+			if i == 3 {
+				tt.tamper(b)
+			}
+		})
+		n, err := insert(blocks)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: wrong error: have %v, want %q", tt.name, err, tt.err)
+		}
+		if n != 3 {
+			t.Errorf("%s: wrong failed block: have %d, want 3", tt.name, n)
+		}
+	}
+	// Blocks leave the fields empty before the fork only
+	config.PowEconomicsBlock = big.NewInt(4)
+	blocks := generate(func(i int, b *BlockGen) {
+		b.header.PowDifficulty, b.header.PowPrice, b.header.PowGas = nil, nil, 0
+		b.header.AvgRatioNumerator, b.header.AvgRatioDenominator = 0, 0
+		b.header.AvgGasNumerator, b.header.AvgGasDenominator = 0, 0
+	})
+	n, err := insert(blocks)
+	if err == nil || !strings.Contains(err.Error(), "invalid pow difficulty") {
+		t.Errorf("fields missing from the fork on: wrong error: have %v", err)
+	}
+	if n != 3 {
+		t.Errorf("fields missing from the fork on: wrong failed block: have %d, want 3", n)
+	}
+}
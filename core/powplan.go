@@ -0,0 +1,177 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/governance"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Plans of the PoW economics parameters are recorded in the storage of the plan
+// address, so that the parameters in effect at a block derive from the state of
+// its parent alone. Accounts holding the plan role of the governance module
+// schedule plans with plan transactions to the plan address, for a height after
+// the block including them. Each parameter keeps the value in effect and the
+// value pending from the height of the last plan setting it, a plan replacing
+// the pending values of the parameters it sets.
+
+// powPlanPrefix starts the data of the plan transactions, followed by the RLP
+// encoded plan.
+var powPlanPrefix = []byte{0x0D, 0x07}
+
+// ErrInvalidPowPlan is returned by plan transactions whose plan can't be
+// scheduled.
+var ErrInvalidPowPlan = errors.New("invalid pow plan")
+
+// PowPlanData returns the data of a transaction scheduling plan.
+func PowPlanData(plan *Plan) ([]byte, error) {
+	enc, err := rlp.EncodeToBytes(plan)
+	if err != nil {
+		return nil, err
+	}
+	return append(common.CopyBytes(powPlanPrefix), enc...), nil
+}
+
+// isPowPlanTx reports whether a message is a plan transaction.
+func isPowPlanTx(msg *Message) bool {
+	return msg.To != nil && *msg.To == params.PowPlanAddress && bytes.HasPrefix(msg.Data, powPlanPrefix)
+}
+
+// powPlanParam is a parameter plans set, stored as a word: integers big endian,
+// rationals as their numerator and denominator, the zero word leaving the
+// parameter unset.
+type powPlanParam struct {
+	get func(*Plan) common.Hash
+	set func(*params.PowParams, common.Hash)
+}
+
+// powPlanParams are the parameters plans set, in the order of their slots.
+var powPlanParams = []powPlanParam{
+	{func(p *Plan) common.Hash { return bigWord(p.Difficulty) }, func(pp *params.PowParams, w common.Hash) { pp.Difficulty = w.Big() }},
+	{func(p *Plan) common.Hash { return ratWord(p.TargetPowRatio) }, func(pp *params.PowParams, w common.Hash) { pp.TargetPowRatio = wordRat(w) }},
+	{func(p *Plan) common.Hash { return uintWord(p.MinPowGas) }, func(pp *params.PowParams, w common.Hash) { pp.MinPowGas = w.Big().Uint64() }},
+	{func(p *Plan) common.Hash { return uintWord(p.MaxPowGas) }, func(pp *params.PowParams, w common.Hash) { pp.MaxPowGas = w.Big().Uint64() }},
+	{func(p *Plan) common.Hash { return uintWord(p.InitialGas) }, func(pp *params.PowParams, w common.Hash) { pp.InitialGas = w.Big().Uint64() }},
+	{func(p *Plan) common.Hash { return bigWord(p.MinPrice) }, func(pp *params.PowParams, w common.Hash) { pp.MinPrice = w.Big() }},
+	{func(p *Plan) common.Hash { return bigWord(p.MaxPrice) }, func(pp *params.PowParams, w common.Hash) { pp.MaxPrice = w.Big() }},
+	{func(p *Plan) common.Hash { return ratWord(p.Alpha) }, func(pp *params.PowParams, w common.Hash) { pp.Alpha = wordRat(w) }},
+	{func(p *Plan) common.Hash { return ratWord(p.Fmin) }, func(pp *params.PowParams, w common.Hash) { pp.Fmin = wordRat(w) }},
+	{func(p *Plan) common.Hash { return ratWord(p.Fmax) }, func(pp *params.PowParams, w common.Hash) { pp.Fmax = wordRat(w) }},
+	{func(p *Plan) common.Hash { return ratWord(p.Kp) }, func(pp *params.PowParams, w common.Hash) { pp.Kp = wordRat(w) }},
+	{func(p *Plan) common.Hash { return ratWord(p.Ki) }, func(pp *params.PowParams, w common.Hash) { pp.Ki = wordRat(w) }},
+}
+
+// Slots of a parameter at the plan address.
+const (
+	powPlanCurrent = iota // Value in effect
+	powPlanPending        // Value pending
+	powPlanHeight         // Height the pending value takes effect at
+)
+
+// powPlanSlot returns the slot of the plan address holding a field of the
+// parameter with the given index.
+func powPlanSlot(param int, field byte) common.Hash {
+	return crypto.Keccak256Hash([]byte("powPlan"), binary.BigEndian.AppendUint64(nil, uint64(param)), []byte{field})
+}
+
+// PowParamsAt returns the PoW economics parameters in effect at the block with
+// the given number, from the state of its parent.
+func PowParamsAt(statedb vm.StateDB, number uint64) *params.PowParams {
+	pow := params.DefaultPowParams()
+	for i, param := range powPlanParams {
+		value := statedb.GetState(params.PowPlanAddress, powPlanSlot(i, powPlanCurrent))
+		if height := statedb.GetState(params.PowPlanAddress, powPlanSlot(i, powPlanHeight)).Big(); height.Sign() > 0 && height.Uint64() <= number {
+			value = statedb.GetState(params.PowPlanAddress, powPlanSlot(i, powPlanPending))
+		}
+		if value != (common.Hash{}) {
+			param.set(pow, value)
+		}
+	}
+	return pow
+}
+
+// schedulePowPlan records the plan of a plan transaction of the block with the
+// given number.
+func schedulePowPlan(statedb vm.StateDB, number uint64, plan *Plan) error {
+	if plan.Height <= number {
+		return fmt.Errorf("%w: height %d not after block %d", ErrInvalidPowPlan, plan.Height, number)
+	}
+	for _, r := range []*common.Rational{plan.TargetPowRatio, plan.Alpha, plan.Fmin, plan.Fmax, plan.Kp, plan.Ki} {
+		if r != nil && r.Denominator == 0 {
+			return fmt.Errorf("%w: zero denominator", ErrInvalidPowPlan)
+		}
+	}
+	for _, v := range []*big.Int{plan.Difficulty, plan.MinPrice, plan.MaxPrice} {
+		if v != nil && v.BitLen() > 256 {
+			return fmt.Errorf("%w: %v exceeds 256 bits", ErrInvalidPowPlan, v)
+		}
+	}
+	// The nonce keeps the account from being cleared as empty
+	if statedb.GetNonce(params.PowPlanAddress) == 0 {
+		statedb.SetNonce(params.PowPlanAddress, 1)
+	}
+	for i, param := range powPlanParams {
+		value := param.get(plan)
+		if value == (common.Hash{}) {
+			continue
+		}
+		// Pending values in effect already become the current ones
+		var (
+			current = powPlanSlot(i, powPlanCurrent)
+			pending = powPlanSlot(i, powPlanPending)
+			height  = powPlanSlot(i, powPlanHeight)
+		)
+		if h := statedb.GetState(params.PowPlanAddress, height).Big(); h.Sign() > 0 && h.Uint64() <= number {
+			statedb.SetState(params.PowPlanAddress, current, statedb.GetState(params.PowPlanAddress, pending))
+		}
+		statedb.SetState(params.PowPlanAddress, pending, value)
+		statedb.SetState(params.PowPlanAddress, height, uintWord(plan.Height))
+	}
+	return nil
+}
+
+// schedulePowPlan runs a plan transaction, scheduling its plan if the sender
+// holds the plan role.
+func (st *StateTransition) schedulePowPlan() error {
+	config := st.evm.ChainConfig().Security
+	if !governance.HasRole(config, st.state, governance.RolePlan, st.msg.From) {
+		return fmt.Errorf("%w: %v lacks role %d", governance.ErrUnauthorized, st.msg.From, governance.RolePlan)
+	}
+	plan := new(Plan)
+	if err := rlp.DecodeBytes(st.msg.Data[len(powPlanPrefix):], plan); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPowPlan, err)
+	}
+	return schedulePowPlan(st.state, st.evm.Context.BlockNumber.Uint64(), plan)
+}
+
+func bigWord(v *big.Int) common.Hash {
+	if v == nil {
+		return common.Hash{}
+	}
+	return common.BigToHash(v)
+}
+
+func uintWord(v uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(v))
+}
+
+func ratWord(r *common.Rational) common.Hash {
+	var w common.Hash
+	if r != nil {
+		binary.BigEndian.PutUint64(w[16:], r.Numerator)
+		binary.BigEndian.PutUint64(w[24:], r.Denominator)
+	}
+	return w
+}
+
+func wordRat(w common.Hash) *common.Rational {
+	return common.NewRational(binary.BigEndian.Uint64(w[16:]), binary.BigEndian.Uint64(w[24:]))
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that plans take effect at their height, and that later plans only
+// replace the pending values of the parameters they set.
+func TestPowPlanSchedule(t *testing.T) {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	defaults := params.DefaultPowParams()
+
+	if err := schedulePowPlan(statedb, 5, &Plan{Height: 10, MinPrice: big.NewInt(200), Kp: common.NewRational(0, 1)}); err != nil {
+		t.Fatalf("failed to schedule plan: %v", err)
+	}
+	if pow := PowParamsAt(statedb, 9); pow.MinPrice.Cmp(defaults.MinPrice) != 0 || *pow.Kp != *defaults.Kp {
+		t.Errorf("plan in effect before its height: min price %v, kp %v", pow.MinPrice, pow.Kp)
+	}
+	if pow := PowParamsAt(statedb, 10); pow.MinPrice.Cmp(big.NewInt(200)) != 0 || pow.Kp.Numerator != 0 {
+		t.Errorf("plan not in effect at its height: min price %v, kp %v", pow.MinPrice, pow.Kp)
+	}
+	if err := schedulePowPlan(statedb, 12, &Plan{Height: 15, MinPrice: big.NewInt(300)}); err != nil {
+		t.Fatalf("failed to schedule plan: %v", err)
+	}
+	if pow := PowParamsAt(statedb, 14); pow.MinPrice.Cmp(big.NewInt(200)) != 0 || pow.Kp.Numerator != 0 {
+		t.Errorf("first plan not in effect before the second: min price %v, kp %v", pow.MinPrice, pow.Kp)
+	}
+	if pow := PowParamsAt(statedb, 15); pow.MinPrice.Cmp(big.NewInt(300)) != 0 || pow.Kp.Numerator != 0 {
+		t.Errorf("wrong params after the second plan: min price %v, kp %v", pow.MinPrice, pow.Kp)
+	}
+	// Plans must take effect after their block, and be well formed
+	for i, plan := range []*Plan{
+		{Height: 12, MaxPowGas: 1},
+		{Height: 20, Alpha: &common.Rational{Numerator: 1}},
+		{Height: 20, MaxPrice: new(big.Int).Lsh(common.Big1, 256)},
+	} {
+		if err := schedulePowPlan(statedb, 12, plan); !errors.Is(err, ErrInvalidPowPlan) {
+			t.Errorf("plan %d: wrong error: have %v, want %v", i, err, ErrInvalidPowPlan)
+		}
+	}
+}
+
+// Tests that the PoW economics fields of imported blocks follow the plans the
+// plan transactions of their ancestors recorded.
+func TestPowPlanImport(t *testing.T) {
+	var (
+		key, _      = crypto.GenerateKey()
+		addr        = crypto.PubkeyToAddress(key.PublicKey)
+		stranger, _ = crypto.GenerateKey()
+		config      = *params.TestChainConfig
+		gspec       = &Genesis{
+			Config: &config,
+			Alloc: GenesisAlloc{
+				addr: {Balance: big.NewInt(params.Ether)},
+				crypto.PubkeyToAddress(stranger.PublicKey): {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		signer = types.LatestSigner(gspec.Config)
+		plan   = &Plan{Height: 3, MinPrice: big.NewInt(5000), MaxPrice: big.NewInt(20000)}
+	)
+	config.Security = &params.SecurityConfig{Admins: []common.Address{addr}}
+	config.PowEconomicsBlock = big.NewInt(0)
+
+	data, err := PowPlanData(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas, err := IntrinsicGas(data, nil, false, true, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generate := func(tamper func(i int, b *BlockGen)) ([]*types.Block, []types.Receipts) {
+		_, blocks, receipts := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 5, func(i int, b *BlockGen) {
+			if i == 0 {
+				for _, sender := range []*ecdsa.PrivateKey{key, stranger} {
+					tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(crypto.PubkeyToAddress(sender.PublicKey)), params.PowPlanAddress, new(big.Int), gas+10000, b.BaseFee(), data), signer, sender)
+					b.AddTx(tx)
+				}
+			}
+			setPowEconomics(b)
+			if tamper != nil {
+				tamper(i, b)
+			}
+		})
+		return blocks, receipts
+	}
+	blocks, receipts := generate(nil)
+	if receipts[0][0].Status != types.ReceiptStatusSuccessful || receipts[0][1].Status != types.ReceiptStatusFailed {
+		t.Fatalf("wrong plan transaction statuses: admin %d, stranger %d", receipts[0][0].Status, receipts[0][1].Status)
+	}
+	for i, receipt := range receipts[0] {
+		if receipt.GasUsed != gas {
+			t.Errorf("plan transaction %d gas mismatch: have %d, want %d", i, receipt.GasUsed, gas)
+		}
+	}
+	if price := blocks[1].Header().PowPrice; price.Cmp(plan.MinPrice) >= 0 {
+		t.Errorf("plan in effect before its height: price %v", price)
+	}
+	if price := blocks[2].Header().PowPrice; price.Cmp(plan.MinPrice) < 0 {
+		t.Errorf("plan not in effect at its height: price %v", price)
+	}
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain with plan: %v", err)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sender := range []*ecdsa.PrivateKey{key, stranger} {
+		if nonce := statedb.GetNonce(crypto.PubkeyToAddress(sender.PublicKey)); nonce != 1 {
+			t.Errorf("plan sender nonce mismatch: have %d, want 1", nonce)
+		}
+	}
+	// Blocks ignoring the plan are rejected
+	blocks, _ = generate(func(i int, b *BlockGen) {
+		if i == 2 {
+			b.header.PowDifficulty, b.header.PowPrice, b.header.PowGas = CalcPowParams(b.cm, params.DefaultPowParams(), b.parent.Header())
+		}
+	})
+	chain, err = NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(blocks); err == nil || !strings.Contains(err.Error(), "invalid pow price") || n != 2 {
+		t.Errorf("wrong import of block ignoring the plan: block %d, err %v", n, err)
+	}
+}
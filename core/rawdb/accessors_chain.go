@@ -627,11 +627,14 @@ func ReadReceipts(db ethdb.Reader, hash common.Hash, number uint64, time uint64,
 	}
 	header := ReadHeader(db, hash, number)
 
-	var baseFee *big.Int
+	var (
+		baseFee  *big.Int
+		coinbase common.Address
+	)
 	if header == nil {
 		baseFee = big.NewInt(0)
 	} else {
-		baseFee = header.BaseFee
+		baseFee, coinbase = header.BaseFee, header.Coinbase
 	}
 	// Compute effective blob gas price.
 	var blobGasPrice *big.Int
@@ -642,6 +645,10 @@ func ReadReceipts(db ethdb.Reader, hash common.Hash, number uint64, time uint64,
 		log.Error("Failed to derive block receipts fields", "hash", hash, "number", number, "err", err)
 		return nil
 	}
+	if err := receipts.DeriveFeeSettlements(config, number, time, baseFee, coinbase, body.Transactions, ReadVoucherRates(db, hash, number)); err != nil {
+		log.Error("Failed to derive block fee settlements", "hash", hash, "number", number, "err", err)
+		return nil
+	}
 	return receipts
 }
 
@@ -676,6 +683,8 @@ type storedReceiptRLP struct {
 	PostStateOrStatus []byte
 	CumulativeGasUsed uint64
 	Logs              []*types.Log
+	InterestFee       *big.Int `rlp:"optional"`
+	BalanceFee        *big.Int `rlp:"optional"`
 }
 
 // ReceiptLogs is a barebone version of ReceiptForStorage which only keeps
@@ -810,6 +819,7 @@ func writeAncientBlock(op ethdb.AncientWriteOp, block *types.Block, header *type
 // DeleteBlock removes all block data associated with a hash.
 func DeleteBlock(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	DeleteReceipts(db, hash, number)
+	DeleteVoucherRates(db, hash, number)
 	DeleteHeader(db, hash, number)
 	DeleteBody(db, hash, number)
 	DeleteTd(db, hash, number)
@@ -0,0 +1,99 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// UpgradeAlgorithmEntry is a stored version of an upgraded crypto algorithm.
+type UpgradeAlgorithmEntry struct {
+	NameHash   common.Hash
+	Activation uint64 // First block number the version is active at
+	Record     []byte // Encoded algorithm record, opaque to the database
+}
+
+// ReadUpgradeAlgorithm retrieves the record of the algorithm version that is
+// activated at the given block number.
+func ReadUpgradeAlgorithm(db ethdb.KeyValueReader, name string, number uint64) []byte {
+	data, _ := db.Get(upgradeAlgorithmKey(crypto.Keccak256Hash([]byte(name)), number))
+	return data
+}
+
+// WriteUpgradeAlgorithm stores the record of an algorithm version that is
+// active from the given block number on.
+func WriteUpgradeAlgorithm(db ethdb.KeyValueWriter, name string, number uint64, record []byte) {
+	if err := db.Put(upgradeAlgorithmKey(crypto.Keccak256Hash([]byte(name)), number), record); err != nil {
+		log.Crit("Failed to store upgrade algorithm", "name", name, "err", err)
+	}
+}
+
+// DeleteUpgradeAlgorithm removes the algorithm version activated at the given
+// block number.
+func DeleteUpgradeAlgorithm(db ethdb.KeyValueWriter, name string, number uint64) {
+	if err := db.Delete(upgradeAlgorithmKey(crypto.Keccak256Hash([]byte(name)), number)); err != nil {
+		log.Crit("Failed to delete upgrade algorithm", "name", name, "err", err)
+	}
+}
+
+// ReadAllUpgradeAlgorithms retrieves every stored algorithm version, ordered
+// by name hash and activation block.
+func ReadAllUpgradeAlgorithms(db ethdb.Iteratee) []UpgradeAlgorithmEntry {
+	var (
+		entries []UpgradeAlgorithmEntry
+		it      = db.NewIterator(UpgradeAlgorithmPrefix, nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(UpgradeAlgorithmPrefix)+common.HashLength+8 {
+			continue
+		}
+		key = key[len(UpgradeAlgorithmPrefix):]
+		entries = append(entries, UpgradeAlgorithmEntry{
+			NameHash:   common.BytesToHash(key[:common.HashLength]),
+			Activation: binary.BigEndian.Uint64(key[common.HashLength:]),
+			Record:     common.CopyBytes(it.Value()),
+		})
+	}
+	return entries
+}
+
+// ReadUpgradeAlgorithmSynced retrieves the number of the last block whose
+// codestorage events have been indexed.
+func ReadUpgradeAlgorithmSynced(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(upgradeAlgorithmSyncedKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteUpgradeAlgorithmSynced stores the number of the last block whose
+// codestorage events have been indexed.
+func WriteUpgradeAlgorithmSynced(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(upgradeAlgorithmSyncedKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store upgrade algorithm sync progress", "err", err)
+	}
+}
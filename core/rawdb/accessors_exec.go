@@ -0,0 +1,150 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadExecBatch retrieves the pending execution batch at the given consensus
+// height, nil if there is none.
+func ReadExecBatch(db ethdb.KeyValueReader, height uint64) *types.ExecBatch {
+	data, _ := db.Get(execBatchKey(height))
+	if len(data) == 0 {
+		return nil
+	}
+	batch := new(types.ExecBatch)
+	if err := rlp.DecodeBytes(data, batch); err != nil {
+		log.Error("Invalid execution batch RLP", "height", height, "err", err)
+		return nil
+	}
+	return batch
+}
+
+// HasExecBatch checks if an execution batch is pending at the given consensus
+// height.
+func HasExecBatch(db ethdb.KeyValueReader, height uint64) bool {
+	has, _ := db.Has(execBatchKey(height))
+	return has
+}
+
+// WriteExecBatch stores an execution batch until it's executed.
+func WriteExecBatch(db ethdb.KeyValueWriter, batch *types.ExecBatch) {
+	data, err := rlp.EncodeToBytes(batch)
+	if err != nil {
+		log.Crit("Failed to encode execution batch", "err", err)
+	}
+	if err := db.Put(execBatchKey(batch.Height), data); err != nil {
+		log.Crit("Failed to store execution batch", "err", err)
+	}
+}
+
+// DeleteExecBatch removes an execution batch.
+func DeleteExecBatch(db ethdb.KeyValueWriter, height uint64) {
+	if err := db.Delete(execBatchKey(height)); err != nil {
+		log.Crit("Failed to delete execution batch", "err", err)
+	}
+}
+
+// ReadExecBatchHeights retrieves the consensus heights of all pending execution
+// batches in ascending order.
+func ReadExecBatchHeights(db ethdb.Iteratee) []uint64 {
+	var (
+		heights []uint64
+		it      = db.NewIterator(ExecBatchPrefix, nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(ExecBatchPrefix)+8 {
+			continue
+		}
+		heights = append(heights, binary.BigEndian.Uint64(it.Key()[len(ExecBatchPrefix):]))
+	}
+	return heights
+}
+
+// ReadExecutedBatch retrieves the consensus height of the last executed batch,
+// nil if no batch was executed yet.
+func ReadExecutedBatch(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(execBatchExecutedKey)
+	if len(data) != 8 {
+		return nil
+	}
+	height := binary.BigEndian.Uint64(data)
+	return &height
+}
+
+// WriteExecutedBatch stores the consensus height of the last executed batch.
+func WriteExecutedBatch(db ethdb.KeyValueWriter, height uint64) {
+	if err := db.Put(execBatchExecutedKey, encodeBlockNumber(height)); err != nil {
+		log.Crit("Failed to store last executed batch", "err", err)
+	}
+}
+
+// ReadExecSequenced reports whether the consensus layer sent batch heights.
+func ReadExecSequenced(db ethdb.KeyValueReader) bool {
+	has, _ := db.Has(execBatchSequencedKey)
+	return has
+}
+
+// WriteExecSequenced flags that the consensus layer sent batch heights.
+func WriteExecSequenced(db ethdb.KeyValueWriter) {
+	if err := db.Put(execBatchSequencedKey, []byte{1}); err != nil {
+		log.Crit("Failed to store execution batch sequencing", "err", err)
+	}
+}
+
+// ReadBlockExecBatch retrieves the consensus height of the batch executed by the
+// block with the given hash, nil if the block didn't execute a batch.
+func ReadBlockExecBatch(db ethdb.KeyValueReader, hash common.Hash) *uint64 {
+	data, _ := db.Get(blockExecBatchKey(hash))
+	if len(data) != 8 {
+		return nil
+	}
+	height := binary.BigEndian.Uint64(data)
+	return &height
+}
+
+// WriteBlockExecBatch stores the consensus height of the batch executed by the
+// block with the given hash.
+func WriteBlockExecBatch(db ethdb.KeyValueWriter, hash common.Hash, height uint64) {
+	if err := db.Put(blockExecBatchKey(hash), encodeBlockNumber(height)); err != nil {
+		log.Crit("Failed to store block execution batch", "err", err)
+	}
+}
+
+// ReadCrossShardPayout retrieves the hash of the transaction paying out a
+// cross-shard transaction of the given source shard, zero if it wasn't paid.
+func ReadCrossShardPayout(db ethdb.KeyValueReader, shard uint64, hash common.Hash) common.Hash {
+	data, _ := db.Get(crossShardPayoutKey(shard, hash))
+	return common.BytesToHash(data)
+}
+
+// WriteCrossShardPayout stores the hash of the transaction paying out a
+// cross-shard transaction of the given source shard.
+func WriteCrossShardPayout(db ethdb.KeyValueWriter, shard uint64, hash common.Hash, payout common.Hash) {
+	if err := db.Put(crossShardPayoutKey(shard, hash), payout.Bytes()); err != nil {
+		log.Crit("Failed to store cross-shard payout", "err", err)
+	}
+}
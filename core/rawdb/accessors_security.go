@@ -0,0 +1,74 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadSecurityLevelHistory retrieves the security level changes of an account
+// ordered by block number. Changes are indexed for every block written, so the
+// history includes the changes of blocks that aren't canonical (anymore).
+func ReadSecurityLevelHistory(db ethdb.Iteratee, account common.Address) []types.SecurityLevelChange {
+	var (
+		history []types.SecurityLevelChange
+		prefix  = append(append([]byte{}, SecurityLevelHistoryPrefix...), account.Bytes()...)
+		it      = db.NewIterator(prefix, nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(prefix)+8+common.HashLength {
+			continue
+		}
+		var changes []types.SecurityLevelChange
+		if err := rlp.DecodeBytes(it.Value(), &changes); err != nil {
+			log.Error("Invalid security level changes RLP", "account", account, "err", err)
+			continue
+		}
+		history = append(history, changes...)
+	}
+	return history
+}
+
+// WriteSecurityLevelChanges indexes the security level changes made by a block
+// under the accounts they were made to.
+func WriteSecurityLevelChanges(db ethdb.KeyValueWriter, hash common.Hash, number uint64, changes []types.SecurityLevelChange) {
+	var (
+		accounts []common.Address
+		byAcc    = make(map[common.Address][]types.SecurityLevelChange)
+	)
+	for _, change := range changes {
+		if _, ok := byAcc[change.Account]; !ok {
+			accounts = append(accounts, change.Account)
+		}
+		byAcc[change.Account] = append(byAcc[change.Account], change)
+	}
+	for _, account := range accounts {
+		data, err := rlp.EncodeToBytes(byAcc[account])
+		if err != nil {
+			log.Crit("Failed to encode security level changes", "err", err)
+		}
+		if err := db.Put(securityLevelHistoryKey(account, number, hash), data); err != nil {
+			log.Crit("Failed to store security level changes", "err", err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadVoucherRates retrieves the voucher rate snapshot the transactions of a
+// block converted their fees at. Blocks the snapshot is unknown of, e.g. snap
+// synced ones, return nil.
+func ReadVoucherRates(db ethdb.KeyValueReader, hash common.Hash, number uint64) []types.VoucherRate {
+	data, _ := db.Get(voucherRatesKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var rates []types.VoucherRate
+	if err := rlp.DecodeBytes(data, &rates); err != nil {
+		log.Error("Invalid voucher rates RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return rates
+}
+
+// WriteVoucherRates stores the voucher rate snapshot of a block.
+func WriteVoucherRates(db ethdb.KeyValueWriter, hash common.Hash, number uint64, rates []types.VoucherRate) {
+	data, err := rlp.EncodeToBytes(rates)
+	if err != nil {
+		log.Crit("Failed to encode voucher rates", "err", err)
+	}
+	if err := db.Put(voucherRatesKey(number, hash), data); err != nil {
+		log.Crit("Failed to store voucher rates", "err", err)
+	}
+}
+
+// DeleteVoucherRates removes the voucher rate snapshot of a block.
+func DeleteVoucherRates(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(voucherRatesKey(number, hash)); err != nil {
+		log.Crit("Failed to delete voucher rates", "err", err)
+	}
+}
@@ -479,6 +479,11 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		bloomBits       stat
 		beaconHeaders   stat
 		cliqueSnaps     stat
+		upgradeAlgos    stat
+		voucherRates    stat
+		securityLevels  stat
+		execBatches     stat
+		crossShard      stat
 
 		// Les statistic
 		chtTrieNodes   stat
@@ -541,6 +546,18 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			beaconHeaders.Add(size)
 		case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
 			cliqueSnaps.Add(size)
+		case bytes.HasPrefix(key, UpgradeAlgorithmPrefix) && len(key) == len(UpgradeAlgorithmPrefix)+common.HashLength+8:
+			upgradeAlgos.Add(size)
+		case bytes.HasPrefix(key, VoucherRatesPrefix) && len(key) == len(VoucherRatesPrefix)+8+common.HashLength:
+			voucherRates.Add(size)
+		case bytes.HasPrefix(key, SecurityLevelHistoryPrefix) && len(key) == len(SecurityLevelHistoryPrefix)+common.AddressLength+8+common.HashLength:
+			securityLevels.Add(size)
+		case bytes.HasPrefix(key, ExecBatchPrefix) && len(key) == len(ExecBatchPrefix)+8:
+			execBatches.Add(size)
+		case bytes.HasPrefix(key, BlockExecBatchPrefix) && len(key) == len(BlockExecBatchPrefix)+common.HashLength:
+			execBatches.Add(size)
+		case bytes.HasPrefix(key, CrossShardPayoutPrefix) && len(key) == len(CrossShardPayoutPrefix)+8+common.HashLength:
+			crossShard.Add(size)
 		case bytes.HasPrefix(key, ChtTablePrefix) ||
 			bytes.HasPrefix(key, ChtIndexTablePrefix) ||
 			bytes.HasPrefix(key, ChtPrefix): // Canonical hash trie
@@ -557,6 +574,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 				uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 				persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
+				upgradeAlgorithmSyncedKey, execBatchExecutedKey, execBatchSequencedKey,
 			} {
 				if bytes.Equal(key, meta) {
 					metadata.Add(size)
@@ -594,6 +612,11 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
 		{"Key-Value store", "Beacon sync headers", beaconHeaders.Size(), beaconHeaders.Count()},
 		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
+		{"Key-Value store", "Upgrade algorithms", upgradeAlgos.Size(), upgradeAlgos.Count()},
+		{"Key-Value store", "Voucher rates", voucherRates.Size(), voucherRates.Count()},
+		{"Key-Value store", "Security level history", securityLevels.Size(), securityLevels.Count()},
+		{"Key-Value store", "Execution batches", execBatches.Size(), execBatches.Count()},
+		{"Key-Value store", "Cross-shard payouts", crossShard.Size(), crossShard.Count()},
 		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
 		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
@@ -96,6 +96,15 @@ var (
 	// snapSyncStatusFlagKey flags that status of snap sync.
 	snapSyncStatusFlagKey = []byte("SnapSyncStatus")
 
+	// upgradeAlgorithmSyncedKey tracks the last block whose codestorage events have been indexed.
+	upgradeAlgorithmSyncedKey = []byte("UpgradeAlgorithmSynced")
+
+	// execBatchExecutedKey tracks the consensus height of the last executed batch.
+	execBatchExecutedKey = []byte("LastExecBatch")
+
+	// execBatchSequencedKey flags that the consensus layer sent batch heights.
+	execBatchSequencedKey = []byte("ExecBatchSequenced")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -134,6 +143,18 @@ var (
 
 	CliqueSnapshotPrefix = []byte("clique-")
 
+	UpgradeAlgorithmPrefix = []byte("upgrade-algo-") // UpgradeAlgorithmPrefix + name hash + activation num (uint64 big endian) -> algorithm record
+
+	VoucherRatesPrefix = []byte("voucher-rates-") // VoucherRatesPrefix + num (uint64 big endian) + hash -> voucher rate snapshot
+
+	SecurityLevelHistoryPrefix = []byte("security-level-") // SecurityLevelHistoryPrefix + address + num (uint64 big endian) + hash -> security level changes
+
+	ExecBatchPrefix = []byte("exec-batch-") // ExecBatchPrefix + consensus height (uint64 big endian) -> pending execution batch
+
+	BlockExecBatchPrefix = []byte("block-exec-batch-") // BlockExecBatchPrefix + hash -> consensus height of the batch executed by the block
+
+	CrossShardPayoutPrefix = []byte("cross-shard-") // CrossShardPayoutPrefix + source shard (uint64 big endian) + tx hash -> payout tx hash
+
 	BestUpdateKey         = []byte("update-")    // bigEndian64(syncPeriod) -> RLP(types.LightClientUpdate)  (nextCommittee only referenced by root hash)
 	FixedCommitteeRootKey = []byte("fixedRoot-") // bigEndian64(syncPeriod) -> committee root hash
 	SyncCommitteeKey      = []byte("committee-") // bigEndian64(syncPeriod) -> serialized committee
@@ -157,6 +178,36 @@ func encodeBlockNumber(number uint64) []byte {
 	return enc
 }
 
+// upgradeAlgorithmKey = UpgradeAlgorithmPrefix + name hash + activation num (uint64 big endian)
+func upgradeAlgorithmKey(nameHash common.Hash, number uint64) []byte {
+	return append(append(append([]byte{}, UpgradeAlgorithmPrefix...), nameHash.Bytes()...), encodeBlockNumber(number)...)
+}
+
+// voucherRatesKey = VoucherRatesPrefix + num (uint64 big endian) + hash
+func voucherRatesKey(number uint64, hash common.Hash) []byte {
+	return append(append(append([]byte{}, VoucherRatesPrefix...), encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// securityLevelHistoryKey = SecurityLevelHistoryPrefix + address + num (uint64 big endian) + hash
+func securityLevelHistoryKey(account common.Address, number uint64, hash common.Hash) []byte {
+	return append(append(append(append([]byte{}, SecurityLevelHistoryPrefix...), account.Bytes()...), encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// execBatchKey = ExecBatchPrefix + consensus height (uint64 big endian)
+func execBatchKey(height uint64) []byte {
+	return append(append([]byte{}, ExecBatchPrefix...), encodeBlockNumber(height)...)
+}
+
+// blockExecBatchKey = BlockExecBatchPrefix + hash
+func blockExecBatchKey(hash common.Hash) []byte {
+	return append(append([]byte{}, BlockExecBatchPrefix...), hash.Bytes()...)
+}
+
+// crossShardPayoutKey = CrossShardPayoutPrefix + source shard (uint64 big endian) + tx hash
+func crossShardPayoutKey(shard uint64, hash common.Hash) []byte {
+	return append(append(append([]byte{}, CrossShardPayoutPrefix...), encodeBlockNumber(shard)...), hash.Bytes()...)
+}
+
 // headerKeyPrefix = headerPrefix + num (uint64 big endian)
 func headerKeyPrefix(number uint64) []byte {
 	return append(headerPrefix, encodeBlockNumber(number)...)
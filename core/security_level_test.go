@@ -0,0 +1,131 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/governance"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the security level changes made through the governance module are
+// indexed per account when the blocks making them are imported.
+func TestSecurityLevelHistory(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		admin   = crypto.PubkeyToAddress(key.PublicKey)
+		account = common.Address{0xaa}
+		config  = *params.TestChainConfig
+		genesis = &Genesis{
+			Config:  &config,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Alloc:   GenesisAlloc{admin: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(&config)
+	)
+	config.Security = &params.SecurityConfig{Admins: []common.Address{admin}}
+	config.SecurityLevelBlock = big.NewInt(0)
+	module := config.Security.Governance()
+
+	_, blocks, receipts := GenerateChainWithGenesis(genesis, ethash.NewFaker(), 3, func(i int, b *BlockGen) {
+		method := "lock"
+		if i == 1 {
+			method = "unlock"
+		}
+		input, _ := governance.GovernanceABI.Pack(method, account, [32]byte{byte(i)})
+		tx, _ := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     b.TxNonce(admin),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: b.header.BaseFee,
+			Gas:       100000,
+			To:        &module,
+			Data:      input,
+		})
+		b.AddTx(tx)
+	})
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	history := rawdb.ReadSecurityLevelHistory(db, account)
+	if len(history) != len(blocks) {
+		t.Fatalf("history length mismatch: have %d, want %d", len(history), len(blocks))
+	}
+	for i, change := range history {
+		if receipts[i][0].Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("change %d: transaction failed", i)
+		}
+		want := types.SecurityLevelChange{
+			Account:     account,
+			By:          admin,
+			Previous:    1,
+			Level:       0,
+			Reason:      common.Hash{byte(i)},
+			BlockNumber: blocks[i].NumberU64(),
+			BlockHash:   blocks[i].Hash(),
+			TxHash:      blocks[i].Transactions()[0].Hash(),
+		}
+		if i == 1 {
+			want.Previous, want.Level = 0, 1
+		}
+		if change != want {
+			t.Errorf("change %d mismatch: have %+v, want %+v", i, change, want)
+		}
+	}
+}
+
+// Tests that plain transfers keep costing 21000 gas under the call policy, the
+// top frame of a transaction reading the policy for free.
+func TestSecurityPolicyTransfer(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.TestChainConfig
+		genesis = &Genesis{
+			Config:  &config,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Alloc:   GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(&config)
+	)
+	config.Security = &params.SecurityConfig{Admins: []common.Address{sender}}
+	config.SecurityPolicyGasBlock = big.NewInt(0)
+
+	_, blocks, receipts := GenerateChainWithGenesis(genesis, ethash.NewFaker(), 1, func(i int, b *BlockGen) {
+		tx, _ := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     b.TxNonce(sender),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: b.header.BaseFee,
+			Gas:       params.TxGas,
+			To:        &common.Address{0xaa},
+			Value:     big.NewInt(1),
+		})
+		b.AddTx(tx)
+	})
+	if receipts[0][0].Status != types.ReceiptStatusSuccessful || receipts[0][0].GasUsed != params.TxGas {
+		t.Fatalf("transfer mismatch: status %d, gas used %d", receipts[0][0].Status, receipts[0][0].GasUsed)
+	}
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+}
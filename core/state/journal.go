@@ -17,6 +17,8 @@
 package state
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
 )
@@ -118,6 +120,11 @@ type (
 		account *common.Address
 		prev    uint64
 	}
+	interestChange struct {
+		account   *common.Address
+		prev      *uint256.Int
+		prevBlock *big.Int
+	}
 	storageChange struct {
 		account       *common.Address
 		key, prevalue common.Hash
@@ -232,6 +239,14 @@ func (ch securityLevelChange) dirtied() *common.Address {
 	return ch.account
 }
 
+func (ch interestChange) revert(s *StateDB) {
+	s.getStateObject(*ch.account).setInterest(ch.prev, ch.prevBlock)
+}
+
+func (ch interestChange) dirtied() *common.Address {
+	return ch.account
+}
+
 func (ch codeChange) revert(s *StateDB) {
 	s.getStateObject(*ch.account).setCode(common.BytesToHash(ch.prevhash), ch.prevcode)
 }
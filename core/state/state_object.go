@@ -20,12 +20,14 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie/trienode"
 	"github.com/holiman/uint256"
@@ -92,8 +94,13 @@ type stateObject struct {
 }
 
 // empty returns whether the account is considered empty.
+//
+// Once the state keeps security levels, an account whose security level differs
+// from the default isn't empty, so that locking an account survives the clearing
+// of empty accounts.
 func (s *stateObject) empty() bool {
-	return s.data.Nonce == 0 && s.data.Balance.Sign() == 0 && bytes.Equal(s.data.CodeHash, types.EmptyCodeHash.Bytes())
+	return s.data.Nonce == 0 && s.data.Balance.Sign() == 0 && bytes.Equal(s.data.CodeHash, types.EmptyCodeHash.Bytes()) &&
+		(!s.db.keepLevels || s.data.SecurityLevel == params.DefaultSecurityLevel)
 }
 
 // newObject creates a state object.
@@ -560,3 +567,33 @@ func (s *stateObject) setSecurityLevel(level uint64) {
 func (s *stateObject) SecurityLevel() uint64 {
 	return s.data.SecurityLevel
 }
+
+func (s *stateObject) SetInterest(interest *uint256.Int, block *big.Int) {
+	s.db.journal.append(interestChange{
+		account:   &s.address,
+		prev:      s.data.Interest,
+		prevBlock: s.data.LastBlockNumber,
+	})
+	s.setInterest(interest, block)
+}
+
+func (s *stateObject) setInterest(interest *uint256.Int, block *big.Int) {
+	s.data.Interest = interest
+	s.data.LastBlockNumber = block
+}
+
+// Interest returns the interest accrued by the account, as a fixed point number.
+func (s *stateObject) Interest() *uint256.Int {
+	if s.data.Interest == nil {
+		return new(uint256.Int)
+	}
+	return s.data.Interest
+}
+
+// InterestBlock returns the block the interest of the account was last accrued at.
+func (s *stateObject) InterestBlock() uint64 {
+	if s.data.LastBlockNumber == nil {
+		return 0
+	}
+	return s.data.LastBlockNumber.Uint64()
+}
@@ -48,6 +48,10 @@ type revision struct {
 	journalIndex int
 }
 
+// InterestAccrual credits addr the interest its balance earned up to block
+// @number. It must not change balances.
+type InterestAccrual func(s *StateDB, addr common.Address, number uint64)
+
 // StateDB structs within the ethereum protocol are used to store anything
 // within the merkle trie. StateDBs take care of caching and storing
 // nested states. It's the general query interface to retrieve:
@@ -112,6 +116,14 @@ type StateDB struct {
 	// Transient storage
 	transientStorage transientStorage
 
+	// Interest accrual run before balance changes, and the block accounts are
+	// accrued at. Nil on chains without an interest model.
+	accrual      InterestAccrual
+	accrualBlock uint64
+
+	// Whether accounts with a non default security level aren't empty
+	keepLevels bool
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -139,9 +151,6 @@ type StateDB struct {
 
 	// Testing hooks
 	onCommit func(states *triestate.Set) // Hook invoked when commit is performed
-
-	// Interest rate, 0-100
-	interestRate int64
 }
 
 // New creates a new state from a given trie.
@@ -365,6 +374,12 @@ func (s *StateDB) Database() Database {
 	return s.db
 }
 
+// OriginalRoot returns the root of the state the StateDB was opened at, or last
+// committed.
+func (s *StateDB) OriginalRoot() common.Hash {
+	return s.originalRoot
+}
+
 func (s *StateDB) HasSelfDestructed(addr common.Address) bool {
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
@@ -379,6 +394,9 @@ func (s *StateDB) HasSelfDestructed(addr common.Address) bool {
 
 // AddBalance adds amount to the account associated with addr.
 func (s *StateDB) AddBalance(addr common.Address, amount *uint256.Int) {
+	if !amount.IsZero() {
+		s.AccrueInterest(addr)
+	}
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.AddBalance(amount)
@@ -387,6 +405,9 @@ func (s *StateDB) AddBalance(addr common.Address, amount *uint256.Int) {
 
 // SubBalance subtracts amount from the account associated with addr.
 func (s *StateDB) SubBalance(addr common.Address, amount *uint256.Int) {
+	if !amount.IsZero() {
+		s.AccrueInterest(addr)
+	}
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SubBalance(amount)
@@ -394,6 +415,7 @@ func (s *StateDB) SubBalance(addr common.Address, amount *uint256.Int) {
 }
 
 func (s *StateDB) SetBalance(addr common.Address, amount *uint256.Int) {
+	s.AccrueInterest(addr)
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetBalance(amount)
@@ -636,6 +658,10 @@ func (s *StateDB) getOrNewStateObject(addr common.Address) *stateObject {
 func (s *StateDB) createObject(addr common.Address) (newobj, prev *stateObject) {
 	prev = s.getDeletedStateObject(addr) // Note, prev might have been deleted, we need that!
 	newobj = newObject(s, addr, nil)
+	if s.accrual != nil {
+		// New accounts start accruing interest at the current block
+		newobj.setInterest(new(uint256.Int), new(big.Int).SetUint64(s.accrualBlock))
+	}
 	if prev == nil {
 		s.journal.append(createObjectChange{account: &addr})
 	} else {
@@ -707,6 +733,9 @@ func (s *StateDB) Copy() *StateDB {
 		stateObjectsDirty:    make(map[common.Address]struct{}, len(s.journal.dirties)),
 		stateObjectsDestruct: make(map[common.Address]*types.StateAccount, len(s.stateObjectsDestruct)),
 		refund:               s.refund,
+		accrual:              s.accrual,
+		accrualBlock:         s.accrualBlock,
+		keepLevels:           s.keepLevels,
 		logs:                 make(map[common.Hash][]*types.Log, len(s.logs)),
 		logSize:              s.logSize,
 		preimages:            make(map[common.Hash][]byte, len(s.preimages)),
@@ -716,9 +745,8 @@ func (s *StateDB) Copy() *StateDB {
 		// to the snapshot tree, we need to copy that as well. Otherwise, any
 		// block mined by ourselves will cause gaps in the tree, and force the
 		// miner to operate trie-backed only.
-		snaps:        s.snaps,
-		snap:         s.snap,
-		interestRate: s.interestRate,
+		snaps: s.snaps,
+		snap:  s.snap,
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range s.journal.dirties {
@@ -1418,12 +1446,14 @@ func copy2DSet[k comparable](set map[k]map[common.Hash][]byte) map[k]map[common.
 }
 
 // security level
+// GetSecurityLevel returns the security level of addr, the default level for
+// accounts that don't exist.
 func (s *StateDB) GetSecurityLevel(addr common.Address) uint64 {
-	stateObject := s.getOrNewStateObject(addr)
+	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.SecurityLevel()
 	}
-	return 0
+	return params.DefaultSecurityLevel
 }
 
 func (s *StateDB) SetSecurityLevel(addr common.Address, level uint64) {
@@ -1434,73 +1464,72 @@ func (s *StateDB) SetSecurityLevel(addr common.Address, level uint64) {
 
 }
 
-// * Methods are used to interest compute
-func (s *StateDB) SetInterestRate(rate int64) error {
-	if rate < 0 || rate > 100 {
-		return fmt.Errorf("interest rate: %v is illegal", rate)
-	} else {
-		s.interestRate = rate
-		return nil
+// GetInterest returns the interest accrued by addr, as a fixed point number with
+// the precision of the chain's interest model.
+func (s *StateDB) GetInterest(addr common.Address) *uint256.Int {
+	stateObject := s.getStateObject(addr)
+	if stateObject != nil {
+		return new(uint256.Int).Set(stateObject.Interest())
 	}
+	return new(uint256.Int)
 }
 
-// Return the old block number(interest edit), and record the current number
-func (s *StateDB) ComputeInterest(addr common.Address, currentNumber *big.Int) {
-	obj := s.getStateObject(addr)
-	if obj != nil {
-		// Initial pointer
-		if obj.data.LastBlockNumber == nil {
-			obj.data.LastBlockNumber = big.NewInt(0)
-		}
-	} else {
-		// State not exist, exit
-		log.Error(fmt.Sprintf("obi of %s is not exit", addr))
-		return
+// GetInterestBlock returns the block the interest of addr was last accrued at.
+func (s *StateDB) GetInterestBlock(addr common.Address) uint64 {
+	stateObject := s.getStateObject(addr)
+	if stateObject != nil {
+		return stateObject.InterestBlock()
 	}
+	return 0
+}
 
-	// Compute interest=(currentNumber - lastNumber) * rate * balance
-	interest := big.NewInt(1)
-	interest.Sub(currentNumber, obj.data.LastBlockNumber)
-	interest.Mul(interest, big.NewInt(s.interestRate))
+// SetInterest sets the interest accrued by addr as of the given block.
+func (s *StateDB) SetInterest(addr common.Address, interest *uint256.Int, block uint64) {
+	stateObject := s.getOrNewStateObject(addr)
+	if stateObject != nil {
+		stateObject.SetInterest(new(uint256.Int).Set(interest), new(big.Int).SetUint64(block))
+	}
+}
 
-	interest_u256 := uint256.NewInt(0)
-	interest_u256.SetFromBig(interest)
+// SetInterestAccrual makes the balance changes of the state accrue the interest
+// of the account first, with accrual as of block @number. Accounts created from
+// now on start accruing at that block. A nil accrual disables both.
+func (s *StateDB) SetInterestAccrual(accrual InterestAccrual, number uint64) {
+	s.accrual, s.accrualBlock = accrual, number
+}
 
-	balance_u256 := s.GetBalance(addr)
-	interest_u256.Mul(interest_u256, balance_u256)
-	// Initial interest
-	if obj.data.Interest == nil {
-		obj.data.Interest = uint256.NewInt(0)
+// AccrueInterest credits addr the interest accrued up to the block set with
+// SetInterestAccrual, if any.
+func (s *StateDB) AccrueInterest(addr common.Address) {
+	if s.accrual != nil {
+		s.accrual(s, addr, s.accrualBlock)
 	}
-	// Change account state
-	obj.data.Interest.Add(interest_u256, obj.data.Interest)
-	obj.data.LastBlockNumber.Set(currentNumber)
-	fmt.Printf("Add %v(expand 100 times) interest to address:%s. Last edit interest block number: %v, current Number: %v\n", interest_u256, addr, obj.data.LastBlockNumber, currentNumber)
 }
 
-// Use interest to pay gas, if insufficient return the insufficient amount
-func (s *StateDB) UseInterest(addr common.Address, usedInterest *uint256.Int) *uint256.Int {
+// SetKeepSecurityLevels sets whether accounts with a non default security level
+// count as non empty, keeping them from being cleared as empty accounts.
+func (s *StateDB) SetKeepSecurityLevels(keep bool) {
+	s.keepLevels = keep
+}
+
+// UseInterest spends up to amount of the interest accrued by addr, and returns
+// the part of amount the interest didn't cover. Amounts are in the fixed point
+// unit interest is accrued in.
+func (s *StateDB) UseInterest(addr common.Address, amount *uint256.Int) *uint256.Int {
 	obj := s.getStateObject(addr)
-	// Interest in stateDB is expanded by 100 times to avoid decimals. So there need to mul 100
-	usedInterest.Mul(usedInterest, uint256.NewInt(100))
-
-	interest := obj.data.Interest
-	if interest == nil {
-		log.Error("Interest is not initial")
-		return usedInterest
-	}
-
-	if interest.Cmp(usedInterest) == -1 {
-		// Insufficient to pay gas
-		insuffAmount := uint256.NewInt(0)
-		insuffAmount.Sub(usedInterest, obj.data.Interest)
-		// Div 100
-		insuffAmount_div100 := uint256.NewInt(0)
-		insuffAmount_div100.Div(insuffAmount, uint256.NewInt(100))
-		return insuffAmount
-	} else {
-		// Sufficient to pay gas
-		obj.data.Interest.Sub(obj.data.Interest, usedInterest)
-		return nil
+	if obj == nil {
+		return new(uint256.Int).Set(amount)
+	}
+	var (
+		interest = obj.Interest()
+		used     = amount
+	)
+	if interest.Lt(amount) {
+		used = interest
+	}
+	if used.IsZero() {
+		return new(uint256.Int).Set(amount)
 	}
+	obj.SetInterest(new(uint256.Int).Sub(interest, used), obj.data.LastBlockNumber)
+	return new(uint256.Int).Sub(amount, used)
 }
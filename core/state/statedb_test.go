@@ -546,6 +546,23 @@ func TestTouchDelete(t *testing.T) {
 	}
 }
 
+// Tests that locked accounts only survive the clearing of empty accounts once
+// the state keeps security levels.
+func TestKeepSecurityLevels(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		s := newStateEnv()
+		s.state.SetKeepSecurityLevels(keep)
+
+		addr := common.Address{0xaa}
+		s.state.SetSecurityLevel(addr, 0)
+		s.state.Finalise(true)
+
+		if exist := s.state.Exist(addr); exist != keep {
+			t.Errorf("keep %v: locked empty account exists: %v", keep, exist)
+		}
+	}
+}
+
 // TestCopyOfCopy tests that modified objects are carried over to the copy, and the copy of the copy.
 // See https://github.com/ethereum/go-ethereum/pull/15225#issuecomment-380191512
 func TestCopyOfCopy(t *testing.T) {
@@ -69,6 +69,8 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		allLogs     []*types.Log
 		gp          = new(GasPool).AddGas(block.GasLimit())
 	)
+	PrepareState(p.config, statedb, blockNumber)
+
 	// Mutate the block and state according to any hard-fork specs
 	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
 		misc.ApplyDAOHardFork(statedb)
@@ -106,6 +108,14 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	return receipts, allLogs, *usedGas, nil
 }
 
+// PrepareState sets up statedb for the execution of block @number under the
+// rules of the chain that live in the state rather than in the EVM: interest
+// accrual and the security levels keeping accounts from being empty.
+func PrepareState(config *params.ChainConfig, statedb *state.StateDB, number *big.Int) {
+	SetInterestAccrual(config, statedb, number)
+	statedb.SetKeepSecurityLevels(config.IsSecurityLevel(number))
+}
+
 // Add param incentive to deliver coinbase's incentive
 func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM, incentive *uint256.Int) (*types.Receipt, error) {
 	// Create a new context to be used in the EVM environment.
@@ -147,6 +157,10 @@ func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, sta
 		receipt.BlobGasUsed = uint64(len(tx.BlobHashes()) * params.BlobTxBlobGasPerBlob)
 		receipt.BlobGasPrice = evm.Context.BlobBaseFee
 	}
+	receipt.Settlement = result.Settlement
+	receipt.InterestFee = result.InterestFee
+	receipt.BalanceFee = result.BalanceFee
+	receipt.Tainted = result.Tainted
 
 	// If the transaction created a contract, store the creation address in the receipt.
 	if msg.To == nil {
@@ -176,9 +190,6 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	txContext := NewEVMTxContext(msg)
 	vmenv := vm.NewEVM(blockContext, txContext, statedb, config, cfg)
 
-	// Compute interest: (current number - last number)* Interest rate
-	statedb.ComputeInterest(msg.From, header.Number)
-
 	// check header is None
 	if header.Incentive == nil {
 		header.Incentive = new(uint256.Int)
@@ -25,9 +25,11 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	cmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/governance"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/voucher"
@@ -37,11 +39,15 @@ import (
 // ExecutionResult includes all output after executing given evm
 // message no matter the execution itself is successful or not.
 type ExecutionResult struct {
-	UsedGas     uint64       // Total used gas, not including the refunded gas
-	RefundedGas uint64       // Total gas refunded after execution
-	Incentive   *uint256.Int //Total incentive of miner
-	Err         error        // Any error encountered during the execution(listed in core/vm/errors.go)
-	ReturnData  []byte       // Returned data from evm(function result or data supplied with revert opcode)
+	UsedGas     uint64               // Total used gas, not including the refunded gas
+	RefundedGas uint64               // Total gas refunded after execution
+	Incentive   *uint256.Int         //Total incentive of miner
+	Settlement  *types.FeeSettlement // Fee settlement of a voucher transaction
+	InterestFee *big.Int             // Fee paid from the sender's accrued interest, for native fee transactions
+	BalanceFee  *big.Int             // Fee paid from the sender's balance, for native fee transactions
+	Tainted     []common.Address     // Addresses a PUNK lock transaction tainted
+	Err         error                // Any error encountered during the execution(listed in core/vm/errors.go)
+	ReturnData  []byte               // Returned data from evm(function result or data supplied with revert opcode)
 }
 
 // Unwrap returns the internal evm error which allows us for further
@@ -146,9 +152,9 @@ type Message struct {
 	BlobGasFeeCap *big.Int
 	BlobHashes    []common.Hash
 
-	// Attribute for MultiVoucher
-	tokenName   string
+	// Attribute for MultiVoucher, a nil FeeCurrency means gas is paid natively
 	FeeCurrency *common.Address
+	Voucher     string
 
 	// When SkipAccountChecks is true, the message nonce is not checked against the
 	// account nonce in state. It also disables checking that the sender is an EOA.
@@ -166,33 +172,6 @@ type Message struct {
 	IsPow bool
 }
 
-// Parse voucher info from Tx.Data, delete flag header when finished.
-// Extra information of Tx.Data contains: name([10]byte)
-func (m *Message) ParseVoucher() {
-	// log.Info("Begin to parse voucher")
-	// fmt.Printf("len(m.Data): %v. m.Data %v\n", len(m.Data), m.Data)
-	// Data's legitimacy check
-	if len(m.Data) >= 23 && m.Data[0] == 0x0A && m.Data[1] == 0x0D && m.Data[2] == 0x03 {
-		// Initial and assign pointer
-		m.FeeCurrency = new(common.Address)
-		*m.FeeCurrency = voucher.VoucherAddress
-
-		// Parse token name
-		i := 3
-		for ; i < 23; i++ {
-			if m.Data[i] == 0 {
-				break
-			}
-		}
-		m.tokenName = string(m.Data[3:i])
-		// Delete prefix
-		m.Data = m.Data[23:]
-		log.Info("Tx use %s to pay Gas,contract address is %s!\n", m.tokenName, m.FeeCurrency)
-	} else {
-		log.Info("Tx is not use voucher to pay gas")
-	}
-}
-
 // TransactionToMessage converts a transaction into a Message.
 func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.Int) (*Message, error) {
 	msg := &Message{
@@ -208,10 +187,10 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 		SkipAccountChecks: false,
 		BlobHashes:        tx.BlobHashes(),
 		BlobGasFeeCap:     tx.BlobGasFeeCap(),
+		FeeCurrency:       tx.FeeCurrency(),
+		Voucher:           tx.Voucher(),
 	}
 
-	msg.ParseVoucher()
-
 	// If baseFee provided, set gasPrice to effectiveGasPrice.
 	if baseFee != nil {
 		msg.GasPrice = cmath.BigMin(msg.GasPrice.Add(msg.GasTipCap, baseFee), msg.GasFeeCap)
@@ -232,6 +211,13 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 // indicates a core error meaning that the message would always fail for that particular
 // state and would never be accepted within a block.
 func ApplyMessage(evm *vm.EVM, msg *Message, gp *GasPool) (*ExecutionResult, error) {
+	if statedb, ok := evm.StateDB.(*state.StateDB); ok {
+		PrepareState(evm.ChainConfig(), statedb, evm.Context.BlockNumber)
+
+		// Accrue the interest of the sender, so that it's spendable on fees. The
+		// other accounts accrue as their balance changes.
+		statedb.AccrueInterest(msg.From)
+	}
 	return NewStateTransition(evm, msg, gp).TransitionDb()
 }
 
@@ -264,6 +250,9 @@ type StateTransition struct {
 	initialGas   uint64
 	state        vm.StateDB
 	evm          *vm.EVM
+	voucherRate  *big.Int     // Rate the fees of a voucher transaction are converted at
+	interestFee  *uint256.Int // Part of a native fee paid from the sender's accrued interest
+	balanceFee   *uint256.Int // Part of a native fee paid from the sender's balance
 }
 
 // NewStateTransition initialises and returns a new state transition object.
@@ -312,71 +301,105 @@ func (st *StateTransition) buyGas() error {
 			mgval.Add(mgval, blobFee)
 		}
 	}
-	balanceCheckU256, overflow := uint256.FromBig(balanceCheck)
-	if overflow {
+	if _, overflow := uint256.FromBig(balanceCheck); overflow {
 		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, st.msg.From.Hex())
 	}
 
-	BalanceOfGas := uint64(0)
 	// Check account balance enough to pay gas
 	if st.msg.FeeCurrency != nil {
-		// Using voucher to buy gas
-		balance := big.NewInt(0)
-		BalanceOfGas, _ = voucher.BalanceOf.Execute(st.evm, &balance, &st.msg.From, uint256.NewInt(0), st.msg.tokenName, st.msg.From)
-		// Legitimacy check
-		if balance.Cmp(balanceCheck) < 0 {
-			fmt.Println("Balance of account is insufficient!")
-			return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg, balance, balanceCheck)
-		} else {
-			fmt.Printf("Call BalanceOf use %v unit of gas\n", BalanceOfGas)
+		// Using voucher to buy gas, the value is still transferred natively
+		// and checked before the call. Like the native balance, the voucher
+		// balance is looked up free of charge.
+		feeCheck := new(big.Int).Set(mgval)
+		if st.msg.GasFeeCap != nil {
+			feeCheck.Mul(new(big.Int).SetUint64(st.msg.GasLimit), st.msg.GasFeeCap)
+		}
+		balance, _, err := VoucherBalance(st.evm, *st.msg.FeeCurrency, st.msg.Voucher, st.msg.From)
+		if err != nil {
+			return fmt.Errorf("%w: address %v voucher %q: %v", ErrInsufficientFunds, st.msg.From.Hex(), st.msg.Voucher, err)
+		}
+		if balance.Cmp(feeCheck) < 0 {
+			return fmt.Errorf("%w: address %v voucher %q have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), st.msg.Voucher, balance, feeCheck)
 		}
 	} else {
-		// At first, use interest to pay gas.
-		insufficient := st.state.UseInterest(st.msg.From, balanceCheckU256)
-		if insufficient != nil {
-			// Only using native token to pay insufficient part
-			fmt.Println("Using interest to pay gas, insufficient amount is:", insufficient)
-			if have, want := st.state.GetBalance(st.msg.From), insufficient; have.Cmp(want) < 0 {
-				return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, want)
-			}
-		} else {
-			fmt.Printf("Using interest to pay all %v units of gas\n", balanceCheckU256)
+		// Fees are paid from the accrued interest first and from the balance
+		// for the rest, the value always comes from the balance.
+		feeCheck := new(big.Int).Set(balanceCheck)
+		if st.msg.GasFeeCap != nil {
+			feeCheck.Sub(feeCheck, st.msg.Value)
+		}
+		interest := NativeInterest(st.evm.ChainConfig(), st.state.GetInterest(st.msg.From)).ToBig()
+		want := new(big.Int).Sub(balanceCheck, cmath.BigMin(interest, feeCheck))
+		if have := st.state.GetBalance(st.msg.From); have.ToBig().Cmp(want) < 0 {
+			return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, want)
 		}
 	}
 
 	if err := st.gp.SubGas(st.msg.GasLimit); err != nil {
 		return err
 	}
-	// If use voucher to pay,need pay the gas of call BalanceOf
-	st.gasRemaining += (st.msg.GasLimit - BalanceOfGas)
+	st.gasRemaining += st.msg.GasLimit
 
 	st.initialGas = st.msg.GasLimit
 	mgvalU256, _ := uint256.FromBig(mgval)
-	// If ETH is used to pay for Gas fees, the from account needs to be deducted here.
-	// If the payment is made with a non-original token, no payment is made here
-	// But rather, after tx is executed, the remain is calculated for offset and payment is made.
+	// Voucher fees are settled once the transaction executed, the native fee
+	// of the whole gas limit is debited before execution and the unused part is
+	// refunded afterwards.
 	if st.msg.FeeCurrency == nil {
-		st.state.SubBalance(st.msg.From, mgvalU256)
+		st.payFee(mgvalU256)
 	}
 	return nil
 }
 
+// payFee debits the upfront fee of a native fee transaction, from the accrued
+// interest of the sender first and from its balance for the rest.
+func (st *StateTransition) payFee(fee *uint256.Int) {
+	config := st.evm.ChainConfig()
+
+	st.interestFee = NativeInterest(config, st.state.GetInterest(st.msg.From))
+	if st.interestFee.Gt(fee) {
+		st.interestFee.Set(fee)
+	}
+	st.state.UseInterest(st.msg.From, interestUnits(config, st.interestFee))
+
+	st.balanceFee = new(uint256.Int).Sub(fee, st.interestFee)
+	st.state.SubBalance(st.msg.From, st.balanceFee)
+}
+
+// refundFee returns the fee of the unused gas of a native fee transaction. As
+// the interest was spent first, the refund goes to the balance first.
+func (st *StateTransition) refundFee(refund *uint256.Int) {
+	toBalance := new(uint256.Int).Set(refund)
+	if toBalance.Gt(st.balanceFee) {
+		toBalance.Set(st.balanceFee)
+	}
+	st.balanceFee.Sub(st.balanceFee, toBalance)
+	st.state.AddBalance(st.msg.From, toBalance)
+
+	if toInterest := new(uint256.Int).Sub(refund, toBalance); !toInterest.IsZero() {
+		st.interestFee.Sub(st.interestFee, toInterest)
+
+		interest := st.state.GetInterest(st.msg.From)
+		interest.Add(interest, interestUnits(st.evm.ChainConfig(), toInterest))
+		st.state.SetInterest(st.msg.From, interest, st.state.GetInterestBlock(st.msg.From))
+	}
+}
+
 func (st *StateTransition) preCheck() error {
 	// Only check transactions that are not fake
 	msg := st.msg
 	// check address is locked?(it means the account sercurity level is 0)
 	// ! Account lock
-	fromSL := st.state.GetSecurityLevel(msg.From)
-	fmt.Println("fromSL=", fromSL)
-	if fromSL == 0 {
-		return fmt.Errorf("%w: address %v, account is locked", ErrAccountLocked, msg.From.Hex())
-	}
+	var (
+		fromSL = st.state.GetSecurityLevel(msg.From)
+		to     common.Address
+		toSL   uint64
+	)
 	if msg.To != nil {
-		toSL := st.state.GetSecurityLevel(*msg.To)
-		fmt.Println("toSL=", toSL)
-		if toSL == 0 {
-			return fmt.Errorf("%w: address %v, account is locked", ErrAccountLocked, msg.To.Hex())
-		}
+		to, toSL = *msg.To, st.state.GetSecurityLevel(*msg.To)
+	}
+	if fromSL == 0 || (msg.To != nil && toSL == 0) {
+		return &vm.SecurityLevelError{Err: ErrAccountLocked, Caller: msg.From, CallerLevel: fromSL, Callee: to, CalleeLevel: toSL}
 	}
 
 	if !msg.SkipAccountChecks {
@@ -399,6 +422,17 @@ func (st *StateTransition) preCheck() error {
 				msg.From.Hex(), codeHash)
 		}
 	}
+	// Voucher fees are converted at the rate of the block's snapshot
+	if msg.FeeCurrency != nil {
+		if !st.evm.ChainConfig().Voucher.IsFeeCurrency(*msg.FeeCurrency) {
+			return fmt.Errorf("%w: address %v fee currency %v", ErrFeeCurrency, msg.From.Hex(), msg.FeeCurrency)
+		}
+		rate, err := blockVoucherRate(st.evm, *msg.FeeCurrency, msg.Voucher)
+		if err != nil {
+			return fmt.Errorf("%w: address %v voucher %q of %v: %v", ErrVoucherRate, msg.From.Hex(), msg.Voucher, msg.FeeCurrency, err)
+		}
+		st.voucherRate = rate
+	}
 	// Make sure that transaction 	 is greater than the baseFee (post london)
 	if st.evm.ChainConfig().IsLondon(st.evm.Context.BlockNumber) {
 		// Skip the checks if gas fields are zero and baseFee was explicitly disabled (eth_call)
@@ -418,9 +452,9 @@ func (st *StateTransition) preCheck() error {
 			}
 			// This will panic if baseFee is nil, but basefee presence is verified
 			// as part of header validation.
-			if msg.GasFeeCap.Cmp(st.evm.Context.BaseFee) < 0 {
+			if baseFee := st.baseFee(); msg.GasFeeCap.Cmp(baseFee) < 0 {
 				return fmt.Errorf("%w: address %v, maxFeePerGas: %s, baseFee: %s", ErrFeeCapTooLow,
-					msg.From.Hex(), msg.GasFeeCap, st.evm.Context.BaseFee)
+					msg.From.Hex(), msg.GasFeeCap, baseFee)
 			}
 		}
 	}
@@ -519,52 +553,6 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 		}, nil
 	}
 
-	if isPUNKTaintedLockTx(st.msg) {
-		log.Info("PUNKTaintedLock transaction", "from", st.msg.From.Hex())
-
-		// Parse Data: After the first two bytes 0x0D03, the third byte indicates the number of addresses,
-		// followed by 20 bytes for each address.
-		// Set the security level of these addresses to 0. It means the account is locked.
-		addressCount := st.msg.Data[2]
-		addresses := make([]common.Address, addressCount)
-		for i := 0; i < int(addressCount); i++ {
-			copy(addresses[i][:], st.msg.Data[3+i*20:])
-		}
-		for _, address := range addresses {
-			log.Info("PUNKTaintedLock", "address", address.Hex())
-			st.state.SetSecurityLevel(address, 0)
-		}
-
-		return &ExecutionResult{
-			UsedGas:     st.gasUsed(),
-			RefundedGas: 0,
-			Err:         nil,
-			ReturnData:  nil,
-		}, nil
-	}
-
-	if isPUNKTaintedUnlockTx(st.msg) {
-		log.Info("PUNKTaintedUnlock transaction", "from", st.msg.From.Hex())
-		// Parse Data: After the first two bytes 0x0D04, the third byte indicates the number of addresses,
-		// followed by 20 bytes for each address.
-		// Set the security level of these addresses to 1. It means the account is unlocked.
-		addressCount := st.msg.Data[2]
-		addresses := make([]common.Address, addressCount)
-		for i := 0; i < int(addressCount); i++ {
-			copy(addresses[i][:], st.msg.Data[3+i*20:])
-		}
-		for _, address := range addresses {
-			st.state.SetSecurityLevel(address, 1)
-		}
-
-		return &ExecutionResult{
-			UsedGas:     st.gasUsed(),
-			RefundedGas: 0,
-			Err:         nil,
-			ReturnData:  nil,
-		}, nil
-	}
-
 	if tracer := st.evm.Config.Tracer; tracer != nil {
 		tracer.CaptureTxStart(st.initialGas)
 		defer func() {
@@ -585,6 +573,10 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Voucher transactions reserve the gas of settling their fee
+	if msg.FeeCurrency != nil {
+		gas += params.TxVoucherSettlementGas
+	}
 	if st.gasRemaining < gas {
 		return nil, fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, st.gasRemaining, gas)
 	}
@@ -609,10 +601,23 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	st.state.Prepare(rules, msg.From, st.evm.Context.Coinbase, msg.To, vm.ActivePrecompiles(rules), msg.AccessList)
 
 	var (
-		ret   []byte
-		vmerr error // vm errors do not effect consensus and are therefore not assigned to err
+		ret      []byte
+		vmerr    error // vm errors do not effect consensus and are therefore not assigned to err
+		tainted  []common.Address
+		snapshot = st.state.Snapshot()
 	)
-	if contractCreation {
+	if isPUNKTaintedLockTx(msg) || isPUNKTaintedUnlockTx(msg) {
+		// Increment the nonce for the next transaction
+		st.state.SetNonce(msg.From, st.state.GetNonce(sender.Address())+1)
+		tainted, vmerr = st.applyPUNKTainted()
+	} else if isPowPlanTx(msg) {
+		log.Info("PoW plan transaction", "from", msg.From.Hex())
+		// Increment the nonce for the next transaction
+		st.state.SetNonce(msg.From, st.state.GetNonce(sender.Address())+1)
+		// Schedule the plan of the PoW economics parameters, the parameters of
+		// the blocks derive from the plans recorded in the state of their parent
+		vmerr = st.schedulePowPlan()
+	} else if contractCreation {
 		ret, _, st.gasRemaining, vmerr = st.evm.Create(sender, msg.Data, st.gasRemaining, value)
 		if vmerr != nil {
 			log.Error("Create vmerr", "err", vmerr)
@@ -620,71 +625,70 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	} else {
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From, st.state.GetNonce(sender.Address())+1)
-		ret, st.gasRemaining, vmerr = st.evm.Call(sender, st.to(), msg.Data, st.gasRemaining, value)
+		ret, st.gasRemaining, vmerr = st.call(sender, value)
 		if vmerr != nil {
 			log.Error("Call vmerr", "err", vmerr)
 		}
 	}
 	var gasRefund uint64
-
-	// Voucher pay gas
-	if st.msg.FeeCurrency != nil {
-		// When use no-native tokens, refund = 0
-		gasUsedFee := new(big.Int)
-		gasUsedFee.SetUint64(st.gasUsed())
-		gasUsedFee.Mul(gasUsedFee, st.msg.GasPrice)
-
-		var flag bool
-		// Simulation of execution to calculate the cost of Gas consumed
-		snapShot := st.state.Snapshot()
-		useMethodGas, _ := voucher.Use.Execute(st.evm, &flag, &st.msg.From, uint256.NewInt(0), st.msg.tokenName, gasUsedFee)
-		st.state.RevertToSnapshot(snapShot)
-
-		// Dedections from non-native token account, including the gas of call method use
-		gasUsedFee.Add(gasUsedFee, new(big.Int).SetUint64(useMethodGas))
-		if _, err := voucher.Use.Execute(st.evm, &flag, &st.msg.From, uint256.NewInt(0), st.msg.tokenName, gasUsedFee); err != nil {
-			return nil, err
-		}
+	if !rules.IsLondon {
+		// Before EIP-3529: refunds were capped to gasUsed / 2
+		gasRefund = st.refundGas(params.RefundQuotient)
 	} else {
-		// Using Native to pay gas, need to refund gas fee.
-		if !rules.IsLondon {
-			// Before EIP-3529: refunds were capped to gasUsed / 2
-			gasRefund = st.refundGas(params.RefundQuotient)
-		} else {
-			// After EIP-3529: refunds are capped to gasUsed / 5
-			gasRefund = st.refundGas(params.RefundQuotientEIP3529)
-		}
+		// After EIP-3529: refunds are capped to gasUsed / 5
+		gasRefund = st.refundGas(params.RefundQuotientEIP3529)
 	}
-
 	effectiveTip := msg.GasPrice
 	if st.msg.IsPow {
 		effectiveTip = st.evm.Context.PowPrice
 	}
 	if rules.IsLondon {
-		effectiveTip = cmath.BigMin(msg.GasTipCap, new(big.Int).Sub(msg.GasFeeCap, st.evm.Context.BaseFee))
+		effectiveTip = cmath.BigMin(msg.GasTipCap, new(big.Int).Sub(msg.GasFeeCap, st.baseFee()))
 	}
-	effectiveTipU256, _ := uint256.FromBig(effectiveTip)
-
-	incentive := new(uint256.Int)
+	var (
+		incentive  = new(uint256.Int)
+		settlement *types.FeeSettlement
+	)
 	if st.evm.Config.NoBaseFee && msg.GasFeeCap.Sign() == 0 && msg.GasTipCap.Sign() == 0 {
 		// Skip fee payment when NoBaseFee is set and the fee fields
 		// are 0. This avoids a negative effectiveTip being applied to
 		// the coinbase when simulating calls.
+	} else if msg.FeeCurrency != nil {
+		// Voucher transactions pay the tip to the coinbase in their voucher
+		settlement = types.NewFeeSettlement(*msg.FeeCurrency, msg.Voucher, msg.From, st.evm.Context.Coinbase, st.initialGas, st.gasUsed(), st.voucherGasPrice(), effectiveTip, st.voucherRate)
+		if err := st.settleVoucherFee(settlement); err != nil {
+			// The execution spent the voucher paying for it: undo it and charge
+			// the fee on the state the transaction started from
+			st.state.RevertToSnapshot(snapshot)
+			st.state.SetNonce(msg.From, st.state.GetNonce(msg.From)+1)
+			ret, vmerr = nil, fmt.Errorf("%w: %v", ErrVoucherSettlement, err)
+			if err := st.settleVoucherFee(settlement); err != nil {
+				return nil, fmt.Errorf("%w: address %v voucher %q: %v", ErrVoucherSettlement, msg.From.Hex(), msg.Voucher, err)
+			}
+		}
 	} else {
 		fee := new(uint256.Int).SetUint64(st.gasUsed())
+		effectiveTipU256, _ := uint256.FromBig(effectiveTip)
 		fee.Mul(fee, effectiveTipU256)
 		// Incentive
 		incentive = fee
 		// old code: when a tx has executed  ,add balance to the coinbase.
 		// st.state.AddBalance(st.evm.Context.Coinbase, fee)
 	}
-	return &ExecutionResult{
+	result := &ExecutionResult{
 		UsedGas:     st.gasUsed(),
 		RefundedGas: gasRefund,
 		Incentive:   incentive,
+		Settlement:  settlement,
+		Tainted:     tainted,
 		Err:         vmerr,
 		ReturnData:  ret,
-	}, nil
+	}
+	if st.interestFee != nil {
+		result.InterestFee = st.interestFee.ToBig()
+		result.BalanceFee = st.balanceFee.ToBig()
+	}
+	return result, nil
 }
 
 func (st *StateTransition) refundGas(refundQuotient uint64) uint64 {
@@ -695,17 +699,18 @@ func (st *StateTransition) refundGas(refundQuotient uint64) uint64 {
 	}
 	st.gasRemaining += refund
 
-	// Return ETH for remaining gas, exchanged at the original rate.
+	// Return ETH for remaining gas, exchanged at the original rate. Voucher
+	// transactions are never charged for it, see settleVoucherFee.
 	remaining := uint256.NewInt(st.gasRemaining)
 	if st.msg.IsPow {
 		// For PoW tx, use PowPrice instead of GasPrice
 		st.gp.AddGas(st.gasRemaining)
 		return 0
 
-	} else {
+	} else if st.msg.FeeCurrency == nil {
 		remaining = remaining.Mul(remaining, uint256.MustFromBig(st.msg.GasPrice))
+		st.refundFee(remaining)
 	}
-	st.state.AddBalance(st.msg.From, remaining)
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
@@ -714,6 +719,33 @@ func (st *StateTransition) refundGas(refundQuotient uint64) uint64 {
 	return refund
 }
 
+// settleVoucherFee settles the fee of a voucher transaction with a single call
+// of its fee currency, metered against the settlement gas reserved upfront, so
+// the fee never depends on the cost of the settlement.
+func (st *StateTransition) settleVoucherFee(s *types.FeeSettlement) error {
+	_, err := voucher.Settle(st.evm, s.Currency, s.Voucher, s.Payer, s.Coinbase, s.GrossFee, s.Refund, s.Tip, params.TxVoucherSettlementGas)
+	return err
+}
+
+// baseFee returns the base fee in the unit the transaction pays its fees in:
+// voucher transactions convert it at the rate of their voucher.
+func (st *StateTransition) baseFee() *big.Int {
+	if st.voucherRate == nil {
+		return st.evm.Context.BaseFee
+	}
+	return types.VoucherBaseFee(st.evm.Context.BaseFee, st.voucherRate)
+}
+
+// voucherGasPrice returns the effective gas price of a voucher transaction in
+// units of its voucher, i.e. the converted base fee plus the tip, capped by the
+// fee cap.
+func (st *StateTransition) voucherGasPrice() *big.Int {
+	if st.msg.GasFeeCap == nil || st.evm.Context.BaseFee == nil {
+		return st.msg.GasPrice
+	}
+	return cmath.BigMin(new(big.Int).Add(st.msg.GasTipCap, st.baseFee()), st.msg.GasFeeCap)
+}
+
 // gasUsed returns the amount of gas used up by the state transition.
 func (st *StateTransition) gasUsed() uint64 {
 	return st.initialGas - st.gasRemaining
@@ -724,6 +756,41 @@ func (st *StateTransition) blobGasUsed() uint64 {
 	return uint64(len(st.msg.BlobHashes) * params.BlobTxBlobGasPerBlob)
 }
 
+// applyPUNKTainted runs a PUNK lock or unlock transaction in place of a call,
+// returning the addresses it tainted. Senders lacking the role fail like any
+// other reverted execution.
+func (st *StateTransition) applyPUNKTainted() ([]common.Address, error) {
+	addresses := parseTaintedAddresses(st.msg.Data)
+	if isPUNKTaintedLockTx(st.msg) {
+		log.Info("PUNKTaintedLock transaction", "from", st.msg.From.Hex())
+
+		// Set the security level of the listed addresses to 0. It means the account is locked,
+		// and the address is recorded in the tainted-address DAG of the block.
+		if err := st.setTaintedLevel(governance.RoleLock, addresses, 0); err != nil {
+			return nil, err
+		}
+		return addresses, nil
+	}
+	log.Info("PUNKTaintedUnlock transaction", "from", st.msg.From.Hex())
+	// Set the security level of the listed addresses to 1. It means the account is unlocked.
+	return nil, st.setTaintedLevel(governance.RoleUnlock, addresses, 1)
+}
+
+// setTaintedLevel sets the security level of the addresses of a PUNK lock or
+// unlock transaction, like the governance module does, if the sender holds the
+// role to do so.
+func (st *StateTransition) setTaintedLevel(role governance.Role, addresses []common.Address, level uint64) error {
+	config := st.evm.ChainConfig().Security
+	if !governance.HasRole(config, st.state, role, st.msg.From) {
+		return fmt.Errorf("%w: %v lacks role %d", governance.ErrUnauthorized, st.msg.From, role)
+	}
+	for _, address := range addresses {
+		log.Info("PUNKTainted", "address", address.Hex(), "level", level)
+		governance.SetLevel(config, st.state, st.msg.From, address, level, common.Hash{})
+	}
+	return nil
+}
+
 func isTokenTransition(msg *Message) bool {
 	if msg.Data == nil || len(msg.Data) < 3 {
 		return false
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/merkeldag"
+	"github.com/ethereum/go-ethereum/merkeldag/mdagdb"
+)
+
+// ErrTaintDAGFull is returned when the tainted-address DAG can't grow to fit the
+// addresses locked by a block anymore, past merkeldag.MaxLeafNodes addresses.
+// Locks that can't be recorded make the block invalid, so that every locked
+// address can be proven against the tainted root.
+var ErrTaintDAGFull = errors.New("tainted-address DAG full")
+
+// parseTaintedAddresses returns the addresses listed by the payload of a PUNK
+// lock or unlock transaction: after the two prefix bytes, the third byte is the
+// number of addresses, followed by 20 bytes for each address. Addresses cut
+// short by the end of the payload are ignored.
+func parseTaintedAddresses(data []byte) []common.Address {
+	if len(data) < 3 {
+		return nil
+	}
+	count := int(data[2])
+	if available := (len(data) - 3) / common.AddressLength; count > available {
+		count = available
+	}
+	addresses := make([]common.Address, count)
+	for i := range addresses {
+		addresses[i] = common.BytesToAddress(data[3+i*common.AddressLength : 3+(i+1)*common.AddressLength])
+	}
+	return addresses
+}
+
+// TaintedAddresses returns the addresses the transactions of a block tainted,
+// in transaction order, as recorded in their receipts.
+func TaintedAddresses(receipts types.Receipts) []common.Address {
+	var tainted []common.Address
+	for _, receipt := range receipts {
+		tainted = append(tainted, receipt.Tainted...)
+	}
+	return tainted
+}
+
+// UpdateTaintDAG adds the tainted addresses to the tainted-address DAG of the
+// parent block, identified by its root, and returns the DAG of the block, see
+// AddTainted. Without tainted addresses, the block keeps the DAG of its parent
+// and no DAG is returned.
+//
+// The root of the DAG is committed to by the Tainted field of the headers. The
+// empty root is the empty DAG, which chains start with.
+func UpdateTaintDAG(db *mdagdb.MerkleDAGDB, parent []byte, tainted []common.Address) (root []byte, dag *merkeldag.MerkelDAG, err error) {
+	if len(tainted) == 0 {
+		return parent, nil, nil
+	}
+	if dag, err = db.LoadVersion(parent); err != nil {
+		return nil, nil, err
+	}
+	if dag, err = AddTainted(dag, tainted); err != nil {
+		return nil, nil, err
+	}
+	return dag.GetRoot().GetHash(), dag, nil
+}
+
+// AddTainted returns the tainted-address DAG with the tainted addresses added,
+// leaving the given DAG unchanged. The DAG keeps its addresses sorted, so that
+// both membership and non-membership can be proven against its root. Addresses
+// already in the DAG aren't added again. The DAG grows a level whenever its
+// leaves run out, new addresses that don't fit at its maximum depth fail with
+// ErrTaintDAGFull.
+func AddTainted(dag *merkeldag.MerkelDAG, tainted []common.Address) (*merkeldag.MerkelDAG, error) {
+	var (
+		added = make([][]byte, 0, len(tainted))
+		seen  = make(map[common.Address]bool)
+	)
+	for _, addr := range tainted {
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		if _, ok, err := dag.Search(addr.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to look up tainted address %v: %w", addr, err)
+		} else if ok {
+			continue
+		}
+		if dag.GetCurrentIndex()+uint64(len(added)) >= merkeldag.MaxLeafNodes {
+			return nil, fmt.Errorf("%w: can't record %v", ErrTaintDAGFull, addr)
+		}
+		added = append(added, addr.Bytes())
+	}
+	dag = dag.Copy()
+	if err := dag.InsertSorted(added...); err != nil {
+		return nil, fmt.Errorf("failed to record tainted addresses: %w", err)
+	}
+	return dag, nil
+}
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/merkeldag"
+	"github.com/ethereum/go-ethereum/merkeldag/mdagdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that lock and unlock payloads only yield the addresses they carry whole.
+func TestParseTaintedAddresses(t *testing.T) {
+	for i, tt := range []struct {
+		data []byte
+		want []common.Address
+	}{
+		{data: []byte{0x0d, 0x03}},
+		{data: []byte{0x0d, 0x03, 0x00}, want: []common.Address{}},
+		{data: append([]byte{0x0d, 0x03, 0x02}, common.Address{0xaa}.Bytes()...), want: []common.Address{{0xaa}}},
+		{data: append(append([]byte{0x0d, 0x03, 0x01}, common.Address{0xaa}.Bytes()...), 0xbb), want: []common.Address{{0xaa}}},
+	} {
+		have := parseTaintedAddresses(tt.data)
+		if len(have) != len(tt.want) {
+			t.Fatalf("test %d: address count mismatch: have %d, want %d", i, len(have), len(tt.want))
+		}
+		for j := range have {
+			if have[j] != tt.want[j] {
+				t.Errorf("test %d: address %d mismatch: have %v, want %v", i, j, have[j], tt.want[j])
+			}
+		}
+	}
+}
+
+// Tests that the tainted-address DAG grows once its leaves run out, keeping the
+// addresses recorded before, and that recorded addresses can be locked again.
+func TestUpdateTaintDAGGrow(t *testing.T) {
+	db := mdagdb.NewMerkleDAGDB(rawdb.NewMemoryDatabase())
+
+	full := make([]common.Address, 1<<merkeldag.TreeDepth)
+	for i := range full {
+		full[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+	root, dag, err := UpdateTaintDAG(db, nil, full)
+	if err != nil {
+		t.Fatalf("failed to fill the DAG: %v", err)
+	}
+	if err := db.SaveVersion(dag); err != nil {
+		t.Fatalf("failed to save the DAG: %v", err)
+	}
+	if have, _, err := UpdateTaintDAG(db, root, full[:1]); err != nil || !bytes.Equal(have, root) {
+		t.Errorf("relocking a recorded address failed: %v", err)
+	}
+	grown, dag, err := UpdateTaintDAG(db, root, []common.Address{{0xaa}})
+	if err != nil {
+		t.Fatalf("address beyond the capacity rejected: %v", err)
+	}
+	if err := db.SaveVersion(dag); err != nil {
+		t.Fatalf("failed to save the grown DAG: %v", err)
+	}
+	if dag, err = db.LoadVersion(grown); err != nil {
+		t.Fatalf("failed to load the grown DAG: %v", err)
+	}
+	if have, want := dag.GetCurrentIndex(), uint64(len(full)+1); have != want {
+		t.Errorf("tainted address count mismatch: have %d, want %d", have, want)
+	}
+	for _, addr := range append(full[:1:1], full[len(full)-1], common.Address{0xaa}) {
+		index, ok := dag.IndexOf(addr.Bytes())
+		if !ok {
+			t.Fatalf("tainted address %v not recorded", addr)
+		}
+		proof, err := dag.Prove(index)
+		if err != nil {
+			t.Fatalf("failed to prove %v: %v", addr, err)
+		}
+		if err := proof.Verify(grown); err != nil {
+			t.Errorf("proof of %v doesn't verify: %v", addr, err)
+		}
+	}
+}
+
+// Tests that addresses beyond the maximum size of the tainted-address DAG are
+// rejected, leaving the DAG they were added to unchanged.
+func TestUpdateTaintDAGFull(t *testing.T) {
+	db := mdagdb.NewMerkleDAGDB(rawdb.NewMemoryDatabase())
+
+	full := make([]common.Address, merkeldag.MaxLeafNodes)
+	for i := range full {
+		full[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+	root, dag, err := UpdateTaintDAG(db, nil, full)
+	if err != nil {
+		t.Fatalf("failed to fill the DAG: %v", err)
+	}
+	if _, err := AddTainted(dag, full[:1]); err != nil {
+		t.Errorf("relocking a recorded address failed: %v", err)
+	}
+	if _, err := AddTainted(dag, []common.Address{{0xaa}}); !errors.Is(err, ErrTaintDAGFull) {
+		t.Errorf("address beyond the maximum size: have %v, want %v", err, ErrTaintDAGFull)
+	}
+	if have := dag.GetRoot().GetHash(); !bytes.Equal(have, root) {
+		t.Errorf("rejected address changed the DAG")
+	}
+}
+
+// Tests that blocks commit to the tainted-address DAG of the chain, that the
+// importing node rebuilds and persists it, and that blocks committing to a
+// different DAG are rejected. Only senders holding the lock role taint.
+func TestTaintedDAG(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		other, _ = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		config   = *params.TestChainConfig
+		genesis  = &Genesis{
+			Config:  &config,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Alloc: GenesisAlloc{
+				sender:                                  {Balance: big.NewInt(params.Ether)},
+				crypto.PubkeyToAddress(other.PublicKey): {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		signer  = types.LatestSigner(&config)
+		tainted = []common.Address{{0xaa}, {0xbb}}
+	)
+	config.Security = &params.SecurityConfig{Admins: []common.Address{sender}}
+
+	lock := func(addrs ...common.Address) []byte {
+		data := []byte{0x0d, 0x03, byte(len(addrs))}
+		for _, addr := range addrs {
+			data = append(data, addr.Bytes()...)
+		}
+		return data
+	}
+	_, blocks, receipts := GenerateChainWithGenesis(genesis, ethash.NewFaker(), 3, func(i int, b *BlockGen) {
+		var (
+			data []byte
+			from = key
+		)
+		switch i {
+		case 0:
+			data = lock(tainted[0])
+		case 1:
+			data, from = lock(common.Address{0xdd}), other // lacks the lock role
+		case 2:
+			data = lock(tainted[1], tainted[0]) // already tainted address not recorded again
+		}
+		tx, _ := types.SignNewTx(from, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     b.TxNonce(crypto.PubkeyToAddress(from.PublicKey)),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: b.header.BaseFee,
+			Gas:       100000,
+			To:        &common.Address{0xcc},
+			Data:      data,
+		})
+		b.AddTx(tx)
+	})
+	if len(blocks[0].Header().Tainted) == 0 {
+		t.Fatal("block with lock transaction has no tainted root")
+	}
+	if receipts[1][0].Status != types.ReceiptStatusFailed {
+		t.Error("lock transaction of a sender without the lock role succeeded")
+	}
+	if intrinsic, _ := IntrinsicGas(lock(common.Address{0xdd}), nil, false, true, true, true); receipts[1][0].GasUsed != intrinsic {
+		t.Errorf("unauthorized lock gas mismatch: have %d, want %d", receipts[1][0].GasUsed, intrinsic)
+	}
+	if !bytes.Equal(blocks[1].Header().Tainted, blocks[0].Header().Tainted) {
+		t.Error("unauthorized lock transaction changed the tainted root")
+	}
+	// Tamper with the root of the last block, the import must fail on it
+	header := blocks[2].Header()
+	header.Tainted = blocks[0].Header().Tainted
+	tampered := blocks[2].WithSeal(header)
+
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(append(blocks[:2:2], tampered)); err == nil || !strings.Contains(err.Error(), "invalid tainted root") {
+		t.Fatalf("block with invalid tainted root: have %v, want invalid tainted root", err)
+	}
+	if _, err := chain.InsertChain(blocks[2:]); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to open head state: %v", err)
+	}
+	if nonce := statedb.GetNonce(crypto.PubkeyToAddress(other.PublicKey)); nonce != 1 {
+		t.Errorf("unauthorized lock sender nonce mismatch: have %d, want 1", nonce)
+	}
+	if nonce := statedb.GetNonce(sender); nonce != 2 {
+		t.Errorf("lock sender nonce mismatch: have %d, want 2", nonce)
+	}
+	dag, err := mdagdb.NewMerkleDAGDB(db).LoadVersion(chain.CurrentBlock().Tainted)
+	if err != nil {
+		t.Fatalf("failed to load tainted DAG of the head: %v", err)
+	}
+	if have := dag.GetCurrentIndex(); have != uint64(len(tainted)) {
+		t.Errorf("tainted address count mismatch: have %d, want %d", have, len(tainted))
+	}
+	for i, addr := range tainted {
+		if index, ok := dag.IndexOf(addr.Bytes()); !ok || index != uint64(i) {
+			t.Errorf("tainted address %v not recorded at %d: have %d (%v)", addr, i, index, ok)
+		}
+	}
+}
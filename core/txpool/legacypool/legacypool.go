@@ -19,6 +19,7 @@ package legacypool
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"sort"
@@ -70,12 +71,14 @@ var (
 	pendingReplaceMeter   = metrics.NewRegisteredMeter("txpool/pending/replace", nil)
 	pendingRateLimitMeter = metrics.NewRegisteredMeter("txpool/pending/ratelimit", nil) // Dropped due to rate limiting
 	pendingNofundsMeter   = metrics.NewRegisteredMeter("txpool/pending/nofunds", nil)   // Dropped due to out-of-funds
+	pendingLockedMeter    = metrics.NewRegisteredMeter("txpool/pending/locked", nil)    // Dropped due to locked sender or recipient
 
 	// Metrics for the queued pool
 	queuedDiscardMeter   = metrics.NewRegisteredMeter("txpool/queued/discard", nil)
 	queuedReplaceMeter   = metrics.NewRegisteredMeter("txpool/queued/replace", nil)
 	queuedRateLimitMeter = metrics.NewRegisteredMeter("txpool/queued/ratelimit", nil) // Dropped due to rate limiting
 	queuedNofundsMeter   = metrics.NewRegisteredMeter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
+	queuedLockedMeter    = metrics.NewRegisteredMeter("txpool/queued/locked", nil)    // Dropped due to locked sender or recipient
 	queuedEvictionMeter  = metrics.NewRegisteredMeter("txpool/queued/eviction", nil)  // Dropped due to lifetime
 
 	// General tx metrics
@@ -220,6 +223,7 @@ type LegacyPool struct {
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
 	all     *lookup                      // All transactions to allow lookups
 	priced  *pricedList                  // All transactions sorted by price
+	rates   *voucherRates                // Conversion rates of the vouchers paying for pooled transactions
 
 	reqResetCh      chan *txpoolResetRequest
 	reqPromoteCh    chan *accountSet
@@ -252,6 +256,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		queue:           make(map[common.Address]*list),
 		beats:           make(map[common.Address]time.Time),
 		all:             newLookup(),
+		rates:           newVoucherRates(),
 		reqResetCh:      make(chan *txpoolResetRequest),
 		reqPromoteCh:    make(chan *accountSet),
 		queueTxEventCh:  make(chan *types.Transaction),
@@ -264,7 +269,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
 	}
-	pool.priced = newPricedList(pool.all)
+	pool.priced = newPricedList(pool.all, pool.rates)
 
 	if !config.NoLocals && config.Journal != "" {
 		pool.journal = newTxJournal(config.Journal)
@@ -530,9 +535,22 @@ func (pool *LegacyPool) Pending(enforceTips bool) map[common.Address][]*txpool.L
 		txs := list.Flatten()
 
 		// If the miner requests tip enforcement, cap the lists now
+		baseFee := pool.priced.urgent.baseFee
 		if enforceTips && !pool.locals.contains(addr) {
 			for i, tx := range txs {
-				if tx.EffectiveGasTipIntCmp(pool.gasTip.Load(), pool.priced.urgent.baseFee) < 0 {
+				if tx.FeeCurrency() != nil {
+					// Voucher transactions must pay the minimum tip in native units
+					feeCap, tip := pool.rates.feeCaps(tx)
+					if baseFee != nil {
+						tip = effectiveTip(feeCap, tip, baseFee)
+					}
+					if tip.Cmp(pool.gasTip.Load()) < 0 {
+						txs = txs[:i]
+						break
+					}
+					continue
+				}
+				if tx.EffectiveGasTipIntCmp(pool.gasTip.Load(), baseFee) < 0 {
 					txs = txs[:i]
 					break
 				}
@@ -541,13 +559,15 @@ func (pool *LegacyPool) Pending(enforceTips bool) map[common.Address][]*txpool.L
 		if len(txs) > 0 {
 			lazies := make([]*txpool.LazyTransaction, len(txs))
 			for i := 0; i < len(txs); i++ {
+				// Voucher transactions are priced in native units for the miner
+				feeCap, tipCap := pool.rates.feeCaps(txs[i])
 				lazies[i] = &txpool.LazyTransaction{
 					Pool:      pool,
 					Hash:      txs[i].Hash(),
 					Tx:        txs[i],
 					Time:      txs[i].Time(),
-					GasFeeCap: txs[i].GasFeeCap(),
-					GasTipCap: txs[i].GasTipCap(),
+					GasFeeCap: feeCap,
+					GasTipCap: tipCap,
 					Gas:       txs[i].Gas(),
 					BlobGas:   txs[i].BlobGas(),
 				}
@@ -594,7 +614,8 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 			1<<types.AccessListTxType |
 			1<<types.DynamicFeeTxType |
 			1<<types.PowTxType |
-			1<<types.DynamicCryptoTxType,
+			1<<types.DynamicCryptoTxType |
+			1<<types.VoucherTxType,
 		MaxSize: txMaxSize,
 		MinTip:  pool.gasTip.Load(),
 	}
@@ -638,10 +659,22 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 			}
 			return nil
 		},
+		VoucherBalance:             pool.voucherBalance,
+		ExistingVoucherExpenditure: pool.voucherExpenditure,
+		ExistingVoucherCost:        pool.voucherCost,
 	}
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err
 	}
+	// Voucher transactions are priced through their voucher's rate, make sure
+	// it's known before the transaction enters the price heaps
+	if key, ok := voucherKeyOf(tx); ok && !pool.rates.has(key) {
+		rate, err := pool.voucherRate(key)
+		if err != nil {
+			return fmt.Errorf("%w: voucher %q of %v has no rate: %v", core.ErrTxTypeNotSupported, key.name, key.currency, err)
+		}
+		pool.rates.set(key, rate)
+	}
 	return nil
 }
 
@@ -1415,6 +1448,9 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
 
+	// Refresh the voucher rates, the price heaps are rebuilt after the reset
+	pool.rates.refresh(pool.voucherRate)
+
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	core.SenderCacher.Recover(pool.signer, reinject)
@@ -1442,8 +1478,32 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 			pool.all.Remove(hash)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
+
+		// Drop all transactions of locked accounts, they can't execute
+		if pool.currentState.GetSecurityLevel(addr) == 0 {
+			locked := list.Forward(math.MaxUint64)
+			for _, tx := range locked {
+				pool.all.Remove(tx.Hash())
+			}
+			log.Trace("Removed queued transactions of locked account", "count", len(locked))
+			queuedLockedMeter.Mark(int64(len(locked)))
+			forwards = append(forwards, locked...)
+		}
+		// Drop all transactions sent to locked accounts, they can't execute either
+		locked, _ := list.FilterRecipients(pool.isLocked)
+		for _, tx := range locked {
+			pool.all.Remove(tx.Hash())
+		}
+		log.Trace("Removed queued transactions to locked accounts", "count", len(locked))
+		queuedLockedMeter.Mark(int64(len(locked)))
+		forwards = append(forwards, locked...)
+
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr).ToBig(), gasLimit)
+
+		// Drop all voucher transactions whose fees aren't covered after the pending ones
+		overdrawn, _ := list.FilterVouchers(pool.voucherBalances(addr, pool.pending[addr]))
+		drops = append(drops, overdrawn...)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
@@ -1643,8 +1703,35 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			pool.all.Remove(hash)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
+		// Drop all transactions of locked accounts, they can't execute
+		if pool.currentState.GetSecurityLevel(addr) == 0 {
+			locked := list.Forward(math.MaxUint64)
+			for _, tx := range locked {
+				hash := tx.Hash()
+				pool.all.Remove(hash)
+				log.Trace("Removed pending transaction of locked account", "hash", hash)
+			}
+			pendingLockedMeter.Mark(int64(len(locked)))
+			olds = append(olds, locked...)
+		}
+		// Drop all transactions sent to locked accounts, and queue any invalids back for later
+		locked, lockedInvalids := list.FilterRecipients(pool.isLocked)
+		for _, tx := range locked {
+			hash := tx.Hash()
+			pool.all.Remove(hash)
+			log.Trace("Removed pending transaction to locked account", "hash", hash)
+		}
+		pendingLockedMeter.Mark(int64(len(locked)))
+		olds = append(olds, locked...)
+
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr).ToBig(), gasLimit)
+		invalids = append(invalids, lockedInvalids...)
+
+		// Drop all voucher transactions whose fees aren't covered anymore, and queue any invalids back for later
+		overdrawn, voucherInvalids := list.FilterVouchers(pool.voucherBalances(addr, nil))
+		drops = append(drops, overdrawn...)
+		invalids = append(invalids, voucherInvalids...)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
@@ -1685,6 +1772,12 @@ func (pool *LegacyPool) demoteUnexecutables() {
 	}
 }
 
+// isLocked reports whether the security level of addr locks it in the current
+// state, so that transactions from or to it can't execute.
+func (pool *LegacyPool) isLocked(addr common.Address) bool {
+	return pool.currentState.GetSecurityLevel(addr) == 0
+}
+
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
 type addressByHeartbeat struct {
 	address   common.Address
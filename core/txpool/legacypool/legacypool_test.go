@@ -692,6 +692,94 @@ func TestDropping(t *testing.T) {
 	}
 }
 
+// Tests that transactions of locked accounts are rejected, and that the pending
+// and queued transactions of an account are dropped once it gets locked.
+func TestLockedAccounts(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	// Lock the sender and check that its transactions are rejected
+	pool.mu.Lock()
+	pool.currentState.SetSecurityLevel(account, 0)
+	pool.mu.Unlock()
+
+	if err := pool.addRemote(transaction(0, 100000, key)); !errors.Is(err, core.ErrAccountLocked) {
+		t.Fatalf("locked sender error mismatch: have %v, want %v", err, core.ErrAccountLocked)
+	}
+	// Unlock it, fill the pool and check that locking the account drops everything
+	pool.mu.Lock()
+	pool.currentState.SetSecurityLevel(account, params.DefaultSecurityLevel)
+	pool.mu.Unlock()
+
+	for _, nonce := range []uint64{0, 1, 10} {
+		if err := pool.addRemoteSync(transaction(nonce, 100000, key)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", nonce, err)
+		}
+	}
+	if pending, queued := pool.Stats(); pending != 2 || queued != 1 {
+		t.Fatalf("pool size mismatch: have %d/%d, want 2/1", pending, queued)
+	}
+	pool.mu.Lock()
+	pool.currentState.SetSecurityLevel(account, 0)
+	pool.mu.Unlock()
+
+	<-pool.requestReset(nil, nil)
+	if pending, queued := pool.Stats(); pending != 0 || queued != 0 {
+		t.Errorf("pool size mismatch: have %d/%d, want 0/0", pending, queued)
+	}
+	if pool.all.Count() != 0 {
+		t.Errorf("total transaction mismatch: have %d, want %d", pool.all.Count(), 0)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the transactions sent to an account are dropped once it gets
+// locked, postponing the pending ones of the sender following them.
+func TestLockedRecipients(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	recipient := common.Address{0xaa}
+	send := func(nonce uint64, to common.Address) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(100), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		return tx
+	}
+	for _, tx := range []*types.Transaction{send(0, common.Address{}), send(1, recipient), send(2, common.Address{}), send(10, recipient)} {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", tx.Nonce(), err)
+		}
+	}
+	if pending, queued := pool.Stats(); pending != 3 || queued != 1 {
+		t.Fatalf("pool size mismatch: have %d/%d, want 3/1", pending, queued)
+	}
+	pool.mu.Lock()
+	pool.currentState.SetSecurityLevel(recipient, 0)
+	pool.mu.Unlock()
+
+	<-pool.requestReset(nil, nil)
+	if pending, queued := pool.Stats(); pending != 1 || queued != 1 {
+		t.Errorf("pool size mismatch: have %d/%d, want 1/1", pending, queued)
+	}
+	if pool.all.Count() != 2 {
+		t.Errorf("total transaction mismatch: have %d, want %d", pool.all.Count(), 2)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that if a transaction is dropped from the current pending pool (e.g. out
 // of fund), all consecutive (still valid, but not executable) transactions are
 // postponed back into the future queue to prevent broadcasting them.
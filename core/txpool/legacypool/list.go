@@ -274,16 +274,19 @@ type list struct {
 	costcap   *big.Int // Price of the highest costing transaction (reset only if exceeds balance)
 	gascap    uint64   // Gas limit of the highest spending transaction (reset only if exceeds block limit)
 	totalcost *big.Int // Total cost of all transactions in the list
+
+	vouchercost map[voucherKey]*big.Int // Total voucher fees of the transactions in the list, per voucher
 }
 
 // newList create a new transaction list for maintaining nonce-indexable fast,
 // gapped, sortable transaction lists.
 func newList(strict bool) *list {
 	return &list{
-		strict:    strict,
-		txs:       newSortedMap(),
-		costcap:   new(big.Int),
-		totalcost: new(big.Int),
+		strict:      strict,
+		txs:         newSortedMap(),
+		costcap:     new(big.Int),
+		totalcost:   new(big.Int),
+		vouchercost: make(map[voucherKey]*big.Int),
 	}
 }
 
@@ -326,6 +329,12 @@ func (l *list) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Transa
 	}
 	// Add new tx cost to totalcost
 	l.totalcost.Add(l.totalcost, tx.Cost())
+	if key, ok := voucherKeyOf(tx); ok {
+		if l.vouchercost[key] == nil {
+			l.vouchercost[key] = new(big.Int)
+		}
+		l.vouchercost[key].Add(l.vouchercost[key], tx.VoucherCost())
+	}
 	// Otherwise overwrite the old transaction with the current one
 	l.txs.Put(tx)
 	if cost := tx.Cost(); l.costcap.Cmp(cost) < 0 {
@@ -389,6 +398,96 @@ func (l *list) Filter(costLimit *big.Int, gasLimit uint64) (types.Transactions,
 	return removed, invalids
 }
 
+// FilterVouchers removes all voucher-paid transactions from the list whose fees,
+// accumulated in nonce order per voucher, exceed the sender's balance of that
+// voucher as reported by balanceOf. Every removed transaction is returned for
+// any post-removal maintenance. Strict-mode invalidated transactions are also
+// returned.
+func (l *list) FilterVouchers(balanceOf func(voucherKey) *big.Int) (types.Transactions, types.Transactions) {
+	// If every voucher covers all the fees paid with it, short circuit
+	balances := make(map[voucherKey]*big.Int, len(l.vouchercost))
+	covered := true
+	for key, cost := range l.vouchercost {
+		balances[key] = balanceOf(key)
+		if balances[key].Cmp(cost) < 0 {
+			covered = false
+		}
+	}
+	if covered {
+		return nil, nil
+	}
+	// Pay the fees in nonce order, marking every transaction that overdraws
+	spent := make(map[voucherKey]*big.Int, len(balances))
+	overdrawn := make(map[common.Hash]struct{})
+	for _, tx := range l.txs.Flatten() {
+		key, ok := voucherKeyOf(tx)
+		if !ok {
+			continue
+		}
+		if spent[key] == nil {
+			spent[key] = new(big.Int)
+		}
+		need := new(big.Int).Add(spent[key], tx.VoucherCost())
+		if need.Cmp(balances[key]) > 0 {
+			overdrawn[tx.Hash()] = struct{}{}
+			continue
+		}
+		spent[key] = need
+	}
+	removed := l.txs.Filter(func(tx *types.Transaction) bool {
+		_, ok := overdrawn[tx.Hash()]
+		return ok
+	})
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	var invalids types.Transactions
+	// If the list was strict, filter anything above the lowest nonce
+	if l.strict {
+		lowest := uint64(math.MaxUint64)
+		for _, tx := range removed {
+			if nonce := tx.Nonce(); lowest > nonce {
+				lowest = nonce
+			}
+		}
+		invalids = l.txs.filter(func(tx *types.Transaction) bool { return tx.Nonce() > lowest })
+	}
+	// Reset total cost
+	l.subTotalCost(removed)
+	l.subTotalCost(invalids)
+	l.txs.reheap()
+	return removed, invalids
+}
+
+// FilterRecipients removes all transactions from the list sent to a recipient
+// reported locked, which can't execute. Every removed transaction is returned
+// for any post-removal maintenance. Strict-mode invalidated transactions are
+// also returned.
+func (l *list) FilterRecipients(locked func(common.Address) bool) (types.Transactions, types.Transactions) {
+	removed := l.txs.Filter(func(tx *types.Transaction) bool {
+		return tx.To() != nil && locked(*tx.To())
+	})
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	var invalids types.Transactions
+	// If the list was strict, filter anything above the lowest nonce
+	if l.strict {
+		lowest := uint64(math.MaxUint64)
+		for _, tx := range removed {
+			if nonce := tx.Nonce(); lowest > nonce {
+				lowest = nonce
+			}
+		}
+		invalids = l.txs.filter(func(tx *types.Transaction) bool { return tx.Nonce() > lowest })
+	}
+	// Reset total cost
+	l.subTotalCost(removed)
+	l.subTotalCost(invalids)
+	l.txs.reheap()
+	return removed, invalids
+}
+
 // Cap places a hard limit on the number of items, returning all transactions
 // exceeding that limit.
 func (l *list) Cap(threshold int) types.Transactions {
@@ -457,15 +556,22 @@ func (l *list) LastElement() *types.Transaction {
 func (l *list) subTotalCost(txs []*types.Transaction) {
 	for _, tx := range txs {
 		l.totalcost.Sub(l.totalcost, tx.Cost())
+		if key, ok := voucherKeyOf(tx); ok {
+			if cost := l.vouchercost[key].Sub(l.vouchercost[key], tx.VoucherCost()); cost.Sign() == 0 {
+				delete(l.vouchercost, key)
+			}
+		}
 	}
 }
 
 // priceHeap is a heap.Interface implementation over transactions for retrieving
 // price-sorted transactions to discard when the pool fills up. If baseFee is set
 // then the heap is sorted based on the effective tip based on the given base fee.
-// If baseFee is nil then the sorting is based on gasFeeCap.
+// If baseFee is nil then the sorting is based on gasFeeCap. Voucher-paid
+// transactions are priced in native units through their voucher's rate.
 type priceHeap struct {
-	baseFee *big.Int // heap should always be re-sorted after baseFee is changed
+	baseFee *big.Int      // heap should always be re-sorted after baseFee is changed
+	rates   *voucherRates // heap should always be re-sorted after the rates are refreshed
 	list    []*types.Transaction
 }
 
@@ -484,6 +590,9 @@ func (h *priceHeap) Less(i, j int) bool {
 }
 
 func (h *priceHeap) cmp(a, b *types.Transaction) int {
+	if a.FeeCurrency() != nil || b.FeeCurrency() != nil {
+		return h.cmpNative(a, b)
+	}
 	if h.baseFee != nil {
 		// Compare effective tips if baseFee is specified
 		if c := a.EffectiveGasTipCmp(b, h.baseFee); c != 0 {
@@ -498,6 +607,34 @@ func (h *priceHeap) cmp(a, b *types.Transaction) int {
 	return a.GasTipCapCmp(b)
 }
 
+// cmpNative is the counterpart of cmp for voucher-paid transactions, comparing
+// the fee caps and tips converted into native units.
+func (h *priceHeap) cmpNative(a, b *types.Transaction) int {
+	aFeeCap, aTipCap := h.rates.feeCaps(a)
+	bFeeCap, bTipCap := h.rates.feeCaps(b)
+	if h.baseFee != nil {
+		// Compare effective tips if baseFee is specified
+		if c := effectiveTip(aFeeCap, aTipCap, h.baseFee).Cmp(effectiveTip(bFeeCap, bTipCap, h.baseFee)); c != 0 {
+			return c
+		}
+	}
+	// Compare fee caps if baseFee is not specified or effective tips are equal
+	if c := aFeeCap.Cmp(bFeeCap); c != 0 {
+		return c
+	}
+	// Compare tips if effective tips and fee caps are equal
+	return aTipCap.Cmp(bTipCap)
+}
+
+// effectiveTip returns the tip paid per gas on top of baseFee by the given caps.
+func effectiveTip(feeCap, tipCap, baseFee *big.Int) *big.Int {
+	tip := new(big.Int).Sub(feeCap, baseFee)
+	if tip.Cmp(tipCap) > 0 {
+		tip.Set(tipCap)
+	}
+	return tip
+}
+
 func (h *priceHeap) Push(x interface{}) {
 	tx := x.(*types.Transaction)
 	h.list = append(h.list, tx)
@@ -539,9 +676,11 @@ const (
 )
 
 // newPricedList creates a new price-sorted transaction heap.
-func newPricedList(all *lookup) *pricedList {
+func newPricedList(all *lookup, rates *voucherRates) *pricedList {
 	return &pricedList{
-		all: all,
+		all:      all,
+		urgent:   priceHeap{rates: rates},
+		floating: priceHeap{rates: rates},
 	}
 }
 
@@ -21,6 +21,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -51,6 +52,57 @@ func TestStrictListAdd(t *testing.T) {
 	}
 }
 
+// Tests that voucher-paid transactions overdrawing their voucher are filtered
+// out of strict lists, invalidating every later nonce, while the per-voucher
+// costs are kept in sync.
+func TestStrictListFilterVouchers(t *testing.T) {
+	currency := common.HexToAddress("0xfee")
+	voucherTx := func(nonce uint64, name string) *types.Transaction {
+		return types.NewTx(&types.VoucherTx{
+			Nonce:       nonce,
+			GasTipCap:   big.NewInt(1),
+			GasFeeCap:   big.NewInt(10),
+			Gas:         100,
+			To:          &common.Address{},
+			Value:       big.NewInt(0),
+			FeeCurrency: currency,
+			Voucher:     name,
+		})
+	}
+	// Pay with two vouchers, interleaving them by nonce
+	list := newList(true)
+	for i := uint64(0); i < 6; i++ {
+		name := "a"
+		if i%2 == 1 {
+			name = "b"
+		}
+		list.Add(voucherTx(i, name), DefaultConfig.PriceBump)
+	}
+	a, b := voucherKey{currency, "a"}, voucherKey{currency, "b"}
+	if cost := list.vouchercost[a]; cost.Cmp(big.NewInt(3000)) != 0 {
+		t.Fatalf("voucher a cost mismatch: have %v, want %v", cost, 3000)
+	}
+	// Voucher b covers everything, voucher a only its first two transactions
+	balances := map[voucherKey]*big.Int{a: big.NewInt(2500), b: big.NewInt(3000)}
+	removed, invalids := list.FilterVouchers(func(key voucherKey) *big.Int { return balances[key] })
+	if len(removed) != 1 || removed[0].Nonce() != 4 {
+		t.Fatalf("removed transactions mismatch: have %v, want nonce 4", removed)
+	}
+	if len(invalids) != 1 || invalids[0].Nonce() != 5 {
+		t.Fatalf("invalidated transactions mismatch: have %v, want nonce 5", invalids)
+	}
+	if cost := list.vouchercost[a]; cost.Cmp(big.NewInt(2000)) != 0 {
+		t.Errorf("voucher a cost mismatch: have %v, want %v", cost, 2000)
+	}
+	if cost := list.vouchercost[b]; cost.Cmp(big.NewInt(2000)) != 0 {
+		t.Errorf("voucher b cost mismatch: have %v, want %v", cost, 2000)
+	}
+	// Once everything is covered, nothing else is dropped
+	if removed, invalids := list.FilterVouchers(func(key voucherKey) *big.Int { return balances[key] }); len(removed)+len(invalids) != 0 {
+		t.Errorf("unexpected drops: removed %v, invalids %v", removed, invalids)
+	}
+}
+
 func BenchmarkListAdd(b *testing.B) {
 	// Generate a list of transactions to insert
 	key, _ := crypto.GenerateKey()
@@ -0,0 +1,216 @@
+package legacypool
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// voucherBalance looks up how much of the named voucher addr holds in the fee
+// currency contract, as seen by the pool's current state. The lookup runs on a
+// snapshot, so the pool state is left untouched.
+//
+// The caller must hold pool.mu.
+func (pool *LegacyPool) voucherBalance(currency common.Address, name string, addr common.Address) (*big.Int, error) {
+	head := pool.currentHead.Load()
+	blockCtx := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		Coinbase:    head.Coinbase,
+		GasLimit:    head.GasLimit,
+		BlockNumber: new(big.Int).Add(head.Number, common.Big1),
+		Time:        head.Time,
+		Difficulty:  new(big.Int),
+		BaseFee:     new(big.Int),
+	}
+	txCtx := vm.TxContext{Origin: addr, GasPrice: new(big.Int)}
+	evm := vm.NewEVM(blockCtx, txCtx, pool.currentState, pool.chainconfig, vm.Config{NoBaseFee: true})
+
+	snapshot := pool.currentState.Snapshot()
+	defer pool.currentState.RevertToSnapshot(snapshot)
+
+	balance, _, err := core.VoucherBalance(evm, currency, name, addr)
+	return balance, err
+}
+
+// voucherRate looks up the exchange rate of the named voucher, i.e. how many
+// voucher units one unit of the native token buys. The pool's current state is
+// the state the next block starts from, so the rate is the one of the snapshot
+// that block converts fees at.
+//
+// The caller must hold pool.mu.
+func (pool *LegacyPool) voucherRate(key voucherKey) (*big.Int, error) {
+	head := pool.currentHead.Load()
+	blockCtx := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		Coinbase:    head.Coinbase,
+		GasLimit:    head.GasLimit,
+		BlockNumber: new(big.Int).Add(head.Number, common.Big1),
+		Time:        head.Time,
+		Difficulty:  new(big.Int),
+		BaseFee:     new(big.Int),
+	}
+	return core.NewVoucherRates(pool.chainconfig, blockCtx, pool.currentState).Rate(key.currency, key.name)
+}
+
+// voucherBalances returns a lookup of the voucher balances of addr that are still
+// free to pay for transaction fees, after deducting whatever the transactions in
+// the committed list already spend. Failed lookups are treated as empty balances.
+//
+// The caller must hold pool.mu.
+func (pool *LegacyPool) voucherBalances(addr common.Address, committed *list) func(voucherKey) *big.Int {
+	return func(key voucherKey) *big.Int {
+		balance, err := pool.voucherBalance(key.currency, key.name, addr)
+		if err != nil {
+			log.Trace("Failed to retrieve voucher balance", "addr", addr, "currency", key.currency, "voucher", key.name, "err", err)
+			return new(big.Int)
+		}
+		if committed != nil {
+			if spent := committed.vouchercost[key]; spent != nil {
+				balance = new(big.Int).Sub(balance, spent)
+				if balance.Sign() < 0 {
+					balance.SetInt64(0)
+				}
+			}
+		}
+		return balance
+	}
+}
+
+// voucherExpenditure returns the cumulative voucher fees of all the transactions
+// addr has pooled (pending or queued) that pay with the given voucher.
+//
+// The caller must hold pool.mu.
+func (pool *LegacyPool) voucherExpenditure(addr common.Address, currency common.Address, name string) *big.Int {
+	key := voucherKey{currency, name}
+	spent := new(big.Int)
+	for _, lists := range []map[common.Address]*list{pool.pending, pool.queue} {
+		if list := lists[addr]; list != nil {
+			if cost := list.vouchercost[key]; cost != nil {
+				spent.Add(spent, cost)
+			}
+		}
+	}
+	return spent
+}
+
+// voucherCost returns the voucher fee of the transaction addr has pooled with the
+// given nonce, if it pays with the given voucher.
+//
+// The caller must hold pool.mu.
+func (pool *LegacyPool) voucherCost(addr common.Address, nonce uint64, currency common.Address, name string) *big.Int {
+	key := voucherKey{currency, name}
+	for _, lists := range []map[common.Address]*list{pool.pending, pool.queue} {
+		if list := lists[addr]; list != nil {
+			if tx := list.txs.Get(nonce); tx != nil {
+				if k, ok := voucherKeyOf(tx); ok && k == key {
+					return tx.VoucherCost()
+				}
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// voucherKey identifies a voucher by the fee currency contract holding it and
+// its name within that contract.
+type voucherKey struct {
+	currency common.Address
+	name     string
+}
+
+// voucherKeyOf returns the voucher a transaction pays its fees with, if any.
+func voucherKeyOf(tx *types.Transaction) (voucherKey, bool) {
+	currency := tx.FeeCurrency()
+	if currency == nil {
+		return voucherKey{}, false
+	}
+	return voucherKey{*currency, tx.Voucher()}, true
+}
+
+// voucherRates caches the exchange rates of the vouchers used by the pooled
+// transactions, so that they can be priced in native units without running the
+// EVM on every comparison. Rates are only ever refreshed on a pool reset, after
+// which the price heaps are rebuilt anyway.
+type voucherRates struct {
+	lock  sync.RWMutex
+	rates map[voucherKey]*big.Int
+}
+
+// newVoucherRates creates an empty voucher exchange rate cache.
+func newVoucherRates() *voucherRates {
+	return &voucherRates{
+		rates: make(map[voucherKey]*big.Int),
+	}
+}
+
+// has returns whether the rate of the given voucher is cached.
+func (r *voucherRates) has(key voucherKey) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	_, ok := r.rates[key]
+	return ok
+}
+
+// set caches the rate of the given voucher.
+func (r *voucherRates) set(key voucherKey, rate *big.Int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.rates[key] = rate
+}
+
+// refresh re-fetches the rate of every cached voucher, dropping the ones whose
+// rate can no longer be retrieved.
+func (r *voucherRates) refresh(fetch func(voucherKey) (*big.Int, error)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for key := range r.rates {
+		rate, err := fetch(key)
+		if err != nil {
+			log.Debug("Dropping voucher rate", "currency", key.currency, "voucher", key.name, "err", err)
+			delete(r.rates, key)
+			continue
+		}
+		r.rates[key] = rate
+	}
+}
+
+// toNative converts an amount of the given voucher into native units. Vouchers
+// with an unknown or zero rate are worth nothing.
+func (r *voucherRates) toNative(key voucherKey, amount *big.Int) *big.Int {
+	if r == nil {
+		return new(big.Int)
+	}
+	r.lock.RLock()
+	rate := r.rates[key]
+	r.lock.RUnlock()
+
+	if rate == nil || rate.Sign() <= 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Div(amount, rate)
+}
+
+// feeCaps returns the fee cap and tip cap of a transaction in native units. The
+// caps of voucher transactions are converted at their voucher's rate, like their
+// fees are, so the effective tip derived from the returned caps is the voucher
+// transaction's effective tip in native units.
+func (r *voucherRates) feeCaps(tx *types.Transaction) (*big.Int, *big.Int) {
+	key, ok := voucherKeyOf(tx)
+	if !ok {
+		return tx.GasFeeCap(), tx.GasTipCap()
+	}
+	return r.toNative(key, tx.GasFeeCap()), r.toNative(key, tx.GasTipCap())
+}
@@ -77,7 +77,7 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if !opts.Config.IsBerlin(head.Number) && tx.Type() != types.LegacyTxType {
 		return fmt.Errorf("%w: type %d rejected, pool not yet in Berlin", core.ErrTxTypeNotSupported, tx.Type())
 	}
-	if !opts.Config.IsLondon(head.Number) && tx.Type() == types.DynamicFeeTxType {
+	if !opts.Config.IsLondon(head.Number) && (tx.Type() == types.DynamicFeeTxType || tx.Type() == types.VoucherTxType) {
 		return fmt.Errorf("%w: type %d rejected, pool not yet in London", core.ErrTxTypeNotSupported, tx.Type())
 	}
 	if !opts.Config.IsCancun(head.Number, head.Time) && tx.Type() == types.BlobTxType {
@@ -118,6 +118,12 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if err != nil {
 		return err
 	}
+	if currency := tx.FeeCurrency(); currency != nil {
+		if !opts.Config.Voucher.IsFeeCurrency(*currency) {
+			return fmt.Errorf("%w: %v", core.ErrFeeCurrency, currency)
+		}
+		intrGas += params.TxVoucherSettlementGas
+	}
 	if tx.Gas() < intrGas {
 		return fmt.Errorf("%w: needed %v, allowed %v", core.ErrIntrinsicGas, intrGas, tx.Gas())
 	}
@@ -200,6 +206,21 @@ type ValidationOptionsWithState struct {
 	// ExistingCost is a mandatory callback to retrieve an already pooled
 	// transaction's cost with the given nonce to check for overdrafts.
 	ExistingCost func(addr common.Address, nonce uint64) *big.Int
+
+	// VoucherBalance is an optional callback to retrieve how much of a voucher
+	// an account holds in a fee currency contract. If this method is not set,
+	// transactions paying their gas with vouchers are rejected.
+	VoucherBalance func(currency common.Address, name string, addr common.Address) (*big.Int, error)
+
+	// ExistingVoucherExpenditure is an optional callback to retrieve the cumulative
+	// voucher fees of the already pooled transactions paying with the same voucher
+	// to check for overdrafts.
+	ExistingVoucherExpenditure func(addr common.Address, currency common.Address, name string) *big.Int
+
+	// ExistingVoucherCost is an optional callback to retrieve an already pooled
+	// transaction's voucher fee with the given nonce, if it pays with the same
+	// voucher. It must be set if ExistingVoucherExpenditure is.
+	ExistingVoucherCost func(addr common.Address, nonce uint64, currency common.Address, name string) *big.Int
 }
 
 // ValidateTransactionWithState is a helper method to check whether a transaction
@@ -225,6 +246,14 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 			return fmt.Errorf("%w: tx nonce %v, gapped nonce %v", core.ErrNonceTooHigh, tx.Nonce(), gap)
 		}
 	}
+	// Ensure neither the transactor nor the recipient is locked, transactions
+	// touching locked accounts fail when executed
+	if opts.State.GetSecurityLevel(from) == 0 {
+		return fmt.Errorf("%w: sender %v", core.ErrAccountLocked, from)
+	}
+	if to := tx.To(); to != nil && opts.State.GetSecurityLevel(*to) == 0 {
+		return fmt.Errorf("%w: recipient %v", core.ErrAccountLocked, *to)
+	}
 	// Ensure the transactor has enough funds to cover the transaction costs
 	var (
 		balance = opts.State.GetBalance(from).ToBig()
@@ -233,6 +262,32 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 	if balance.Cmp(cost) < 0 {
 		return fmt.Errorf("%w: balance %v, tx cost %v, overshot %v", core.ErrInsufficientFunds, balance, cost, new(big.Int).Sub(cost, balance))
 	}
+	// Ensure voucher transactions can pay the fee in their fee currency
+	if currency := tx.FeeCurrency(); currency != nil {
+		if opts.VoucherBalance == nil {
+			return fmt.Errorf("%w: voucher fee payment not supported by this pool", core.ErrTxTypeNotSupported)
+		}
+		balance, err := opts.VoucherBalance(*currency, tx.Voucher(), from)
+		if err != nil {
+			return fmt.Errorf("%w: voucher %q of %v: %v", core.ErrInsufficientFunds, tx.Voucher(), currency, err)
+		}
+		fee := tx.VoucherCost()
+		if balance.Cmp(fee) < 0 {
+			return fmt.Errorf("%w: voucher %q balance %v, tx fee %v, overshot %v", core.ErrInsufficientFunds, tx.Voucher(), balance, fee, new(big.Int).Sub(fee, balance))
+		}
+		// Ensure the voucher also covers the fees of the pooled transactions
+		// paying with it, minus the one being replaced, if any
+		if opts.ExistingVoucherExpenditure != nil {
+			spent := opts.ExistingVoucherExpenditure(from, *currency, tx.Voucher())
+			if prev := opts.ExistingVoucherCost(from, tx.Nonce(), *currency, tx.Voucher()); prev != nil {
+				spent = new(big.Int).Sub(spent, prev)
+			}
+			need := new(big.Int).Add(spent, fee)
+			if balance.Cmp(need) < 0 {
+				return fmt.Errorf("%w: voucher %q balance %v, queued fee %v, tx fee %v, overshot %v", core.ErrInsufficientFunds, tx.Voucher(), balance, spent, fee, new(big.Int).Sub(need, balance))
+			}
+		}
+	}
 	// Ensure the transactor has enough funds to cover for replacements or nonce
 	// expansions without overdrafts
 	spent := opts.ExistingExpenditure(from)
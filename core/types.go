@@ -31,9 +31,9 @@ type Validator interface {
 	// ValidateBody validates the given block's content.
 	ValidateBody(block *types.Block) error
 
-	// ValidateState validates the given statedb and optionally the receipts and
-	// gas used.
-	ValidateState(block *types.Block, state *state.StateDB, receipts types.Receipts, usedGas uint64) error
+	// ValidateState validates the given statedb and optionally the receipts, the
+	// gas used and the tainted-address DAG root computed from the receipts.
+	ValidateState(block *types.Block, state *state.StateDB, receipts types.Receipts, usedGas uint64, tainted []byte) error
 }
 
 // Prefetcher is an interface for pre-caching transaction signatures and state.
@@ -138,6 +138,7 @@ type headerMarshaling struct {
 	AvgGasDenominator   hexutil.Uint64 // Add avg gas denominator
 	RandomNumber        *hexutil.Big   // Add random number
 	RandomRoot          common.Hash    // Add random root
+	Tainted             hexutil.Bytes
 }
 
 // Hash returns the block hash of the header, which is simply the keccak256 hash of its
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ExecBatch is a batch of transactions the consensus layer committed for
+// execution, as journaled by the executor until the block executing it is
+// written.
+type ExecBatch struct {
+	Height       uint64         // Consensus height of the batch
+	Timestamp    uint64         // Time the batch was committed, used for the block
+	Txs          Transactions   // Transactions in consensus order
+	Leader       common.Address // Consensus leader of the batch
+	RandomNumber *big.Int       // Random number drawn by the consensus layer
+	Incentive    []byte         // Encoded incentives of the batch
+}
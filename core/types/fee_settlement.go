@@ -0,0 +1,55 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+//go:generate go run github.com/fjl/gencodec -type FeeSettlement -field-override feeSettlementMarshaling -out gen_fee_settlement_json.go
+
+// FeeSettlement records how the fee of a transaction paying with a voucher was
+// settled once it executed, in a single settle call of the fee currency. The
+// gross fee is the price of the whole gas limit, of which the price of the
+// unused gas is refunded: the payer is only debited the rest. The tip part of
+// it is credited to the coinbase, the base fee part is burnt. All amounts are
+// in units of the voucher, converted from native units at Rate.
+type FeeSettlement struct {
+	Currency common.Address `json:"currency" gencodec:"required"` // Fee currency contract
+	Voucher  string         `json:"voucher"  gencodec:"required"` // Name of the voucher paying the fee
+	Payer    common.Address `json:"payer"    gencodec:"required"` // Account the fee was debited from
+	Coinbase common.Address `json:"coinbase" gencodec:"required"` // Account the tip was credited to
+	GrossFee *big.Int       `json:"grossFee" gencodec:"required"` // Gas limit * effective gas price
+	Refund   *big.Int       `json:"refund"   gencodec:"required"` // Unused gas * effective gas price
+	Tip      *big.Int       `json:"tip"      gencodec:"required"` // Used gas * effective tip
+	Rate     *big.Int       `json:"rate"     gencodec:"required"` // Voucher units per native unit the fee was converted at
+}
+
+type feeSettlementMarshaling struct {
+	GrossFee *hexutil.Big
+	Refund   *hexutil.Big
+	Tip      *hexutil.Big
+	Rate     *hexutil.Big
+}
+
+// NewFeeSettlement creates the fee settlement of a voucher transaction that used
+// gasUsed of its gasLimit at the given effective gas price and tip, in voucher
+// units converted at rate.
+func NewFeeSettlement(currency common.Address, voucher string, payer common.Address, coinbase common.Address, gasLimit, gasUsed uint64, gasPrice *big.Int, gasTip *big.Int, rate *big.Int) *FeeSettlement {
+	return &FeeSettlement{
+		Currency: currency,
+		Voucher:  voucher,
+		Payer:    payer,
+		Coinbase: coinbase,
+		GrossFee: new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPrice),
+		Refund:   new(big.Int).Mul(new(big.Int).SetUint64(gasLimit-gasUsed), gasPrice),
+		Tip:      new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), gasTip),
+		Rate:     new(big.Int).Set(rate),
+	}
+}
+
+// Charged returns the fee debited from the payer's voucher.
+func (s *FeeSettlement) Charged() *big.Int {
+	return new(big.Int).Sub(s.GrossFee, s.Refund)
+}
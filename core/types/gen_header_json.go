@@ -44,7 +44,7 @@ func (h Header) MarshalJSON() ([]byte, error) {
 		PoSLeader           common.Address  `json:"posLeader" rlp:"optional"`
 		PoSVoting           []byte          `json:"posVoting" rlp:"optional"`
 		CommitTxLength      uint64          `json:"commitTxLength" rlp:"optional"`
-		Tainted             []byte          `json:"tainted" rlp:"optional"`
+		Tainted             hexutil.Bytes   `json:"tainted" rlp:"optional"`
 		Incentive           *uint256.Int    `json:"incentive" rlp:"optional"`
 		BaseFee             *hexutil.Big    `json:"baseFeePerGas" rlp:"optional"`
 		WithdrawalsHash     *common.Hash    `json:"withdrawalsRoot" rlp:"optional"`
@@ -122,7 +122,7 @@ func (h *Header) UnmarshalJSON(input []byte) error {
 		PoSLeader           *common.Address `json:"posLeader" rlp:"optional"`
 		PoSVoting           []byte          `json:"posVoting" rlp:"optional"`
 		CommitTxLength      *uint64         `json:"commitTxLength" rlp:"optional"`
-		Tainted             []byte          `json:"tainted" rlp:"optional"`
+		Tainted             *hexutil.Bytes  `json:"tainted" rlp:"optional"`
 		Incentive           *uint256.Int    `json:"incentive" rlp:"optional"`
 		BaseFee             *hexutil.Big    `json:"baseFeePerGas" rlp:"optional"`
 		WithdrawalsHash     *common.Hash    `json:"withdrawalsRoot" rlp:"optional"`
@@ -231,7 +231,7 @@ func (h *Header) UnmarshalJSON(input []byte) error {
 		h.CommitTxLength = *dec.CommitTxLength
 	}
 	if dec.Tainted != nil {
-		h.Tainted = dec.Tainted
+		h.Tainted = *dec.Tainted
 	}
 	if dec.Incentive != nil {
 		h.Incentive = dec.Incentive
@@ -59,12 +59,16 @@ type Receipt struct {
 	Logs              []*Log `json:"logs"              gencodec:"required"`
 
 	// Implementation fields: These fields are added by geth when processing a transaction.
-	TxHash            common.Hash    `json:"transactionHash" gencodec:"required"`
-	ContractAddress   common.Address `json:"contractAddress"`
-	GasUsed           uint64         `json:"gasUsed" gencodec:"required"`
-	EffectiveGasPrice *big.Int       `json:"effectiveGasPrice"` // required, but tag omitted for backwards compatibility
-	BlobGasUsed       uint64         `json:"blobGasUsed,omitempty"`
-	BlobGasPrice      *big.Int       `json:"blobGasPrice,omitempty"`
+	TxHash            common.Hash      `json:"transactionHash" gencodec:"required"`
+	ContractAddress   common.Address   `json:"contractAddress"`
+	GasUsed           uint64           `json:"gasUsed" gencodec:"required"`
+	EffectiveGasPrice *big.Int         `json:"effectiveGasPrice"` // required, but tag omitted for backwards compatibility
+	BlobGasUsed       uint64           `json:"blobGasUsed,omitempty"`
+	BlobGasPrice      *big.Int         `json:"blobGasPrice,omitempty"`
+	Settlement        *FeeSettlement   `json:"feeSettlement,omitempty"` // Set for voucher transactions
+	InterestFee       *big.Int         `json:"interestFee,omitempty"`   // Fee paid from accrued interest, set for native fee transactions
+	BalanceFee        *big.Int         `json:"balanceFee,omitempty"`    // Fee paid from the balance, set for native fee transactions
+	Tainted           []common.Address `json:"-"`                       // Accounts locked by the transaction, not persisted
 
 	// Inclusion information: These fields provide information about the inclusion of the
 	// transaction corresponding to this receipt.
@@ -82,6 +86,8 @@ type receiptMarshaling struct {
 	EffectiveGasPrice *hexutil.Big
 	BlobGasUsed       hexutil.Uint64
 	BlobGasPrice      *hexutil.Big
+	InterestFee       *hexutil.Big
+	BalanceFee        *hexutil.Big
 	BlockNumber       *hexutil.Big
 	TransactionIndex  hexutil.Uint
 }
@@ -94,11 +100,14 @@ type receiptRLP struct {
 	Logs              []*Log
 }
 
-// storedReceiptRLP is the storage encoding of a receipt.
+// storedReceiptRLP is the storage encoding of a receipt. The fee split is only
+// present for native fee transactions.
 type storedReceiptRLP struct {
 	PostStateOrStatus []byte
 	CumulativeGasUsed uint64
 	Logs              []*Log
+	InterestFee       *big.Int `rlp:"optional"`
+	BalanceFee        *big.Int `rlp:"optional"`
 }
 
 // NewReceipt creates a barebone transaction receipt, copying the init fields.
@@ -204,7 +213,7 @@ func (r *Receipt) decodeTyped(b []byte) error {
 		return errShortTypedReceipt
 	}
 	switch b[0] {
-	case DynamicFeeTxType, AccessListTxType, BlobTxType:
+	case DynamicFeeTxType, AccessListTxType, BlobTxType, VoucherTxType:
 		var data receiptRLP
 		err := rlp.DecodeBytes(b[1:], &data)
 		if err != nil {
@@ -275,6 +284,10 @@ func (r *ReceiptForStorage) EncodeRLP(_w io.Writer) error {
 		}
 	}
 	w.ListEnd(logList)
+	if r.InterestFee != nil || r.BalanceFee != nil {
+		w.WriteBigInt(bigOrZero(r.InterestFee))
+		w.WriteBigInt(bigOrZero(r.BalanceFee))
+	}
 	w.ListEnd(outerList)
 	return w.Flush()
 }
@@ -292,6 +305,8 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	r.CumulativeGasUsed = stored.CumulativeGasUsed
 	r.Logs = stored.Logs
 	r.Bloom = CreateBloom(Receipts{(*Receipt)(r)})
+	r.InterestFee = stored.InterestFee
+	r.BalanceFee = stored.BalanceFee
 
 	return nil
 }
@@ -312,7 +327,7 @@ func (rs Receipts) EncodeIndex(i int, w *bytes.Buffer) {
 	}
 	w.WriteByte(r.Type)
 	switch r.Type {
-	case AccessListTxType, DynamicFeeTxType, BlobTxType:
+	case AccessListTxType, DynamicFeeTxType, BlobTxType, VoucherTxType:
 		rlp.Encode(w, data)
 	default:
 		// For unsupported types, write nothing. Since this is for
@@ -375,3 +390,45 @@ func (rs Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, nu
 	}
 	return nil
 }
+
+// DeriveFeeSettlements fills the fee settlements of the voucher transactions,
+// which depend on the voucher rates in effect for the block besides the block
+// itself. The receipts must have been derived by DeriveFields first. Voucher
+// transactions whose rate is unknown are left without settlement.
+func (rs Receipts) DeriveFeeSettlements(config *params.ChainConfig, number uint64, time uint64, baseFee *big.Int, coinbase common.Address, txs []*Transaction, rates []VoucherRate) error {
+	signer := MakeSigner(config, new(big.Int).SetUint64(number), time)
+
+	if len(txs) != len(rs) {
+		return errors.New("transaction and receipt count mismatch")
+	}
+	for i := 0; i < len(rs); i++ {
+		if txs[i].Type() != VoucherTxType {
+			continue
+		}
+		currency, voucher := *txs[i].FeeCurrency(), txs[i].Voucher()
+		rate := findVoucherRate(rates, currency, voucher)
+		if rate == nil {
+			continue
+		}
+		// Voucher fees are paid at the base fee converted at the block's rate,
+		// the unused gas being refunded at the effective price. The coinbase
+		// gets what exceeds the base fee.
+		voucherBaseFee := VoucherBaseFee(baseFee, rate)
+		rs[i].EffectiveGasPrice = txs[i].inner.effectiveGasPrice(new(big.Int), voucherBaseFee)
+		tip := rs[i].EffectiveGasPrice
+		if voucherBaseFee != nil {
+			tip = new(big.Int).Sub(tip, voucherBaseFee)
+		}
+		from, _ := Sender(signer, txs[i])
+		rs[i].Settlement = NewFeeSettlement(currency, voucher, from, coinbase, txs[i].Gas(), rs[i].GasUsed, rs[i].EffectiveGasPrice, tip, rate)
+	}
+	return nil
+}
+
+// bigOrZero returns x, or zero if x is nil.
+func bigOrZero(x *big.Int) *big.Int {
+	if x == nil {
+		return new(big.Int)
+	}
+	return x
+}
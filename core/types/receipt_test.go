@@ -392,6 +392,28 @@ func TestTypedReceiptEncodingDecoding(t *testing.T) {
 	}
 }
 
+// Tests that the fee split of native fee transactions survives the storage
+// encoding, and that receipts stored without it still decode.
+func TestReceiptFeeSplitStorage(t *testing.T) {
+	for i, want := range []*Receipt{
+		{Status: ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*Log{}, InterestFee: big.NewInt(1000), BalanceFee: big.NewInt(20000)},
+		{Status: ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*Log{}, InterestFee: big.NewInt(21000), BalanceFee: new(big.Int)},
+		{Status: ReceiptStatusFailed, CumulativeGasUsed: 21000, Logs: []*Log{}},
+	} {
+		enc, err := rlp.EncodeToBytes((*ReceiptForStorage)(want))
+		if err != nil {
+			t.Fatalf("test %d: encode error: %v", i, err)
+		}
+		var have ReceiptForStorage
+		if err := rlp.DecodeBytes(enc, &have); err != nil {
+			t.Fatalf("test %d: decode error: %v", i, err)
+		}
+		if !reflect.DeepEqual(have.InterestFee, want.InterestFee) || !reflect.DeepEqual(have.BalanceFee, want.BalanceFee) {
+			t.Errorf("test %d: fee split mismatch: have %v/%v, want %v/%v", i, have.InterestFee, have.BalanceFee, want.InterestFee, want.BalanceFee)
+		}
+	}
+}
+
 func TestReceiptMarshalBinary(t *testing.T) {
 	// Legacy Receipt
 	legacyReceipt.Bloom = CreateBloom(Receipts{legacyReceipt})
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+//go:generate go run github.com/fjl/gencodec -type SecurityLevelChange -field-override securityLevelChangeMarshaling -out gen_security_level_change_json.go
+
+// SecurityLevelChange is a change of the security level of an account, as made
+// through the security level governance module.
+type SecurityLevelChange struct {
+	Account     common.Address `json:"account"         gencodec:"required"`
+	By          common.Address `json:"by"              gencodec:"required"` // Account the change was made by
+	Previous    uint64         `json:"previous"        gencodec:"required"`
+	Level       uint64         `json:"level"           gencodec:"required"`
+	Reason      common.Hash    `json:"reason"`
+	BlockNumber uint64         `json:"blockNumber"     gencodec:"required"`
+	BlockHash   common.Hash    `json:"blockHash"       gencodec:"required"`
+	TxHash      common.Hash    `json:"transactionHash" gencodec:"required"`
+}
+
+type securityLevelChangeMarshaling struct {
+	Previous    hexutil.Uint64
+	Level       hexutil.Uint64
+	BlockNumber hexutil.Uint64
+}
@@ -21,6 +21,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/holiman/uint256"
 )
@@ -45,7 +46,7 @@ func NewEmptyStateAccount() *StateAccount {
 		Balance:         new(uint256.Int),
 		Root:            EmptyRootHash,
 		CodeHash:        EmptyCodeHash.Bytes(),
-		SecurityLevel:   1,
+		SecurityLevel:   params.DefaultSecurityLevel,
 		Interest:        uint256.NewInt(0),
 		LastBlockNumber: big.NewInt(0),
 	}
@@ -53,18 +54,27 @@ func NewEmptyStateAccount() *StateAccount {
 
 // Copy returns a deep-copied state account object.
 func (acct *StateAccount) Copy() *StateAccount {
-	var balance *uint256.Int
+	var (
+		balance, interest *uint256.Int
+		lastBlockNumber   *big.Int
+	)
 	if acct.Balance != nil {
 		balance = new(uint256.Int).Set(acct.Balance)
 	}
+	if acct.Interest != nil {
+		interest = new(uint256.Int).Set(acct.Interest)
+	}
+	if acct.LastBlockNumber != nil {
+		lastBlockNumber = new(big.Int).Set(acct.LastBlockNumber)
+	}
 	return &StateAccount{
 		Nonce:           acct.Nonce,
 		Balance:         balance,
 		Root:            acct.Root,
 		CodeHash:        common.CopyBytes(acct.CodeHash),
 		SecurityLevel:   acct.SecurityLevel,
-		Interest:        acct.Interest,
-		LastBlockNumber: acct.LastBlockNumber,
+		Interest:        interest,
+		LastBlockNumber: lastBlockNumber,
 	}
 }
 
@@ -50,6 +50,7 @@ const (
 	BlobTxType          = 0x03
 	PowTxType           = 0x04 // New transaction type
 	DynamicCryptoTxType = 0x05
+	VoucherTxType       = 0x06
 )
 
 // Transaction is an Ethereum transaction.
@@ -211,6 +212,8 @@ func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 		inner = new(PowTx)
 	case DynamicCryptoTxType:
 		inner = new(DynamicCryptoTx)
+	case VoucherTxType:
+		inner = new(VoucherTx)
 	default:
 		return nil, ErrTxTypeNotSupported
 	}
@@ -315,7 +318,12 @@ func (tx *Transaction) To() *common.Address {
 }
 
 // Cost returns (gas * gasPrice) + (blobGas * blobGasPrice) + value.
+// Voucher transactions pay their gas in the fee currency, so only the value
+// is counted against the native balance, see VoucherCost for the rest.
 func (tx *Transaction) Cost() *big.Int {
+	if tx.Type() == VoucherTxType {
+		return tx.Value()
+	}
 	total := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
 	if tx.Type() == BlobTxType {
 		total.Add(total, new(big.Int).Mul(tx.BlobGasFeeCap(), new(big.Int).SetUint64(tx.BlobGas())))
@@ -324,6 +332,15 @@ func (tx *Transaction) Cost() *big.Int {
 	return total
 }
 
+// VoucherCost returns gas * gasFeeCap, the most a voucher transaction can be
+// charged in its fee currency. It is zero for transactions paying natively.
+func (tx *Transaction) VoucherCost() *big.Int {
+	if tx.Type() != VoucherTxType {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+}
+
 // RawSignatureValues returns the V, R, S signature values of the transaction.
 // The return values should not be modified by the caller.
 func (tx *Transaction) RawSignatureValues() (v, r, s *big.Int) {
@@ -626,3 +643,21 @@ func (tx *Transaction) PublicKeyIndex() uint64 {
 	}
 	return 0
 }
+
+// FeeCurrency returns the contract the gas of a voucher transaction is paid
+// from, nil for transactions paying in the native token.
+func (tx *Transaction) FeeCurrency() *common.Address {
+	if voucherTx, ok := tx.inner.(*VoucherTx); ok {
+		return copyAddressPtr(&voucherTx.FeeCurrency)
+	}
+	return nil
+}
+
+// Voucher returns the name of the voucher paying the gas, empty for
+// transactions paying in the native token.
+func (tx *Transaction) Voucher() string {
+	if voucherTx, ok := tx.inner.(*VoucherTx); ok {
+		return voucherTx.Voucher
+	}
+	return ""
+}
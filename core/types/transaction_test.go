@@ -929,3 +929,83 @@ func TestPowTxSerialization(t *testing.T) {
 		})
 	}
 }
+
+func TestVoucherTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	currency := common.BytesToAddress([]byte{68})
+
+	voucherTx := &VoucherTx{
+		ChainID:     big.NewInt(1),
+		Nonce:       123,
+		GasTipCap:   big.NewInt(1),
+		GasFeeCap:   big.NewInt(200),
+		Gas:         50000,
+		To:          &addr,
+		Value:       big.NewInt(1000),
+		Data:        []byte{},
+		AccessList:  AccessList{},
+		FeeCurrency: currency,
+		Voucher:     "BitCoin",
+	}
+	signer := NewLondonSigner(big.NewInt(1))
+	signedTx, err := SignNewTx(key, signer, voucherTx)
+	if err != nil {
+		t.Fatalf("Failed to sign tx: %v", err)
+	}
+
+	t.Run("Encoding and Decoding", func(t *testing.T) {
+		encodedTx, err := signedTx.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to encode tx: %v", err)
+		}
+		var decodedTx Transaction
+		if err := decodedTx.UnmarshalBinary(encodedTx); err != nil {
+			t.Fatalf("Failed to decode tx: %v", err)
+		}
+		if decodedTx.Hash() != signedTx.Hash() {
+			t.Errorf("Hash mismatch: got %x, want %x", decodedTx.Hash(), signedTx.Hash())
+		}
+		if *decodedTx.FeeCurrency() != currency || decodedTx.Voucher() != "BitCoin" {
+			t.Errorf("Fee currency mismatch: got %v %q", decodedTx.FeeCurrency(), decodedTx.Voucher())
+		}
+	})
+
+	t.Run("JSON Marshalling and Unmarshalling", func(t *testing.T) {
+		jsonData, err := json.Marshal(signedTx)
+		if err != nil {
+			t.Fatalf("Failed to marshal tx to JSON: %v", err)
+		}
+		var unmarshalledTx Transaction
+		if err := json.Unmarshal(jsonData, &unmarshalledTx); err != nil {
+			t.Fatalf("Failed to unmarshal tx from JSON: %v", err)
+		}
+		if unmarshalledTx.Hash() != signedTx.Hash() {
+			t.Errorf("Hash mismatch: got %x, want %x", unmarshalledTx.Hash(), signedTx.Hash())
+		}
+	})
+
+	t.Run("Signature covers fee currency", func(t *testing.T) {
+		from, err := Sender(signer, signedTx)
+		if err != nil {
+			t.Fatalf("Failed to recover sender: %v", err)
+		}
+		if from != addr {
+			t.Errorf("Sender mismatch: got %v, want %v", from, addr)
+		}
+		tampered := signedTx.inner.copy().(*VoucherTx)
+		tampered.Voucher = "Other"
+		if from, _ := Sender(signer, NewTx(tampered)); from == addr {
+			t.Errorf("Sender recovered after changing the voucher")
+		}
+	})
+
+	t.Run("Cost", func(t *testing.T) {
+		if signedTx.Cost().Cmp(big.NewInt(1000)) != 0 {
+			t.Errorf("Native cost mismatch: got %v, want 1000", signedTx.Cost())
+		}
+		if signedTx.VoucherCost().Cmp(big.NewInt(50000*200)) != 0 {
+			t.Errorf("Voucher cost mismatch: got %v, want %v", signedTx.VoucherCost(), 50000*200)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// VoucherTx is a dynamic fee transaction whose gas is paid in a voucher instead
+// of the native token. The fee currency and the voucher name are part of the
+// signed payload, so they can't be altered by whoever relays the transaction.
+type VoucherTx struct {
+	ChainID     *big.Int
+	Nonce       uint64
+	GasTipCap   *big.Int // a.k.a. maxPriorityFeePerGas
+	GasFeeCap   *big.Int // a.k.a. maxFeePerGas
+	Gas         uint64
+	To          *common.Address `rlp:"nil"` // nil means contract creation
+	Value       *big.Int
+	Data        []byte
+	AccessList  AccessList
+	FeeCurrency common.Address // Contract holding the voucher balances
+	Voucher     string         // Name of the voucher the fee is paid with
+
+	// Signature values
+	V *big.Int `json:"v" gencodec:"required"`
+	R *big.Int `json:"r" gencodec:"required"`
+	S *big.Int `json:"s" gencodec:"required"`
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *VoucherTx) copy() TxData {
+	cpy := &VoucherTx{
+		Nonce:       tx.Nonce,
+		To:          copyAddressPtr(tx.To),
+		Data:        common.CopyBytes(tx.Data),
+		Gas:         tx.Gas,
+		FeeCurrency: tx.FeeCurrency,
+		Voucher:     tx.Voucher,
+		// These are copied below.
+		AccessList: make(AccessList, len(tx.AccessList)),
+		Value:      new(big.Int),
+		ChainID:    new(big.Int),
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		V:          new(big.Int),
+		R:          new(big.Int),
+		S:          new(big.Int),
+	}
+	copy(cpy.AccessList, tx.AccessList)
+	if tx.Value != nil {
+		cpy.Value.Set(tx.Value)
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.GasTipCap != nil {
+		cpy.GasTipCap.Set(tx.GasTipCap)
+	}
+	if tx.GasFeeCap != nil {
+		cpy.GasFeeCap.Set(tx.GasFeeCap)
+	}
+	if tx.V != nil {
+		cpy.V.Set(tx.V)
+	}
+	if tx.R != nil {
+		cpy.R.Set(tx.R)
+	}
+	if tx.S != nil {
+		cpy.S.Set(tx.S)
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *VoucherTx) txType() byte           { return VoucherTxType }
+func (tx *VoucherTx) chainID() *big.Int      { return tx.ChainID }
+func (tx *VoucherTx) accessList() AccessList { return tx.AccessList }
+func (tx *VoucherTx) data() []byte           { return tx.Data }
+func (tx *VoucherTx) gas() uint64            { return tx.Gas }
+func (tx *VoucherTx) gasFeeCap() *big.Int    { return tx.GasFeeCap }
+func (tx *VoucherTx) gasTipCap() *big.Int    { return tx.GasTipCap }
+func (tx *VoucherTx) gasPrice() *big.Int     { return tx.GasFeeCap }
+func (tx *VoucherTx) value() *big.Int        { return tx.Value }
+func (tx *VoucherTx) nonce() uint64          { return tx.Nonce }
+func (tx *VoucherTx) to() *common.Address    { return tx.To }
+
+func (tx *VoucherTx) effectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return dst.Set(tx.GasFeeCap)
+	}
+	tip := dst.Sub(tx.GasFeeCap, baseFee)
+	if tip.Cmp(tx.GasTipCap) > 0 {
+		tip.Set(tx.GasTipCap)
+	}
+	return tip.Add(tip, baseFee)
+}
+
+func (tx *VoucherTx) rawSignatureValues() (v, r, s *big.Int) {
+	return tx.V, tx.R, tx.S
+}
+
+func (tx *VoucherTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID, tx.V, tx.R, tx.S = chainID, v, r, s
+}
+
+func (tx *VoucherTx) encode(b *bytes.Buffer) error {
+	return rlp.Encode(b, tx)
+}
+
+func (tx *VoucherTx) decode(input []byte) error {
+	return rlp.DecodeBytes(input, tx)
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+//go:generate go run github.com/fjl/gencodec -type VoucherRate -field-override voucherRateMarshaling -out gen_voucher_rate_json.go
+
+// VoucherRate is the exchange rate of a voucher in effect for a block, i.e. how
+// many units of the voucher one unit of the native token buys. Rates are looked
+// up on the state the block starts from, and stay the same for all of its
+// transactions.
+type VoucherRate struct {
+	Currency common.Address `json:"currency" gencodec:"required"` // Fee currency contract
+	Voucher  string         `json:"voucher"  gencodec:"required"` // Name of the voucher
+	Rate     *big.Int       `json:"rate"     gencodec:"required"` // Voucher units per native unit
+}
+
+type voucherRateMarshaling struct {
+	Rate *hexutil.Big
+}
+
+// findVoucherRate returns the rate of the named voucher of the fee currency
+// contract, or nil if there is none.
+func findVoucherRate(rates []VoucherRate, currency common.Address, voucher string) *big.Int {
+	for _, rate := range rates {
+		if rate.Currency == currency && rate.Voucher == voucher {
+			return rate.Rate
+		}
+	}
+	return nil
+}
+
+// VoucherBaseFee converts a base fee into units of a voucher quoted at rate.
+func VoucherBaseFee(baseFee *big.Int, rate *big.Int) *big.Int {
+	if baseFee == nil {
+		return nil
+	}
+	return new(big.Int).Mul(baseFee, rate)
+}
@@ -19,6 +19,8 @@ package vm
 import (
 	"errors"
 	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // List evm execution errors
@@ -38,13 +40,33 @@ var (
 	ErrInvalidCode              = errors.New("invalid code: must not begin with 0xef")
 	ErrNonceUintOverflow        = errors.New("nonce uint64 overflow")
 
-	ErrSecurityTooLow = errors.New("security level too low")
+	ErrSecurityTooLow     = errors.New("security level too low")
+	ErrValueLimitExceeded = errors.New("security level value limit exceeded")
+	ErrCreateNotAllowed   = errors.New("security level too low to create contracts")
 
 	// errStopToken is an internal token indicating interpreter loop termination,
 	// never returned to outside callers.
 	errStopToken = errors.New("stop token")
 )
 
+// SecurityLevelError is returned if a call is rejected because of the security
+// levels of the accounts involved.
+type SecurityLevelError struct {
+	Err         error // Rule the call violates, e.g. ErrSecurityTooLow
+	Caller      common.Address
+	CallerLevel uint64
+	Callee      common.Address
+	CalleeLevel uint64
+}
+
+func (e *SecurityLevelError) Error() string {
+	return fmt.Sprintf("%v: caller %v level %d, callee %v level %d", e.Err, e.Caller.Hex(), e.CallerLevel, e.Callee.Hex(), e.CalleeLevel)
+}
+
+func (e *SecurityLevelError) Unwrap() error {
+	return e.Err
+}
+
 // ErrStackUnderflow wraps an evm error when the items on the stack less
 // than the minimal requirement.
 type ErrStackUnderflow struct {
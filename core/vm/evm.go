@@ -18,6 +18,7 @@ package vm
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"sync/atomic"
 
@@ -25,15 +26,15 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/cryptoupgrade"
+	"github.com/ethereum/go-ethereum/governance"
 
 	// "github.com/ethereum/go-ethereum/cryptoupgrade"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/voucher/settlement"
 	"github.com/holiman/uint256"
 )
 
-const GOVERNANCE = "0x0d77d7A0769eB6a70bc08C7D40C10AaB052b8c4c"
-
 type (
 	// CanTransferFunc is the signature of a transfer guard function
 	CanTransferFunc func(StateDB, common.Address, *uint256.Int) bool
@@ -202,10 +203,12 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	}
 	snapshot := evm.StateDB.Snapshot()
 	p, isPrecompile := evm.precompile(addr)
+	isInterestClaim := evm.isInterestClaim(addr)
+	isGovernance := governance.IsGovernance(evm.chainConfig.Security, addr)
 	debug := evm.Config.Tracer != nil
 
 	if !evm.StateDB.Exist(addr) {
-		if !isPrecompile && evm.chainRules.IsEIP158 && value.Sign() == 0 {
+		if !isPrecompile && !isInterestClaim && !isGovernance && evm.chainRules.IsEIP158 && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
 			if debug {
 				if evm.depth == 0 {
@@ -220,16 +223,23 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		}
 		evm.StateDB.CreateAccount(addr)
 	}
-	governanceAddr := common.HexToAddress(GOVERNANCE)
-	if caller.Address().Cmp(governanceAddr) == 0 && len(input) == 3 {
-		if input[0] == 0x0A && input[1] == 0x0D {
-			var securityLevel uint64 = uint64(input[2])
-			evm.StateDB.SetSecurityLevel(addr, securityLevel)
-			log.Info("set security level done,", addr, " security level now is :", evm.StateDB.GetSecurityLevel(addr))
-			return nil, gas, nil
+	// System contracts are exempt from the security level call policy
+	isUpgradeAlgorithm := cryptoupgrade.IsUpgradeAlgorithm(evm.chainConfig.CryptoUpgrade, addr, input)
+	isSettlement := settlement.IsSettlement(evm.chainConfig.Voucher, addr, input)
+
+	var decision *PolicyDecision
+	if !isPrecompile && !isUpgradeAlgorithm && !isInterestClaim && !isGovernance && !isSettlement {
+		if decision, gas, err = evm.callPolicy(CALL, caller.Address(), addr, value, gas); err != nil {
+			evm.StateDB.RevertToSnapshot(snapshot)
+			return nil, gas, err
+		}
+		if !decision.Allowed() {
+			// Nothing was transferred yet, but the callee may have been created
+			evm.StateDB.RevertToSnapshot(snapshot)
+			evm.captureDenied(decision, input, gas, value.ToBig())
+			return nil, gas, decision.error()
 		}
 	}
-
 	evm.Context.Transfer(evm.StateDB, caller.Address(), addr, value)
 
 	// Capture the tracer start/end events in debug mode
@@ -246,27 +256,20 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 				evm.Config.Tracer.CaptureExit(ret, startGas-gas, err)
 			}(gas)
 		}
+		evm.capturePolicy(decision)
 	}
 
 	if isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Context)
-	} else if cryptoupgrade.IsUpgradeAlgorithm(addr, input) {
-		callFuncParam := cryptoupgrade.UnpackCall(input)
-		algorithmName := callFuncParam[0].(string)
-		algorithmParams := callFuncParam[1].([]byte)
-		// Call Algorithm: updata gas and return
-		ret, gas = cryptoupgrade.CallAlgorithm(algorithmName, gas, algorithmParams)
-
+	} else if isUpgradeAlgorithm {
+		ret, gas, err = evm.runUpgradeAlgorithm(input, gas)
+	} else if isInterestClaim {
+		ret, gas, err = evm.claimInterest(caller.Address(), input, value, gas)
+	} else if isGovernance {
+		ret, gas, err = evm.runGovernance(caller.Address(), input, value, gas, false)
+	} else if isSettlement {
+		ret, gas, err = evm.runSettlement(caller.Address(), addr, input, value, gas)
 	} else {
-		// security level check
-		callerSL := evm.StateDB.GetSecurityLevel(caller.Address())
-		addrSL := evm.StateDB.GetSecurityLevel(addr)
-		// EOA securityLevel is zero, so EOA can call any contract. This rule just ensure that contract can't call contract with lower security level.
-		if callerSL > addrSL {
-			log.Warn("caller security level is higher than contract security level, caller:", caller.Address(), "caller's security levle", evm.StateDB.GetSecurityLevel(caller.Address()), " contract:", addr, "contract's security levle", evm.StateDB.GetSecurityLevel(addr))
-			return nil, gas, ErrSecurityTooLow
-		}
-
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
 		code := evm.StateDB.GetCode(addr)
@@ -316,6 +319,20 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	if !evm.Context.CanTransfer(evm.StateDB, caller.Address(), value) {
 		return nil, gas, ErrInsufficientBalance
 	}
+	// Precompiles are exempt from the security level call policy. The value
+	// stays with the caller, so it's no transfer the policy limits.
+	p, isPrecompile := evm.precompile(addr)
+
+	var decision *PolicyDecision
+	if !isPrecompile {
+		if decision, gas, err = evm.callPolicy(CALLCODE, caller.Address(), addr, nil, gas); err != nil {
+			return nil, gas, err
+		}
+		if !decision.Allowed() {
+			evm.captureDenied(decision, input, gas, value.ToBig())
+			return nil, gas, decision.error()
+		}
+	}
 	var snapshot = evm.StateDB.Snapshot()
 
 	// Invoke tracer hooks that signal entering/exiting a call frame
@@ -324,20 +341,13 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 		defer func(startGas uint64) {
 			evm.Config.Tracer.CaptureExit(ret, startGas-gas, err)
 		}(gas)
+		evm.capturePolicy(decision)
 	}
 
 	// It is allowed to call precompiles, even via delegatecall
-	if p, isPrecompile := evm.precompile(addr); isPrecompile {
+	if isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Context)
 	} else {
-		// security level check
-		callerSL := evm.StateDB.GetSecurityLevel(caller.Address())
-		addrSL := evm.StateDB.GetSecurityLevel(addr)
-		// EOA securityLevel is zero, so EOA can call any contract. This rule just ensure that contract can't call contract with lower security level.
-		if callerSL > addrSL {
-			return nil, gas, ErrSecurityTooLow
-		}
-
 		addrCopy := addr
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
@@ -365,6 +375,24 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
 	}
+	// Precompiles are exempt from the security level call policy
+	p, isPrecompile := evm.precompile(addr)
+
+	var decision *PolicyDecision
+	if !isPrecompile {
+		if decision, gas, err = evm.callPolicy(DELEGATECALL, caller.Address(), addr, nil, gas); err != nil {
+			return nil, gas, err
+		}
+		if !decision.Allowed() {
+			// DELEGATECALL inherits value from parent call
+			var value *big.Int
+			if parent, ok := caller.(*Contract); ok {
+				value = parent.value.ToBig()
+			}
+			evm.captureDenied(decision, input, gas, value)
+			return nil, gas, decision.error()
+		}
+	}
 	var snapshot = evm.StateDB.Snapshot()
 
 	// Invoke tracer hooks that signal entering/exiting a call frame
@@ -377,20 +405,13 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 		defer func(startGas uint64) {
 			evm.Config.Tracer.CaptureExit(ret, startGas-gas, err)
 		}(gas)
+		evm.capturePolicy(decision)
 	}
 
 	// It is allowed to call precompiles, even via delegatecall
-	if p, isPrecompile := evm.precompile(addr); isPrecompile {
+	if isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Context)
 	} else {
-		// security level check
-		callerSL := evm.StateDB.GetSecurityLevel(caller.Address())
-		addrSL := evm.StateDB.GetSecurityLevel(addr)
-		// EOA securityLevel is zero, so EOA can call any contract. This rule just ensure that contract can't call contract with lower security level.
-		if callerSL > addrSL {
-			return nil, gas, ErrSecurityTooLow
-		}
-
 		addrCopy := addr
 		// Initialise a new contract and make initialise the delegate values
 		contract := NewContract(caller, AccountRef(caller.Address()), nil, gas).AsDelegate()
@@ -416,6 +437,22 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
 	}
+	// System contracts are exempt from the security level call policy
+	var (
+		p, isPrecompile    = evm.precompile(addr)
+		isUpgradeAlgorithm = cryptoupgrade.IsUpgradeAlgorithm(evm.chainConfig.CryptoUpgrade, addr, input)
+		isGovernance       = governance.IsGovernance(evm.chainConfig.Security, addr)
+		decision           *PolicyDecision
+	)
+	if !isPrecompile && !isUpgradeAlgorithm && !isGovernance {
+		if decision, gas, err = evm.callPolicy(STATICCALL, caller.Address(), addr, nil, gas); err != nil {
+			return nil, gas, err
+		}
+		if !decision.Allowed() {
+			evm.captureDenied(decision, input, gas, nil)
+			return nil, gas, decision.error()
+		}
+	}
 	// We take a snapshot here. This is a bit counter-intuitive, and could probably be skipped.
 	// However, even a staticcall is considered a 'touch'. On mainnet, static calls were introduced
 	// after all empty accounts were deleted, so this is not required. However, if we omit this,
@@ -435,19 +472,17 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 		defer func(startGas uint64) {
 			evm.Config.Tracer.CaptureExit(ret, startGas-gas, err)
 		}(gas)
+		evm.capturePolicy(decision)
 	}
 
-	if p, isPrecompile := evm.precompile(addr); isPrecompile {
+	if isPrecompile {
 		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Context)
+	} else if isUpgradeAlgorithm {
+		// callFunc is a view method, so contracts reach it through staticcall
+		ret, gas, err = evm.runUpgradeAlgorithm(input, gas)
+	} else if isGovernance {
+		ret, gas, err = evm.runGovernance(caller.Address(), input, new(uint256.Int), gas, true)
 	} else {
-		// security level check
-		callerSL := evm.StateDB.GetSecurityLevel(caller.Address())
-		addrSL := evm.StateDB.GetSecurityLevel(addr)
-		// EOA securityLevel is zero, so EOA can call any contract. This rule just ensure that contract can't call contract with lower security level.
-		if callerSL > addrSL {
-			return nil, gas, ErrSecurityTooLow
-		}
-
 		// At this point, we use a copy of address. If we don't, the go compiler will
 		// leak the 'contract' to the outer scope, and make allocation for 'contract'
 		// even if the actual execution ends on RunPrecompiled above.
@@ -471,6 +506,103 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	return ret, gas, err
 }
 
+// runUpgradeAlgorithm executes a callFunc call of codestorage in the algorithm
+// sandbox. Like precompiles it runs inside the call frame of codestorage, so
+// tracers see it through the enter/exit hooks of that frame.
+func (evm *EVM) runUpgradeAlgorithm(input []byte, gas uint64) ([]byte, uint64, error) {
+	ret, gas, err := cryptoupgrade.CallFunc(evm.StateDB, evm.chainConfig, input, evm.Context.BlockNumber.Uint64(), gas)
+	switch {
+	case errors.Is(err, cryptoupgrade.ErrOutOfGas):
+		return nil, 0, ErrOutOfGas
+	case err != nil:
+		// ret holds the revert reason
+		return ret, gas, ErrExecutionReverted
+	}
+	return ret, gas, nil
+}
+
+// isInterestClaim reports whether addr is where accrued interest is claimed at.
+func (evm *EVM) isInterestClaim(addr common.Address) bool {
+	return evm.chainConfig.Interest != nil && addr == evm.chainConfig.Interest.ClaimAddress()
+}
+
+// claimInterest moves interest accrued by the caller into its balance. Empty
+// input claims all whole wei accrued, a 32 byte word the given amount of wei.
+// It returns the amount claimed as a 32 byte word. The interest of the caller is
+// accrued up to the current block first, so that it can claim what the API
+// reports as claimable at that block.
+func (evm *EVM) claimInterest(caller common.Address, input []byte, value *uint256.Int, gas uint64) ([]byte, uint64, error) {
+	if gas < params.InterestClaimGas {
+		return nil, 0, ErrOutOfGas
+	}
+	gas -= params.InterestClaimGas
+
+	// Value sent along would be stuck at the claim address
+	if !value.IsZero() {
+		return nil, gas, ErrExecutionReverted
+	}
+	evm.StateDB.AccrueInterest(caller)
+	var (
+		scale, _ = uint256.FromBig(evm.chainConfig.Interest.Scale())
+		interest = evm.StateDB.GetInterest(caller)
+		amount   = new(uint256.Int).Div(interest, scale)
+	)
+	switch len(input) {
+	case 0:
+	case 32:
+		requested := new(uint256.Int).SetBytes(input)
+		if requested.Gt(amount) {
+			return nil, gas, ErrExecutionReverted
+		}
+		amount = requested
+	default:
+		return nil, gas, ErrExecutionReverted
+	}
+	interest.Sub(interest, new(uint256.Int).Mul(amount, scale))
+	evm.StateDB.SetInterest(caller, interest, evm.StateDB.GetInterestBlock(caller))
+	evm.StateDB.AddBalance(caller, amount)
+
+	return common.LeftPadBytes(amount.Bytes(), 32), gas, nil
+}
+
+// runGovernance executes a call of the security level governance module. Like
+// precompiles it runs inside the call frame of the module.
+func (evm *EVM) runGovernance(caller common.Address, input []byte, value *uint256.Int, gas uint64, readOnly bool) ([]byte, uint64, error) {
+	if gas < params.SecurityGovernanceGas {
+		return nil, 0, ErrOutOfGas
+	}
+	gas -= params.SecurityGovernanceGas
+
+	// Value sent along would be stuck in the module
+	if !value.IsZero() {
+		return nil, gas, ErrExecutionReverted
+	}
+	ret, err := governance.Run(evm.chainConfig.Security, evm.StateDB, caller, input, readOnly || evm.interpreter.readOnly)
+	if err != nil {
+		// ret holds the revert reason
+		return ret, gas, ErrExecutionReverted
+	}
+	return ret, gas, nil
+}
+
+// runSettlement executes a settle call of a fee currency, settling the fee of a
+// voucher transaction on behalf of the contract.
+func (evm *EVM) runSettlement(caller common.Address, addr common.Address, input []byte, value *uint256.Int, gas uint64) ([]byte, uint64, error) {
+	// Value sent along would be stuck in the contract
+	if !value.IsZero() {
+		return nil, gas, ErrExecutionReverted
+	}
+	ret, gas, err := settlement.Run(evm.StateDB, addr, caller, input, gas, evm.Context.BlockNumber.Uint64())
+	switch {
+	case errors.Is(err, settlement.ErrOutOfGas):
+		return nil, 0, ErrOutOfGas
+	case err != nil:
+		// ret holds the revert reason
+		return ret, gas, ErrExecutionReverted
+	}
+	return ret, gas, nil
+}
+
 type codeAndHash struct {
 	code []byte
 	hash common.Hash
@@ -498,6 +630,16 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		return nil, common.Address{}, gas, ErrNonceUintOverflow
 	}
 	evm.StateDB.SetNonce(caller.Address(), nonce+1)
+
+	// Creations the call policy denies fail like any other, after the nonce bump
+	decision, gas, err := evm.callPolicy(typ, caller.Address(), address, value, gas)
+	if err != nil {
+		return nil, common.Address{}, gas, err
+	}
+	if !decision.Allowed() {
+		evm.captureDenied(decision, codeAndHash.code, gas, value.ToBig())
+		return nil, common.Address{}, gas, decision.error()
+	}
 	// We add this to the access list _before_ taking a snapshot. Even if the creation fails,
 	// the access-list change should not be rolled back
 	if evm.chainRules.IsBerlin {
@@ -530,6 +672,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		} else {
 			evm.Config.Tracer.CaptureEnter(typ, caller.Address(), address, codeAndHash.code, gas, value.ToBig())
 		}
+		evm.capturePolicy(decision)
 	}
 
 	ret, err := evm.interpreter.Run(contract, nil, false)
@@ -84,7 +84,11 @@ type StateDB interface {
 	AddLog(*types.Log)
 	AddPreimage(common.Hash, []byte)
 
-	UseInterest(addr common.Address, usedInterest *uint256.Int) *uint256.Int
+	GetInterest(common.Address) *uint256.Int
+	GetInterestBlock(common.Address) uint64
+	SetInterest(common.Address, *uint256.Int, uint64)
+	UseInterest(addr common.Address, amount *uint256.Int) *uint256.Int
+	AccrueInterest(common.Address)
 }
 
 // CallContext provides a basic interface for the EVM calling conventions. The EVM
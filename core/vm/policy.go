@@ -0,0 +1,150 @@
+package vm
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/governance"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// PolicyRule names the rule of the security level call policy deciding on a
+// call frame.
+type PolicyRule string
+
+const (
+	PolicyLevel       PolicyRule = "level"       // Decided by the levels of caller and callee
+	PolicyPair        PolicyRule = "pair"        // Caller and callee are an allow-listed pair
+	PolicyValueLimit  PolicyRule = "valueLimit"  // Transferred value exceeds the limit of the caller's level
+	PolicyCreateLevel PolicyRule = "createLevel" // Decided by the lowest level allowed to create contracts
+)
+
+// PolicyDecision is the verdict of the security level call policy on a call
+// frame.
+type PolicyDecision struct {
+	Type        OpCode
+	Caller      common.Address
+	CallerLevel uint64
+	Callee      common.Address // Created contract for CREATE and CREATE2
+	CalleeLevel uint64
+	Value       *big.Int   // Value transferred, nil if the frame transfers none
+	Rule        PolicyRule // Rule deciding on the frame
+	Err         error      // Violation denying the frame, nil if it's allowed
+}
+
+// Allowed reports whether the call policy lets the frame through.
+func (d *PolicyDecision) Allowed() bool {
+	return d.Err == nil
+}
+
+// error returns the error the EVM fails a denied frame with.
+func (d *PolicyDecision) error() error {
+	return &SecurityLevelError{Err: d.Err, Caller: d.Caller, CallerLevel: d.CallerLevel, Callee: d.Callee, CalleeLevel: d.CalleeLevel}
+}
+
+// PolicyLogger is implemented by EVMLoggers interested in the decisions of the
+// security level call policy. CapturePolicy is called for every frame the policy
+// decides on, after the frame was entered. Frames denied by the policy are left
+// right after, without running any code.
+type PolicyLogger interface {
+	CapturePolicy(decision *PolicyDecision)
+}
+
+// policyState is the metered state the call policy is evaluated on. Reads of
+// the policy amendments stored in the governance module are charged like SLOAD
+// under EIP-2929.
+type policyState struct {
+	StateDB
+	gas uint64
+}
+
+// GetState returns a slot of the governance module, charging a cold or warm
+// storage read.
+func (s *policyState) GetState(addr common.Address, slot common.Hash) common.Hash {
+	if _, warm := s.SlotInAccessList(addr, slot); warm {
+		s.gas += params.WarmStorageReadCostEIP2929
+	} else {
+		s.AddSlotToAccessList(addr, slot)
+		s.gas += params.ColdSloadCostEIP2929
+	}
+	return s.StateDB.GetState(addr, slot)
+}
+
+// callPolicy evaluates the call policy of the chain on a frame of the given
+// type from caller to callee, transferring value if it isn't nil. From the
+// security policy gas fork, the storage reads of the policy are paid from gas
+// by the frames below the top one. The top frame of a transaction reads for
+// free, so that plain transfers keep costing the intrinsic gas alone. The gas
+// left is returned along with the decision. Running out of gas fails with
+// ErrOutOfGas before any decision.
+func (evm *EVM) callPolicy(typ OpCode, caller, callee common.Address, value *uint256.Int, gas uint64) (*PolicyDecision, uint64, error) {
+	var (
+		config = evm.chainConfig.Security
+		meter  = &policyState{StateDB: evm.StateDB}
+		state  = governance.StateDB(evm.StateDB)
+	)
+	if evm.depth > 0 && evm.chainConfig.IsSecurityPolicyGas(evm.Context.BlockNumber) {
+		state = meter
+	}
+	decision := &PolicyDecision{
+		Type:        typ,
+		Caller:      caller,
+		CallerLevel: evm.StateDB.GetSecurityLevel(caller),
+		Callee:      callee,
+		CalleeLevel: evm.StateDB.GetSecurityLevel(callee),
+	}
+	if value != nil {
+		decision.Value = value.ToBig()
+	}
+	switch {
+	case typ == CREATE || typ == CREATE2:
+		decision.Rule = PolicyCreateLevel
+		if decision.CallerLevel < governance.CreateLevel(config, state) {
+			decision.Err = ErrCreateNotAllowed
+		}
+	case governance.PairAllowed(config, state, caller, callee):
+		decision.Rule = PolicyPair
+	default:
+		decision.Rule = PolicyLevel
+		if !governance.LevelAllowed(config, state, decision.CallerLevel, decision.CalleeLevel) {
+			decision.Err = ErrSecurityTooLow
+		}
+	}
+	if decision.Err == nil && decision.Value != nil && decision.Value.Sign() > 0 {
+		if limit := governance.ValueLimit(config, state, decision.CallerLevel); limit != nil && decision.Value.Cmp(limit) > 0 {
+			decision.Rule, decision.Err = PolicyValueLimit, ErrValueLimitExceeded
+		}
+	}
+	if gas < meter.gas {
+		return nil, 0, ErrOutOfGas
+	}
+	return decision, gas - meter.gas, nil
+}
+
+// capturePolicy hands the decision on the current frame to the tracer, if it's
+// interested.
+func (evm *EVM) capturePolicy(decision *PolicyDecision) {
+	if tracer, ok := evm.Config.Tracer.(PolicyLogger); ok && decision != nil {
+		tracer.CapturePolicy(decision)
+	}
+}
+
+// captureDenied pings the tracer about a frame the call policy denied, which
+// is left as soon as it's entered.
+func (evm *EVM) captureDenied(decision *PolicyDecision, input []byte, gas uint64, value *big.Int) {
+	if evm.Config.Tracer == nil {
+		return
+	}
+	err := decision.error()
+	if evm.depth == 0 {
+		create := decision.Type == CREATE || decision.Type == CREATE2
+		evm.Config.Tracer.CaptureStart(evm, decision.Caller, decision.Callee, create, input, gas, value)
+		evm.capturePolicy(decision)
+		evm.Config.Tracer.CaptureEnd(nil, 0, err)
+	} else {
+		evm.Config.Tracer.CaptureEnter(decision.Type, decision.Caller, decision.Callee, input, gas, value)
+		evm.capturePolicy(decision)
+		evm.Config.Tracer.CaptureExit(nil, 0, err)
+	}
+}
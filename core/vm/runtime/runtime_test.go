@@ -17,6 +17,8 @@
 package runtime
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
@@ -32,6 +34,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/cryptoupgrade"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/params"
@@ -913,3 +917,231 @@ func BenchmarkTracerStepVsCallFrame(b *testing.B) {
 	benchmarkNonModifyingCode(10000000, code, "tracer-step-10M", stepTracer, b)
 	benchmarkNonModifyingCode(10000000, code, "tracer-call-frame-10M", callFrameTracer, b)
 }
+
+// frameRecorder records the call frames entered during execution, and the
+// decisions of the security level call policy on them.
+type frameRecorder struct {
+	enters    []common.Address
+	errs      []error
+	used      []uint64
+	decisions []*vm.PolicyDecision
+}
+
+func (r *frameRecorder) CapturePolicy(decision *vm.PolicyDecision) {
+	r.decisions = append(r.decisions, decision)
+}
+
+func (r *frameRecorder) CaptureTxStart(uint64) {}
+func (r *frameRecorder) CaptureTxEnd(uint64)   {}
+func (r *frameRecorder) CaptureStart(*vm.EVM, common.Address, common.Address, bool, []byte, uint64, *big.Int) {
+}
+func (r *frameRecorder) CaptureEnd([]byte, uint64, error) {}
+func (r *frameRecorder) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	r.enters = append(r.enters, to)
+}
+func (r *frameRecorder) CaptureExit(output []byte, gasUsed uint64, err error) {
+	r.errs = append(r.errs, err)
+	r.used = append(r.used, gasUsed)
+}
+func (r *frameRecorder) CaptureState(uint64, vm.OpCode, uint64, uint64, *vm.ScopeContext, []byte, int, error) {
+}
+func (r *frameRecorder) CaptureFault(uint64, vm.OpCode, uint64, uint64, *vm.ScopeContext, int, error) {
+}
+
+// Tests that contracts reach upgraded algorithms through staticcall with the
+// gas and error semantics of precompiles, visible to tracers.
+func TestUpgradeAlgorithmCall(t *testing.T) {
+	// Module exporting echo, returning its input, and spin, which never ends
+	wasm := common.FromHex("0061736d01000000010c0260017f017f60027f7f017e03040300010105030100010607017f014180080b072004066d656d6f7279020005616c6c6f630000046563686f0001047370696e00020a24030b002300230020006a24000b0c002000ad4220862001ad840b090003400c000b42000b")
+	path := t.TempDir() + "/echo.wasm"
+	if err := os.WriteFile(path, wasm, 0644); err != nil {
+		t.Fatal(err)
+	}
+	governor, _ := crypto.GenerateKey()
+	var packages []*cryptoupgrade.Package
+	for _, name := range []string{"echo", "spin"} {
+		pkg, err := cryptoupgrade.NewPackage(path, cryptoupgrade.Manifest{
+			Name:        name,
+			IType:       "bytes",
+			OType:       "bytes",
+			Gas:         100,
+			WordGas:     3,
+			ChainID:     params.AllEthashProtocolChanges.ChainID.Uint64(),
+			CodeStorage: params.DefaultCodeStorageAddress,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pkg.Sign(governor); err != nil {
+			t.Fatal(err)
+		}
+		packages = append(packages, pkg)
+	}
+	config := *params.AllEthashProtocolChanges
+	config.CryptoUpgrade = &params.CryptoUpgradeConfig{Governors: []common.Address{crypto.PubkeyToAddress(governor.PublicKey)}, Threshold: 1}
+
+	// Forwards its calldata to codestorage with staticcall and bubbles up the result
+	forwarder := common.HexToAddress("0xaa")
+	code := common.FromHex("3660006000376000600036600060435afa3d600060003e601e573d6000fd5b3d6000f3")
+	bytesType, _ := abi.NewType("bytes", "", nil)
+	param, _ := abi.Arguments{{Type: bytesType}}.Pack([]byte("Hello world!"))
+
+	run := func(name string) ([]byte, error, *frameRecorder) {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetCode(forwarder, code)
+		for slot, value := range cryptoupgrade.CodeStorageGenesis(common.Address{}, packages...) {
+			statedb.SetState(params.DefaultCodeStorageAddress, slot, value)
+		}
+		input, err := cryptoupgrade.CodeStorageABI.Pack("callFunc", name, param)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tracer := new(frameRecorder)
+		ret, _, err := Call(forwarder, input, &Config{State: statedb, ChainConfig: &config, GasLimit: 1000000, EVMConfig: vm.Config{Tracer: tracer}})
+		if len(tracer.enters) != 1 || tracer.enters[0] != params.DefaultCodeStorageAddress {
+			t.Fatalf("%s: algorithm frame not traced: %v", name, tracer.enters)
+		}
+		return ret, err, tracer
+	}
+	ret, err, tracer := run("echo")
+	if err != nil || !bytes.Equal(ret, param) {
+		t.Fatalf("echo failed: %x %v", ret, err)
+	}
+	if tracer.errs[0] != nil || tracer.used[0] <= 100+3*3 {
+		t.Errorf("echo frame: err %v, gas used %d", tracer.errs[0], tracer.used[0])
+	}
+	_, err, tracer = run("spin")
+	if err != vm.ErrExecutionReverted || tracer.errs[0] != vm.ErrOutOfGas {
+		t.Errorf("spin not out of gas: %v, frame %v", err, tracer.errs[0])
+	}
+	ret, err, tracer = run("missing")
+	if err != vm.ErrExecutionReverted || tracer.errs[0] != vm.ErrExecutionReverted {
+		t.Fatalf("unknown algorithm not reverted: %v, frame %v", err, tracer.errs[0])
+	}
+	if reason, err := abi.UnpackRevert(ret); err != nil || !strings.Contains(reason, "unknown upgrade algorithm") {
+		t.Errorf("unexpected revert reason %q: %v", reason, err)
+	}
+}
+
+// Tests that the security level call policy decides on every frame, that denied
+// frames transfer no value, and that tracers see the decisions.
+func TestSecurityPolicy(t *testing.T) {
+	var (
+		caller = common.HexToAddress("0xaa")
+		callee = common.HexToAddress("0xbb")
+		// Calls the callee with 5 wei and returns whether the call succeeded
+		code = common.FromHex("6000600060006000600560bb5af160005260206000f3")
+	)
+	for i, tt := range []struct {
+		policy  *params.SecurityPolicy
+		allowed bool
+		rule    vm.PolicyRule
+		err     error
+	}{
+		{policy: nil, rule: vm.PolicyLevel, err: vm.ErrSecurityTooLow}, // callers may not call lower levels by default
+		{policy: &params.SecurityPolicy{Rules: []params.SecurityRule{{Caller: 2, Callee: 1, Allow: true}}}, allowed: true, rule: vm.PolicyLevel},
+		{policy: &params.SecurityPolicy{Allow: []params.SecurityPair{{Caller: caller, Callee: callee}}}, allowed: true, rule: vm.PolicyPair},
+		{
+			policy: &params.SecurityPolicy{
+				Allow:       []params.SecurityPair{{Caller: caller, Callee: callee}},
+				ValueLimits: []params.SecurityValueLimit{{Level: 2, Limit: big.NewInt(4)}},
+			},
+			rule: vm.PolicyValueLimit, err: vm.ErrValueLimitExceeded,
+		},
+	} {
+		config := *params.AllEthashProtocolChanges
+		config.Security = &params.SecurityConfig{Policy: tt.policy}
+
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetCode(caller, code)
+		statedb.SetBalance(caller, uint256.NewInt(100))
+		statedb.SetSecurityLevel(caller, 2)
+		statedb.SetNonce(callee, 1)
+
+		tracer := new(frameRecorder)
+		ret, _, err := Call(caller, nil, &Config{State: statedb, ChainConfig: &config, GasLimit: 1000000, EVMConfig: vm.Config{Tracer: tracer}})
+		if err != nil {
+			t.Fatalf("test %d: call failed: %v", i, err)
+		}
+		if have := new(uint256.Int).SetBytes(ret).Uint64() == 1; have != tt.allowed {
+			t.Errorf("test %d: call success mismatch: have %v, want %v", i, have, tt.allowed)
+		}
+		transferred := uint64(0)
+		if tt.allowed {
+			transferred = 5
+		}
+		if have := statedb.GetBalance(callee).Uint64(); have != transferred {
+			t.Errorf("test %d: transferred value mismatch: have %d, want %d", i, have, transferred)
+		}
+		// The top call and the subcall are decided on, the denied one is traced
+		if len(tracer.decisions) != 2 {
+			t.Fatalf("test %d: decision count mismatch: have %d, want 2", i, len(tracer.decisions))
+		}
+		decision := tracer.decisions[1]
+		if decision.Type != vm.CALL || decision.Caller != caller || decision.CallerLevel != 2 || decision.CalleeLevel != 1 {
+			t.Errorf("test %d: decision mismatch: %+v", i, decision)
+		}
+		if decision.Allowed() != tt.allowed || decision.Rule != tt.rule || decision.Err != tt.err {
+			t.Errorf("test %d: verdict mismatch: have %v/%v/%v, want %v/%v/%v", i, decision.Allowed(), decision.Rule, decision.Err, tt.allowed, tt.rule, tt.err)
+		}
+		if len(tracer.errs) != 1 || !errors.Is(tracer.errs[0], tt.err) {
+			t.Errorf("test %d: frame error mismatch: have %v, want %v", i, tracer.errs, tt.err)
+		}
+	}
+	// Check that accounts below the create level may not create contracts
+	config := *params.AllEthashProtocolChanges
+	config.Security = &params.SecurityConfig{Policy: &params.SecurityPolicy{CreateLevel: 2}}
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	cfg := &Config{State: statedb, ChainConfig: &config, Origin: common.HexToAddress("0xcc")}
+	if _, _, _, err := Create(code, cfg); !errors.Is(err, vm.ErrCreateNotAllowed) {
+		t.Errorf("create below level: have %v, want %v", err, vm.ErrCreateNotAllowed)
+	}
+	if nonce := statedb.GetNonce(cfg.Origin); nonce != 1 {
+		t.Errorf("denied create nonce mismatch: have %d, want 1", nonce)
+	}
+	statedb.SetSecurityLevel(cfg.Origin, 2)
+	if _, _, _, err := Create(code, cfg); err != nil {
+		t.Errorf("create at level failed: %v", err)
+	}
+}
+
+// Tests that from the security policy gas fork, the storage reads of the call
+// policy amendments are charged like SLOAD to the frames below the top one, and
+// that frames unable to pay for them run out of gas.
+func TestSecurityPolicyGas(t *testing.T) {
+	var (
+		caller = common.HexToAddress("0xaa")
+		callee = common.HexToAddress("0xbb")
+		config = *params.AllEthashProtocolChanges
+		cost   = 2 * params.ColdSloadCostEIP2929 // pair and level rules
+	)
+	config.Security = &params.SecurityConfig{}
+	config.SecurityPolicyGasBlock = big.NewInt(0)
+
+	// Calls the callee with the given gas and returns whether the call succeeded
+	code := func(gas uint64) []byte {
+		return append(append(common.FromHex("6000600060006000600060bb61"), byte(gas>>8), byte(gas)), common.FromHex("f160005260206000f3")...)
+	}
+	for _, gas := range []uint64{cost, cost - 1} {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetCode(caller, code(gas))
+		statedb.SetNonce(callee, 1)
+
+		ret, _, err := Call(caller, nil, &Config{State: statedb, ChainConfig: &config, GasLimit: 1000000})
+		if err != nil {
+			t.Fatalf("gas %d: call failed: %v", gas, err)
+		}
+		if have, want := new(uint256.Int).SetBytes(ret).Uint64() == 1, gas >= cost; have != want {
+			t.Errorf("gas %d: subcall success mismatch: have %v, want %v", gas, have, want)
+		}
+	}
+	// The top frame of a transaction reads the policy for free
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetNonce(callee, 1)
+
+	_, left, err := Call(callee, nil, &Config{State: statedb, ChainConfig: &config, GasLimit: 1})
+	if err != nil || left != 1 {
+		t.Errorf("top frame charged for the policy: left %d of 1, err %v", left, err)
+	}
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/voucher"
+)
+
+// VoucherBalance returns how much of the named voucher owner holds in the fee
+// currency contract, together with the gas spent on the lookup. Lookups are
+// free of charge, so they only call fee currencies of the chain and with the
+// small allowance of params.VoucherLookupGas.
+func VoucherBalance(evm *vm.EVM, currency common.Address, name string, owner common.Address) (*big.Int, uint64, error) {
+	if !evm.ChainConfig().Voucher.IsFeeCurrency(currency) {
+		return nil, 0, fmt.Errorf("%w: %v", ErrFeeCurrency, currency)
+	}
+	backend := voucher.NewEVMBackend(evm, owner, params.VoucherLookupGas)
+	contract, err := voucher.NewMultiVoucherCaller(currency, backend)
+	if err != nil {
+		return nil, 0, err
+	}
+	balance, err := contract.BalanceOf(backend.CallOpts(), name, owner)
+	return balance, backend.GasUsed(), err
+}
+
+// VoucherRate returns how many units of the named voucher one unit of the native
+// token buys, quoted by the rate source of the chain on the current state of
+// the EVM.
+func VoucherRate(evm *vm.EVM, currency common.Address, name string) (*big.Int, error) {
+	if !evm.ChainConfig().Voucher.IsFeeCurrency(currency) {
+		return nil, fmt.Errorf("%w: %v", ErrFeeCurrency, currency)
+	}
+	return voucher.NewRateSource(evm.ChainConfig().Voucher).Rate(evm, currency, name)
+}
+
+// voucherRateKey identifies a rate quoted for a block: rate sources may depend
+// on the block context besides the state.
+type voucherRateKey struct {
+	config   *params.ChainConfig
+	root     common.Hash
+	number   uint64
+	time     uint64
+	currency common.Address
+	name     string
+}
+
+// voucherRateCache memoizes quoted rates, so that a block converts the fees of
+// all its transactions paying with the same voucher at the cost of one quote.
+var voucherRateCache = lru.NewCache[voucherRateKey, *big.Int](1024)
+
+// VoucherRates is the voucher rate snapshot of a block. Rates are quoted on the
+// state the block starts from rather than on its current state, so that every
+// transaction of the block converts its fee at the same rate, whatever the ones
+// before it did to the rate source.
+type VoucherRates struct {
+	config  *params.ChainConfig
+	context vm.BlockContext
+	root    common.Hash
+	db      state.Database
+	statedb *state.StateDB // Block start state, opened on first quote
+}
+
+// NewVoucherRates creates the voucher rate snapshot of the block with the given
+// context, starting from the state statedb was opened at.
+func NewVoucherRates(config *params.ChainConfig, context vm.BlockContext, statedb *state.StateDB) *VoucherRates {
+	return &VoucherRates{
+		config:  config,
+		context: context,
+		root:    statedb.OriginalRoot(),
+		db:      statedb.Database(),
+	}
+}
+
+// Rate returns the rate of the named voucher of the fee currency contract.
+func (r *VoucherRates) Rate(currency common.Address, name string) (*big.Int, error) {
+	key := voucherRateKey{r.config, r.root, r.context.BlockNumber.Uint64(), r.context.Time, currency, name}
+	if rate, ok := voucherRateCache.Get(key); ok {
+		return new(big.Int).Set(rate), nil
+	}
+	if r.statedb == nil {
+		statedb, err := state.New(r.root, r.db, nil)
+		if err != nil {
+			return nil, err
+		}
+		r.statedb = statedb
+	}
+	evm := vm.NewEVM(r.context, vm.TxContext{GasPrice: new(big.Int)}, r.statedb, r.config, vm.Config{NoBaseFee: true})
+
+	snapshot := r.statedb.Snapshot()
+	defer r.statedb.RevertToSnapshot(snapshot)
+
+	rate, err := VoucherRate(evm, currency, name)
+	if err != nil {
+		return nil, err
+	}
+	voucherRateCache.Add(key, rate)
+	return new(big.Int).Set(rate), nil
+}
+
+// Snapshot quotes the rates of the given vouchers, dropping the ones without a
+// rate, sorted by fee currency and name.
+func (r *VoucherRates) Snapshot(vouchers []types.VoucherRate) []types.VoucherRate {
+	var (
+		rates = make([]types.VoucherRate, 0, len(vouchers))
+		seen  = make(map[types.VoucherRate]bool)
+	)
+	for _, v := range vouchers {
+		id := types.VoucherRate{Currency: v.Currency, Voucher: v.Voucher}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if rate, err := r.Rate(v.Currency, v.Voucher); err == nil {
+			rates = append(rates, types.VoucherRate{Currency: v.Currency, Voucher: v.Voucher, Rate: rate})
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Currency != rates[j].Currency {
+			return rates[i].Currency.Cmp(rates[j].Currency) < 0
+		}
+		return rates[i].Voucher < rates[j].Voucher
+	})
+	return rates
+}
+
+// blockVoucherRate returns the rate the transactions of the block the EVM runs
+// convert their fees in the named voucher at. EVMs not running on a StateDB
+// quote on their current state.
+func blockVoucherRate(evm *vm.EVM, currency common.Address, name string) (*big.Int, error) {
+	if statedb, ok := evm.StateDB.(*state.StateDB); ok {
+		return NewVoucherRates(evm.ChainConfig(), evm.Context, statedb).Rate(currency, name)
+	}
+	return VoucherRate(evm, currency, name)
+}
@@ -0,0 +1,316 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/voucher"
+	"github.com/holiman/uint256"
+)
+
+// Tests that the fee of a voucher transaction is converted at the rate of the
+// block's snapshot and settled once executed, the payer being charged the used
+// gas only and the coinbase credited the tip in the voucher, and that the
+// receipt records how it was settled.
+func TestVoucherFeeSettlement(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		coinbase = common.Address{0xcb}
+		supply   = new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(10))
+		rate     = big.NewInt(2)
+		config   = params.TestChainConfig
+		signer   = types.LatestSigner(config)
+	)
+	genesis := &Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc:   GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+		Voucher: &GenesisVoucher{
+			Owner:    &sender,
+			Vouchers: []GenesisVoucherBalance{{Name: "BitCoin", ConversionRate: rate, Supply: supply}},
+		},
+	}
+	db := rawdb.NewMemoryDatabase()
+	triedb := trie.NewDatabase(db, trie.HashDefaults)
+	block := genesis.MustCommit(db, triedb)
+
+	statedb, err := state.New(block.Root(), state.NewDatabaseWithNodeDB(db, triedb), nil)
+	if err != nil {
+		t.Fatalf("failed to open genesis state: %v", err)
+	}
+	header := &types.Header{
+		ParentHash: block.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   block.GasLimit(),
+		BaseFee:    block.BaseFee(),
+		Difficulty: big.NewInt(1),
+		Time:       block.Time() + 1,
+	}
+	baseFee := new(big.Int).Mul(block.BaseFee(), rate)
+	mkTx := func(nonce uint64, gas uint64, feeCap *big.Int) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.VoucherTx{
+			ChainID:     config.ChainID,
+			Nonce:       nonce,
+			GasTipCap:   big.NewInt(1),
+			GasFeeCap:   feeCap,
+			Gas:         gas,
+			To:          &common.Address{0xaa},
+			Value:       big.NewInt(1),
+			FeeCurrency: voucher.VoucherAddress,
+			Voucher:     "BitCoin",
+		})
+	}
+	feeCap := new(big.Int).Add(baseFee, common.Big1)
+
+	// Transactions not covering the settlement gas are rejected, their state
+	// changes are dropped like in block building
+	gp := new(GasPool).AddGas(header.GasLimit)
+	snapshot := statedb.Snapshot()
+	if _, err := ApplyTransaction(config, nil, &coinbase, gp, statedb, header, mkTx(0, params.TxGas, feeCap), new(uint64), vm.Config{}); !errors.Is(err, ErrIntrinsicGas) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrIntrinsicGas)
+	}
+	statedb.RevertToSnapshot(snapshot)
+	gp.SetGas(header.GasLimit)
+	// Transactions not covering the base fee converted at the rate are rejected
+	gas := 2 * (params.TxGas + params.TxVoucherSettlementGas)
+	if _, err := ApplyTransaction(config, nil, &coinbase, gp, statedb, header, mkTx(0, gas, block.BaseFee()), new(uint64), vm.Config{}); !errors.Is(err, ErrFeeCapTooLow) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrFeeCapTooLow)
+	}
+	// Rate changes within the block don't affect the block's snapshot
+	statedb.SetState(voucher.VoucherAddress, voucher.RateSlot("BitCoin"), common.BigToHash(big.NewInt(5)))
+
+	// Transactions covering both are charged the used gas only
+	tx := mkTx(0, gas, feeCap)
+	receipt, err := ApplyTransaction(config, nil, &coinbase, gp, statedb, header, tx, new(uint64), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to apply voucher transaction: %v", err)
+	}
+	if want := params.TxGas + params.TxVoucherSettlementGas; receipt.GasUsed != want {
+		t.Errorf("gas used mismatch: have %d, want %d", receipt.GasUsed, want)
+	}
+	settlement := receipt.Settlement
+	if settlement == nil {
+		t.Fatal("missing fee settlement")
+	}
+	want := types.NewFeeSettlement(voucher.VoucherAddress, "BitCoin", sender, coinbase, tx.Gas(), receipt.GasUsed, feeCap, common.Big1, rate)
+	if settlement.Currency != want.Currency || settlement.Voucher != want.Voucher || settlement.Payer != want.Payer || settlement.Coinbase != coinbase ||
+		settlement.GrossFee.Cmp(want.GrossFee) != 0 || settlement.Refund.Cmp(want.Refund) != 0 || settlement.Tip.Cmp(want.Tip) != 0 || settlement.Rate.Cmp(rate) != 0 {
+		t.Errorf("settlement mismatch: have %+v, want %+v", settlement, want)
+	}
+	// The voucher was debited exactly the charged fee, the native balance only the value
+	evm := vm.NewEVM(NewEVMBlockContext(header, nil, &coinbase), vm.TxContext{}, statedb, config, vm.Config{})
+	balance, _, err := VoucherBalance(evm, voucher.VoucherAddress, "BitCoin", sender)
+	if err != nil {
+		t.Fatalf("failed to retrieve voucher balance: %v", err)
+	}
+	if want := new(big.Int).Sub(supply, settlement.Charged()); balance.Cmp(want) != 0 {
+		t.Errorf("voucher balance mismatch: have %v, want %v", balance, want)
+	}
+	// The coinbase was credited the tip in the voucher, not natively
+	tipped, _, err := VoucherBalance(evm, voucher.VoucherAddress, "BitCoin", coinbase)
+	if err != nil {
+		t.Fatalf("failed to retrieve coinbase voucher balance: %v", err)
+	}
+	if want := new(big.Int).SetUint64(receipt.GasUsed); tipped.Cmp(want) != 0 {
+		t.Errorf("coinbase voucher balance mismatch: have %v, want %v", tipped, want)
+	}
+	if have := statedb.GetBalance(coinbase); !have.IsZero() {
+		t.Errorf("coinbase paid natively: %v", have)
+	}
+	if have, want := statedb.GetBalance(sender).ToBig(), big.NewInt(params.Ether-1); have.Cmp(want) != 0 {
+		t.Errorf("native balance mismatch: have %v, want %v", have, want)
+	}
+	// Deriving the receipt fields with the block's rates yields the same settlement
+	txs := []*types.Transaction{tx}
+	derived := types.Receipts{{CumulativeGasUsed: receipt.GasUsed, Logs: []*types.Log{}}}
+	if err := derived.DeriveFields(config, common.Hash{}, 1, header.Time, header.BaseFee, nil, txs); err != nil {
+		t.Fatalf("failed to derive receipt fields: %v", err)
+	}
+	rates := NewVoucherRates(config, NewEVMBlockContext(header, nil, &coinbase), statedb).Snapshot([]types.VoucherRate{{Currency: voucher.VoucherAddress, Voucher: "BitCoin"}})
+	if len(rates) != 1 || rates[0].Rate.Cmp(rate) != 0 {
+		t.Fatalf("rate snapshot mismatch: have %v, want %v", rates, rate)
+	}
+	if err := derived.DeriveFeeSettlements(config, 1, header.Time, header.BaseFee, coinbase, txs, rates); err != nil {
+		t.Fatalf("failed to derive fee settlements: %v", err)
+	}
+	if have := derived[0].Settlement; have == nil || have.Payer != sender || have.Coinbase != coinbase || have.Refund.Cmp(settlement.Refund) != 0 || have.Tip.Cmp(settlement.Tip) != 0 || have.Rate.Cmp(rate) != 0 {
+		t.Errorf("derived settlement mismatch: have %+v, want %+v", have, settlement)
+	}
+	if have := derived[0].EffectiveGasPrice; have.Cmp(feeCap) != 0 {
+		t.Errorf("derived effective gas price mismatch: have %v, want %v", have, feeCap)
+	}
+	// A transaction spending the voucher it pays with is undone and charged on
+	// the state it started from
+	spend, err := voucher.VoucherABI.Pack("use", "BitCoin", balance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx = types.MustSignNewTx(key, signer, &types.VoucherTx{
+		ChainID:     config.ChainID,
+		Nonce:       1,
+		GasTipCap:   big.NewInt(1),
+		GasFeeCap:   feeCap,
+		Gas:         200_000,
+		To:          &voucher.VoucherAddress,
+		Value:       new(big.Int),
+		Data:        spend,
+		FeeCurrency: voucher.VoucherAddress,
+		Voucher:     "BitCoin",
+	})
+	receipt, err = ApplyTransaction(config, nil, &coinbase, gp, statedb, header, tx, new(uint64), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to apply voucher transaction: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusFailed {
+		t.Errorf("transaction spent the voucher paying its fee")
+	}
+	after, _, err := VoucherBalance(evm, voucher.VoucherAddress, "BitCoin", sender)
+	if err != nil {
+		t.Fatalf("failed to retrieve voucher balance: %v", err)
+	}
+	if want := new(big.Int).Sub(balance, receipt.Settlement.Charged()); after.Cmp(want) != 0 {
+		t.Errorf("voucher balance mismatch: have %v, want %v", after, want)
+	}
+	// Only the system address settles fees
+	settle, err := voucher.SettleInput("BitCoin", sender, sender, common.Big1, common.Big0, common.Big0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := evm.Call(vm.AccountRef(sender), voucher.VoucherAddress, settle, 100_000, new(uint256.Int)); !errors.Is(err, vm.ErrExecutionReverted) {
+		t.Errorf("settlement by the sender not rejected: %v", err)
+	}
+}
+
+// Tests that fees are only paid with the vouchers of the fee currencies of the
+// chain, and that the free lookups of other contracts are capped.
+func TestVoucherFeeCurrencies(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		spinner  = common.Address{0xee} // Loops until it runs out of gas
+		foreign  = common.Address{0xff} // Copy of the voucher contract, not a fee currency
+		config   = *params.TestChainConfig
+		signer   = types.LatestSigner(&config)
+		coinbase = common.Address{0xcb}
+	)
+	config.Voucher = &params.VoucherConfig{Currencies: []common.Address{spinner}}
+
+	genesis := &Genesis{
+		Config:  &config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: GenesisAlloc{
+			sender:  {Balance: big.NewInt(params.Ether)},
+			spinner: {Code: common.FromHex("5b600056")},
+		},
+		Voucher: &GenesisVoucher{
+			Owner:    &sender,
+			Vouchers: []GenesisVoucherBalance{{Name: "BitCoin", ConversionRate: big.NewInt(1), Supply: big.NewInt(params.Ether)}},
+		},
+	}
+	db := rawdb.NewMemoryDatabase()
+	triedb := trie.NewDatabase(db, trie.HashDefaults)
+	block := genesis.MustCommit(db, triedb)
+
+	statedb, err := state.New(block.Root(), state.NewDatabaseWithNodeDB(db, triedb), nil)
+	if err != nil {
+		t.Fatalf("failed to open genesis state: %v", err)
+	}
+	statedb.SetCode(foreign, statedb.GetCode(voucher.VoucherAddress))
+	statedb.SetState(foreign, voucher.RateSlot("BitCoin"), common.BigToHash(big.NewInt(1)))
+
+	header := &types.Header{
+		ParentHash: block.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   block.GasLimit(),
+		BaseFee:    block.BaseFee(),
+		Difficulty: big.NewInt(1),
+		Time:       block.Time() + 1,
+	}
+	evm := vm.NewEVM(NewEVMBlockContext(header, nil, &coinbase), vm.TxContext{}, statedb, &config, vm.Config{})
+	if _, _, err := VoucherBalance(evm, foreign, "BitCoin", sender); !errors.Is(err, ErrFeeCurrency) {
+		t.Errorf("balance of foreign currency looked up: %v", err)
+	}
+	if _, err := VoucherRate(evm, foreign, "BitCoin"); !errors.Is(err, ErrFeeCurrency) {
+		t.Errorf("rate of foreign currency looked up: %v", err)
+	}
+	if _, gas, err := VoucherBalance(evm, spinner, "BitCoin", sender); err == nil || gas != params.VoucherLookupGas {
+		t.Errorf("lookup not capped: used %d gas, err %v", gas, err)
+	}
+	if _, gas, err := VoucherBalance(evm, voucher.VoucherAddress, "BitCoin", sender); err != nil || gas > params.VoucherLookupGas {
+		t.Errorf("predeployed currency lookup failed: used %d gas, err %v", gas, err)
+	}
+	tx := types.MustSignNewTx(key, signer, &types.VoucherTx{
+		ChainID:     config.ChainID,
+		GasTipCap:   big.NewInt(1),
+		GasFeeCap:   new(big.Int).Mul(block.BaseFee(), big.NewInt(2)),
+		Gas:         params.TxGas + params.TxVoucherSettlementGas,
+		To:          &common.Address{0xaa},
+		Value:       new(big.Int),
+		FeeCurrency: foreign,
+		Voucher:     "BitCoin",
+	})
+	gp := new(GasPool).AddGas(header.GasLimit)
+	if _, err := ApplyTransaction(&config, nil, &coinbase, gp, statedb, header, tx, new(uint64), vm.Config{}); !errors.Is(err, ErrFeeCurrency) {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrFeeCurrency)
+	}
+}
+
+// Tests that the rate snapshot of a block covers only the vouchers its
+// transactions paid with, quoted on the state the block started from, not on its
+// post state.
+func TestVoucherRateSnapshot(t *testing.T) {
+	var (
+		owner  = common.Address{0xaa}
+		config = *params.TestChainConfig
+	)
+	genesis := &Genesis{
+		Config:  &config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Voucher: &GenesisVoucher{
+			Owner:    &owner,
+			Vouchers: []GenesisVoucherBalance{{Name: "BitCoin", ConversionRate: big.NewInt(2), Supply: big.NewInt(params.Ether)}},
+		},
+	}
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	parent := chain.Genesis()
+	statedb, err := chain.StateAt(parent.Root())
+	if err != nil {
+		t.Fatalf("failed to open genesis state: %v", err)
+	}
+	// The block's transactions changed the rate
+	statedb.SetState(voucher.VoucherAddress, voucher.RateSlot("BitCoin"), common.BigToHash(big.NewInt(5)))
+
+	block := types.NewBlockWithHeader(&types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   parent.GasLimit(),
+		BaseFee:    parent.BaseFee(),
+		Difficulty: big.NewInt(1),
+		Time:       parent.Time() + 1,
+	})
+	// Vouchers tracked by the parent's snapshot aren't quoted again
+	if rates := chain.voucherRateSnapshot(block, nil, statedb); len(rates) != 0 {
+		t.Fatalf("rate snapshot of a block without settlements: have %v, want none", rates)
+	}
+	receipts := []*types.Receipt{{Settlement: &types.FeeSettlement{Currency: voucher.VoucherAddress, Voucher: "BitCoin"}}}
+	rates := chain.voucherRateSnapshot(block, receipts, statedb)
+	if len(rates) != 1 || rates[0].Voucher != "BitCoin" || rates[0].Rate.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("rate snapshot mismatch: have %v, want the start state rate 2", rates)
+	}
+}
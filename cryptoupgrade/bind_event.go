@@ -3,17 +3,24 @@ package cryptoupgrade
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"math/big"
 )
 
 // Define Interface to avoid cricle import ethclient->core->upgradecrptoupgrade
 type client interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
 	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
 	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
 }
@@ -41,100 +48,119 @@ func ParseReceipt(receipt *types.Receipt) {
 	}
 }
 
-func BindPullcode(client client) {
+// BindPullcode indexes the versions announced in codestorage since the last run
+// into the registry, then follows the pullcode events to keep it up to date. A
+// copy of every approved module is written into dir, for inspection.
+func BindPullcode(client client, config *params.ChainConfig, dir string) {
 	query := ethereum.FilterQuery{
-		Addresses: []common.Address{CodeStorageAddress},
+		Addresses: []common.Address{config.CryptoUpgrade.CodeStorage()},
 		Topics:    [][]common.Hash{{pullCodeEventHash}}, // Event hash
 	}
+	// Subscribe before catching up, so that no event is missed in between
 	logCh := make(chan types.Log)
-	// Subscribe to logs that meet FilterQuery,and logs will be stored in the logCh
 	sub, err := client.SubscribeFilterLogs(context.Background(), query, logCh)
 	if err != nil {
-		log.Error("Failed to subscribe to logs: %v", err)
+		log.Error("Failed to subscribe to logs", "err", err)
+		return
 	}
+	defer sub.Unsubscribe()
 
+	r := registry.Load()
+	if err := r.Sync(client, config); err != nil {
+		log.Error("Failed to rebuild upgrade algorithm registry", "err", err)
+	}
 	for {
 		select {
 		case err := <-sub.Err():
-			log.Info("Error while listening for logs: %v", err)
+			log.Info("Error while listening for logs", "err", err)
+			return
 		case Log := <-logCh:
-			// Parse name from event
-			log.Info("Catch pull code event!")
-			var name string
-			err = CodeStorageABI.UnpackIntoInterface(&name, "pullcode", Log.Data)
-			if err != nil {
-				log.Error("Decode log data err!")
-			} else if name == "" {
-				log.Error("Err in parse name from pullcode event")
-			}
-			pc := lookupCodeInfo(client, name)
-			if pc == nil {
-				return
-			}
-			// Decompressed string to gofile
-			sourcefilePath := compressedPath + "src/" + name + ".go"
-			err = decompressStringToFile(pc.code, sourcefilePath)
-			if err == nil {
-				log.Info(fmt.Sprintf("Decompressed algorithm %s to %s", name, sourcefilePath))
-			} else {
-				log.Error(fmt.Sprintf("Error in decompressed algorithm %s to %s.Err:%v", name, sourcefilePath, err))
+			log.Info("Catch pull code event!", "block", Log.BlockNumber, "removed", Log.Removed)
+			p := r.handleLog(client, config, Log)
+			if p == nil {
+				continue
 			}
-			goVersion := GoversionCheck()
-			// Compiled to .so file
-			pluginfilePath := compressedPath + "so/" + name + ".so"
-			err = compilePlugin(sourcefilePath, pluginfilePath)
-			if err != nil {
-				log.Error(fmt.Sprintf("Error in plugin compile:%v", err))
-			} else {
-				log.Info(fmt.Sprintf("Using go version: %s,compiled algorithm to %s.", goVersion, pluginfilePath))
-				upgradeAlgorithmInfo[name] = *pc
+			modulePath := filepath.Join(dir, fmt.Sprintf("%s-%d.wasm", p.Name, p.Activation))
+			if err := saveModule(p.Code, modulePath); err != nil {
+				log.Warn(fmt.Sprintf("Failed to save algorithm %s to %s:%v", p.Name, modulePath, err))
 			}
 		}
 	}
-
 }
 
-// * Through Client call contract, get the infomation of @name algorithm
-func lookupCodeInfo(client client, name string) *codeInfo {
-
+// * Through Client call contract, get the package of @name algorithm uploaded
+// to codestorage at block @number. The package must be approved by the
+// governors of the chain, which is checked before the code is touched.
+func lookupCodeInfo(client client, config *params.ChainConfig, name string, number *big.Int) (*Package, error) {
 	// Must equal to method in codestorage contract
 	lookupFuncName := "getInfo"
 	input, err := CodeStorageABI.Pack(lookupFuncName, name)
 	if err != nil {
-		log.Error(fmt.Sprintf("Call contract codeStorage err! Algorithm:%s", name))
+		return nil, fmt.Errorf("pack getInfo for %s: %w", name, err)
 	}
+	codeStorage := config.CryptoUpgrade.CodeStorage()
 	msg := ethereum.CallMsg{
-		To:   &CodeStorageAddress,
+		To:   &codeStorage,
 		Data: input,
 	}
-	output, err := client.CallContract(context.Background(), msg, nil)
+	output, err := client.CallContract(context.Background(), msg, number)
 	if err != nil {
-		log.Error(fmt.Sprintf("failed to call contract: %v", err))
+		return nil, fmt.Errorf("failed to call contract: %w", err)
 	}
-
-	ci, err := CodeStorageABI.Unpack("getInfo", output)
+	ci, err := CodeStorageABI.Unpack(lookupFuncName, output)
 	if err != nil {
-		log.Error("Failed to unpack ouput of getInfo in codeStorage contract:", err)
-		return nil
-	} else {
-		log.Info(fmt.Sprintf("Successful get infomation of %s", name))
-		// TODO exception handing
-		return &codeInfo{
-			code:  ci[0].(string),
-			gas:   ci[1].(uint64),
-			itype: ci[2].(string),
-			otype: ci[3].(string),
-		}
+		return nil, fmt.Errorf("failed to unpack ouput of getInfo in codeStorage contract: %w", err)
+	}
+	if len(ci) != 9 {
+		return nil, fmt.Errorf("unexpected getInfo output length %d", len(ci))
+	}
+	code, ok1 := ci[0].(string)
+	gas, ok2 := ci[1].(uint64)
+	itype, ok3 := ci[2].(string)
+	otype, ok4 := ci[3].(string)
+	activation, ok5 := ci[4].(uint64)
+	hash, ok6 := ci[5].([32]byte)
+	author, ok7 := ci[6].(common.Address)
+	signatures, ok8 := ci[7].([][]byte)
+	wordGas, ok9 := ci[8].(uint64)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 || !ok7 || !ok8 || !ok9 {
+		return nil, errors.New("unexpected getInfo output types")
+	}
+	p := &Package{
+		Manifest: Manifest{
+			Name:       name,
+			Digest:     hash,
+			IType:      itype,
+			OType:      otype,
+			Gas:        gas,
+			WordGas:    wordGas,
+			Activation: activation,
+			Author:     author,
+
+			ChainID:     config.ChainID.Uint64(),
+			CodeStorage: codeStorage,
+		},
+		Code: code,
 	}
+	for _, sig := range signatures {
+		p.Signatures = append(p.Signatures, sig)
+	}
+	if err := p.Manifest.Verify(config.ChainID, config.CryptoUpgrade, signatures); err != nil {
+		return nil, fmt.Errorf("package %s by %s rejected: %w", name, author, err)
+	}
+	log.Info(fmt.Sprintf("Successful get infomation of %s", name))
+	return p, nil
 }
 
-// Check whether is callFunc in codestorage contract
-func IsUpgradeAlgorithm(addr common.Address, funcSelector []byte) bool {
-	if len(funcSelector) < 4 {
+// Check whether is callFunc in codestorage contract of a chain with upgraded
+// algorithms enabled. Such calls are always handled by CallAlgorithm, which
+// rejects algorithms that are not active yet.
+func IsUpgradeAlgorithm(config *params.CryptoUpgradeConfig, addr common.Address, funcSelector []byte) bool {
+	if config == nil || len(funcSelector) < 4 {
 		return false
 	} else {
 		funcSelector = funcSelector[:4]
-		return addr == CodeStorageAddress && bytes.Equal(CodeStorageABI.Methods["callFunc"].ID, funcSelector)
+		return addr == config.CodeStorage() && bytes.Equal(CodeStorageABI.Methods["callFunc"].ID, funcSelector)
 	}
 }
 
@@ -147,3 +173,11 @@ func convertBytesToString(array [][10]byte) []string {
 	}
 	return result
 }
+
+// Write the decompressed module of an algorithm to disk
+func saveModule(code string, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return decompressStringToFile(code, path)
+}
@@ -0,0 +1,268 @@
+package cryptoupgrade_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/cryptoupgrade"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// codeStorageBackend runs the compiled codestorage on a simulated chain, which
+// deploys it in the first block from the creation code of the artifact.
+type codeStorageBackend struct {
+	t       *testing.T
+	gspec   *core.Genesis
+	signer  types.Signer
+	address common.Address
+}
+
+func newCodeStorageBackend(t *testing.T, config *params.ChainConfig, publisher *ecdsa.PrivateKey, accounts ...common.Address) *codeStorageBackend {
+	from := crypto.PubkeyToAddress(publisher.PublicKey)
+	alloc := core.GenesisAlloc{from: {Balance: big.NewInt(params.Ether)}}
+	for _, addr := range accounts {
+		alloc[addr] = core.GenesisAccount{Balance: big.NewInt(params.Ether)}
+	}
+	return &codeStorageBackend{
+		t:       t,
+		gspec:   &core.Genesis{Config: config, GasLimit: 30_000_000, Alloc: alloc},
+		signer:  types.LatestSigner(config),
+		address: crypto.CreateAddress(from, 0),
+	}
+}
+
+// transact adds a transaction to b, calling codestorage or deploying it if data
+// is nil.
+func (s *codeStorageBackend) transact(b *core.BlockGen, key *ecdsa.PrivateKey, data []byte) {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	var tx *types.Transaction
+	if data == nil {
+		tx = types.NewContractCreation(b.TxNonce(from), new(big.Int), 5_000_000, b.BaseFee(), cryptoupgrade.CodeStorageBytecode)
+	} else {
+		tx = types.NewTransaction(b.TxNonce(from), s.address, new(big.Int), 5_000_000, b.BaseFee(), data)
+	}
+	signed, err := types.SignTx(tx, s.signer, key)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	b.AddTx(signed)
+}
+
+// generate builds and imports n blocks, returning the chain and the receipts.
+func (s *codeStorageBackend) generate(n int, gen func(i int, b *core.BlockGen)) (*core.BlockChain, []types.Receipts) {
+	_, blocks, receipts := core.GenerateChainWithGenesis(s.gspec, ethash.NewFaker(), n, gen)
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, s.gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	s.t.Cleanup(chain.Stop)
+	if _, err := chain.InsertChain(blocks); err != nil {
+		s.t.Fatalf("failed to import chain: %v", err)
+	}
+	return chain, receipts
+}
+
+// call runs a call of codestorage on the head state of chain.
+func (s *codeStorageBackend) call(chain *core.BlockChain, method string, args ...interface{}) ([]interface{}, error) {
+	return s.callAt(chain, chain.CurrentBlock().Number.Uint64(), method, args...)
+}
+
+// callAt runs a call of codestorage on the state of block @number.
+func (s *codeStorageBackend) callAt(chain *core.BlockChain, number uint64, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := cryptoupgrade.CodeStorageABI.Pack(method, args...)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	header := chain.GetHeaderByNumber(number)
+	statedb, err := chain.StateAt(header.Root)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	msg := &core.Message{
+		To:                &s.address,
+		Value:             new(big.Int),
+		GasLimit:          10_000_000,
+		GasPrice:          new(big.Int),
+		GasFeeCap:         new(big.Int),
+		GasTipCap:         new(big.Int),
+		Data:              input,
+		SkipAccountChecks: true,
+	}
+	evm := vm.NewEVM(core.NewEVMBlockContext(header, chain, nil), core.NewEVMTxContext(msg), statedb, chain.Config(), vm.Config{NoBaseFee: true})
+	result, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(msg.GasLimit))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if result.Err != nil {
+		reason, _ := abi.UnpackRevert(result.Revert())
+		return nil, errors.New(reason)
+	}
+	return cryptoupgrade.CodeStorageABI.Unpack(method, result.Return())
+}
+
+func TestCodeStorageContract(t *testing.T) {
+	var (
+		publisher, _ = crypto.GenerateKey()
+		other, _     = crypto.GenerateKey()
+		backend      = newCodeStorageBackend(t, params.TestChainConfig, publisher, crypto.PubkeyToAddress(other.PublicKey))
+	)
+	p := &cryptoupgrade.Package{
+		Manifest: cryptoupgrade.Manifest{
+			Name:       "echo",
+			Digest:     common.Hash{0x01},
+			IType:      "bytes",
+			OType:      "bytes",
+			Gas:        10,
+			WordGas:    3,
+			Activation: 10,
+			Author:     crypto.PubkeyToAddress(publisher.PublicKey),
+		},
+		Code:       "compressed module",
+		Signatures: []hexutil.Bytes{bytes.Repeat([]byte{0xaa}, 65), bytes.Repeat([]byte{0xbb}, 65)},
+	}
+	upload, _ := p.UploadData()
+	pull, _ := p.PullData()
+
+	chain, receipts := backend.generate(3, func(i int, b *core.BlockGen) {
+		switch i {
+		case 0:
+			backend.transact(b, publisher, nil)
+		case 1:
+			backend.transact(b, other, upload)
+			backend.transact(b, publisher, upload)
+			backend.transact(b, publisher, pull)
+		case 2:
+			// Versions activate in order
+			backend.transact(b, publisher, pull)
+		}
+	})
+	for i, want := range [][]uint64{
+		{types.ReceiptStatusSuccessful},
+		{types.ReceiptStatusFailed, types.ReceiptStatusSuccessful, types.ReceiptStatusSuccessful},
+		{types.ReceiptStatusFailed},
+	} {
+		for j, receipt := range receipts[i] {
+			if receipt.Status != want[j] {
+				t.Errorf("block %d tx %d: wrong status: have %d, want %d", i+1, j, receipt.Status, want[j])
+			}
+		}
+	}
+	// The creation code deploys the code nodes run
+	statedb, _ := chain.State()
+	if code := statedb.GetCode(backend.address); !bytes.Equal(code, cryptoupgrade.CodeStorageCode) {
+		t.Fatalf("deployed code differs from the artifact")
+	}
+	if logs := receipts[1][2].Logs; len(logs) != 1 || logs[0].Topics[0] != cryptoupgrade.CodeStorageABI.Events["pullcode"].ID {
+		t.Fatalf("pullCode didn't emit pullcode: %v", logs)
+	}
+	info, err := backend.call(chain, "getInfo", p.Name)
+	if err != nil {
+		t.Fatalf("getInfo failed: %v", err)
+	}
+	want := []interface{}{p.Code, p.Gas, p.IType, p.OType, p.Activation, [32]byte(p.Digest), p.Author, []byte(nil), p.WordGas}
+	for i := range want {
+		if i == 7 {
+			sigs := info[i].([][]byte)
+			if len(sigs) != len(p.Signatures) || !bytes.Equal(sigs[0], p.Signatures[0]) || !bytes.Equal(sigs[1], p.Signatures[1]) {
+				t.Errorf("wrong signatures: %x", sigs)
+			}
+			continue
+		}
+		if info[i] != want[i] {
+			t.Errorf("getInfo value %d: have %v, want %v", i, info[i], want[i])
+		}
+	}
+	if count, err := backend.call(chain, "versionCount", p.Name); err != nil || count[0].(*big.Int).Uint64() != 1 {
+		t.Errorf("wrong version count: %v, %v", count, err)
+	}
+	// Without upgraded algorithms callFunc reaches the contract
+	if _, err := backend.call(chain, "callFunc", p.Name, []byte{}); err == nil {
+		t.Errorf("callFunc succeeded without upgraded algorithms")
+	}
+}
+
+func TestCodeStorageAlgorithms(t *testing.T) {
+	var (
+		publisher, _ = crypto.GenerateKey()
+		governor, _  = crypto.GenerateKey()
+		config       = *params.TestChainConfig
+		backend      = newCodeStorageBackend(t, &config, publisher)
+	)
+	config.CryptoUpgrade = &params.CryptoUpgradeConfig{
+		Address:   &backend.address,
+		Governors: []common.Address{crypto.PubkeyToAddress(governor.PublicKey)},
+		Threshold: 1,
+	}
+	module := filepath.Join(t.TempDir(), "echo.wasm")
+	if err := os.WriteFile(module, cryptoupgrade.EchoModule, 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := cryptoupgrade.NewPackage(module, cryptoupgrade.Manifest{
+		Name:        "echo",
+		IType:       "bytes",
+		OType:       "bytes",
+		Gas:         10,
+		Activation:  5,
+		Author:      crypto.PubkeyToAddress(publisher.PublicKey),
+		ChainID:     config.ChainID.Uint64(),
+		CodeStorage: backend.address,
+	})
+	if err != nil {
+		t.Fatalf("pack failed: %v", err)
+	}
+	if err := p.Sign(governor); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	upload, _ := p.UploadData()
+	pull, _ := p.PullData()
+
+	chain, receipts := backend.generate(6, func(i int, b *core.BlockGen) {
+		switch i {
+		case 0:
+			backend.transact(b, publisher, nil)
+		case 1:
+			backend.transact(b, publisher, upload)
+			backend.transact(b, publisher, pull)
+		}
+	})
+	for i, receipt := range receipts[1] {
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("publishing tx %d failed", i)
+		}
+	}
+	// Nodes read the versions with the layout the contract stores them in
+	statedb, _ := chain.State()
+	for slot, value := range cryptoupgrade.CodeStorageGenesis(crypto.PubkeyToAddress(publisher.PublicKey), p) {
+		if have := statedb.GetState(backend.address, slot); have != value {
+			t.Errorf("slot %x: have %x, want %x", slot, have, value)
+		}
+	}
+	bytesType, _ := abi.NewType("bytes", "", nil)
+	input, _ := abi.Arguments{{Type: bytesType}}.Pack([]byte("Hello world!"))
+	if _, err := backend.callAt(chain, 4, "callFunc", p.Name, input); err == nil || !strings.Contains(err.Error(), "not active") {
+		t.Errorf("algorithm active before its activation: %v", err)
+	}
+	output, err := backend.callAt(chain, 5, "callFunc", p.Name, input)
+	if err != nil {
+		t.Fatalf("callFunc failed: %v", err)
+	}
+	// Echo returns its abi encoded input, which reads as the output of callFunc
+	if have := output[0].([]byte); string(have) != "Hello world!" {
+		t.Errorf("wrong output: have %q", have)
+	}
+}
@@ -4,20 +4,31 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"errors"
 	"io"
 	"os"
 )
 
-// * Serialize .go file to string
+// Upper bound of decompressed code, protects nodes from gzip bombs in codestorage
+const maxCodeSize = 4 * 1024 * 1024
+
+var errCodeTooLarge = errors.New("decompressed code exceeds size limit")
+
+// * Serialize file to string
 func compressFileToString(filePath string) (string, error) {
 	fileData, err := os.ReadFile(filePath)
 	if err != nil {
 		return "", err
 	}
+	return compressBytesToString(fileData)
+}
+
+// * Serialize raw bytes(e.g. a wasm module) to string stored in codestorage contract
+func compressBytesToString(data []byte) (string, error) {
 	// use gzip to compress
 	var buffer bytes.Buffer
 	writer := gzip.NewWriter(&buffer)
-	_, err = writer.Write(fileData)
+	_, err := writer.Write(data)
 	if err != nil {
 		return "", err
 	}
@@ -28,20 +39,32 @@ func compressFileToString(filePath string) (string, error) {
 	return compressedData, nil
 }
 
-// * Deserialize string to .go file
-func decompressStringToFile(compressedString string, outputPath string) error {
+// * Deserialize string to raw bytes
+func decompressString(compressedString string) ([]byte, error) {
 	// Decode base64
 	compressedData, err := base64.StdEncoding.DecodeString(compressedString)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// Use gzip to zip
 	reader, err := gzip.NewReader(bytes.NewReader(compressedData))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer reader.Close()
-	decodedData, err := io.ReadAll(reader)
+	data, err := io.ReadAll(io.LimitReader(reader, maxCodeSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxCodeSize {
+		return nil, errCodeTooLarge
+	}
+	return data, nil
+}
+
+// * Deserialize string to file
+func decompressStringToFile(compressedString string, outputPath string) error {
+	decodedData, err := decompressString(compressedString)
 	if err != nil {
 		return err
 	}
@@ -51,4 +74,4 @@ func decompressStringToFile(compressedString string, outputPath string) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-// * The test will create a copy in current folder.
+// * The test will create a copy in a temporary folder.
 func TestCompress(t *testing.T) {
 	compressFile := "./compressed.go"
 	compressedString, err := compressFileToString(compressFile)
@@ -16,15 +16,7 @@ func TestCompress(t *testing.T) {
 		fmt.Printf("err: %v\n", err)
 		return
 	}
-	// rootpath is the path of the file to be executed
-	rootPath, err := os.Getwd()
-	if err != nil {
-		fmt.Printf("get current workfolder err:%v\n", err)
-		return
-	} else {
-		fmt.Printf("currentPath: %v\n", rootPath)
-	}
-	outputPath := rootPath + "/decompressed" + "_" + "main.go"
+	outputPath := t.TempDir() + "/decompressed" + "_" + "main.go"
 	err = decompressStringToFile(compressedString, outputPath)
 	if err != nil {
 		fmt.Printf("err: %v\n", err)
@@ -34,9 +26,8 @@ func TestCompress(t *testing.T) {
 // * Decompressed check
 func TestDecompress(t *testing.T) {
 	decodedData := "Hello world!"
-	rootPath, err := os.Getwd()
-	outputPath := rootPath + "/decompressed" + "_" + "main.go"
-	err = os.WriteFile(outputPath, []byte(decodedData), 0644)
+	outputPath := t.TempDir() + "/decompressed" + "_" + "main.go"
+	err := os.WriteFile(outputPath, []byte(decodedData), 0644)
 	if err != nil {
 		fmt.Printf("err: %v\n", err)
 	}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: LGPL-3.0-or-later
+pragma solidity ^0.8.18;
+
+/// @title CodeStorage
+/// @notice Keeps the versions of the upgraded algorithms. The publisher uploads
+/// a code package, then pulls it to append it to the versions of the algorithm.
+/// Nodes resolve the version active at a block from the storage of this
+/// contract, running only versions approved by the governors of the chain.
+///
+/// Nodes read the storage directly, the layout must not change:
+///   slot 0: publisher
+///   slot 1: uploads, pending package of every algorithm
+///   slot 2: versions, pulled packages of every algorithm, by activation
+///
+/// The contract is predeployed in the genesis with the publisher set in slot 0,
+/// or deployed by the publisher.
+contract CodeStorage {
+    struct Version {
+        uint64 gas; // Base gas of every call
+        uint64 wordGas; // Gas per 32 byte word of input
+        uint64 activation; // First block the version is active at
+        address author;
+        bytes32 codeHash; // keccak256 of the decompressed module
+        bytes32 codeKeccak; // keccak256 of the compressed code, set by the contract
+        string itype; // Comma separated abi types of the input
+        string otype; // Comma separated abi types of the output
+        string code; // Compressed module
+        bytes[] signatures; // Governor approvals of the manifest
+    }
+
+    address public publisher;
+    mapping(string => Version) private uploads;
+    mapping(string => Version[]) private versions;
+
+    event CodeUploaded(string name, string code, uint64 gas, uint256 timestamp);
+    event pullcode(string name);
+
+    modifier onlyPublisher() {
+        require(msg.sender == publisher, "caller is not the publisher");
+        _;
+    }
+
+    constructor() {
+        publisher = msg.sender;
+    }
+
+    function uploadCode(
+        string calldata name,
+        string calldata code,
+        uint64 gas,
+        string calldata itype,
+        string calldata otype,
+        uint64 activation,
+        bytes32 codeHash,
+        address author,
+        bytes[] calldata signatures,
+        uint64 wordGas
+    ) external onlyPublisher {
+        require(bytes(code).length > 0, "empty code");
+        Version storage upload = uploads[name];
+        upload.gas = gas;
+        upload.wordGas = wordGas;
+        upload.activation = activation;
+        upload.author = author;
+        upload.codeHash = codeHash;
+        upload.codeKeccak = keccak256(bytes(code));
+        upload.itype = itype;
+        upload.otype = otype;
+        upload.code = code;
+        delete upload.signatures;
+        for (uint256 i = 0; i < signatures.length; i++) {
+            upload.signatures.push(signatures[i]);
+        }
+        emit CodeUploaded(name, code, gas, block.timestamp);
+    }
+
+    function updataGas(string calldata name, uint64 _gas) external onlyPublisher {
+        require(bytes(uploads[name].code).length > 0, "no upload");
+        uploads[name].gas = _gas;
+    }
+
+    /// @notice Appends the uploaded package to the versions of the algorithm.
+    /// Versions activate in the future and in order, so that the version active
+    /// at a past block never changes.
+    function pullCode(string calldata name) external onlyPublisher {
+        Version storage upload = uploads[name];
+        require(bytes(upload.code).length > 0, "no upload");
+        require(upload.activation > block.number, "activation not in the future");
+
+        Version[] storage list = versions[name];
+        if (list.length > 0) {
+            require(upload.activation > list[list.length - 1].activation, "activation not after the last version");
+        }
+        list.push(upload);
+        emit pullcode(name);
+    }
+
+    function getInfo(string calldata name)
+        external
+        view
+        returns (string memory, uint64, string memory, string memory, uint64, bytes32, address, bytes[] memory, uint64)
+    {
+        Version storage v = uploads[name];
+        return (v.code, v.gas, v.itype, v.otype, v.activation, v.codeHash, v.author, v.signatures, v.wordGas);
+    }
+
+    function getCode(string calldata name) external view returns (string memory) {
+        return uploads[name].code;
+    }
+
+    function getGas(string calldata name) external view returns (uint64) {
+        return uploads[name].gas;
+    }
+
+    function versionCount(string calldata name) external view returns (uint256) {
+        return versions[name].length;
+    }
+
+    /// @notice Runs an upgraded algorithm. Nodes with upgraded algorithms enabled
+    /// handle the call themselves, it only reaches this code on other chains.
+    function callFunc(string calldata, bytes calldata) external pure returns (bytes memory) {
+        revert("upgrade algorithms not enabled");
+    }
+}
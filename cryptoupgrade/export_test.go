@@ -0,0 +1,5 @@
+package cryptoupgrade
+
+// EchoModule exposes the test module to the tests running codestorage on a
+// simulated chain, which live in a separate package to import core.
+var EchoModule = echoModule
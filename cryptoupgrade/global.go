@@ -1,30 +1,61 @@
 package cryptoupgrade
 
 import (
+	_ "embed"
+	"fmt"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/cryptoupgrade/wasm"
 )
 
-// * I hope the address of the contract is fixed, which involves the design of the underlying code
+// Hardhat artifact of codestorage, its address is set in the chain config. It is
+// compiled from contract/CodeStorage.sol by solc 0.8.21 with the optimizer (200
+// runs), viaIR and the paris evm version.
+//
+//go:embed contract/CodeStorage.json
+var codeStorageArtifact []byte
+
 var (
-	CodeStorageAddress = common.BytesToAddress([]byte{67})
-	pullCodeEventHash  = crypto.Keccak256Hash([]byte("pullcode(string)"))
-	// ! Last '/' Essential
-	compressedPath       = "./plugin/"
-	CodeStorageABI, _, _ = abi.LoadHardhatContract("/home/ubuntu/project/modifyGeth/cryptoupgrade/contract/CodeStorage.json")
+	pullCodeEventHash = crypto.Keccak256Hash([]byte("pullcode(string)"))
+
+	// Abi, creation code and deployed code of codestorage
+	CodeStorageABI, CodeStorageBytecode, CodeStorageCode = mustParseArtifact(codeStorageArtifact)
 )
 
-// TODO If geth restart, map will be nil
-var upgradeAlgorithmInfo = make(map[string]codeInfo)
+func mustParseArtifact(artifact []byte) (*abi.ABI, []byte, []byte) {
+	parsed, bytecode, deployed, err := abi.ParseHardhatContract(artifact)
+	if err != nil {
+		panic(fmt.Sprintf("invalid codestorage artifact: %v", err))
+	}
+	return parsed, common.FromHex(bytecode), common.FromHex(deployed)
+}
 
 type codeInfo struct {
-	code  string
-	gas   uint64
-	itype string
-	otype string
+	gas     uint64 // Base gas of every call
+	wordGas uint64 // Gas per 32 byte word of input
+	itype   string
+	otype   string
+
+	activation uint64      // First block the version is active at, declared in codestorage
+	hash       common.Hash // Declared keccak256 hash of the decompressed module
+
+	module *wasm.Module // Decoded algorithm, shared by all invocations
+	err    error        // Reason calls are rejected, set if the code doesn't match hash
+}
+
+// requiredGas returns the gas charged for a call over input before execution,
+// the second value reports an overflow.
+func (c *codeInfo) requiredGas(input []byte) (uint64, bool) {
+	words := (uint64(len(input)) + 31) / 32
+	cost, overflow := math.SafeMul(words, c.wordGas)
+	if overflow {
+		return 0, true
+	}
+	return math.SafeAdd(cost, c.gas)
 }
 
 func (c *codeInfo) getTypeList() ([]string, []string) {
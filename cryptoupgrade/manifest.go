@@ -0,0 +1,96 @@
+package cryptoupgrade
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	errNoGovernance = errors.New("no cryptoupgrade governance configured")
+	errWrongChain   = errors.New("code package approved for another chain")
+
+	// ErrNotApproved is returned for code packages lacking enough governor signatures.
+	ErrNotApproved = errors.New("code package not approved by governance")
+)
+
+// Manifest describes a code package uploaded to codestorage. Governors approve
+// a package by signing the hash of its manifest, nodes only load packages
+// carrying the signatures of the threshold set in the genesis. The manifest
+// names the chain and the codestorage it is meant for, so that approvals can't
+// be replayed elsewhere.
+type Manifest struct {
+	Name       string         `json:"name"`
+	Digest     common.Hash    `json:"digest"`  // keccak256 of the decompressed module
+	IType      string         `json:"itype"`   // Comma separated abi types of the input
+	OType      string         `json:"otype"`   // Comma separated abi types of the output
+	Gas        uint64         `json:"gas"`     // Base gas of every call
+	WordGas    uint64         `json:"wordGas"` // Gas per 32 byte word of input
+	Activation uint64         `json:"activation"`
+	Author     common.Address `json:"author"`
+
+	ChainID     uint64         `json:"chainId"`
+	CodeStorage common.Address `json:"codeStorage"`
+}
+
+// Hash returns the hash signed by the governors.
+func (m *Manifest) Hash() common.Hash {
+	enc, _ := rlp.EncodeToBytes(m)
+	return crypto.Keccak256Hash(enc)
+}
+
+// Sign approves the manifest with a governor key, the signature is in the
+// [R || S || V] format.
+func (m *Manifest) Sign(key *ecdsa.PrivateKey) ([]byte, error) {
+	return crypto.Sign(m.Hash().Bytes(), key)
+}
+
+// Signers recovers the address behind each signature.
+func (m *Manifest) Signers(signatures [][]byte) ([]common.Address, error) {
+	hash := m.Hash()
+	signers := make([]common.Address, len(signatures))
+	for i, sig := range signatures {
+		pub, err := crypto.SigToPub(hash.Bytes(), sig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %d: %w", i, err)
+		}
+		signers[i] = crypto.PubkeyToAddress(*pub)
+	}
+	return signers, nil
+}
+
+// Verify checks that the manifest is meant for the codestorage of config on the
+// chain @chainID, and that it is signed by at least config.Threshold distinct
+// governors. Signatures of other keys are ignored.
+func (m *Manifest) Verify(chainID *big.Int, config *params.CryptoUpgradeConfig, signatures [][]byte) error {
+	if config == nil || config.Threshold == 0 || len(config.Governors) == 0 {
+		return errNoGovernance
+	}
+	if chainID == nil || !chainID.IsUint64() || m.ChainID != chainID.Uint64() || m.CodeStorage != config.CodeStorage() {
+		return fmt.Errorf("%w: chain %d codestorage %s", errWrongChain, m.ChainID, m.CodeStorage)
+	}
+	signers, err := m.Signers(signatures)
+	if err != nil {
+		return err
+	}
+	governors := make(map[common.Address]bool, len(config.Governors))
+	for _, addr := range config.Governors {
+		governors[addr] = true
+	}
+	approved := make(map[common.Address]bool)
+	for _, signer := range signers {
+		if governors[signer] {
+			approved[signer] = true
+		}
+	}
+	if uint64(len(approved)) < config.Threshold {
+		return fmt.Errorf("%w: %d of %d signatures", ErrNotApproved, len(approved), config.Threshold)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cryptoupgrade
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestManifestVerify(t *testing.T) {
+	var (
+		keys   []*ecdsa.PrivateKey
+		config = &params.CryptoUpgradeConfig{Threshold: 2}
+	)
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		if i < 3 {
+			config.Governors = append(config.Governors, crypto.PubkeyToAddress(key.PublicKey))
+		}
+	}
+	manifest := &Manifest{
+		Name:        "echo",
+		Digest:      echoHash,
+		IType:       "bytes",
+		OType:       "bytes",
+		Gas:         10,
+		Activation:  100,
+		Author:      common.HexToAddress("0x1234"),
+		ChainID:     1,
+		CodeStorage: config.CodeStorage(),
+	}
+	chainID := big.NewInt(1)
+	sign := func(m *Manifest, signers ...int) [][]byte {
+		var sigs [][]byte
+		for _, i := range signers {
+			sig, err := m.Sign(keys[i])
+			if err != nil {
+				t.Fatalf("sign failed: %v", err)
+			}
+			sigs = append(sigs, sig)
+		}
+		return sigs
+	}
+	if err := manifest.Verify(chainID, config, sign(manifest, 0, 2)); err != nil {
+		t.Fatalf("approved package rejected: %v", err)
+	}
+	// Duplicates and keys outside the governance don't count
+	for _, signers := range [][]int{{0}, {0, 0}, {1, 3}} {
+		if err := manifest.Verify(chainID, config, sign(manifest, signers...)); !errors.Is(err, ErrNotApproved) {
+			t.Errorf("package signed by %v accepted: %v", signers, err)
+		}
+	}
+	// Signatures are bound to every field of the manifest
+	tampered := *manifest
+	tampered.Gas = 1
+	if err := tampered.Verify(chainID, config, sign(manifest, 0, 1, 2)); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("tampered package accepted: %v", err)
+	}
+	if err := manifest.Verify(chainID, config, [][]byte{{1, 2, 3}}); err == nil {
+		t.Error("malformed signature accepted")
+	}
+	if err := manifest.Verify(chainID, nil, sign(manifest, 0, 1, 2)); !errors.Is(err, errNoGovernance) {
+		t.Errorf("package accepted without governance: %v", err)
+	}
+	// Approvals can't be replayed on another chain or codestorage
+	if err := manifest.Verify(big.NewInt(2), config, sign(manifest, 0, 1, 2)); !errors.Is(err, errWrongChain) {
+		t.Errorf("package of another chain accepted: %v", err)
+	}
+	other := common.HexToAddress("0x5678")
+	if err := manifest.Verify(chainID, &params.CryptoUpgradeConfig{Address: &other, Governors: config.Governors, Threshold: 2}, sign(manifest, 0, 1, 2)); !errors.Is(err, errWrongChain) {
+		t.Errorf("package of another codestorage accepted: %v", err)
+	}
+	rebound := *manifest
+	rebound.ChainID = 2
+	if err := rebound.Verify(big.NewInt(2), config, sign(manifest, 0, 1, 2)); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("rebound package accepted: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package cryptoupgrade
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/cryptoupgrade/wasm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Package is a code package ready to be uploaded to codestorage: the compressed
+// module, its manifest and the governor signatures collected so far.
+type Package struct {
+	Manifest
+	Code       string          `json:"code"` // Compressed module, as stored in codestorage
+	Signatures []hexutil.Bytes `json:"signatures"`
+}
+
+// NewPackage compresses the wasm module at @path and wraps it into a package
+// described by manifest. The digest of the manifest is filled in.
+func NewPackage(path string, manifest Manifest) (*Package, error) {
+	code, err := compressFileToString(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := decompressString(code)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Digest = crypto.Keccak256Hash(raw)
+	p := &Package{Manifest: manifest, Code: code}
+	if _, err := p.Module(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// LoadPackage reads a package written by Save.
+func LoadPackage(path string) (*Package, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Package)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("invalid package %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Save writes the package to @path as json.
+func (p *Package) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Module decodes the module of the package, checking it against the digest and
+// the calling convention of the sandbox.
+func (p *Package) Module() (*wasm.Module, error) {
+	module, err := loadModule(p.Code, p.Digest)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := module.Exports[p.Name]; !ok || e.Kind != wasm.ExternFunc {
+		return nil, fmt.Errorf("algorithm module does not export %s", p.Name)
+	}
+	return module, nil
+}
+
+// Sign adds the approval of a governor key to the package.
+func (p *Package) Sign(key *ecdsa.PrivateKey) error {
+	sig, err := p.Manifest.Sign(key)
+	if err != nil {
+		return err
+	}
+	p.Signatures = append(p.Signatures, sig)
+	return nil
+}
+
+// signatures returns the signatures in the form expected by the contract.
+func (p *Package) signatures() [][]byte {
+	sigs := make([][]byte, len(p.Signatures))
+	for i, sig := range p.Signatures {
+		sigs[i] = sig
+	}
+	return sigs
+}
+
+// Approvers returns the addresses that signed the package.
+func (p *Package) Approvers() ([]common.Address, error) {
+	return p.Signers(p.signatures())
+}
+
+// Verify checks the module and the governor approvals of the package, the same
+// way nodes of the chain @chainID do before loading it.
+func (p *Package) Verify(chainID *big.Int, config *params.CryptoUpgradeConfig) error {
+	if _, err := p.Module(); err != nil {
+		return err
+	}
+	return p.Manifest.Verify(chainID, config, p.signatures())
+}
+
+// UploadData returns the calldata of the codestorage uploadCode transaction.
+func (p *Package) UploadData() ([]byte, error) {
+	return CodeStorageABI.Pack("uploadCode", p.Name, p.Code, p.Gas, p.IType, p.OType, p.Activation, p.Digest, p.Author, p.signatures(), p.WordGas)
+}
+
+// PullData returns the calldata of the codestorage pullCode transaction, which
+// announces the package to the nodes.
+func (p *Package) PullData() ([]byte, error) {
+	return CodeStorageABI.Pack("pullCode", p.Name)
+}
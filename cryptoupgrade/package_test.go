@@ -0,0 +1,57 @@
+package cryptoupgrade
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestPackageRoundtrip(t *testing.T) {
+	dir := t.TempDir()
+	module := filepath.Join(dir, "echo.wasm")
+	if err := os.WriteFile(module, echoModule, 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifest := testManifest("echo")
+	manifest.IType, manifest.OType, manifest.Gas, manifest.Activation = "bytes", "bytes", 10, 100
+
+	p, err := NewPackage(module, manifest)
+	if err != nil {
+		t.Fatalf("pack failed: %v", err)
+	}
+	if p.Digest != echoHash {
+		t.Fatalf("digest mismatch: have %x, want %x", p.Digest, echoHash)
+	}
+	key, _ := crypto.GenerateKey()
+	config := testChainConfig(1, key)
+	if err := p.Verify(config.ChainID, config.CryptoUpgrade); !errors.Is(err, ErrNotApproved) {
+		t.Fatalf("unsigned package accepted: %v", err)
+	}
+	if err := p.Sign(key); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	path := filepath.Join(dir, "echo.json")
+	if err := p.Save(path); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	loaded, err := LoadPackage(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if err := loaded.Verify(config.ChainID, config.CryptoUpgrade); err != nil {
+		t.Fatalf("signed package rejected: %v", err)
+	}
+	// Nodes run the package once stored in codestorage
+	cs := newTestCodeStorage(t, loaded)
+	cs.config = config
+	if info, _, err := lookup(cs.state.Copy(), cs.config, "echo", 100); err != nil || info.err != nil {
+		t.Fatalf("package not active: %v", err)
+	}
+	// A package whose entry point isn't exported is refused
+	if _, err := NewPackage(module, Manifest{Name: "missing"}); err == nil {
+		t.Fatal("package without entry point accepted")
+	}
+}
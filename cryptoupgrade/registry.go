@@ -0,0 +1,341 @@
+package cryptoupgrade
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/cryptoupgrade/wasm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+)
+
+const (
+	// Number of decoded modules kept in memory
+	moduleCacheSize = 64
+
+	// Number of governance checks kept in memory
+	approvalCacheSize = 1024
+
+	// Number of blocks requested per filter query while rebuilding the registry
+	syncBatchSize = 10000
+)
+
+var (
+	errUnknownAlgorithm = errors.New("unknown upgrade algorithm")
+
+	// ErrAlgorithmNotActive is returned when an algorithm is called before the
+	// activation block of its first version.
+	ErrAlgorithmNotActive = errors.New("upgrade algorithm not active")
+)
+
+// The caches only hold values derived from their keys, so that every node
+// resolves the same version whatever it has seen before.
+var (
+	modules   = lru.NewCache[moduleKey, *loadedModule](moduleCacheSize)
+	approvals = lru.NewCache[common.Hash, error](approvalCacheSize)
+)
+
+// moduleKey identifies a module by its compressed code, hashed by codestorage,
+// and the digest it is checked against.
+type moduleKey struct {
+	code   common.Hash
+	digest common.Hash
+}
+
+// loadedModule is a decoded module, or the reason it can't be run.
+type loadedModule struct {
+	module *wasm.Module
+	err    error
+}
+
+// lookup resolves the version of @name active at block @number from the state
+// of codestorage: the last pulled version activated by then and approved by the
+// governors of the chain. Versions lacking approval are skipped, versions whose
+// code can't be loaded are returned with the reason calls are rejected. The gas
+// of the storage reads is returned as well, also on failure.
+func lookup(state StateDB, config *params.ChainConfig, name string, number uint64) (*codeInfo, uint64, error) {
+	r := &storageReader{state: state, address: config.CryptoUpgrade.CodeStorage()}
+
+	list := mappingSlot(name, versionsSlot)
+	count := new(uint256.Int).SetBytes(r.read(list).Bytes())
+	if !count.IsUint64() {
+		return nil, r.gas, errInvalidStorage
+	}
+	var (
+		base    = dataSlot(list)
+		pending bool
+	)
+	for i := count.Uint64(); i > 0; i-- {
+		slot := slotOffset(base, (i-1)*versionSlots)
+		if _, _, activation := unpackVersion(r.read(slotOffset(slot, versionPackedSlot))); activation > number {
+			pending = true
+			continue
+		}
+		v, err := r.readVersion(name, slot)
+		if err != nil {
+			return nil, r.gas, err
+		}
+		// Governors approve the package for this chain and codestorage
+		if config.ChainID != nil {
+			v.ChainID = config.ChainID.Uint64()
+		}
+		v.CodeStorage = r.address
+
+		if err := approve(config, v); err != nil {
+			log.Debug("Skipping unapproved upgrade algorithm", "name", name, "activation", v.Activation, "err", err)
+			continue
+		}
+		loaded := load(r, v)
+		return &codeInfo{
+			gas:        v.Gas,
+			wordGas:    v.WordGas,
+			itype:      v.IType,
+			otype:      v.OType,
+			activation: v.Activation,
+			hash:       v.Digest,
+			module:     loaded.module,
+			err:        loaded.err,
+		}, r.gas, nil
+	}
+	if pending {
+		return nil, r.gas, fmt.Errorf("%w: %s at block %d", ErrAlgorithmNotActive, name, number)
+	}
+	return nil, r.gas, fmt.Errorf("%w: %s at block %d", errUnknownAlgorithm, name, number)
+}
+
+// approve checks the governor signatures of a version, caching the outcome.
+func approve(config *params.ChainConfig, v *version) error {
+	governance := config.CryptoUpgrade
+
+	hasher := crypto.NewKeccakState()
+	hasher.Write(v.Hash().Bytes())
+	binary.Write(hasher, binary.BigEndian, governance.Threshold)
+	for _, governor := range governance.Governors {
+		hasher.Write(governor.Bytes())
+	}
+	for _, sig := range v.signatures {
+		hasher.Write(crypto.Keccak256(sig))
+	}
+	var key common.Hash
+	hasher.Read(key[:])
+
+	if err, ok := approvals.Get(key); ok {
+		return err
+	}
+	err := v.Verify(config.ChainID, governance, v.signatures)
+	approvals.Add(key, err)
+	return err
+}
+
+// load returns the decoded module of a version, reading its code from the
+// registry or codestorage unless it is cached. Modules whose code doesn't hash
+// to the declared digest are rejected, instead of falling back to an older
+// version.
+func load(r *storageReader, v *version) *loadedModule {
+	key := moduleKey{code: v.codeKeccak, digest: v.Digest}
+	if loaded, ok := modules.Get(key); ok {
+		return loaded
+	}
+	var (
+		loaded = new(loadedModule)
+		code   []byte
+		err    error
+	)
+	if stored := registry.Load().Code(v.Name, v.Activation, v.codeKeccak); stored != "" {
+		code = []byte(stored)
+	} else {
+		code, err = r.readBytes(v.codeSlot, false)
+	}
+	if err == nil {
+		loaded.module, err = loadModule(string(code), v.Digest)
+	}
+	if err != nil {
+		log.Warn("Invalid upgrade algorithm module", "name", v.Name, "activation", v.Activation, "err", err)
+		loaded.err = err
+	}
+	modules.Add(key, loaded)
+	return loaded
+}
+
+// algorithmRecord is the persisted form of an algorithm version.
+type algorithmRecord struct {
+	Name       string
+	Code       string      // Compressed module, as uploaded to codestorage
+	CodeKeccak common.Hash // Hash of the compressed module, as computed by codestorage
+	Digest     common.Hash
+	Announced  uint64 // Block of the pullcode event announcing the version
+}
+
+// Registry keeps the code of every version of the upgraded algorithms, keyed by
+// name and activation block. It is backed by the chain database and derived from
+// the pullcode events and the state of codestorage, so it survives restarts and
+// can be rebuilt by nodes that sync historical blocks.
+//
+// Codestorage stays the source of truth: the version active at a block is always
+// resolved from its state, the registry only spares reading and decompressing
+// the code of the versions it holds, as long as their hash matches.
+type Registry struct {
+	db ethdb.KeyValueStore
+}
+
+// Registry used by the EVM. It defaults to an in-memory database until the node
+// hands over its chain database through SetRegistry.
+var registry atomic.Pointer[Registry]
+
+func init() {
+	registry.Store(NewRegistry(rawdb.NewMemoryDatabase()))
+}
+
+// SetRegistry replaces the algorithm registry with one backed by db.
+func SetRegistry(db ethdb.KeyValueStore) *Registry {
+	r := NewRegistry(db)
+	registry.Store(r)
+	return r
+}
+
+// NewRegistry creates a registry backed by db.
+func NewRegistry(db ethdb.KeyValueStore) *Registry {
+	return &Registry{db: db}
+}
+
+// read returns the stored version of @name activated at the given block.
+func (r *Registry) read(name string, activation uint64) *algorithmRecord {
+	data := rawdb.ReadUpgradeAlgorithm(r.db, name, activation)
+	if len(data) == 0 {
+		return nil
+	}
+	rec := new(algorithmRecord)
+	if err := rlp.DecodeBytes(data, rec); err != nil {
+		log.Error("Invalid upgrade algorithm record", "name", name, "activation", activation, "err", err)
+		return nil
+	}
+	return rec
+}
+
+// Code returns the compressed module of the version of @name activated at the
+// given block, or an empty string if the registry holds no version with the
+// given code hash.
+func (r *Registry) Code(name string, activation uint64, codeKeccak common.Hash) string {
+	rec := r.read(name, activation)
+	if rec == nil || rec.CodeKeccak != codeKeccak || crypto.Keccak256Hash([]byte(rec.Code)) != codeKeccak {
+		return ""
+	}
+	return rec.Code
+}
+
+// Register stores a version of an algorithm announced in the given block.
+// Packages whose module can't be run by the sandbox are refused.
+func (r *Registry) Register(p *Package, announced uint64) error {
+	if _, err := p.Module(); err != nil {
+		return err
+	}
+	record, err := rlp.EncodeToBytes(&algorithmRecord{
+		Name:       p.Name,
+		Code:       p.Code,
+		CodeKeccak: crypto.Keccak256Hash([]byte(p.Code)),
+		Digest:     p.Digest,
+		Announced:  announced,
+	})
+	if err != nil {
+		return err
+	}
+	rawdb.WriteUpgradeAlgorithm(r.db, p.Name, p.Activation, record)
+	return nil
+}
+
+// Remove deletes the versions of @name announced in the given block, used when
+// the block was reorged out.
+func (r *Registry) Remove(name string, announced uint64) {
+	nameHash := crypto.Keccak256Hash([]byte(name))
+	for _, entry := range rawdb.ReadAllUpgradeAlgorithms(r.db) {
+		if entry.NameHash != nameHash {
+			continue
+		}
+		if rec := r.read(name, entry.Activation); rec != nil && rec.Announced == announced {
+			rawdb.DeleteUpgradeAlgorithm(r.db, name, entry.Activation)
+		}
+	}
+}
+
+// Synced returns the last block whose events have been indexed.
+func (r *Registry) Synced() (uint64, bool) {
+	number := rawdb.ReadUpgradeAlgorithmSynced(r.db)
+	if number == nil {
+		return 0, false
+	}
+	return *number, true
+}
+
+// Sync indexes the pullcode events emitted between the last indexed block and
+// the current head, reading every version from the codestorage state of the
+// block that announced it.
+func (r *Registry) Sync(client client, config *params.ChainConfig) error {
+	head, err := client.BlockNumber(context.Background())
+	if err != nil {
+		return err
+	}
+	from := uint64(0)
+	if synced, ok := r.Synced(); ok {
+		from = synced + 1
+	}
+	for from <= head {
+		to := from + syncBatchSize - 1
+		if to > head {
+			to = head
+		}
+		logs, err := client.FilterLogs(context.Background(), ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{config.CryptoUpgrade.CodeStorage()},
+			Topics:    [][]common.Hash{{pullCodeEventHash}},
+		})
+		if err != nil {
+			return err
+		}
+		for _, l := range logs {
+			r.handleLog(client, config, l)
+		}
+		rawdb.WriteUpgradeAlgorithmSynced(r.db, to)
+		from = to + 1
+	}
+	return nil
+}
+
+// handleLog indexes the version announced by a pullcode event and returns its
+// package, or nil if the event was dropped.
+func (r *Registry) handleLog(client client, config *params.ChainConfig, l types.Log) *Package {
+	var name string
+	if err := CodeStorageABI.UnpackIntoInterface(&name, "pullcode", l.Data); err != nil || name == "" {
+		log.Error("Err in parse name from pullcode event", "block", l.BlockNumber, "err", err)
+		return nil
+	}
+	if l.Removed {
+		log.Info("Removing reorged upgrade algorithm", "name", name, "announced", l.BlockNumber)
+		r.Remove(name, l.BlockNumber)
+		return nil
+	}
+	p, err := lookupCodeInfo(client, config, name, new(big.Int).SetUint64(l.BlockNumber))
+	if err != nil {
+		log.Error("Failed to look up upgrade algorithm", "name", name, "block", l.BlockNumber, "err", err)
+		return nil
+	}
+	if err := r.Register(p, l.BlockNumber); err != nil {
+		log.Error("Failed to register upgrade algorithm", "name", name, "err", err)
+		return nil
+	}
+	log.Info("Registered upgrade algorithm", "name", name, "activation", p.Activation, "hash", p.Digest)
+	return p
+}
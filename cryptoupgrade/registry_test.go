@@ -0,0 +1,133 @@
+package cryptoupgrade
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestLookupVersions(t *testing.T) {
+	unapproved := echoPackage(t, "echo", 4, 0, 25)
+	unapproved.Signatures = nil
+
+	cs := newTestCodeStorage(t,
+		echoPackage(t, "echo", 1, 0, 10),
+		echoPackage(t, "echo", 2, 0, 20),
+		unapproved,
+		echoPackage(t, "echo", 3, 0, 30),
+	)
+	if _, _, err := lookup(cs.state.Copy(), cs.config, "echo", 9); !errors.Is(err, ErrAlgorithmNotActive) {
+		t.Errorf("algorithm active before its first activation: %v", err)
+	}
+	if _, _, err := lookup(cs.state.Copy(), cs.config, "other", 100); !errors.Is(err, errUnknownAlgorithm) {
+		t.Errorf("unknown algorithm found: %v", err)
+	}
+	// Approvals of another chain don't count
+	other := *cs.config
+	other.ChainID = big.NewInt(2)
+	if _, _, err := lookup(cs.state.Copy(), &other, "echo", 100); !errors.Is(err, errUnknownAlgorithm) {
+		t.Errorf("version approved for another chain active: %v", err)
+	}
+	// Versions without the governance approval are skipped
+	for number, gas := range map[uint64]uint64{10: 1, 19: 1, 20: 2, 25: 2, 29: 2, 30: 3, 1000: 3} {
+		info, _, err := lookup(cs.state.Copy(), cs.config, "echo", number)
+		if err != nil {
+			t.Fatalf("lookup at %d failed: %v", number, err)
+		}
+		if info.gas != gas {
+			t.Errorf("wrong version at block %d: have gas %d, want %d", number, info.gas, gas)
+		}
+		if info.module == nil {
+			t.Errorf("module of version at block %d not loaded", number)
+		}
+	}
+}
+
+func TestLookupRejectsInvalidModule(t *testing.T) {
+	code, err := compressBytesToString([]byte("not a module"))
+	if err != nil {
+		t.Fatalf("compress failed: %v", err)
+	}
+	manifest := testManifest("bad")
+	manifest.Digest, manifest.Activation = crypto.Keccak256Hash([]byte("not a module")), 1
+
+	p := &Package{Manifest: manifest, Code: code}
+	if err := p.Sign(testGovernor); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	cs := newTestCodeStorage(t, p)
+	if info, _, err := lookup(cs.state.Copy(), cs.config, "bad", 1); err != nil || info.err == nil {
+		t.Fatalf("invalid module active: %v", err)
+	}
+}
+
+func TestLookupPinsCodeHash(t *testing.T) {
+	mismatch := echoPackage(t, "echo", 10, 0, 20)
+	mismatch.Digest = common.Hash{1}
+	mismatch.Signatures = nil
+	if err := mismatch.Sign(testGovernor); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	cs := newTestCodeStorage(t, echoPackage(t, "echo", 10, 0, 10), mismatch)
+
+	if info, _, err := lookup(cs.state.Copy(), cs.config, "echo", 15); err != nil || info.err != nil {
+		t.Errorf("matching version rejected: %v %v", err, info.err)
+	}
+	// A later version with a wrong hash must not fall back to the first one
+	if info, _, err := lookup(cs.state.Copy(), cs.config, "echo", 25); err != nil || !errors.Is(info.err, ErrCodeHashMismatch) {
+		t.Errorf("mismatching version accepted: %v", err)
+	}
+}
+
+func TestRegistryVersions(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	r := NewRegistry(db)
+	first, second := echoPackage(t, "echo", 1, 0, 10), echoPackage(t, "echo", 2, 0, 20)
+	if err := r.Register(first, 5); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if err := r.Register(second, 15); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	codeKeccak := crypto.Keccak256Hash([]byte(first.Code))
+
+	// A restarted node must see the same versions
+	r = NewRegistry(db)
+	for _, activation := range []uint64{10, 20} {
+		if r.Code("echo", activation, codeKeccak) != first.Code {
+			t.Errorf("version activated at %d missing", activation)
+		}
+	}
+	if r.Code("echo", 10, common.Hash{1}) != "" {
+		t.Error("version returned for another code hash")
+	}
+	// Reorged versions disappear, also from disk
+	r.Remove("echo", 15)
+	if r := NewRegistry(db); r.Code("echo", 20, codeKeccak) != "" || r.Code("echo", 10, codeKeccak) == "" {
+		t.Error("wrong versions removed")
+	}
+}
+
+func TestLookupFromRegistry(t *testing.T) {
+	p := echoPackage(t, "echo", 1, 0, 10)
+	cs := newTestCodeStorage(t, p)
+
+	// Drop the code from codestorage, leaving only the registry to load it from
+	slot := slotOffset(dataSlot(mappingSlot(p.Name, versionsSlot)), versionCodeSlot)
+	cs.state.SetState(cs.config.CryptoUpgrade.CodeStorage(), slot, common.Hash{})
+
+	defer registry.Store(registry.Load())
+	registry.Store(NewRegistry(rawdb.NewMemoryDatabase()))
+	if err := registry.Load().Register(p, 5); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	modules.Purge()
+	info, _, err := lookup(cs.state.Copy(), cs.config, p.Name, 10)
+	if err != nil || info.err != nil {
+		t.Fatalf("registered version not loaded: %v %v", err, info.err)
+	}
+}
@@ -0,0 +1,190 @@
+package cryptoupgrade
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/cryptoupgrade/wasm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Upgrade algorithms are WASM modules executed by the deterministic interpreter
+// in package wasm. A module has to follow this calling convention:
+//   - export its linear memory as "memory"
+//   - export alloc(size i32) i32, which reserves size bytes for the input
+//   - export the algorithm itself under its registered name, with signature
+//     (ptr i32, len i32) i64. It reads the abi encoded input at ptr and returns
+//     the location of the abi encoded output as ptr<<32 | len.
+const (
+	memoryExport = "memory"
+	allocExport  = "alloc"
+
+	// Interpreter fuel bought by one unit of gas
+	fuelPerGas = 10
+)
+
+var (
+	errNoMemory      = errors.New("algorithm module does not export memory")
+	errBadResultType = errors.New("algorithm returned unexpected values")
+
+	errInvalidCall   = errors.New("invalid callFunc call")
+	errInvalidInput  = errors.New("invalid algorithm input")
+	errInvalidOutput = errors.New("invalid algorithm output")
+
+	// ErrOutOfGas is returned when an algorithm call runs out of gas.
+	ErrOutOfGas = errors.New("out of gas")
+
+	// ErrCodeHashMismatch is returned when the code of an algorithm doesn't hash
+	// to the value pinned in codestorage.
+	ErrCodeHashMismatch = errors.New("upgrade algorithm code hash mismatch")
+
+	// Selector of the solidity Error(string) revert reason
+	revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+	// Resource limits of every algorithm invocation
+	sandboxConfig = wasm.DefaultConfig
+)
+
+// loadModule decodes a compressed wasm module fetched from codestorage, after
+// checking that the decompressed module hashes to @hash.
+func loadModule(code string, hash common.Hash) (*wasm.Module, error) {
+	raw, err := decompressString(code)
+	if err != nil {
+		return nil, err
+	}
+	if have := crypto.Keccak256Hash(raw); have != hash {
+		return nil, fmt.Errorf("%w: have %x, want %x", ErrCodeHashMismatch, have, hash)
+	}
+	module, err := wasm.Decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := module.Exports[memoryExport]; !ok || e.Kind != wasm.ExternMemory {
+		return nil, errNoMemory
+	}
+	if e, ok := module.Exports[allocExport]; !ok || e.Kind != wasm.ExternFunc {
+		return nil, fmt.Errorf("algorithm module does not export %s", allocExport)
+	}
+	return module, nil
+}
+
+// runAlgorithm executes the algorithm @name of module over input with the given
+// gas budget. It returns the output and the amount of gas consumed by execution,
+// which is also reported for failed runs.
+func runAlgorithm(module *wasm.Module, name string, input []byte, gas uint64) ([]byte, uint64, error) {
+	fuel := uint64(math.MaxUint64)
+	if gas < math.MaxUint64/fuelPerGas {
+		fuel = gas * fuelPerGas
+	}
+	instance, err := wasm.Instantiate(module, sandboxConfig, fuel)
+	if err != nil {
+		return nil, gas, err
+	}
+	output, err := callInstance(instance, name, input)
+	used := (fuel - instance.Fuel() + fuelPerGas - 1) / fuelPerGas
+	if used > gas {
+		used = gas
+	}
+	return output, used, err
+}
+
+// callInstance copies input into the instance, runs the entry point and reads
+// back its output.
+func callInstance(instance *wasm.Instance, name string, input []byte) ([]byte, error) {
+	ret, err := instance.Call(allocExport, uint64(len(input)))
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) != 1 {
+		return nil, errBadResultType
+	}
+	ptr := uint32(ret[0])
+	if err := instance.WriteMemory(ptr, input); err != nil {
+		return nil, err
+	}
+	ret, err = instance.Call(name, uint64(ptr), uint64(len(input)))
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) != 1 {
+		return nil, errBadResultType
+	}
+	return instance.ReadMemory(uint32(ret[0]>>32), uint32(ret[0]))
+}
+
+// revertReason abi encodes err as a solidity Error(string) revert reason.
+func revertReason(err error) []byte {
+	stringType, _ := abi.NewType("string", "", nil)
+	reason, _ := abi.Arguments{{Type: stringType}}.Pack(err.Error())
+	return append(common.CopyBytes(revertSelector), reason...)
+}
+
+// CallAlgorithm runs the version of upgrade algorithm @funName active at block
+// @number over the abi encoded input, as resolved from the codestorage of the
+// chain in state. It returns the abi encoded output and the remaining gas.
+//
+// Calls follow the rules of precompiled contracts. The storage reads resolving
+// the version and the cost declared in codestorage are charged upfront, then
+// the execution is metered. Running out of gas returns ErrOutOfGas and consumes
+// all gas. Any other error reverts the call: the returned data is the revert
+// reason and the unused gas is returned.
+func CallAlgorithm(state StateDB, config *params.ChainConfig, funName string, number uint64, gas uint64, encodedInput []byte) ([]byte, uint64, error) {
+	funcInfo, lookupGas, err := lookup(state, config, funName, number)
+	if gas < lookupGas {
+		return nil, 0, ErrOutOfGas
+	}
+	gas -= lookupGas
+	if err == nil {
+		err = funcInfo.err
+	}
+	if err != nil {
+		log.Debug("Rejected call to upgrade algorithm", "name", funName, "number", number, "err", err)
+		return revertReason(err), gas, err
+	}
+	cost, overflow := funcInfo.requiredGas(encodedInput)
+	if overflow || gas < cost {
+		return nil, 0, ErrOutOfGas
+	}
+	gas -= cost
+	// Input and output must match the types declared in codestorage
+	inputType, outputType := funcInfo.getTypeList()
+	if _, err := UnpackInput(encodedInput, inputType); err != nil {
+		err = fmt.Errorf("%w: %v", errInvalidInput, err)
+		return revertReason(err), gas, err
+	}
+	encodedOutput, used, err := runAlgorithm(funcInfo.module, funName, encodedInput, gas)
+	if errors.Is(err, wasm.ErrOutOfFuel) {
+		return nil, 0, ErrOutOfGas
+	}
+	gas -= used
+	if err != nil {
+		log.Debug("Upgrade algorithm execution failed", "name", funName, "err", err)
+		return revertReason(err), gas, err
+	}
+	if _, err := UnpackInput(encodedOutput, outputType); err != nil {
+		err = fmt.Errorf("%w: %v", errInvalidOutput, err)
+		return revertReason(err), gas, err
+	}
+	log.Trace("Called upgrade algorithm", "name", funName, "number", number, "gas", lookupGas+cost+used, "output", hexutil.Bytes(encodedOutput))
+	return encodedOutput, gas, nil
+}
+
+// CallFunc runs a callFunc(name, input) call of codestorage, see CallAlgorithm.
+func CallFunc(state StateDB, config *params.ChainConfig, input []byte, number uint64, gas uint64) ([]byte, uint64, error) {
+	args := UnpackCall(input)
+	if len(args) != 2 {
+		return revertReason(errInvalidCall), gas, errInvalidCall
+	}
+	name, ok1 := args[0].(string)
+	params, ok2 := args[1].([]byte)
+	if !ok1 || !ok2 {
+		return revertReason(errInvalidCall), gas, errInvalidCall
+	}
+	return CallAlgorithm(state, config, name, number, gas, params)
+}
@@ -0,0 +1,251 @@
+package cryptoupgrade
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// echoModule is the following module, alloc is a bump allocator, echo returns
+// its input unchanged and spin never terminates.
+//
+//	(module
+//	  (memory (export "memory") 1)
+//	  (global $heap (mut i32) (i32.const 1024))
+//	  (func (export "alloc") (param $size i32) (result i32)
+//	    global.get $heap
+//	    global.get $heap local.get $size i32.add global.set $heap)
+//	  (func (export "echo") (param $ptr i32) (param $len i32) (result i64)
+//	    local.get $ptr i64.extend_i32_u i64.const 32 i64.shl
+//	    local.get $len i64.extend_i32_u i64.or)
+//	  (func (export "spin") (param i32 i32) (result i64)
+//	    (loop (br 0)) i64.const 0))
+var (
+	echoModule = common.FromHex("0061736d01000000010c0260017f017f60027f7f017e03040300010105030100010607017f014180080b072004066d656d6f7279020005616c6c6f630000046563686f0001047370696e00020a24030b002300230020006a24000b0c002000ad4220862001ad840b090003400c000b42000b")
+	echoHash   = crypto.Keccak256Hash(echoModule)
+)
+
+// testGovernor approves the packages of the tests
+var testGovernor, _ = crypto.GenerateKey()
+
+// testChainConfig returns the configuration of the test chain, governed by the
+// given keys.
+func testChainConfig(threshold uint64, governors ...*ecdsa.PrivateKey) *params.ChainConfig {
+	config := &params.CryptoUpgradeConfig{Threshold: threshold}
+	for _, key := range governors {
+		config.Governors = append(config.Governors, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	return &params.ChainConfig{ChainID: big.NewInt(1), CryptoUpgrade: config}
+}
+
+// testManifest returns a manifest of @name bound to the test chain.
+func testManifest(name string) Manifest {
+	return Manifest{Name: name, ChainID: 1, CodeStorage: params.DefaultCodeStorageAddress}
+}
+
+// testCodeStorage is the state of a codestorage holding test packages.
+type testCodeStorage struct {
+	state  *state.StateDB
+	config *params.ChainConfig
+}
+
+// newTestCodeStorage stores the versions of the packages in a fresh state,
+// approved by testGovernor.
+func newTestCodeStorage(t *testing.T, packages ...*Package) *testCodeStorage {
+	t.Helper()
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := testChainConfig(1, testGovernor)
+	for slot, value := range CodeStorageGenesis(common.Address{1}, packages...) {
+		statedb.SetState(config.CryptoUpgrade.CodeStorage(), slot, value)
+	}
+	return &testCodeStorage{state: statedb, config: config}
+}
+
+// call runs an algorithm on a copy of the state, as the first call of a
+// transaction.
+func (s *testCodeStorage) call(name string, number uint64, gas uint64, input []byte) ([]byte, uint64, error) {
+	return CallAlgorithm(s.state.Copy(), s.config, name, number, gas, input)
+}
+
+// lookupGas returns the gas charged for resolving @name at block @number.
+func (s *testCodeStorage) lookupGas(name string, number uint64) uint64 {
+	_, gas, _ := lookup(s.state.Copy(), s.config, name, number)
+	return gas
+}
+
+// echoPackage returns a package of echoModule approved by testGovernor, @name
+// has to be one of its exported algorithms.
+func echoPackage(t *testing.T, name string, gas, wordGas uint64, activation uint64) *Package {
+	t.Helper()
+	code, err := compressBytesToString(echoModule)
+	if err != nil {
+		t.Fatalf("compress failed: %v", err)
+	}
+	manifest := testManifest(name)
+	manifest.Digest, manifest.IType, manifest.OType = echoHash, "bytes", "bytes"
+	manifest.Gas, manifest.WordGas, manifest.Activation = gas, wordGas, activation
+
+	p := &Package{Manifest: manifest, Code: code}
+	if err := p.Sign(testGovernor); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	return p
+}
+
+// installEcho stores a version of echoModule in a fresh codestorage.
+func installEcho(t *testing.T, name string, gas, wordGas uint64, activation uint64) *testCodeStorage {
+	return newTestCodeStorage(t, echoPackage(t, name, gas, wordGas, activation))
+}
+
+func packBytes(t *testing.T, data []byte) []byte {
+	bytesType, _ := abi.NewType("bytes", "", nil)
+	input, err := abi.Arguments{{Type: bytesType}}.Pack(data)
+	if err != nil {
+		t.Fatalf("encode err: %v", err)
+	}
+	return input
+}
+
+func TestCallAlgorithm(t *testing.T) {
+	cs := installEcho(t, "echo", 10, 0, 0)
+	input := packBytes(t, []byte("Hello world!"))
+	budget := cs.lookupGas("echo", 1) + 10000
+
+	output, gas, err := cs.call("echo", 1, budget, input)
+	if err != nil {
+		t.Fatalf("call rejected: %v", err)
+	}
+	if !bytes.Equal(output, input) {
+		t.Fatalf("output mismatch: have %x, want %x", output, input)
+	}
+	if gas >= 10000-10 {
+		t.Fatalf("execution not charged: remaining gas %d", gas)
+	}
+	// Every node must charge exactly the same
+	for i := 0; i < 3; i++ {
+		if _, again, _ := cs.call("echo", 1, budget, input); again != gas {
+			t.Fatalf("gas not deterministic: have %d, want %d", again, gas)
+		}
+	}
+	// Later calls of the transaction read warm slots
+	statedb := cs.state.Copy()
+	if _, first, _ := CallAlgorithm(statedb, cs.config, "echo", 1, budget, input); first != gas {
+		t.Fatalf("gas not deterministic: have %d, want %d", first, gas)
+	}
+	if _, second, _ := CallAlgorithm(statedb, cs.config, "echo", 1, budget, input); second <= gas {
+		t.Fatalf("warm lookup not cheaper: first call left %d, second %d", gas, second)
+	}
+}
+
+func TestCallAlgorithmOutOfGas(t *testing.T) {
+	cs := installEcho(t, "spin", 10, 0, 0)
+	input := packBytes(t, []byte("Hello world!"))
+	lookupGas := cs.lookupGas("spin", 1)
+
+	output, gas, err := cs.call("spin", 1, lookupGas+1000, input)
+	if !errors.Is(err, ErrOutOfGas) || output != nil || gas != 0 {
+		t.Fatalf("non terminating algorithm not stopped: output %x gas %d err %v", output, gas, err)
+	}
+	// Base cost above the available gas must not underflow
+	output, gas, err = cs.call("spin", 1, lookupGas+5, input)
+	if !errors.Is(err, ErrOutOfGas) || output != nil || gas != 0 {
+		t.Fatalf("insufficient base gas accepted: output %x gas %d err %v", output, gas, err)
+	}
+	// Neither may the storage reads
+	output, gas, err = cs.call("spin", 1, lookupGas-1, input)
+	if !errors.Is(err, ErrOutOfGas) || output != nil || gas != 0 {
+		t.Fatalf("insufficient lookup gas accepted: output %x gas %d err %v", output, gas, err)
+	}
+}
+
+func TestCallAlgorithmWordGas(t *testing.T) {
+	cs := installEcho(t, "echo", 10, 100, 0)
+	short := packBytes(t, nil)
+	long := packBytes(t, make([]byte, 320))
+	lookupGas := cs.lookupGas("echo", 1)
+
+	_, shortGas, err := cs.call("echo", 1, lookupGas+100000, short)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	_, longGas, err := cs.call("echo", 1, lookupGas+100000, long)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	// 10 more words of input, the copy into the sandbox costs a bit more as well
+	if diff := shortGas - longGas; diff < 1000 || diff > 1100 {
+		t.Fatalf("input size not charged: short call left %d, long call left %d", shortGas, longGas)
+	}
+	// The word cost alone may exhaust the gas
+	if _, gas, err := cs.call("echo", 1, lookupGas+1000, long); !errors.Is(err, ErrOutOfGas) || gas != 0 {
+		t.Fatalf("word cost not enforced: gas %d err %v", gas, err)
+	}
+}
+
+func TestCallAlgorithmRevert(t *testing.T) {
+	cs := installEcho(t, "echo", 10, 0, 0)
+	lookupGas := cs.lookupGas("echo", 1)
+
+	output, gas, err := cs.call("echo", 1, lookupGas+1000, []byte{1, 2, 3})
+	if !errors.Is(err, errInvalidInput) {
+		t.Fatalf("malformed input not rejected: %v", err)
+	}
+	if gas != 990 {
+		t.Errorf("unused gas not returned: have %d, want 990", gas)
+	}
+	if reason, err := abi.UnpackRevert(output); err != nil || !strings.Contains(reason, "invalid algorithm input") {
+		t.Errorf("unexpected revert reason %q: %v", reason, err)
+	}
+}
+
+func TestCallAlgorithmBeforeActivation(t *testing.T) {
+	cs := installEcho(t, "echo", 10, 0, 100)
+	input := packBytes(t, []byte("Hello world!"))
+
+	for _, number := range []uint64{0, 99} {
+		lookupGas := cs.lookupGas("echo", number)
+		output, gas, err := cs.call("echo", number, lookupGas+10000, input)
+		if !errors.Is(err, ErrAlgorithmNotActive) {
+			t.Fatalf("call at block %d not rejected: %v", number, err)
+		}
+		if gas != 10000 {
+			t.Errorf("rejected call consumed gas: remaining %d", gas)
+		}
+		reason, err := abi.UnpackRevert(output)
+		if err != nil || !strings.Contains(reason, "not active") {
+			t.Errorf("unexpected revert reason %q: %v", reason, err)
+		}
+	}
+	if _, _, err := cs.call("echo", 100, cs.lookupGas("echo", 100)+10000, input); err != nil {
+		t.Fatalf("call at activation rejected: %v", err)
+	}
+}
+
+func TestLoadModuleRejectsSource(t *testing.T) {
+	source, err := os.ReadFile("./compressed.go")
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	code, err := compressBytesToString(source)
+	if err != nil {
+		t.Fatalf("compress failed: %v", err)
+	}
+	if _, err := loadModule(code, crypto.Keccak256Hash(source)); err == nil {
+		t.Fatal("go source accepted as wasm module")
+	}
+}
@@ -0,0 +1,227 @@
+package cryptoupgrade
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// Storage layout of codestorage, see contract/CodeStorage.sol. Nodes read the
+// versions of the algorithms straight from the state of the executed block, the
+// pending uploads in slot 1 are left to the contract.
+const (
+	publisherSlot = 0
+	versionsSlot  = 2
+
+	// Fields of a version, relative to its first slot
+	versionPackedSlot     = 0 // gas, wordGas and activation, from the low order bytes
+	versionAuthorSlot     = 1
+	versionHashSlot       = 2
+	versionCodeKeccakSlot = 3
+	versionITypeSlot      = 4
+	versionOTypeSlot      = 5
+	versionCodeSlot       = 6
+	versionSignaturesSlot = 7
+	versionSlots          = 8
+
+	// Sanity limits of the values read from storage, far above anything the
+	// publisher can store
+	maxStorageBytes = 16 * 1024 * 1024
+	maxSignatures   = 256
+)
+
+var errInvalidStorage = errors.New("invalid codestorage storage")
+
+// StateDB is the part of the EVM state the upgraded algorithms are resolved
+// from. Reads of codestorage are charged like SLOAD under EIP-2929.
+type StateDB interface {
+	GetState(common.Address, common.Hash) common.Hash
+	SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool)
+	AddSlotToAccessList(addr common.Address, slot common.Hash)
+}
+
+// storageReader reads codestorage and sums up the gas of the metered reads.
+type storageReader struct {
+	state   StateDB
+	address common.Address
+	gas     uint64
+}
+
+// read returns a slot of codestorage, charging a cold or warm storage read.
+func (r *storageReader) read(slot common.Hash) common.Hash {
+	if _, warm := r.state.SlotInAccessList(r.address, slot); warm {
+		r.gas += params.WarmStorageReadCostEIP2929
+	} else {
+		r.state.AddSlotToAccessList(r.address, slot)
+		r.gas += params.ColdSloadCostEIP2929
+	}
+	return r.state.GetState(r.address, slot)
+}
+
+// readBytes decodes a solidity string or bytes value stored at slot. The code of
+// modules is read unmetered, as it is only loaded once per node: its cost is
+// part of the gas declared for the algorithm.
+func (r *storageReader) readBytes(slot common.Hash, metered bool) ([]byte, error) {
+	get := r.read
+	if !metered {
+		get = func(slot common.Hash) common.Hash { return r.state.GetState(r.address, slot) }
+	}
+	head := get(slot)
+	if head[31]&1 == 0 {
+		// Short values live in the slot itself, with twice the length in the last byte
+		size := int(head[31] / 2)
+		if size > 31 {
+			return nil, errInvalidStorage
+		}
+		return common.CopyBytes(head[:size]), nil
+	}
+	length := new(uint256.Int).SetBytes(head[:])
+	length.Rsh(length, 1)
+	if !length.IsUint64() || length.Uint64() > maxStorageBytes {
+		return nil, errInvalidStorage
+	}
+	var (
+		size = int(length.Uint64())
+		data = make([]byte, 0, size+31)
+		base = dataSlot(slot)
+	)
+	for i := uint64(0); len(data) < size; i++ {
+		word := get(slotOffset(base, i))
+		data = append(data, word[:]...)
+	}
+	return data[:size], nil
+}
+
+// version is the metadata of a version stored in codestorage.
+type version struct {
+	Manifest
+	codeKeccak common.Hash // Hash of the compressed code, computed by the contract
+	codeSlot   common.Hash
+	signatures [][]byte
+}
+
+// unpackVersion splits the first slot of a version into gas, word gas and
+// activation block.
+func unpackVersion(packed common.Hash) (uint64, uint64, uint64) {
+	return binary.BigEndian.Uint64(packed[24:]), binary.BigEndian.Uint64(packed[16:24]), binary.BigEndian.Uint64(packed[8:16])
+}
+
+// readVersion reads the metadata of the version of @name stored at slot.
+func (r *storageReader) readVersion(name string, slot common.Hash) (*version, error) {
+	v := &version{codeSlot: slotOffset(slot, versionCodeSlot)}
+	v.Name = name
+	v.Gas, v.WordGas, v.Activation = unpackVersion(r.read(slotOffset(slot, versionPackedSlot)))
+	v.Author = common.BytesToAddress(r.read(slotOffset(slot, versionAuthorSlot)).Bytes())
+	v.Digest = r.read(slotOffset(slot, versionHashSlot))
+	v.codeKeccak = r.read(slotOffset(slot, versionCodeKeccakSlot))
+
+	itype, err := r.readBytes(slotOffset(slot, versionITypeSlot), true)
+	if err != nil {
+		return nil, err
+	}
+	otype, err := r.readBytes(slotOffset(slot, versionOTypeSlot), true)
+	if err != nil {
+		return nil, err
+	}
+	v.IType, v.OType = string(itype), string(otype)
+
+	sigSlot := slotOffset(slot, versionSignaturesSlot)
+	count := new(uint256.Int).SetBytes(r.read(sigSlot).Bytes())
+	if !count.IsUint64() || count.Uint64() > maxSignatures {
+		return nil, errInvalidStorage
+	}
+	base := dataSlot(sigSlot)
+	for i := uint64(0); i < count.Uint64(); i++ {
+		sig, err := r.readBytes(slotOffset(base, i), true)
+		if err != nil {
+			return nil, err
+		}
+		v.signatures = append(v.signatures, sig)
+	}
+	return v, nil
+}
+
+// mappingSlot returns the slot of @name in the string keyed mapping at slot.
+func mappingSlot(name string, slot uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte(name), wordHash(slot).Bytes())
+}
+
+// dataSlot returns the first slot of the data of the dynamic value at slot.
+func dataSlot(slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(slot.Bytes())
+}
+
+// wordHash returns n as a storage word.
+func wordHash(n uint64) common.Hash {
+	return uint256.NewInt(n).Bytes32()
+}
+
+// slotOffset returns the slot @offset slots after slot.
+func slotOffset(slot common.Hash, offset uint64) common.Hash {
+	s := new(uint256.Int).SetBytes(slot.Bytes())
+	s.Add(s, uint256.NewInt(offset))
+	return s.Bytes32()
+}
+
+// CodeStorageGenesis returns the storage of codestorage holding the versions of
+// the given packages, to predeploy it in a genesis block with the code
+// CodeStorageCode. Versions of the same algorithm must be in activation order.
+func CodeStorageGenesis(publisher common.Address, packages ...*Package) map[common.Hash]common.Hash {
+	storage := map[common.Hash]common.Hash{
+		wordHash(publisherSlot): common.BytesToHash(publisher.Bytes()),
+	}
+	counts := make(map[string]uint64)
+	for _, p := range packages {
+		list := mappingSlot(p.Name, versionsSlot)
+		slot := slotOffset(dataSlot(list), counts[p.Name]*versionSlots)
+		counts[p.Name]++
+		storage[list] = wordHash(counts[p.Name])
+
+		var packed common.Hash
+		binary.BigEndian.PutUint64(packed[24:], p.Gas)
+		binary.BigEndian.PutUint64(packed[16:24], p.WordGas)
+		binary.BigEndian.PutUint64(packed[8:16], p.Activation)
+		storage[slotOffset(slot, versionPackedSlot)] = packed
+		storage[slotOffset(slot, versionAuthorSlot)] = common.BytesToHash(p.Author.Bytes())
+		storage[slotOffset(slot, versionHashSlot)] = p.Digest
+		storage[slotOffset(slot, versionCodeKeccakSlot)] = crypto.Keccak256Hash([]byte(p.Code))
+		storeBytes(storage, slotOffset(slot, versionITypeSlot), []byte(p.IType))
+		storeBytes(storage, slotOffset(slot, versionOTypeSlot), []byte(p.OType))
+		storeBytes(storage, slotOffset(slot, versionCodeSlot), []byte(p.Code))
+
+		sigSlot := slotOffset(slot, versionSignaturesSlot)
+		storage[sigSlot] = wordHash(uint64(len(p.Signatures)))
+		for i, sig := range p.Signatures {
+			storeBytes(storage, slotOffset(dataSlot(sigSlot), uint64(i)), sig)
+		}
+	}
+	// Drop the zero slots, like the contract does
+	for slot, value := range storage {
+		if value == (common.Hash{}) {
+			delete(storage, slot)
+		}
+	}
+	return storage
+}
+
+// storeBytes encodes a solidity string or bytes value at slot.
+func storeBytes(storage map[common.Hash]common.Hash, slot common.Hash, data []byte) {
+	if len(data) < 32 {
+		var head common.Hash
+		copy(head[:], data)
+		head[31] = byte(len(data) * 2)
+		storage[slot] = head
+		return
+	}
+	storage[slot] = wordHash(uint64(len(data))*2 + 1)
+	base := dataSlot(slot)
+	for i := 0; i*32 < len(data); i++ {
+		var word common.Hash
+		copy(word[:], data[i*32:])
+		storage[slotOffset(base, uint64(i))] = word
+	}
+}
@@ -0,0 +1,722 @@
+// Package wasm implements a small, deterministic WebAssembly interpreter used to
+// run upgraded crypto algorithms inside the node.
+//
+// Only the integer subset of the WebAssembly MVP is supported. Floating point
+// instructions, imports and passive segments are rejected at decode time, so a
+// module can neither observe the host nor behave differently across platforms.
+package wasm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ValueType is a WebAssembly value type. Only integer types are accepted.
+type ValueType byte
+
+const (
+	I32 ValueType = 0x7f
+	I64 ValueType = 0x7e
+)
+
+const (
+	magic   = 0x6d736100 // \0asm
+	version = 1
+
+	pageSize = 65536 // Size of a linear memory page in bytes
+
+	// Limits applied while decoding, to keep a malicious module from making the
+	// node allocate unbounded amounts of memory before execution even starts.
+	maxFunctions = 1 << 16
+	maxLocals    = 1 << 16
+	maxTableSize = 1 << 16
+	maxParams    = 1 << 8
+)
+
+// External kinds used by exports.
+const (
+	ExternFunc   byte = 0x00
+	ExternTable  byte = 0x01
+	ExternMemory byte = 0x02
+	ExternGlobal byte = 0x03
+)
+
+var (
+	ErrInvalidMagic   = errors.New("wasm: invalid magic number")
+	ErrInvalidVersion = errors.New("wasm: unsupported binary version")
+	ErrUnexpectedEOF  = errors.New("wasm: unexpected end of module")
+	ErrImport         = errors.New("wasm: imports are not allowed in sandboxed modules")
+	ErrFloat          = errors.New("wasm: floating point is not allowed in sandboxed modules")
+)
+
+// FuncType is the signature of a function.
+type FuncType struct {
+	Params  []ValueType
+	Results []ValueType
+}
+
+// Function is a function defined in the module.
+type Function struct {
+	Type   uint32      // Index into Module.Types
+	Locals []ValueType // Declared locals, not including parameters
+	Body   []byte      // Instruction stream, terminated by the final end
+
+	blocks map[uint32]block // Structured control info, keyed by the pc of block/loop/if
+}
+
+// block records where the else and end instructions of a structured control
+// instruction are located, so branches don't need to rescan the body.
+type block struct {
+	elsePC uint32 // pc of the else opcode, zero if absent
+	endPC  uint32 // pc of the matching end opcode
+}
+
+// Limits describes the size bounds of a memory or table.
+type Limits struct {
+	Min    uint32
+	Max    uint32
+	HasMax bool
+}
+
+// Global is a module defined global variable.
+type Global struct {
+	Type    ValueType
+	Mutable bool
+	Init    uint64
+}
+
+// Export is an entity made visible to the host.
+type Export struct {
+	Kind  byte
+	Index uint32
+}
+
+// ElemSegment initialises a range of the function table.
+type ElemSegment struct {
+	Offset uint32
+	Funcs  []uint32
+}
+
+// DataSegment initialises a range of linear memory.
+type DataSegment struct {
+	Offset uint32
+	Data   []byte
+}
+
+// Module is a decoded WebAssembly module.
+type Module struct {
+	Types   []FuncType
+	Funcs   []Function
+	Table   *Limits
+	Memory  *Limits
+	Globals []Global
+	Exports map[string]Export
+	Start   *uint32
+	Elems   []ElemSegment
+	Data    []DataSegment
+}
+
+// reader is a cursor over a byte slice with LEB128 helpers.
+type reader struct {
+	buf []byte
+	pos int
+}
+
+func (r *reader) eof() bool { return r.pos >= len(r.buf) }
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, ErrUnexpectedEOF
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n uint32) ([]byte, error) {
+	if uint64(r.pos)+uint64(n) > uint64(len(r.buf)) {
+		return nil, ErrUnexpectedEOF
+	}
+	b := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	v, err := r.uleb(32)
+	return uint32(v), err
+}
+
+func (r *reader) uleb(bits uint) (uint64, error) {
+	var (
+		result uint64
+		shift  uint
+	)
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift >= bits {
+			return 0, errors.New("wasm: integer representation too long")
+		}
+		result |= uint64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			if bits < 64 && result>>bits != 0 {
+				return 0, errors.New("wasm: integer too large")
+			}
+			return result, nil
+		}
+	}
+}
+
+func (r *reader) sleb(bits uint) (int64, error) {
+	var (
+		result int64
+		shift  uint
+		b      byte
+		err    error
+	)
+	for {
+		b, err = r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift >= bits {
+			return 0, errors.New("wasm: integer representation too long")
+		}
+		result |= int64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if shift < 64 && b&0x40 != 0 {
+		result |= -1 << shift
+	}
+	return result, nil
+}
+
+func (r *reader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(n)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errors.New("wasm: invalid utf-8 name")
+	}
+	return string(b), nil
+}
+
+func (r *reader) valueType() (ValueType, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch ValueType(b) {
+	case I32, I64:
+		return ValueType(b), nil
+	case 0x7d, 0x7c:
+		return 0, ErrFloat
+	}
+	return 0, fmt.Errorf("wasm: unsupported value type 0x%x", b)
+}
+
+func (r *reader) limits(max uint32) (*Limits, error) {
+	flag, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	l := new(Limits)
+	if l.Min, err = r.u32(); err != nil {
+		return nil, err
+	}
+	switch flag {
+	case 0x00:
+	case 0x01:
+		if l.Max, err = r.u32(); err != nil {
+			return nil, err
+		}
+		l.HasMax = true
+		if l.Max < l.Min {
+			return nil, errors.New("wasm: limits maximum below minimum")
+		}
+	default:
+		return nil, fmt.Errorf("wasm: unsupported limits flag 0x%x", flag)
+	}
+	if l.Min > max || (l.HasMax && l.Max > max) {
+		return nil, errors.New("wasm: limits exceed allowed size")
+	}
+	return l, nil
+}
+
+// Decode parses a WebAssembly binary into a Module, rejecting every feature the
+// sandbox does not support.
+func Decode(code []byte) (*Module, error) {
+	if len(code) < 8 {
+		return nil, ErrUnexpectedEOF
+	}
+	if binary.LittleEndian.Uint32(code[:4]) != magic {
+		return nil, ErrInvalidMagic
+	}
+	if binary.LittleEndian.Uint32(code[4:8]) != version {
+		return nil, ErrInvalidVersion
+	}
+	var (
+		m       = &Module{Exports: make(map[string]Export)}
+		r       = &reader{buf: code, pos: 8}
+		funcs   []uint32
+		lastID  byte
+		hasCode bool
+	)
+	for !r.eof() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := r.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		if id != 0 {
+			if id <= lastID && !(lastID == 12 && id > 9) {
+				return nil, fmt.Errorf("wasm: section %d out of order", id)
+			}
+			lastID = id
+		}
+		s := &reader{buf: payload}
+		switch id {
+		case 0: // custom section, ignored
+			continue
+		case 1:
+			err = m.decodeTypes(s)
+		case 2:
+			return nil, ErrImport
+		case 3:
+			funcs, err = decodeFunctionIndices(s, len(m.Types))
+		case 4:
+			err = m.decodeTable(s)
+		case 5:
+			err = m.decodeMemory(s)
+		case 6:
+			err = m.decodeGlobals(s)
+		case 7:
+			err = m.decodeExports(s)
+		case 8:
+			var idx uint32
+			if idx, err = s.u32(); err == nil {
+				m.Start = &idx
+			}
+		case 9:
+			err = m.decodeElems(s)
+		case 10:
+			hasCode = true
+			err = m.decodeCode(s, funcs)
+		case 11:
+			err = m.decodeData(s)
+		case 12: // data count, only informational for us
+			_, err = s.u32()
+		default:
+			return nil, fmt.Errorf("wasm: unknown section %d", id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !s.eof() {
+			return nil, fmt.Errorf("wasm: section %d has trailing bytes", id)
+		}
+	}
+	if len(funcs) > 0 && !hasCode {
+		return nil, errors.New("wasm: function and code section count mismatch")
+	}
+	return m, m.validate()
+}
+
+func (m *Module) decodeTypes(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > maxFunctions {
+		return errors.New("wasm: too many types")
+	}
+	m.Types = make([]FuncType, n)
+	for i := range m.Types {
+		form, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if form != 0x60 {
+			return fmt.Errorf("wasm: invalid function type form 0x%x", form)
+		}
+		if m.Types[i].Params, err = decodeValueTypes(r); err != nil {
+			return err
+		}
+		if m.Types[i].Results, err = decodeValueTypes(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeValueTypes(r *reader) ([]ValueType, error) {
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if n > maxParams {
+		return nil, errors.New("wasm: too many parameters")
+	}
+	types := make([]ValueType, n)
+	for i := range types {
+		if types[i], err = r.valueType(); err != nil {
+			return nil, err
+		}
+	}
+	return types, nil
+}
+
+func decodeFunctionIndices(r *reader, types int) ([]uint32, error) {
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if n > maxFunctions {
+		return nil, errors.New("wasm: too many functions")
+	}
+	funcs := make([]uint32, n)
+	for i := range funcs {
+		if funcs[i], err = r.u32(); err != nil {
+			return nil, err
+		}
+		if int(funcs[i]) >= types {
+			return nil, fmt.Errorf("wasm: function %d has unknown type %d", i, funcs[i])
+		}
+	}
+	return funcs, nil
+}
+
+func (m *Module) decodeTable(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > 1 {
+		return errors.New("wasm: at most one table is supported")
+	}
+	if n == 0 {
+		return nil
+	}
+	elemType, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if elemType != 0x70 {
+		return fmt.Errorf("wasm: unsupported table element type 0x%x", elemType)
+	}
+	m.Table, err = r.limits(maxTableSize)
+	return err
+}
+
+func (m *Module) decodeMemory(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > 1 {
+		return errors.New("wasm: at most one memory is supported")
+	}
+	if n == 0 {
+		return nil
+	}
+	m.Memory, err = r.limits(65536)
+	return err
+}
+
+func (m *Module) decodeGlobals(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > maxFunctions {
+		return errors.New("wasm: too many globals")
+	}
+	m.Globals = make([]Global, 0, n)
+	for i := uint32(0); i < n; i++ {
+		var g Global
+		if g.Type, err = r.valueType(); err != nil {
+			return err
+		}
+		mut, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if mut > 1 {
+			return fmt.Errorf("wasm: invalid global mutability 0x%x", mut)
+		}
+		g.Mutable = mut == 1
+		if g.Init, err = m.constExpr(r); err != nil {
+			return err
+		}
+		m.Globals = append(m.Globals, g)
+	}
+	return nil
+}
+
+func (m *Module) decodeExports(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < n; i++ {
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		if _, ok := m.Exports[name]; ok {
+			return fmt.Errorf("wasm: duplicate export %q", name)
+		}
+		var e Export
+		if e.Kind, err = r.byte(); err != nil {
+			return err
+		}
+		if e.Index, err = r.u32(); err != nil {
+			return err
+		}
+		m.Exports[name] = e
+	}
+	return nil
+}
+
+func (m *Module) decodeElems(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < n; i++ {
+		flag, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if flag != 0 {
+			return fmt.Errorf("wasm: unsupported element segment kind %d", flag)
+		}
+		offset, err := m.constExpr(r)
+		if err != nil {
+			return err
+		}
+		count, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if count > maxTableSize {
+			return errors.New("wasm: element segment too large")
+		}
+		seg := ElemSegment{Offset: uint32(offset), Funcs: make([]uint32, count)}
+		for j := range seg.Funcs {
+			if seg.Funcs[j], err = r.u32(); err != nil {
+				return err
+			}
+		}
+		m.Elems = append(m.Elems, seg)
+	}
+	return nil
+}
+
+func (m *Module) decodeCode(r *reader, funcs []uint32) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if int(n) != len(funcs) {
+		return errors.New("wasm: function and code section count mismatch")
+	}
+	m.Funcs = make([]Function, n)
+	for i := range m.Funcs {
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		body, err := r.bytes(size)
+		if err != nil {
+			return err
+		}
+		b := &reader{buf: body}
+		groups, err := b.u32()
+		if err != nil {
+			return err
+		}
+		var locals []ValueType
+		for j := uint32(0); j < groups; j++ {
+			count, err := b.u32()
+			if err != nil {
+				return err
+			}
+			if uint64(len(locals))+uint64(count) > maxLocals {
+				return errors.New("wasm: too many locals")
+			}
+			typ, err := b.valueType()
+			if err != nil {
+				return err
+			}
+			for k := uint32(0); k < count; k++ {
+				locals = append(locals, typ)
+			}
+		}
+		m.Funcs[i] = Function{
+			Type:   funcs[i],
+			Locals: locals,
+			Body:   body[b.pos:],
+		}
+	}
+	return nil
+}
+
+func (m *Module) decodeData(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < n; i++ {
+		flag, err := r.u32()
+		if err != nil {
+			return err
+		}
+		switch flag {
+		case 0:
+		case 2:
+			mem, err := r.u32()
+			if err != nil {
+				return err
+			}
+			if mem != 0 {
+				return fmt.Errorf("wasm: unknown memory %d", mem)
+			}
+		default:
+			return fmt.Errorf("wasm: unsupported data segment kind %d", flag)
+		}
+		offset, err := m.constExpr(r)
+		if err != nil {
+			return err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		data, err := r.bytes(size)
+		if err != nil {
+			return err
+		}
+		m.Data = append(m.Data, DataSegment{Offset: uint32(offset), Data: data})
+	}
+	return nil
+}
+
+// constExpr evaluates an initialiser expression. Only integer constants and
+// reads of previously defined immutable globals are allowed.
+func (m *Module) constExpr(r *reader) (uint64, error) {
+	op, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	switch op {
+	case opI32Const:
+		n, err := r.sleb(32)
+		if err != nil {
+			return 0, err
+		}
+		v = uint64(uint32(n))
+	case opI64Const:
+		n, err := r.sleb(64)
+		if err != nil {
+			return 0, err
+		}
+		v = uint64(n)
+	case opGlobalGet:
+		idx, err := r.u32()
+		if err != nil {
+			return 0, err
+		}
+		if int(idx) >= len(m.Globals) || m.Globals[idx].Mutable {
+			return 0, fmt.Errorf("wasm: invalid global %d in constant expression", idx)
+		}
+		v = m.Globals[idx].Init
+	default:
+		return 0, fmt.Errorf("wasm: unsupported constant expression opcode 0x%x", op)
+	}
+	end, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	if end != opEnd {
+		return 0, errors.New("wasm: constant expression not terminated")
+	}
+	return v, nil
+}
+
+// validate checks cross references between sections and precomputes the
+// control structure of every function body.
+func (m *Module) validate() error {
+	for name, e := range m.Exports {
+		switch e.Kind {
+		case ExternFunc:
+			if int(e.Index) >= len(m.Funcs) {
+				return fmt.Errorf("wasm: export %q references unknown function", name)
+			}
+		case ExternMemory:
+			if m.Memory == nil || e.Index != 0 {
+				return fmt.Errorf("wasm: export %q references unknown memory", name)
+			}
+		case ExternTable:
+			if m.Table == nil || e.Index != 0 {
+				return fmt.Errorf("wasm: export %q references unknown table", name)
+			}
+		case ExternGlobal:
+			if int(e.Index) >= len(m.Globals) {
+				return fmt.Errorf("wasm: export %q references unknown global", name)
+			}
+		default:
+			return fmt.Errorf("wasm: export %q has unknown kind %d", name, e.Kind)
+		}
+	}
+	if m.Start != nil {
+		if int(*m.Start) >= len(m.Funcs) {
+			return errors.New("wasm: unknown start function")
+		}
+		typ := m.Types[m.Funcs[*m.Start].Type]
+		if len(typ.Params) != 0 || len(typ.Results) != 0 {
+			return errors.New("wasm: start function must have an empty signature")
+		}
+	}
+	if len(m.Elems) > 0 && m.Table == nil {
+		return errors.New("wasm: element segment without table")
+	}
+	for _, seg := range m.Elems {
+		for _, idx := range seg.Funcs {
+			if int(idx) >= len(m.Funcs) {
+				return fmt.Errorf("wasm: element segment references unknown function %d", idx)
+			}
+		}
+	}
+	if len(m.Data) > 0 && m.Memory == nil {
+		return errors.New("wasm: data segment without memory")
+	}
+	for i := range m.Funcs {
+		if err := m.scan(&m.Funcs[i]); err != nil {
+			return fmt.Errorf("wasm: function %d: %w", i, err)
+		}
+	}
+	return nil
+}
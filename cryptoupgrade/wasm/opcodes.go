@@ -0,0 +1,326 @@
+package wasm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Opcodes of the integer subset of the WebAssembly MVP, plus the sign-extension
+// and bulk memory copy/fill extensions emitted by current toolchains.
+const (
+	opUnreachable  = 0x00
+	opNop          = 0x01
+	opBlock        = 0x02
+	opLoop         = 0x03
+	opIf           = 0x04
+	opElse         = 0x05
+	opEnd          = 0x0b
+	opBr           = 0x0c
+	opBrIf         = 0x0d
+	opBrTable      = 0x0e
+	opReturn       = 0x0f
+	opCall         = 0x10
+	opCallIndirect = 0x11
+
+	opDrop         = 0x1a
+	opSelect       = 0x1b
+	opSelectTyped  = 0x1c
+	opLocalGet     = 0x20
+	opLocalSet     = 0x21
+	opLocalTee     = 0x22
+	opGlobalGet    = 0x23
+	opGlobalSet    = 0x24
+	opI32Load      = 0x28
+	opI64Load      = 0x29
+	opI32Load8S    = 0x2c
+	opI32Load8U    = 0x2d
+	opI32Load16S   = 0x2e
+	opI32Load16U   = 0x2f
+	opI64Load8S    = 0x30
+	opI64Load8U    = 0x31
+	opI64Load16S   = 0x32
+	opI64Load16U   = 0x33
+	opI64Load32S   = 0x34
+	opI64Load32U   = 0x35
+	opI32Store     = 0x36
+	opI64Store     = 0x37
+	opI32Store8    = 0x3a
+	opI32Store16   = 0x3b
+	opI64Store8    = 0x3c
+	opI64Store16   = 0x3d
+	opI64Store32   = 0x3e
+	opMemorySize   = 0x3f
+	opMemoryGrow   = 0x40
+	opI32Const     = 0x41
+	opI64Const     = 0x42
+	opI32Eqz       = 0x45
+	opI32Eq        = 0x46
+	opI32Ne        = 0x47
+	opI32LtS       = 0x48
+	opI32LtU       = 0x49
+	opI32GtS       = 0x4a
+	opI32GtU       = 0x4b
+	opI32LeS       = 0x4c
+	opI32LeU       = 0x4d
+	opI32GeS       = 0x4e
+	opI32GeU       = 0x4f
+	opI64Eqz       = 0x50
+	opI64Eq        = 0x51
+	opI64Ne        = 0x52
+	opI64LtS       = 0x53
+	opI64LtU       = 0x54
+	opI64GtS       = 0x55
+	opI64GtU       = 0x56
+	opI64LeS       = 0x57
+	opI64LeU       = 0x58
+	opI64GeS       = 0x59
+	opI64GeU       = 0x5a
+	opI32Clz       = 0x67
+	opI32Ctz       = 0x68
+	opI32Popcnt    = 0x69
+	opI32Add       = 0x6a
+	opI32Sub       = 0x6b
+	opI32Mul       = 0x6c
+	opI32DivS      = 0x6d
+	opI32DivU      = 0x6e
+	opI32RemS      = 0x6f
+	opI32RemU      = 0x70
+	opI32And       = 0x71
+	opI32Or        = 0x72
+	opI32Xor       = 0x73
+	opI32Shl       = 0x74
+	opI32ShrS      = 0x75
+	opI32ShrU      = 0x76
+	opI32Rotl      = 0x77
+	opI32Rotr      = 0x78
+	opI64Clz       = 0x79
+	opI64Ctz       = 0x7a
+	opI64Popcnt    = 0x7b
+	opI64Add       = 0x7c
+	opI64Sub       = 0x7d
+	opI64Mul       = 0x7e
+	opI64DivS      = 0x7f
+	opI64DivU      = 0x80
+	opI64RemS      = 0x81
+	opI64RemU      = 0x82
+	opI64And       = 0x83
+	opI64Or        = 0x84
+	opI64Xor       = 0x85
+	opI64Shl       = 0x86
+	opI64ShrS      = 0x87
+	opI64ShrU      = 0x88
+	opI64Rotl      = 0x89
+	opI64Rotr      = 0x8a
+	opI32WrapI64   = 0xa7
+	opI64ExtendS32 = 0xac
+	opI64ExtendU32 = 0xad
+	opI32Extend8S  = 0xc0
+	opI32Extend16S = 0xc1
+	opI64Extend8S  = 0xc2
+	opI64Extend16S = 0xc3
+	opI64Extend32S = 0xc4
+	opPrefixFC     = 0xfc
+
+	opMemoryCopy = 10 // 0xfc sub-opcode
+	opMemoryFill = 11 // 0xfc sub-opcode
+)
+
+// isFloatOp reports whether the opcode belongs to the floating point part of
+// the instruction set, which is never accepted by the sandbox.
+func isFloatOp(op byte) bool {
+	switch {
+	case op == 0x2a || op == 0x2b || op == 0x38 || op == 0x39: // f32/f64 load and store
+		return true
+	case op == 0x43 || op == 0x44: // f32/f64 const
+		return true
+	case op >= 0x5b && op <= 0x66: // f32/f64 comparisons
+		return true
+	case op >= 0x8b && op <= 0xa6: // f32/f64 arithmetic
+		return true
+	case op >= 0xa8 && op <= 0xab, op >= 0xae && op <= 0xbf: // conversions touching floats
+		return true
+	}
+	return false
+}
+
+// blockType decodes the block type immediate of block, loop and if, returning
+// the number of parameters and results of the block.
+func (m *Module) blockType(r *reader) (params int, results int, err error) {
+	if r.pos >= len(r.buf) {
+		return 0, 0, ErrUnexpectedEOF
+	}
+	switch b := r.buf[r.pos]; {
+	case b == 0x40:
+		r.pos++
+		return 0, 0, nil
+	case ValueType(b) == I32 || ValueType(b) == I64:
+		r.pos++
+		return 0, 1, nil
+	case b == 0x7d || b == 0x7c:
+		return 0, 0, ErrFloat
+	}
+	idx, err := r.sleb(33)
+	if err != nil {
+		return 0, 0, err
+	}
+	if idx < 0 || int(idx) >= len(m.Types) {
+		return 0, 0, fmt.Errorf("unknown block type %d", idx)
+	}
+	typ := m.Types[idx]
+	return len(typ.Params), len(typ.Results), nil
+}
+
+// scan walks a function body once, checking that every instruction is
+// supported and well formed and recording the else/end positions of every
+// structured control instruction.
+func (m *Module) scan(fn *Function) error {
+	var (
+		r      = &reader{buf: fn.Body}
+		open   []uint32
+		locals = uint32(len(m.Types[fn.Type].Params) + len(fn.Locals))
+	)
+	fn.blocks = make(map[uint32]block)
+	for !r.eof() {
+		pc := uint32(r.pos)
+		op, _ := r.byte()
+		if isFloatOp(op) {
+			return ErrFloat
+		}
+		var err error
+		switch op {
+		case opBlock, opLoop, opIf:
+			if _, _, err = m.blockType(r); err != nil {
+				return err
+			}
+			open = append(open, pc)
+			fn.blocks[pc] = block{}
+		case opElse:
+			if len(open) == 0 || fn.Body[open[len(open)-1]] != opIf {
+				return errors.New("else without if")
+			}
+			start := open[len(open)-1]
+			b := fn.blocks[start]
+			if b.elsePC != 0 {
+				return errors.New("duplicate else")
+			}
+			b.elsePC = pc
+			fn.blocks[start] = b
+		case opEnd:
+			if len(open) == 0 {
+				if !r.eof() {
+					return errors.New("instructions after function end")
+				}
+				return nil
+			}
+			start := open[len(open)-1]
+			open = open[:len(open)-1]
+			b := fn.blocks[start]
+			b.endPC = pc
+			fn.blocks[start] = b
+		case opBr, opBrIf:
+			var depth uint32
+			if depth, err = r.u32(); err == nil && int(depth) > len(open) {
+				err = fmt.Errorf("branch depth %d out of range", depth)
+			}
+		case opBrTable:
+			var n uint32
+			if n, err = r.u32(); err != nil {
+				return err
+			}
+			if n > maxTableSize {
+				return errors.New("branch table too large")
+			}
+			for i := uint32(0); i <= n && err == nil; i++ {
+				var depth uint32
+				if depth, err = r.u32(); err == nil && int(depth) > len(open) {
+					err = fmt.Errorf("branch depth %d out of range", depth)
+				}
+			}
+		case opCall:
+			var idx uint32
+			if idx, err = r.u32(); err == nil && int(idx) >= len(m.Funcs) {
+				err = fmt.Errorf("call to unknown function %d", idx)
+			}
+		case opCallIndirect:
+			var idx uint32
+			if idx, err = r.u32(); err == nil && int(idx) >= len(m.Types) {
+				err = fmt.Errorf("call_indirect with unknown type %d", idx)
+			}
+			if err == nil {
+				var table byte
+				if table, err = r.byte(); err == nil && (table != 0 || m.Table == nil) {
+					err = errors.New("call_indirect without table")
+				}
+			}
+		case opSelectTyped:
+			var n uint32
+			if n, err = r.u32(); err == nil && n != 1 {
+				err = errors.New("typed select must have one type")
+			}
+			if err == nil {
+				_, err = r.valueType()
+			}
+		case opLocalGet, opLocalSet, opLocalTee:
+			var idx uint32
+			if idx, err = r.u32(); err == nil && idx >= locals {
+				err = fmt.Errorf("unknown local %d", idx)
+			}
+		case opGlobalGet, opGlobalSet:
+			var idx uint32
+			if idx, err = r.u32(); err == nil && int(idx) >= len(m.Globals) {
+				err = fmt.Errorf("unknown global %d", idx)
+			}
+			if err == nil && op == opGlobalSet && !m.Globals[idx].Mutable {
+				err = fmt.Errorf("write to immutable global %d", idx)
+			}
+		case opMemorySize, opMemoryGrow:
+			var mem byte
+			if mem, err = r.byte(); err == nil && (mem != 0 || m.Memory == nil) {
+				err = errors.New("memory instruction without memory")
+			}
+		case opI32Const:
+			_, err = r.sleb(32)
+		case opI64Const:
+			_, err = r.sleb(64)
+		case opPrefixFC:
+			var sub uint32
+			if sub, err = r.u32(); err != nil {
+				return err
+			}
+			if m.Memory == nil {
+				return errors.New("memory instruction without memory")
+			}
+			switch sub {
+			case opMemoryCopy:
+				_, err = r.bytes(2)
+			case opMemoryFill:
+				_, err = r.bytes(1)
+			case 0, 1, 2, 3, 4, 5, 6, 7:
+				return ErrFloat
+			default:
+				return fmt.Errorf("unsupported instruction 0xfc %d", sub)
+			}
+		default:
+			switch {
+			case op >= opI32Load && op <= opI64Store32:
+				if m.Memory == nil {
+					return errors.New("memory instruction without memory")
+				}
+				if _, err = r.u32(); err == nil { // alignment hint
+					_, err = r.u32() // offset
+				}
+			case op == opUnreachable, op == opNop, op == opReturn, op == opDrop, op == opSelect:
+			case op >= opI32Eqz && op <= opI64GeU:
+			case op >= opI32Clz && op <= opI64Rotr:
+			case op == opI32WrapI64, op == opI64ExtendS32, op == opI64ExtendU32:
+			case op >= opI32Extend8S && op <= opI64Extend32S:
+			default:
+				return fmt.Errorf("unsupported instruction 0x%x", op)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return errors.New("function body not terminated")
+}
@@ -0,0 +1,723 @@
+package wasm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Config bounds the resources a single instance may use.
+type Config struct {
+	MaxMemoryPages uint32 // Upper bound on linear memory, on top of the module's own maximum
+	MaxCallDepth   int    // Maximum nesting of wasm function calls
+	MaxStackHeight int    // Maximum number of values on the operand stack
+}
+
+// DefaultConfig allows 16MiB of linear memory and a call depth matching the EVM.
+var DefaultConfig = Config{
+	MaxMemoryPages: 256,
+	MaxCallDepth:   1024,
+	MaxStackHeight: 1 << 16,
+}
+
+// Fuel charged for the different kinds of work performed by the interpreter.
+const (
+	FuelInstruction uint64 = 1    // Every executed instruction
+	FuelCall        uint64 = 8    // Additional cost of entering a function
+	FuelPage        uint64 = 4096 // Every page of memory, at instantiation or growth
+	FuelByte        uint64 = 1    // Every 32 bytes touched by memory.copy and memory.fill, and per data byte
+)
+
+var (
+	ErrOutOfFuel          = errors.New("wasm: out of fuel")
+	ErrUnreachable        = errors.New("wasm: unreachable executed")
+	ErrMemoryOutOfBounds  = errors.New("wasm: out of bounds memory access")
+	ErrMemoryLimit        = errors.New("wasm: memory limit exceeded")
+	ErrCallStackExhausted = errors.New("wasm: call stack exhausted")
+	ErrStackOverflow      = errors.New("wasm: operand stack overflow")
+	ErrDivideByZero       = errors.New("wasm: integer divide by zero")
+	ErrIntegerOverflow    = errors.New("wasm: integer overflow")
+	ErrIndirectCall       = errors.New("wasm: invalid indirect call")
+	ErrTableOutOfBounds   = errors.New("wasm: table index out of bounds")
+	ErrExportNotFound     = errors.New("wasm: exported function not found")
+	ErrSignature          = errors.New("wasm: argument count mismatch")
+	ErrTrap               = errors.New("wasm: trap")
+)
+
+// trap is used to unwind the interpreter from deep inside an instruction.
+type trap struct{ err error }
+
+// Instance is an instantiated module. An instance is not safe for concurrent
+// use and is meant to be thrown away after a single algorithm invocation.
+type Instance struct {
+	module   *Module
+	config   Config
+	memory   []byte
+	maxPages uint32
+	table    []uint32 // Function index plus one, zero marks an empty slot
+	globals  []uint64
+	stack    []uint64
+	depth    int
+	fuel     uint64
+}
+
+// Instantiate creates a new instance of the module with the given fuel budget,
+// initialises memory, table and globals and runs the start function.
+func Instantiate(m *Module, config Config, fuel uint64) (in *Instance, err error) {
+	in = &Instance{
+		module: m,
+		config: config,
+		fuel:   fuel,
+		stack:  make([]uint64, 0, 64),
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			in, err = nil, recovered(r)
+		}
+	}()
+	if m.Memory != nil {
+		in.maxPages = config.MaxMemoryPages
+		if m.Memory.HasMax && m.Memory.Max < in.maxPages {
+			in.maxPages = m.Memory.Max
+		}
+		if m.Memory.Min > in.maxPages {
+			return nil, ErrMemoryLimit
+		}
+		in.useFuel(uint64(m.Memory.Min) * FuelPage)
+		in.memory = make([]byte, int(m.Memory.Min)*pageSize)
+	}
+	for _, seg := range m.Data {
+		in.useFuel(uint64(len(seg.Data)) * FuelByte)
+		if uint64(seg.Offset)+uint64(len(seg.Data)) > uint64(len(in.memory)) {
+			return nil, ErrMemoryOutOfBounds
+		}
+		copy(in.memory[seg.Offset:], seg.Data)
+	}
+	if m.Table != nil {
+		in.table = make([]uint32, m.Table.Min)
+	}
+	for _, seg := range m.Elems {
+		if uint64(seg.Offset)+uint64(len(seg.Funcs)) > uint64(len(in.table)) {
+			return nil, ErrTableOutOfBounds
+		}
+		for i, idx := range seg.Funcs {
+			in.table[int(seg.Offset)+i] = idx + 1
+		}
+	}
+	in.globals = make([]uint64, len(m.Globals))
+	for i, g := range m.Globals {
+		in.globals[i] = g.Init
+	}
+	if m.Start != nil {
+		in.invoke(*m.Start)
+	}
+	return in, nil
+}
+
+// Call invokes the exported function with the given arguments. Integer
+// arguments and results are passed as raw 64 bit patterns, i32 values in the
+// low 32 bits.
+func (in *Instance) Call(name string, args ...uint64) (results []uint64, err error) {
+	export, ok := in.module.Exports[name]
+	if !ok || export.Kind != ExternFunc {
+		return nil, fmt.Errorf("%w: %s", ErrExportNotFound, name)
+	}
+	typ := in.module.Types[in.module.Funcs[export.Index].Type]
+	if len(args) != len(typ.Params) {
+		return nil, ErrSignature
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			results, err = nil, recovered(r)
+		}
+	}()
+	in.stack = append(in.stack[:0], args...)
+	in.invoke(export.Index)
+	results = make([]uint64, len(typ.Results))
+	copy(results, in.stack)
+	return results, nil
+}
+
+// Fuel returns the remaining fuel of the instance.
+func (in *Instance) Fuel() uint64 { return in.fuel }
+
+// Memory returns the linear memory of the instance.
+func (in *Instance) Memory() []byte { return in.memory }
+
+// ReadMemory returns a copy of the memory range [ptr, ptr+size).
+func (in *Instance) ReadMemory(ptr, size uint32) ([]byte, error) {
+	if uint64(ptr)+uint64(size) > uint64(len(in.memory)) {
+		return nil, ErrMemoryOutOfBounds
+	}
+	out := make([]byte, size)
+	copy(out, in.memory[ptr:])
+	return out, nil
+}
+
+// WriteMemory copies data into memory starting at ptr.
+func (in *Instance) WriteMemory(ptr uint32, data []byte) error {
+	if uint64(ptr)+uint64(len(data)) > uint64(len(in.memory)) {
+		return ErrMemoryOutOfBounds
+	}
+	copy(in.memory[ptr:], data)
+	return nil
+}
+
+// recovered converts a panic raised during execution into an error. Traps
+// carry their own error, anything else (e.g. a malformed module underflowing
+// the operand stack) is reported as a generic trap instead of crashing the node.
+func recovered(r interface{}) error {
+	if t, ok := r.(trap); ok {
+		return t.err
+	}
+	return fmt.Errorf("%w: %v", ErrTrap, r)
+}
+
+func (in *Instance) fail(err error) {
+	panic(trap{err})
+}
+
+func (in *Instance) useFuel(amount uint64) {
+	if in.fuel < amount {
+		in.fuel = 0
+		in.fail(ErrOutOfFuel)
+	}
+	in.fuel -= amount
+}
+
+func (in *Instance) push(v uint64) {
+	if len(in.stack) >= in.config.MaxStackHeight {
+		in.fail(ErrStackOverflow)
+	}
+	in.stack = append(in.stack, v)
+}
+
+func (in *Instance) pop() uint64 {
+	v := in.stack[len(in.stack)-1]
+	in.stack = in.stack[:len(in.stack)-1]
+	return v
+}
+
+func (in *Instance) push32(v uint32) { in.push(uint64(v)) }
+func (in *Instance) pop32() uint32   { return uint32(in.pop()) }
+
+func (in *Instance) pushBool(b bool) {
+	if b {
+		in.push(1)
+	} else {
+		in.push(0)
+	}
+}
+
+// invoke calls the function with the given index. Its arguments are taken
+// from the operand stack and its results are left there.
+func (in *Instance) invoke(idx uint32) {
+	if in.depth >= in.config.MaxCallDepth {
+		in.fail(ErrCallStackExhausted)
+	}
+	in.useFuel(FuelCall)
+	in.depth++
+	defer func() { in.depth-- }()
+
+	var (
+		fn  = &in.module.Funcs[idx]
+		typ = &in.module.Types[fn.Type]
+		np  = len(typ.Params)
+	)
+	locals := make([]uint64, np+len(fn.Locals))
+	copy(locals, in.stack[len(in.stack)-np:])
+	in.stack = in.stack[:len(in.stack)-np]
+
+	base := len(in.stack)
+	in.execute(fn, locals)
+
+	nr := len(typ.Results)
+	copy(in.stack[base:], in.stack[len(in.stack)-nr:])
+	in.stack = in.stack[:base+nr]
+}
+
+// label is an entry of the control stack of a running function.
+type label struct {
+	pc     uint32 // Branch target: loop body start, or the end of a block/if
+	arity  int    // Number of values transferred by a branch
+	height int    // Operand stack height at block entry
+	loop   bool
+}
+
+// branch unwinds the control stack to the label at the given depth. It returns
+// true if the branch targets the function body, i.e. acts as a return.
+func (in *Instance) branch(r *reader, labels *[]label, depth uint32) bool {
+	ls := *labels
+	if int(depth) == len(ls) {
+		return true
+	}
+	l := ls[len(ls)-1-int(depth)]
+	copy(in.stack[l.height:], in.stack[len(in.stack)-l.arity:])
+	in.stack = in.stack[:l.height+l.arity]
+	if l.loop {
+		*labels = ls[:len(ls)-int(depth)]
+		r.pos = int(l.pc)
+	} else {
+		*labels = ls[:len(ls)-1-int(depth)]
+		r.pos = int(l.pc) + 1
+	}
+	return false
+}
+
+// address pops the base address of a memory access, applies the static offset
+// and checks that size bytes are accessible.
+func (in *Instance) address(r *reader, size uint64) uint64 {
+	r.u32() // alignment hint, irrelevant for the interpreter
+	offset, _ := r.u32()
+	ea := uint64(in.pop32()) + uint64(offset)
+	if ea+size > uint64(len(in.memory)) {
+		in.fail(ErrMemoryOutOfBounds)
+	}
+	return ea
+}
+
+// execute runs the body of fn until its final end or a return.
+func (in *Instance) execute(fn *Function, locals []uint64) {
+	var (
+		r      = &reader{buf: fn.Body}
+		labels = make([]label, 0, 8)
+		le     = binary.LittleEndian
+	)
+	for {
+		in.useFuel(FuelInstruction)
+
+		pc := uint32(r.pos)
+		op := r.buf[r.pos]
+		r.pos++
+
+		switch op {
+		case opUnreachable:
+			in.fail(ErrUnreachable)
+		case opNop:
+
+		case opBlock, opLoop, opIf:
+			params, results, _ := in.module.blockType(r)
+			b := fn.blocks[pc]
+			l := label{pc: b.endPC, arity: results, loop: op == opLoop}
+			if l.loop {
+				l.pc, l.arity = uint32(r.pos), params
+			}
+			if op == opIf && in.pop32() == 0 {
+				if b.elsePC == 0 {
+					r.pos = int(b.endPC) + 1
+					continue
+				}
+				r.pos = int(b.elsePC) + 1
+			}
+			l.height = len(in.stack) - params
+			labels = append(labels, l)
+
+		case opElse:
+			// Reached the end of the then arm, skip the else arm.
+			l := labels[len(labels)-1]
+			labels = labels[:len(labels)-1]
+			r.pos = int(l.pc) + 1
+
+		case opEnd:
+			if len(labels) == 0 {
+				return
+			}
+			labels = labels[:len(labels)-1]
+
+		case opBr:
+			depth, _ := r.u32()
+			if in.branch(r, &labels, depth) {
+				return
+			}
+		case opBrIf:
+			depth, _ := r.u32()
+			if in.pop32() != 0 && in.branch(r, &labels, depth) {
+				return
+			}
+		case opBrTable:
+			n, _ := r.u32()
+			idx := in.pop32()
+			var depth uint32
+			for i := uint32(0); i <= n; i++ {
+				d, _ := r.u32()
+				if i == idx || i == n {
+					depth = d
+					break
+				}
+			}
+			if in.branch(r, &labels, depth) {
+				return
+			}
+		case opReturn:
+			return
+
+		case opCall:
+			idx, _ := r.u32()
+			in.invoke(idx)
+		case opCallIndirect:
+			typeIdx, _ := r.u32()
+			r.byte() // table index, always zero
+			elem := in.pop32()
+			if uint64(elem) >= uint64(len(in.table)) {
+				in.fail(ErrTableOutOfBounds)
+			}
+			target := in.table[elem]
+			if target == 0 {
+				in.fail(ErrIndirectCall)
+			}
+			if !sameType(in.module.Types[in.module.Funcs[target-1].Type], in.module.Types[typeIdx]) {
+				in.fail(ErrIndirectCall)
+			}
+			in.invoke(target - 1)
+
+		case opDrop:
+			in.pop()
+		case opSelect, opSelectTyped:
+			if op == opSelectTyped {
+				r.u32()
+				r.byte()
+			}
+			cond := in.pop32()
+			b, a := in.pop(), in.pop()
+			if cond != 0 {
+				in.push(a)
+			} else {
+				in.push(b)
+			}
+
+		case opLocalGet:
+			idx, _ := r.u32()
+			in.push(locals[idx])
+		case opLocalSet:
+			idx, _ := r.u32()
+			locals[idx] = in.pop()
+		case opLocalTee:
+			idx, _ := r.u32()
+			locals[idx] = in.stack[len(in.stack)-1]
+		case opGlobalGet:
+			idx, _ := r.u32()
+			in.push(in.globals[idx])
+		case opGlobalSet:
+			idx, _ := r.u32()
+			in.globals[idx] = in.pop()
+
+		case opI32Load:
+			in.push32(le.Uint32(in.memory[in.address(r, 4):]))
+		case opI64Load:
+			in.push(le.Uint64(in.memory[in.address(r, 8):]))
+		case opI32Load8S:
+			in.push32(uint32(int32(int8(in.memory[in.address(r, 1)]))))
+		case opI32Load8U:
+			in.push32(uint32(in.memory[in.address(r, 1)]))
+		case opI32Load16S:
+			in.push32(uint32(int32(int16(le.Uint16(in.memory[in.address(r, 2):])))))
+		case opI32Load16U:
+			in.push32(uint32(le.Uint16(in.memory[in.address(r, 2):])))
+		case opI64Load8S:
+			in.push(uint64(int64(int8(in.memory[in.address(r, 1)]))))
+		case opI64Load8U:
+			in.push(uint64(in.memory[in.address(r, 1)]))
+		case opI64Load16S:
+			in.push(uint64(int64(int16(le.Uint16(in.memory[in.address(r, 2):])))))
+		case opI64Load16U:
+			in.push(uint64(le.Uint16(in.memory[in.address(r, 2):])))
+		case opI64Load32S:
+			in.push(uint64(int64(int32(le.Uint32(in.memory[in.address(r, 4):])))))
+		case opI64Load32U:
+			in.push(uint64(le.Uint32(in.memory[in.address(r, 4):])))
+
+		case opI32Store, opI64Store32:
+			v := uint32(in.pop())
+			le.PutUint32(in.memory[in.address(r, 4):], v)
+		case opI64Store:
+			v := in.pop()
+			le.PutUint64(in.memory[in.address(r, 8):], v)
+		case opI32Store8, opI64Store8:
+			v := byte(in.pop())
+			in.memory[in.address(r, 1)] = v
+		case opI32Store16, opI64Store16:
+			v := uint16(in.pop())
+			le.PutUint16(in.memory[in.address(r, 2):], v)
+
+		case opMemorySize:
+			r.byte()
+			in.push32(uint32(len(in.memory) / pageSize))
+		case opMemoryGrow:
+			r.byte()
+			delta := in.pop32()
+			pages := uint32(len(in.memory) / pageSize)
+			if uint64(pages)+uint64(delta) > uint64(in.maxPages) {
+				in.push32(math.MaxUint32) // -1, growth refused
+				break
+			}
+			in.useFuel(uint64(delta) * FuelPage)
+			in.memory = append(in.memory, make([]byte, int(delta)*pageSize)...)
+			in.push32(pages)
+
+		case opI32Const:
+			v, _ := r.sleb(32)
+			in.push32(uint32(v))
+		case opI64Const:
+			v, _ := r.sleb(64)
+			in.push(uint64(v))
+
+		case opI32Eqz:
+			in.pushBool(in.pop32() == 0)
+		case opI64Eqz:
+			in.pushBool(in.pop() == 0)
+
+		case opPrefixFC:
+			sub, _ := r.u32()
+			switch sub {
+			case opMemoryCopy:
+				r.bytes(2)
+				n, src, dst := uint64(in.pop32()), uint64(in.pop32()), uint64(in.pop32())
+				if src+n > uint64(len(in.memory)) || dst+n > uint64(len(in.memory)) {
+					in.fail(ErrMemoryOutOfBounds)
+				}
+				in.useFuel((n + 31) / 32 * FuelByte)
+				copy(in.memory[dst:dst+n], in.memory[src:src+n])
+			case opMemoryFill:
+				r.byte()
+				n, val, dst := uint64(in.pop32()), byte(in.pop32()), uint64(in.pop32())
+				if dst+n > uint64(len(in.memory)) {
+					in.fail(ErrMemoryOutOfBounds)
+				}
+				in.useFuel((n + 31) / 32 * FuelByte)
+				for i := dst; i < dst+n; i++ {
+					in.memory[i] = val
+				}
+			}
+
+		default:
+			switch {
+			case op >= opI32Eq && op <= opI32GeU:
+				b, a := in.pop32(), in.pop32()
+				in.pushBool(compare32(op, a, b))
+			case op >= opI64Eq && op <= opI64GeU:
+				b, a := in.pop(), in.pop()
+				in.pushBool(compare64(op, a, b))
+			case op >= opI32Clz && op <= opI32Popcnt:
+				a := in.pop32()
+				switch op {
+				case opI32Clz:
+					in.push32(uint32(bits.LeadingZeros32(a)))
+				case opI32Ctz:
+					in.push32(uint32(bits.TrailingZeros32(a)))
+				default:
+					in.push32(uint32(bits.OnesCount32(a)))
+				}
+			case op >= opI32Add && op <= opI32Rotr:
+				b, a := in.pop32(), in.pop32()
+				in.push32(in.binary32(op, a, b))
+			case op >= opI64Clz && op <= opI64Popcnt:
+				a := in.pop()
+				switch op {
+				case opI64Clz:
+					in.push(uint64(bits.LeadingZeros64(a)))
+				case opI64Ctz:
+					in.push(uint64(bits.TrailingZeros64(a)))
+				default:
+					in.push(uint64(bits.OnesCount64(a)))
+				}
+			case op >= opI64Add && op <= opI64Rotr:
+				b, a := in.pop(), in.pop()
+				in.push(in.binary64(op, a, b))
+			case op == opI32WrapI64:
+				in.push32(uint32(in.pop()))
+			case op == opI64ExtendS32:
+				in.push(uint64(int64(int32(in.pop32()))))
+			case op == opI64ExtendU32:
+				in.push(uint64(in.pop32()))
+			case op == opI32Extend8S:
+				in.push32(uint32(int32(int8(in.pop32()))))
+			case op == opI32Extend16S:
+				in.push32(uint32(int32(int16(in.pop32()))))
+			case op == opI64Extend8S:
+				in.push(uint64(int64(int8(in.pop()))))
+			case op == opI64Extend16S:
+				in.push(uint64(int64(int16(in.pop()))))
+			case op == opI64Extend32S:
+				in.push(uint64(int64(int32(in.pop()))))
+			default:
+				// Unreachable for decoded modules, scan rejects unknown opcodes.
+				in.fail(fmt.Errorf("%w: unsupported instruction 0x%x", ErrTrap, op))
+			}
+		}
+	}
+}
+
+func sameType(a, b FuncType) bool {
+	if len(a.Params) != len(b.Params) || len(a.Results) != len(b.Results) {
+		return false
+	}
+	for i := range a.Params {
+		if a.Params[i] != b.Params[i] {
+			return false
+		}
+	}
+	for i := range a.Results {
+		if a.Results[i] != b.Results[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func compare32(op byte, a, b uint32) bool {
+	switch op {
+	case opI32Eq:
+		return a == b
+	case opI32Ne:
+		return a != b
+	case opI32LtS:
+		return int32(a) < int32(b)
+	case opI32LtU:
+		return a < b
+	case opI32GtS:
+		return int32(a) > int32(b)
+	case opI32GtU:
+		return a > b
+	case opI32LeS:
+		return int32(a) <= int32(b)
+	case opI32LeU:
+		return a <= b
+	case opI32GeS:
+		return int32(a) >= int32(b)
+	default:
+		return a >= b
+	}
+}
+
+func compare64(op byte, a, b uint64) bool {
+	switch op {
+	case opI64Eq:
+		return a == b
+	case opI64Ne:
+		return a != b
+	case opI64LtS:
+		return int64(a) < int64(b)
+	case opI64LtU:
+		return a < b
+	case opI64GtS:
+		return int64(a) > int64(b)
+	case opI64GtU:
+		return a > b
+	case opI64LeS:
+		return int64(a) <= int64(b)
+	case opI64LeU:
+		return a <= b
+	case opI64GeS:
+		return int64(a) >= int64(b)
+	default:
+		return a >= b
+	}
+}
+
+func (in *Instance) binary32(op byte, a, b uint32) uint32 {
+	switch op {
+	case opI32Add:
+		return a + b
+	case opI32Sub:
+		return a - b
+	case opI32Mul:
+		return a * b
+	case opI32DivS:
+		if b == 0 {
+			in.fail(ErrDivideByZero)
+		}
+		if int32(a) == math.MinInt32 && int32(b) == -1 {
+			in.fail(ErrIntegerOverflow)
+		}
+		return uint32(int32(a) / int32(b))
+	case opI32DivU:
+		if b == 0 {
+			in.fail(ErrDivideByZero)
+		}
+		return a / b
+	case opI32RemS:
+		if b == 0 {
+			in.fail(ErrDivideByZero)
+		}
+		if int32(b) == -1 {
+			return 0
+		}
+		return uint32(int32(a) % int32(b))
+	case opI32RemU:
+		if b == 0 {
+			in.fail(ErrDivideByZero)
+		}
+		return a % b
+	case opI32And:
+		return a & b
+	case opI32Or:
+		return a | b
+	case opI32Xor:
+		return a ^ b
+	case opI32Shl:
+		return a << (b & 31)
+	case opI32ShrS:
+		return uint32(int32(a) >> (b & 31))
+	case opI32ShrU:
+		return a >> (b & 31)
+	case opI32Rotl:
+		return bits.RotateLeft32(a, int(b&31))
+	default:
+		return bits.RotateLeft32(a, -int(b&31))
+	}
+}
+
+func (in *Instance) binary64(op byte, a, b uint64) uint64 {
+	switch op {
+	case opI64Add:
+		return a + b
+	case opI64Sub:
+		return a - b
+	case opI64Mul:
+		return a * b
+	case opI64DivS:
+		if b == 0 {
+			in.fail(ErrDivideByZero)
+		}
+		if int64(a) == math.MinInt64 && int64(b) == -1 {
+			in.fail(ErrIntegerOverflow)
+		}
+		return uint64(int64(a) / int64(b))
+	case opI64DivU:
+		if b == 0 {
+			in.fail(ErrDivideByZero)
+		}
+		return a / b
+	case opI64RemS:
+		if b == 0 {
+			in.fail(ErrDivideByZero)
+		}
+		if int64(b) == -1 {
+			return 0
+		}
+		return uint64(int64(a) % int64(b))
+	case opI64RemU:
+		if b == 0 {
+			in.fail(ErrDivideByZero)
+		}
+		return a % b
+	case opI64And:
+		return a & b
+	case opI64Or:
+		return a | b
+	case opI64Xor:
+		return a ^ b
+	case opI64Shl:
+		return a << (b & 63)
+	case opI64ShrS:
+		return uint64(int64(a) >> (b & 63))
+	case opI64ShrU:
+		return a >> (b & 63)
+	case opI64Rotl:
+		return bits.RotateLeft64(a, int(b&63))
+	default:
+		return bits.RotateLeft64(a, -int(b&63))
+	}
+}
@@ -0,0 +1,304 @@
+package wasm
+
+import (
+	"errors"
+	"testing"
+)
+
+// testFunc describes a function for buildModule.
+type testFunc struct {
+	params, results []ValueType
+	locals          []ValueType
+	body            []byte // without the final end
+	export          string
+}
+
+func uleb(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			out = append(out, b|0x80)
+			continue
+		}
+		return append(out, b)
+	}
+}
+
+func sleb(v int64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func section(id byte, payload []byte) []byte {
+	return append(append([]byte{id}, uleb(uint64(len(payload)))...), payload...)
+}
+
+func vec(items [][]byte) []byte {
+	out := uleb(uint64(len(items)))
+	for _, item := range items {
+		out = append(out, item...)
+	}
+	return out
+}
+
+func valueTypes(types []ValueType) []byte {
+	out := uleb(uint64(len(types)))
+	for _, t := range types {
+		out = append(out, byte(t))
+	}
+	return out
+}
+
+// buildModule assembles a module with one type per function, an optional
+// memory of the given number of pages and the given data segment at offset 0.
+func buildModule(funcs []testFunc, pages int, data []byte) []byte {
+	var types, indices, exports, codes [][]byte
+	for i, f := range funcs {
+		types = append(types, append(append([]byte{0x60}, valueTypes(f.params)...), valueTypes(f.results)...))
+		indices = append(indices, uleb(uint64(i)))
+		if f.export != "" {
+			exports = append(exports, append(append(uleb(uint64(len(f.export))), f.export...), append([]byte{ExternFunc}, uleb(uint64(i))...)...))
+		}
+		var locals [][]byte
+		for _, l := range f.locals {
+			locals = append(locals, []byte{1, byte(l)})
+		}
+		body := append(vec(locals), f.body...)
+		body = append(body, opEnd)
+		codes = append(codes, append(uleb(uint64(len(body))), body...))
+	}
+	mod := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	mod = append(mod, section(1, vec(types))...)
+	mod = append(mod, section(3, vec(indices))...)
+	if pages > 0 {
+		mod = append(mod, section(5, vec([][]byte{append([]byte{0x00}, uleb(uint64(pages))...)}))...)
+		exports = append(exports, append(append(uleb(6), "memory"...), ExternMemory, 0))
+	}
+	mod = append(mod, section(7, vec(exports))...)
+	mod = append(mod, section(10, vec(codes))...)
+	if data != nil {
+		seg := append([]byte{0x00, opI32Const, 0x00, opEnd}, uleb(uint64(len(data)))...)
+		mod = append(mod, section(11, vec([][]byte{append(seg, data...)}))...)
+	}
+	return mod
+}
+
+func instantiate(t *testing.T, code []byte, fuel uint64) *Instance {
+	t.Helper()
+	m, err := Decode(code)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	in, err := Instantiate(m, DefaultConfig, fuel)
+	if err != nil {
+		t.Fatalf("instantiate failed: %v", err)
+	}
+	return in
+}
+
+func TestArithmetic(t *testing.T) {
+	code := buildModule([]testFunc{{
+		params:  []ValueType{I32, I32},
+		results: []ValueType{I32},
+		body:    []byte{opLocalGet, 0, opLocalGet, 1, opI32Add, opI32Const, 3, opI32Mul},
+		export:  "addmul",
+	}}, 0, nil)
+	in := instantiate(t, code, 1000)
+	out, err := in.Call("addmul", 4, 5)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if len(out) != 1 || out[0] != 27 {
+		t.Fatalf("result mismatch: have %v, want 27", out)
+	}
+}
+
+// Sums the integers 1..n with a loop, exercising block, loop and branches.
+func sumLoop() []byte {
+	body := []byte{
+		opBlock, 0x40,
+		opLoop, 0x40,
+		opLocalGet, 0, opI32Eqz, opBrIf, 1,
+		opLocalGet, 1, opLocalGet, 0, opI32Add, opLocalSet, 1,
+		opLocalGet, 0, opI32Const, 1, opI32Sub, opLocalSet, 0,
+		opBr, 0,
+		opEnd,
+		opEnd,
+		opLocalGet, 1,
+	}
+	return buildModule([]testFunc{{
+		params:  []ValueType{I32},
+		results: []ValueType{I32},
+		locals:  []ValueType{I32},
+		body:    body,
+		export:  "sum",
+	}}, 0, nil)
+}
+
+func TestLoop(t *testing.T) {
+	in := instantiate(t, sumLoop(), 100000)
+	out, err := in.Call("sum", 100)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if out[0] != 5050 {
+		t.Fatalf("result mismatch: have %d, want 5050", out[0])
+	}
+}
+
+func TestFuelDeterminism(t *testing.T) {
+	var used []uint64
+	for i := 0; i < 3; i++ {
+		in := instantiate(t, sumLoop(), 100000)
+		if _, err := in.Call("sum", 50); err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+		used = append(used, 100000-in.Fuel())
+	}
+	if used[0] != used[1] || used[1] != used[2] {
+		t.Fatalf("fuel usage not deterministic: %v", used)
+	}
+}
+
+func TestOutOfFuel(t *testing.T) {
+	in := instantiate(t, sumLoop(), 200)
+	if _, err := in.Call("sum", 1000); !errors.Is(err, ErrOutOfFuel) {
+		t.Fatalf("expected out of fuel, got %v", err)
+	}
+	if in.Fuel() != 0 {
+		t.Fatalf("fuel left after exhaustion: %d", in.Fuel())
+	}
+}
+
+func TestIfElse(t *testing.T) {
+	code := buildModule([]testFunc{{
+		params:  []ValueType{I32},
+		results: []ValueType{I64},
+		body: []byte{
+			opLocalGet, 0,
+			opIf, byte(I64), opI64Const, 7, opElse, opI64Const, 9, opEnd,
+		},
+		export: "pick",
+	}}, 0, nil)
+	in := instantiate(t, code, 1000)
+	for arg, want := range map[uint64]uint64{0: 9, 1: 7} {
+		out, err := in.Call("pick", arg)
+		if err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+		if out[0] != want {
+			t.Errorf("pick(%d): have %d, want %d", arg, out[0], want)
+		}
+	}
+}
+
+func TestMemory(t *testing.T) {
+	code := buildModule([]testFunc{{
+		params:  []ValueType{I32},
+		results: []ValueType{I32},
+		body: []byte{
+			opLocalGet, 0, opLocalGet, 0, opI32Load8U, 0, 0, opI32Const, 1, opI32Add, opI32Store8, 0, 0,
+			opLocalGet, 0, opI32Load8U, 0, 0,
+		},
+		export: "inc",
+	}, {
+		results: []ValueType{I32},
+		body:    []byte{opI32Const, 1, opMemoryGrow, 0},
+		export:  "grow",
+	}}, 1, []byte{41})
+	in := instantiate(t, code, 100000)
+	out, err := in.Call("inc", 0)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if out[0] != 42 {
+		t.Fatalf("result mismatch: have %d, want 42", out[0])
+	}
+	if _, err := in.Call("inc", pageSize); !errors.Is(err, ErrMemoryOutOfBounds) {
+		t.Fatalf("expected out of bounds access, got %v", err)
+	}
+	out, err = in.Call("grow")
+	if err != nil || out[0] != 1 {
+		t.Fatalf("grow failed: %v %v", out, err)
+	}
+	if len(in.Memory()) != 2*pageSize {
+		t.Fatalf("memory size mismatch: have %d", len(in.Memory()))
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	code := buildModule([]testFunc{{
+		results: []ValueType{I32},
+		body:    append(append([]byte{opI32Const}, sleb(1000)...), opMemoryGrow, 0),
+		export:  "grow",
+	}}, 1, nil)
+	in := instantiate(t, code, 1<<30)
+	out, err := in.Call("grow")
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if uint32(out[0]) != 0xffffffff {
+		t.Fatalf("growth beyond limit not refused: %d", out[0])
+	}
+}
+
+func TestTraps(t *testing.T) {
+	code := buildModule([]testFunc{{
+		params:  []ValueType{I32, I32},
+		results: []ValueType{I32},
+		body:    []byte{opLocalGet, 0, opLocalGet, 1, opI32DivS},
+		export:  "div",
+	}, {
+		body:   []byte{opUnreachable},
+		export: "boom",
+	}, {
+		body:   []byte{opCall, 2},
+		export: "recurse",
+	}}, 0, nil)
+	in := instantiate(t, code, 1<<30)
+	if _, err := in.Call("div", 1, 0); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("expected divide by zero, got %v", err)
+	}
+	if _, err := in.Call("div", 0x80000000, 0xffffffff); !errors.Is(err, ErrIntegerOverflow) {
+		t.Errorf("expected overflow, got %v", err)
+	}
+	if _, err := in.Call("boom"); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected unreachable, got %v", err)
+	}
+	if _, err := in.Call("recurse"); !errors.Is(err, ErrCallStackExhausted) {
+		t.Errorf("expected call stack exhaustion, got %v", err)
+	}
+	// The instance must stay usable after a trap
+	out, err := in.Call("div", 10, 3)
+	if err != nil || out[0] != 3 {
+		t.Errorf("call after trap failed: %v %v", out, err)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	float := buildModule([]testFunc{{
+		results: []ValueType{I32},
+		body:    []byte{0x43, 0, 0, 0, 0, 0xa8}, // f32.const 0; i32.trunc_f32_s
+		export:  "f",
+	}}, 0, nil)
+	if _, err := Decode(float); !errors.Is(err, ErrFloat) {
+		t.Errorf("expected float rejection, got %v", err)
+	}
+	imports := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	imports = append(imports, section(2, vec(nil))...)
+	if _, err := Decode(imports); !errors.Is(err, ErrImport) {
+		t.Errorf("expected import rejection, got %v", err)
+	}
+	if _, err := Decode([]byte("not a wasm module")); !errors.Is(err, ErrInvalidMagic) {
+		t.Errorf("expected magic rejection, got %v", err)
+	}
+}
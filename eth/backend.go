@@ -498,12 +498,27 @@ func (s *Ethereum) SyncMode() downloader.SyncMode {
 // network protocols to start.
 func (s *Ethereum) Protocols() []p2p.Protocol {
 	protos := eth.MakeProtocols((*ethHandler)(s.handler), s.networkID, s.ethDialCandidates)
+	for i := range protos {
+		// Report the health of the links to the external layers along with the
+		// protocol metadata
+		info := protos[i].NodeInfo
+		protos[i].NodeInfo = func() interface{} {
+			return &nodeInfo{NodeInfo: info().(*eth.NodeInfo), Links: s.miner.Links()}
+		}
+	}
 	if s.config.SnapshotCache > 0 {
 		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler), s.snapDialCandidates)...)
 	}
 	return protos
 }
 
+// nodeInfo extends the `eth` protocol metadata of the host peer with the health
+// of the gRPC links to the external layers.
+type nodeInfo struct {
+	*eth.NodeInfo
+	Links []miner.LinkStatus `json:"links"`
+}
+
 // Start implements node.Lifecycle, starting all internal goroutines needed by the
 // Ethereum protocol implementation.
 func (s *Ethereum) Start() error {
@@ -154,6 +154,10 @@ type Config struct {
 	// send-transaction variants. The unit is ether.
 	RPCTxFeeCap float64
 
+	// Directory keeping a copy of the upgraded crypto algorithm modules. It
+	// defaults to a folder of the node data directory.
+	CryptoUpgradeDir string `toml:",omitempty"`
+
 	// OverrideCancun (TODO: remove after the fork)
 	OverrideCancun *uint64 `toml:",omitempty"`
 
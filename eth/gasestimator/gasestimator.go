@@ -69,8 +69,9 @@ func Estimate(ctx context.Context, call *core.Message, opts *Options, gasCap uin
 	} else {
 		feeCap = common.Big0
 	}
-	// Recap the highest gas limit with account's available balance.
-	if feeCap.BitLen() != 0 {
+	// Recap the highest gas limit with account's available balance. Voucher
+	// calls pay gas in their fee currency, the native balance doesn't cap them.
+	if feeCap.BitLen() != 0 && call.FeeCurrency == nil {
 		balance := opts.State.GetBalance(call.From).ToBig()
 
 		available := balance
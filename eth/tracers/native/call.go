@@ -45,6 +45,14 @@ type callLog struct {
 	Position hexutil.Uint `json:"position"`
 }
 
+// callPolicy is the decision of the security level call policy on a frame.
+type callPolicy struct {
+	CallerLevel uint64        `json:"callerLevel"`
+	CalleeLevel uint64        `json:"calleeLevel"`
+	Rule        vm.PolicyRule `json:"rule"`
+	Allowed     bool          `json:"allowed"`
+}
+
 type callFrame struct {
 	Type         vm.OpCode       `json:"-"`
 	From         common.Address  `json:"from"`
@@ -57,6 +65,7 @@ type callFrame struct {
 	RevertReason string          `json:"revertReason,omitempty"`
 	Calls        []callFrame     `json:"calls,omitempty" rlp:"optional"`
 	Logs         []callLog       `json:"logs,omitempty" rlp:"optional"`
+	Policy       *callPolicy     `json:"policy,omitempty" rlp:"optional"`
 	// Placed at end on purpose. The RLP will be decoded to 0 instead of
 	// nil if there are non-empty elements after in the struct.
 	Value *big.Int `json:"value,omitempty" rlp:"optional"`
@@ -113,6 +122,7 @@ type callTracer struct {
 type callTracerConfig struct {
 	OnlyTopCall bool `json:"onlyTopCall"` // If true, call tracer won't collect any subcalls
 	WithLog     bool `json:"withLog"`     // If true, call tracer will collect event logs
+	WithPolicy  bool `json:"withPolicy"`  // If true, call tracer will collect security level call policy decisions
 }
 
 // newCallTracer returns a native go tracer which tracks
@@ -243,6 +253,28 @@ func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
 	t.callstack[size-1].Calls = append(t.callstack[size-1].Calls, call)
 }
 
+// CapturePolicy implements the vm.PolicyLogger interface to collect the decision
+// of the security level call policy on the current frame.
+func (t *callTracer) CapturePolicy(decision *vm.PolicyDecision) {
+	if !t.config.WithPolicy {
+		return
+	}
+	// The decision on the top call comes first, skip the subcalls
+	if t.config.OnlyTopCall && t.callstack[0].Policy != nil {
+		return
+	}
+	// Skip if tracing was interrupted
+	if t.interrupt.Load() {
+		return
+	}
+	t.callstack[len(t.callstack)-1].Policy = &callPolicy{
+		CallerLevel: decision.CallerLevel,
+		CalleeLevel: decision.CalleeLevel,
+		Rule:        decision.Rule,
+		Allowed:     decision.Allowed(),
+	}
+}
+
 func (t *callTracer) CaptureTxStart(gasLimit uint64) {
 	t.gasLimit = gasLimit
 }
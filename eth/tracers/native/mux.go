@@ -101,6 +101,16 @@ func (t *muxTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
 	}
 }
 
+// CapturePolicy is called with the decision of the security level call policy
+// on the current frame.
+func (t *muxTracer) CapturePolicy(decision *vm.PolicyDecision) {
+	for _, t := range t.tracers {
+		if t, ok := t.(vm.PolicyLogger); ok {
+			t.CapturePolicy(decision)
+		}
+	}
+}
+
 func (t *muxTracer) CaptureTxStart(gasLimit uint64) {
 	for _, t := range t.tracers {
 		t.CaptureTxStart(gasLimit)
@@ -62,13 +62,16 @@ func (ec *Client) CreateAccessList(ctx context.Context, msg ethereum.CallMsg) (*
 
 // AccountResult is the result of a GetProof operation.
 type AccountResult struct {
-	Address      common.Address  `json:"address"`
-	AccountProof []string        `json:"accountProof"`
-	Balance      *big.Int        `json:"balance"`
-	CodeHash     common.Hash     `json:"codeHash"`
-	Nonce        uint64          `json:"nonce"`
-	StorageHash  common.Hash     `json:"storageHash"`
-	StorageProof []StorageResult `json:"storageProof"`
+	Address          common.Address  `json:"address"`
+	AccountProof     []string        `json:"accountProof"`
+	Balance          *big.Int        `json:"balance"`
+	CodeHash         common.Hash     `json:"codeHash"`
+	Nonce            uint64          `json:"nonce"`
+	StorageHash      common.Hash     `json:"storageHash"`
+	StorageProof     []StorageResult `json:"storageProof"`
+	SecurityLevel    uint64          `json:"securityLevel"`
+	Interest         *big.Int        `json:"interest"`         // Fixed point, as stored in the account
+	LastAccrualBlock uint64          `json:"lastAccrualBlock"` // Block interest was last accrued at
 }
 
 // StorageResult provides a proof for a key-value pair.
@@ -88,13 +91,16 @@ func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []s
 	}
 
 	type accountResult struct {
-		Address      common.Address  `json:"address"`
-		AccountProof []string        `json:"accountProof"`
-		Balance      *hexutil.Big    `json:"balance"`
-		CodeHash     common.Hash     `json:"codeHash"`
-		Nonce        hexutil.Uint64  `json:"nonce"`
-		StorageHash  common.Hash     `json:"storageHash"`
-		StorageProof []storageResult `json:"storageProof"`
+		Address          common.Address  `json:"address"`
+		AccountProof     []string        `json:"accountProof"`
+		Balance          *hexutil.Big    `json:"balance"`
+		CodeHash         common.Hash     `json:"codeHash"`
+		Nonce            hexutil.Uint64  `json:"nonce"`
+		StorageHash      common.Hash     `json:"storageHash"`
+		StorageProof     []storageResult `json:"storageProof"`
+		SecurityLevel    hexutil.Uint64  `json:"securityLevel"`
+		Interest         *hexutil.Big    `json:"interest"`
+		LastAccrualBlock hexutil.Uint64  `json:"lastAccrualBlock"`
 	}
 
 	// Avoid keys being 'null'.
@@ -114,13 +120,16 @@ func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []s
 		})
 	}
 	result := AccountResult{
-		Address:      res.Address,
-		AccountProof: res.AccountProof,
-		Balance:      res.Balance.ToInt(),
-		Nonce:        uint64(res.Nonce),
-		CodeHash:     res.CodeHash,
-		StorageHash:  res.StorageHash,
-		StorageProof: storageResults,
+		Address:          res.Address,
+		AccountProof:     res.AccountProof,
+		Balance:          res.Balance.ToInt(),
+		Nonce:            uint64(res.Nonce),
+		CodeHash:         res.CodeHash,
+		StorageHash:      res.StorageHash,
+		StorageProof:     storageResults,
+		SecurityLevel:    uint64(res.SecurityLevel),
+		Interest:         res.Interest.ToInt(),
+		LastAccrualBlock: uint64(res.LastAccrualBlock),
 	}
 	return &result, err
 }
@@ -0,0 +1,119 @@
+package ethclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/merkeldag"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// TaintProof is the proof that an account is or isn't in the tainted-address
+// DAG of a block.
+type TaintProof struct {
+	Address common.Address
+	Root    []byte                  // Tainted root of the block, as claimed by the node
+	Tainted bool                    // Whether the node claims the account is tainted
+	Proof   *merkeldag.Proof        // Leaf of the account, if tainted
+	Absence *merkeldag.AbsenceProof // Adjacent leaves in the sorted DAG, if not tainted
+}
+
+type rpcTaintLeafProof struct {
+	Index    hexutil.Uint64  `json:"index"`
+	Data     hexutil.Bytes   `json:"data"`
+	Siblings []hexutil.Bytes `json:"siblings"`
+}
+
+func (p *rpcTaintLeafProof) toProof() *merkeldag.Proof {
+	if p == nil {
+		return nil
+	}
+	siblings := make([][]byte, len(p.Siblings))
+	for i, sibling := range p.Siblings {
+		siblings[i] = sibling
+	}
+	return &merkeldag.Proof{Index: uint64(p.Index), Data: p.Data, Siblings: siblings}
+}
+
+// TaintProof returns the proof that the given account is or isn't tainted as of
+// the given block. The block number can be nil, in which case the proof is for
+// the latest known block. The proof isn't verified, see VerifyTaintProof.
+func (ec *Client) TaintProof(ctx context.Context, account common.Address, blockNumber *big.Int) (*TaintProof, error) {
+	return ec.taintProof(ctx, account, toBlockNumArg(blockNumber))
+}
+
+// TaintProofAtHash returns the proof that the given account is or isn't tainted
+// as of the given block.
+func (ec *Client) TaintProofAtHash(ctx context.Context, account common.Address, blockHash common.Hash) (*TaintProof, error) {
+	return ec.taintProof(ctx, account, rpc.BlockNumberOrHashWithHash(blockHash, false))
+}
+
+func (ec *Client) taintProof(ctx context.Context, account common.Address, block interface{}) (*TaintProof, error) {
+	var res struct {
+		Address common.Address     `json:"address"`
+		Root    hexutil.Bytes      `json:"root"`
+		Tainted bool               `json:"tainted"`
+		Proof   *rpcTaintLeafProof `json:"proof"`
+		Lower   *rpcTaintLeafProof `json:"lower"`
+		Upper   *rpcTaintLeafProof `json:"upper"`
+	}
+	if err := ec.c.CallContext(ctx, &res, "eth_getTaintProof", account, block); err != nil {
+		return nil, err
+	}
+	proof := &TaintProof{
+		Address: res.Address,
+		Root:    res.Root,
+		Tainted: res.Tainted,
+		Proof:   res.Proof.toProof(),
+	}
+	if res.Lower != nil || res.Upper != nil {
+		proof.Absence = &merkeldag.AbsenceProof{Lower: res.Lower.toProof(), Upper: res.Upper.toProof()}
+	}
+	return proof, nil
+}
+
+// VerifyTaintProof checks the proof that the given account is or isn't tainted
+// against the tainted root the header commits to, and returns whether it is.
+// Headers with an empty root commit to the empty DAG, no account is tainted.
+func VerifyTaintProof(header *types.Header, account common.Address, proof *TaintProof) (bool, error) {
+	if len(header.Tainted) == 0 {
+		return false, nil
+	}
+	if proof == nil || proof.Address != account {
+		return false, errors.New("proof for a different account")
+	}
+	if proof.Proof != nil {
+		if !bytes.Equal(proof.Proof.Data, account.Bytes()) {
+			return false, errors.New("proof for a different leaf")
+		}
+		if err := proof.Proof.Verify(header.Tainted); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if err := proof.Absence.Verify(header.Tainted, account.Bytes()); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// TaintedAt returns whether the given account is tainted as of the given block,
+// verifying the proof given by the node against the header it serves. The block
+// number can be nil, in which case the latest known block is used. Reorgs
+// between the two requests make the verification fail.
+func (ec *Client) TaintedAt(ctx context.Context, account common.Address, blockNumber *big.Int) (bool, error) {
+	header, err := ec.HeaderByNumber(ctx, blockNumber)
+	if err != nil {
+		return false, err
+	}
+	proof, err := ec.TaintProof(ctx, account, header.Number)
+	if err != nil {
+		return false, err
+	}
+	return VerifyTaintProof(header, account, proof)
+}
@@ -0,0 +1,452 @@
+// Package governance implements the security level governance module, the
+// system contract through which accounts holding the right to do so lock,
+// unlock or set the security level of accounts. Every change is announced by a
+// SecurityLevelChanged event, from which the security level history of the
+// accounts is indexed. The module also amends the call policy the chain config
+// starts with, deciding which calls the security levels let through.
+package governance
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Abi of the security level governance module
+const governanceABI = `[
+{"inputs":[{"name":"account","type":"address"},{"name":"level","type":"uint8"},{"name":"reason","type":"bytes32"}],"name":"setSecurityLevel","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"account","type":"address"},{"name":"reason","type":"bytes32"}],"name":"lock","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"account","type":"address"},{"name":"reason","type":"bytes32"}],"name":"unlock","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"role","type":"uint8"},{"name":"account","type":"address"}],"name":"grantRole","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"role","type":"uint8"},{"name":"account","type":"address"}],"name":"revokeRole","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"role","type":"uint8"},{"name":"account","type":"address"}],"name":"hasRole","outputs":[{"name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"account","type":"address"}],"name":"securityLevel","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"callerLevel","type":"uint8"},{"name":"calleeLevel","type":"uint8"},{"name":"rule","type":"uint8"}],"name":"setCallRule","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"caller","type":"address"},{"name":"callee","type":"address"},{"name":"rule","type":"uint8"}],"name":"setPairRule","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"level","type":"uint8"},{"name":"limit","type":"uint256"}],"name":"setValueLimit","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"level","type":"uint8"}],"name":"clearValueLimit","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"level","type":"uint8"}],"name":"setCreateLevel","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"callerLevel","type":"uint8"},{"name":"calleeLevel","type":"uint8"}],"name":"callAllowed","outputs":[{"name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"caller","type":"address"},{"name":"callee","type":"address"}],"name":"pairAllowed","outputs":[{"name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"level","type":"uint8"}],"name":"valueLimit","outputs":[{"name":"limited","type":"bool"},{"name":"limit","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"createLevel","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"account","type":"address"},{"indexed":true,"name":"by","type":"address"},{"indexed":false,"name":"previous","type":"uint8"},{"indexed":false,"name":"level","type":"uint8"},{"indexed":false,"name":"reason","type":"bytes32"}],"name":"SecurityLevelChanged","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"role","type":"uint8"},{"indexed":true,"name":"account","type":"address"},{"indexed":true,"name":"by","type":"address"}],"name":"RoleGranted","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"role","type":"uint8"},{"indexed":true,"name":"account","type":"address"},{"indexed":true,"name":"by","type":"address"}],"name":"RoleRevoked","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"callerLevel","type":"uint8"},{"indexed":true,"name":"calleeLevel","type":"uint8"},{"indexed":true,"name":"by","type":"address"},{"indexed":false,"name":"rule","type":"uint8"}],"name":"CallRuleChanged","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"caller","type":"address"},{"indexed":true,"name":"callee","type":"address"},{"indexed":true,"name":"by","type":"address"},{"indexed":false,"name":"rule","type":"uint8"}],"name":"PairRuleChanged","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"level","type":"uint8"},{"indexed":true,"name":"by","type":"address"},{"indexed":false,"name":"limited","type":"bool"},{"indexed":false,"name":"limit","type":"uint256"}],"name":"ValueLimitChanged","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"by","type":"address"},{"indexed":false,"name":"level","type":"uint8"}],"name":"CreateLevelChanged","type":"event"}
+]`
+
+// GovernanceABI is the abi of the security level governance module.
+var GovernanceABI = mustParseABI(governanceABI)
+
+// Role is a right of the governance module.
+type Role uint8
+
+const (
+	RoleAdmin    Role = iota // Grants and revokes roles, holds every other role
+	RoleLock                 // Locks accounts
+	RoleUnlock               // Unlocks accounts
+	RoleSetLevel             // Sets the security level of unlocked accounts
+	RolePolicy               // Amends the call policy
+	RolePlan                 // Schedules plans of the PoW economics parameters
+	numRoles
+)
+
+// Rule is an amendment of the call policy for a pair of levels or accounts.
+type Rule uint8
+
+const (
+	RuleInherit Rule = iota // Decided by the policy of the chain config
+	RuleAllow               // Calls are allowed
+	RuleDeny                // Calls are denied, or for accounts, left to their levels
+	numRules
+)
+
+var (
+	// ErrUnauthorized is returned if the caller lacks the role a call requires.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrInvalidLevel is returned if a security level is out of range.
+	ErrInvalidLevel = errors.New("invalid security level")
+
+	// ErrInvalidRole is returned for unknown roles.
+	ErrInvalidRole = errors.New("invalid role")
+
+	// ErrInvalidRule is returned for unknown call policy rules.
+	ErrInvalidRule = errors.New("invalid call policy rule")
+
+	// ErrWriteProtection is returned for state changing calls in a static call.
+	ErrWriteProtection = errors.New("write protection")
+
+	// errUnknownMethod is returned if the input selects no method of the module.
+	errUnknownMethod = errors.New("unknown method")
+)
+
+// StateDB is the state the governance module runs on.
+type StateDB interface {
+	GetNonce(common.Address) uint64
+	SetNonce(common.Address, uint64)
+	GetState(common.Address, common.Hash) common.Hash
+	SetState(common.Address, common.Hash, common.Hash)
+	GetSecurityLevel(common.Address) uint64
+	SetSecurityLevel(common.Address, uint64)
+	AddLog(*types.Log)
+}
+
+// IsGovernance reports whether addr is the governance module of the chain.
+func IsGovernance(config *params.SecurityConfig, addr common.Address) bool {
+	return config != nil && addr == config.Governance()
+}
+
+// Run executes a call of the governance module by caller. Failed calls return
+// the abi encoded revert reason along with the error.
+func Run(config *params.SecurityConfig, statedb StateDB, caller common.Address, input []byte, readOnly bool) ([]byte, error) {
+	ret, err := run(config, statedb, caller, input, readOnly)
+	if err != nil {
+		return revertReason(err), err
+	}
+	return ret, nil
+}
+
+func run(config *params.SecurityConfig, statedb StateDB, caller common.Address, input []byte, readOnly bool) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, errUnknownMethod
+	}
+	method, err := GovernanceABI.MethodById(input[:4])
+	if err != nil {
+		return nil, errUnknownMethod
+	}
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	if readOnly && !method.IsConstant() {
+		return nil, ErrWriteProtection
+	}
+	switch method.Name {
+	case "setSecurityLevel":
+		account, level, reason := args[0].(common.Address), uint64(args[1].(uint8)), common.Hash(args[2].([32]byte))
+		if level == 0 || level > params.MaxSecurityLevel {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidLevel, level)
+		}
+		if statedb.GetSecurityLevel(account) == 0 {
+			// Only unlocking may change the level of a locked account
+			return nil, fmt.Errorf("%w: account %v is locked", ErrUnauthorized, account)
+		}
+		return nil, authorizedSetLevel(config, statedb, RoleSetLevel, caller, account, level, reason)
+
+	case "lock":
+		account, reason := args[0].(common.Address), common.Hash(args[1].([32]byte))
+		return nil, authorizedSetLevel(config, statedb, RoleLock, caller, account, 0, reason)
+
+	case "unlock":
+		account, reason := args[0].(common.Address), common.Hash(args[1].([32]byte))
+		return nil, authorizedSetLevel(config, statedb, RoleUnlock, caller, account, 1, reason)
+
+	case "grantRole", "revokeRole":
+		role, account := Role(args[0].(uint8)), args[1].(common.Address)
+		if role >= numRoles {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidRole, role)
+		}
+		if !HasRole(config, statedb, RoleAdmin, caller) {
+			return nil, fmt.Errorf("%w: %v lacks role %d", ErrUnauthorized, caller, RoleAdmin)
+		}
+		setRole(config, statedb, role, account, caller, method.Name == "grantRole")
+		return nil, nil
+
+	case "hasRole":
+		role, account := Role(args[0].(uint8)), args[1].(common.Address)
+		return method.Outputs.Pack(HasRole(config, statedb, role, account))
+
+	case "securityLevel":
+		return method.Outputs.Pack(uint8(statedb.GetSecurityLevel(args[0].(common.Address))))
+
+	case "setCallRule", "setPairRule", "setValueLimit", "clearValueLimit", "setCreateLevel":
+		if !HasRole(config, statedb, RolePolicy, caller) {
+			return nil, fmt.Errorf("%w: %v lacks role %d", ErrUnauthorized, caller, RolePolicy)
+		}
+		return nil, amendPolicy(config, statedb, caller, method.Name, args)
+
+	case "callAllowed":
+		callerLevel, calleeLevel := uint64(args[0].(uint8)), uint64(args[1].(uint8))
+		return method.Outputs.Pack(LevelAllowed(config, statedb, callerLevel, calleeLevel))
+
+	case "pairAllowed":
+		return method.Outputs.Pack(PairAllowed(config, statedb, args[0].(common.Address), args[1].(common.Address)))
+
+	case "valueLimit":
+		limit := ValueLimit(config, statedb, uint64(args[0].(uint8)))
+		if limit == nil {
+			return method.Outputs.Pack(false, new(big.Int))
+		}
+		return method.Outputs.Pack(true, limit)
+
+	case "createLevel":
+		return method.Outputs.Pack(uint8(CreateLevel(config, statedb)))
+	}
+	return nil, errUnknownMethod
+}
+
+// amendPolicy runs a call policy amendment of by.
+func amendPolicy(config *params.SecurityConfig, statedb StateDB, by common.Address, method string, args []interface{}) error {
+	var (
+		module = config.Governance()
+		log    = &types.Log{Address: module}
+	)
+	switch method {
+	case "setCallRule":
+		callerLevel, calleeLevel, rule := args[0].(uint8), args[1].(uint8), Rule(args[2].(uint8))
+		if callerLevel > params.MaxSecurityLevel || calleeLevel > params.MaxSecurityLevel {
+			return fmt.Errorf("%w: %d -> %d", ErrInvalidLevel, callerLevel, calleeLevel)
+		}
+		if rule >= numRules {
+			return fmt.Errorf("%w: %d", ErrInvalidRule, rule)
+		}
+		store(config, statedb, levelRuleSlot(uint64(callerLevel), uint64(calleeLevel)), common.BigToHash(big.NewInt(int64(rule))))
+
+		log.Topics = []common.Hash{GovernanceABI.Events["CallRuleChanged"].ID, common.BigToHash(big.NewInt(int64(callerLevel))), common.BigToHash(big.NewInt(int64(calleeLevel))), common.BytesToHash(by.Bytes())}
+		log.Data, _ = GovernanceABI.Events["CallRuleChanged"].Inputs.NonIndexed().Pack(uint8(rule))
+
+	case "setPairRule":
+		caller, callee, rule := args[0].(common.Address), args[1].(common.Address), Rule(args[2].(uint8))
+		if rule >= numRules {
+			return fmt.Errorf("%w: %d", ErrInvalidRule, rule)
+		}
+		store(config, statedb, pairRuleSlot(caller, callee), common.BigToHash(big.NewInt(int64(rule))))
+
+		log.Topics = []common.Hash{GovernanceABI.Events["PairRuleChanged"].ID, common.BytesToHash(caller.Bytes()), common.BytesToHash(callee.Bytes()), common.BytesToHash(by.Bytes())}
+		log.Data, _ = GovernanceABI.Events["PairRuleChanged"].Inputs.NonIndexed().Pack(uint8(rule))
+
+	case "setValueLimit", "clearValueLimit":
+		level := args[0].(uint8)
+		if level > params.MaxSecurityLevel {
+			return fmt.Errorf("%w: %d", ErrInvalidLevel, level)
+		}
+		// Limits are stored plus one, zero leaving the limit to the chain config.
+		// The largest limit saturates, which is as good as no limit.
+		var (
+			value = common.Hash{}
+			limit = new(big.Int)
+		)
+		if method == "setValueLimit" {
+			limit = args[1].(*big.Int)
+			if stored := new(big.Int).Add(limit, common.Big1); stored.BitLen() > 256 {
+				value = common.MaxHash
+			} else {
+				value = common.BigToHash(stored)
+			}
+		}
+		store(config, statedb, valueLimitSlot(uint64(level)), value)
+
+		log.Topics = []common.Hash{GovernanceABI.Events["ValueLimitChanged"].ID, common.BigToHash(big.NewInt(int64(level))), common.BytesToHash(by.Bytes())}
+		log.Data, _ = GovernanceABI.Events["ValueLimitChanged"].Inputs.NonIndexed().Pack(method == "setValueLimit", limit)
+
+	case "setCreateLevel":
+		level := args[0].(uint8)
+		if level > params.MaxSecurityLevel {
+			return fmt.Errorf("%w: %d", ErrInvalidLevel, level)
+		}
+		// Stored plus one, zero leaving the level to the chain config
+		store(config, statedb, createLevelSlot, common.BigToHash(big.NewInt(int64(level)+1)))
+
+		log.Topics = []common.Hash{GovernanceABI.Events["CreateLevelChanged"].ID, common.BytesToHash(by.Bytes())}
+		log.Data, _ = GovernanceABI.Events["CreateLevelChanged"].Inputs.NonIndexed().Pack(level)
+	}
+	statedb.AddLog(log)
+	return nil
+}
+
+// LevelAllowed reports whether the call policy lets accounts at callerLevel
+// call accounts at calleeLevel.
+func LevelAllowed(config *params.SecurityConfig, statedb StateDB, callerLevel, calleeLevel uint64) bool {
+	if config != nil {
+		switch Rule(statedb.GetState(config.Governance(), levelRuleSlot(callerLevel, calleeLevel)).Big().Uint64()) {
+		case RuleAllow:
+			return true
+		case RuleDeny:
+			return false
+		}
+	}
+	return config.CallPolicy().LevelAllowed(callerLevel, calleeLevel)
+}
+
+// PairAllowed reports whether the call policy lets caller call callee whatever
+// their levels.
+func PairAllowed(config *params.SecurityConfig, statedb StateDB, caller, callee common.Address) bool {
+	if config != nil {
+		switch Rule(statedb.GetState(config.Governance(), pairRuleSlot(caller, callee)).Big().Uint64()) {
+		case RuleAllow:
+			return true
+		case RuleDeny:
+			return false
+		}
+	}
+	return config.CallPolicy().PairAllowed(caller, callee)
+}
+
+// ValueLimit returns the most value the call policy lets accounts at level
+// transfer per call frame, nil if there's no limit.
+func ValueLimit(config *params.SecurityConfig, statedb StateDB, level uint64) *big.Int {
+	if config != nil {
+		if stored := statedb.GetState(config.Governance(), valueLimitSlot(level)); stored != (common.Hash{}) {
+			return new(big.Int).Sub(stored.Big(), common.Big1)
+		}
+	}
+	return config.CallPolicy().ValueLimit(level)
+}
+
+// CreateLevel returns the lowest level the call policy lets create contracts.
+func CreateLevel(config *params.SecurityConfig, statedb StateDB) uint64 {
+	if config != nil {
+		if stored := statedb.GetState(config.Governance(), createLevelSlot); stored != (common.Hash{}) {
+			return stored.Big().Uint64() - 1
+		}
+	}
+	return config.CallPolicy().MinCreateLevel()
+}
+
+// authorizedSetLevel sets the security level of account if caller holds role.
+func authorizedSetLevel(config *params.SecurityConfig, statedb StateDB, role Role, caller, account common.Address, level uint64, reason common.Hash) error {
+	if !HasRole(config, statedb, role, caller) {
+		return fmt.Errorf("%w: %v lacks role %d", ErrUnauthorized, caller, role)
+	}
+	SetLevel(config, statedb, caller, account, level, reason)
+	return nil
+}
+
+// HasRole reports whether account holds role, either as an admin or because
+// it was granted the role.
+func HasRole(config *params.SecurityConfig, statedb StateDB, role Role, account common.Address) bool {
+	if config.IsAdmin(account) {
+		return true
+	}
+	module := config.Governance()
+	if statedb.GetState(module, roleSlot(RoleAdmin, account)) != (common.Hash{}) {
+		return true
+	}
+	return statedb.GetState(module, roleSlot(role, account)) != (common.Hash{})
+}
+
+// setRole grants or revokes role of account on behalf of by.
+func setRole(config *params.SecurityConfig, statedb StateDB, role Role, account, by common.Address, grant bool) {
+	var (
+		value = common.Hash{}
+		event = GovernanceABI.Events["RoleRevoked"]
+	)
+	if grant {
+		value, event = common.BigToHash(common.Big1), GovernanceABI.Events["RoleGranted"]
+	}
+	store(config, statedb, roleSlot(role, account), value)
+	statedb.AddLog(&types.Log{
+		Address: config.Governance(),
+		Topics:  []common.Hash{event.ID, common.BigToHash(big.NewInt(int64(role))), common.BytesToHash(account.Bytes()), common.BytesToHash(by.Bytes())},
+	})
+}
+
+// SetLevel sets the security level of account on behalf of by, announcing the
+// change in a SecurityLevelChanged event of the governance module.
+func SetLevel(config *params.SecurityConfig, statedb StateDB, by, account common.Address, level uint64, reason common.Hash) {
+	previous := statedb.GetSecurityLevel(account)
+	statedb.SetSecurityLevel(account, level)
+
+	event := GovernanceABI.Events["SecurityLevelChanged"]
+	data, err := event.Inputs.NonIndexed().Pack(uint8(previous), uint8(level), [32]byte(reason))
+	if err != nil {
+		panic(err) // Can't fail for well typed values
+	}
+	statedb.AddLog(&types.Log{
+		Address: config.Governance(),
+		Topics:  []common.Hash{event.ID, common.BytesToHash(account.Bytes()), common.BytesToHash(by.Bytes())},
+		Data:    data,
+	})
+}
+
+// ParseLevelChange returns the security level change announced by a log, or
+// nil if the log is no SecurityLevelChanged event of the governance module.
+// Only the fields known from the log itself are set.
+func ParseLevelChange(config *params.SecurityConfig, log *types.Log) *types.SecurityLevelChange {
+	event := GovernanceABI.Events["SecurityLevelChanged"]
+	if log.Address != config.Governance() || len(log.Topics) != 3 || log.Topics[0] != event.ID {
+		return nil
+	}
+	values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+	if err != nil {
+		return nil
+	}
+	return &types.SecurityLevelChange{
+		Account:     common.BytesToAddress(log.Topics[1].Bytes()),
+		By:          common.BytesToAddress(log.Topics[2].Bytes()),
+		Previous:    uint64(values[0].(uint8)),
+		Level:       uint64(values[1].(uint8)),
+		Reason:      common.Hash(values[2].([32]byte)),
+		BlockNumber: log.BlockNumber,
+		BlockHash:   log.BlockHash,
+		TxHash:      log.TxHash,
+	}
+}
+
+// store sets a storage slot of the module.
+func store(config *params.SecurityConfig, statedb StateDB, slot, value common.Hash) {
+	module := config.Governance()
+
+	// Like contracts, the module starts at nonce one so that its storage isn't
+	// cleared as an empty account
+	if statedb.GetNonce(module) == 0 {
+		statedb.SetNonce(module, 1)
+	}
+	statedb.SetState(module, slot, value)
+}
+
+// roleSlot returns the storage slot of the module recording that account was
+// granted role.
+func roleSlot(role Role, account common.Address) common.Hash {
+	return crypto.Keccak256Hash(account.Bytes(), []byte{byte(role)})
+}
+
+// createLevelSlot is the storage slot of the module holding the amended lowest
+// level allowed to create contracts.
+var createLevelSlot = crypto.Keccak256Hash([]byte("createLevel"))
+
+// levelRuleSlot returns the storage slot of the module holding the rule amended
+// for calls from callerLevel to calleeLevel.
+func levelRuleSlot(callerLevel, calleeLevel uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("levelRule"), binary.BigEndian.AppendUint64(nil, callerLevel), binary.BigEndian.AppendUint64(nil, calleeLevel))
+}
+
+// pairRuleSlot returns the storage slot of the module holding the rule amended
+// for calls from caller to callee.
+func pairRuleSlot(caller, callee common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("pairRule"), caller.Bytes(), callee.Bytes())
+}
+
+// valueLimitSlot returns the storage slot of the module holding the value limit
+// amended for level.
+func valueLimitSlot(level uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("valueLimit"), binary.BigEndian.AppendUint64(nil, level))
+}
+
+// revertReason abi encodes err as the reason of a revert, as Error(string).
+func revertReason(err error) []byte {
+	str, _ := abi.NewType("string", "", nil)
+	data, _ := abi.Arguments{{Type: str}}.Pack(err.Error())
+	return append(crypto.Keccak256([]byte("Error(string)"))[:4], data...)
+}
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,217 @@
+package governance
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func pack(t *testing.T, method string, args ...interface{}) []byte {
+	t.Helper()
+	input, err := GovernanceABI.Pack(method, args...)
+	if err != nil {
+		t.Fatalf("failed to pack %s: %v", method, err)
+	}
+	return input
+}
+
+// Tests that security levels are only changed by accounts holding the right to,
+// and that every change is announced in an event.
+func TestGovernanceRoles(t *testing.T) {
+	var (
+		admin   = common.Address{0xad}
+		locker  = common.Address{0x10}
+		account = common.Address{0xaa}
+		reason  = common.Hash{0x01}
+		config  = &params.SecurityConfig{Admins: []common.Address{admin}}
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetTxContext(common.Hash{0x77}, 0)
+	statedb.SetSecurityLevel(account, 2)
+
+	for i, tt := range []struct {
+		caller   common.Address
+		input    []byte
+		readOnly bool
+		err      error
+		level    uint64
+	}{
+		{caller: locker, input: pack(t, "lock", account, reason), err: ErrUnauthorized, level: 2},
+		{caller: locker, input: pack(t, "grantRole", uint8(RoleLock), locker), err: ErrUnauthorized, level: 2},
+		{caller: admin, input: pack(t, "grantRole", uint8(RoleLock), locker), level: 2},
+		{caller: locker, input: pack(t, "lock", account, reason), readOnly: true, err: ErrWriteProtection, level: 2},
+		{caller: locker, input: pack(t, "lock", account, reason), level: 0},
+		{caller: admin, input: pack(t, "setSecurityLevel", account, uint8(3), reason), err: ErrUnauthorized, level: 0}, // locked
+		{caller: locker, input: pack(t, "unlock", account, reason), err: ErrUnauthorized, level: 0},
+		{caller: admin, input: pack(t, "unlock", account, reason), level: 1},
+		{caller: admin, input: pack(t, "setSecurityLevel", account, uint8(params.MaxSecurityLevel+1), reason), err: ErrInvalidLevel, level: 1},
+		{caller: admin, input: pack(t, "setSecurityLevel", account, uint8(5), reason), level: 5},
+		{caller: admin, input: pack(t, "revokeRole", uint8(RoleLock), locker), level: 5},
+		{caller: locker, input: pack(t, "lock", account, reason), err: ErrUnauthorized, level: 5},
+	} {
+		_, err := Run(config, statedb, tt.caller, tt.input, tt.readOnly)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if have := statedb.GetSecurityLevel(account); have != tt.level {
+			t.Errorf("test %d: level mismatch: have %d, want %d", i, have, tt.level)
+		}
+	}
+	// Check the level changes announced, lock, unlock and set
+	var changes []*types.SecurityLevelChange
+	for _, log := range statedb.GetLogs(common.Hash{0x77}, 1, common.Hash{}) {
+		if change := ParseLevelChange(config, log); change != nil {
+			changes = append(changes, change)
+		}
+	}
+	want := []struct {
+		by              common.Address
+		previous, level uint64
+	}{{locker, 2, 0}, {admin, 0, 1}, {admin, 1, 5}}
+	if len(changes) != len(want) {
+		t.Fatalf("change count mismatch: have %d, want %d", len(changes), len(want))
+	}
+	for i, change := range changes {
+		if change.Account != account || change.By != want[i].by || change.Previous != want[i].previous || change.Level != want[i].level || change.Reason != reason {
+			t.Errorf("change %d mismatch: have %+v, want %+v", i, change, want[i])
+		}
+	}
+}
+
+// Tests that the views of the module report roles and levels.
+func TestGovernanceViews(t *testing.T) {
+	var (
+		admin  = common.Address{0xad}
+		config = &params.SecurityConfig{Admins: []common.Address{admin}}
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetSecurityLevel(common.Address{0xaa}, 4)
+
+	ret, err := Run(config, statedb, common.Address{}, pack(t, "hasRole", uint8(RoleSetLevel), admin), true)
+	if err != nil {
+		t.Fatalf("hasRole failed: %v", err)
+	}
+	if have, _ := GovernanceABI.Unpack("hasRole", ret); !have[0].(bool) {
+		t.Error("admin lacks role")
+	}
+	ret, err = Run(config, statedb, common.Address{}, pack(t, "securityLevel", common.Address{0xaa}), true)
+	if err != nil {
+		t.Fatalf("securityLevel failed: %v", err)
+	}
+	if have, _ := GovernanceABI.Unpack("securityLevel", ret); have[0].(uint8) != 4 {
+		t.Errorf("level mismatch: have %v, want 4", have[0])
+	}
+}
+
+// Tests that the call policy of the chain config is amended by accounts holding
+// the right to, and that amendments can be undone to inherit it again.
+func TestGovernancePolicy(t *testing.T) {
+	var (
+		admin  = common.Address{0xad}
+		amend  = common.Address{0x10}
+		caller = common.Address{0xaa}
+		callee = common.Address{0xbb}
+		config = &params.SecurityConfig{
+			Admins: []common.Address{admin},
+			Policy: &params.SecurityPolicy{
+				Rules:       []params.SecurityRule{{Caller: 3, Callee: 2, Allow: true}},
+				Allow:       []params.SecurityPair{{Caller: caller, Callee: callee}},
+				ValueLimits: []params.SecurityValueLimit{{Level: 1, Limit: big.NewInt(100)}},
+				CreateLevel: 2,
+			},
+		}
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	// Check the policy of the chain config and the default rule
+	if !LevelAllowed(config, statedb, 3, 2) || LevelAllowed(config, statedb, 2, 1) || !LevelAllowed(config, statedb, 1, 2) {
+		t.Error("chain config level rules not in effect")
+	}
+	if !PairAllowed(config, statedb, caller, callee) || PairAllowed(config, statedb, callee, caller) {
+		t.Error("chain config allow-list not in effect")
+	}
+	if limit := ValueLimit(config, statedb, 1); limit == nil || limit.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("value limit mismatch: have %v, want 100", limit)
+	}
+	if limit := ValueLimit(config, statedb, 2); limit != nil {
+		t.Errorf("value limit mismatch: have %v, want none", limit)
+	}
+	if level := CreateLevel(config, statedb); level != 2 {
+		t.Errorf("create level mismatch: have %d, want 2", level)
+	}
+	// Amend the policy and check the amendments take precedence
+	for i, tt := range []struct {
+		caller common.Address
+		input  []byte
+		err    error
+	}{
+		{caller: amend, input: pack(t, "setCallRule", uint8(3), uint8(2), uint8(RuleDeny)), err: ErrUnauthorized},
+		{caller: admin, input: pack(t, "grantRole", uint8(RolePolicy), amend)},
+		{caller: amend, input: pack(t, "setCallRule", uint8(3), uint8(2), uint8(RuleDeny))},
+		{caller: amend, input: pack(t, "setCallRule", uint8(2), uint8(1), uint8(RuleAllow))},
+		{caller: amend, input: pack(t, "setCallRule", uint8(2), uint8(params.MaxSecurityLevel+1), uint8(RuleAllow)), err: ErrInvalidLevel},
+		{caller: amend, input: pack(t, "setCallRule", uint8(2), uint8(1), uint8(numRules)), err: ErrInvalidRule},
+		{caller: amend, input: pack(t, "setPairRule", caller, callee, uint8(RuleDeny))},
+		{caller: amend, input: pack(t, "setPairRule", callee, caller, uint8(RuleAllow))},
+		{caller: amend, input: pack(t, "setValueLimit", uint8(1), big.NewInt(0))},
+		{caller: amend, input: pack(t, "setValueLimit", uint8(2), abi.MaxUint256)},
+		{caller: amend, input: pack(t, "setCreateLevel", uint8(0))},
+	} {
+		if _, err := Run(config, statedb, tt.caller, tt.input, false); !errors.Is(err, tt.err) {
+			t.Fatalf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if LevelAllowed(config, statedb, 3, 2) || !LevelAllowed(config, statedb, 2, 1) {
+		t.Error("amended level rules not in effect")
+	}
+	if PairAllowed(config, statedb, caller, callee) || !PairAllowed(config, statedb, callee, caller) {
+		t.Error("amended allow-list not in effect")
+	}
+	if limit := ValueLimit(config, statedb, 1); limit == nil || limit.Sign() != 0 {
+		t.Errorf("value limit mismatch: have %v, want 0", limit)
+	}
+	if limit := ValueLimit(config, statedb, 2); limit == nil || limit.Cmp(new(big.Int).Sub(abi.MaxUint256, common.Big1)) != 0 {
+		t.Errorf("saturated value limit mismatch: have %v", limit)
+	}
+	if level := CreateLevel(config, statedb); level != 0 {
+		t.Errorf("create level mismatch: have %d, want 0", level)
+	}
+	// Undo the amendments and check the chain config applies again
+	for _, input := range [][]byte{
+		pack(t, "setCallRule", uint8(3), uint8(2), uint8(RuleInherit)),
+		pack(t, "setPairRule", caller, callee, uint8(RuleInherit)),
+		pack(t, "clearValueLimit", uint8(1)),
+	} {
+		if _, err := Run(config, statedb, amend, input, false); err != nil {
+			t.Fatalf("failed to undo amendment: %v", err)
+		}
+	}
+	if !LevelAllowed(config, statedb, 3, 2) || !PairAllowed(config, statedb, caller, callee) {
+		t.Error("chain config policy not inherited again")
+	}
+	if limit := ValueLimit(config, statedb, 1); limit == nil || limit.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("value limit mismatch: have %v, want 100", limit)
+	}
+	// Check the views report the amended policy
+	ret, err := Run(config, statedb, common.Address{}, pack(t, "valueLimit", uint8(2)), true)
+	if err != nil {
+		t.Fatalf("valueLimit failed: %v", err)
+	}
+	if have, _ := GovernanceABI.Unpack("valueLimit", ret); !have[0].(bool) {
+		t.Error("amended value limit not reported")
+	}
+	ret, err = Run(config, statedb, common.Address{}, pack(t, "callAllowed", uint8(2), uint8(1)), true)
+	if err != nil {
+		t.Fatalf("callAllowed failed: %v", err)
+	}
+	if have, _ := GovernanceABI.Unpack("callAllowed", ret); !have[0].(bool) {
+		t.Error("amended level rule not reported")
+	}
+}
@@ -152,6 +152,9 @@ type CallMsg struct {
 	Data      []byte          // input data, usually an ABI-encoded contract method invocation
 
 	AccessList types.AccessList // EIP-2930 access list.
+
+	FeeCurrency *common.Address // contract of the voucher paying the gas, nil for native payment
+	Voucher     string          // name of the voucher paying the gas
 }
 
 // A ContractCaller provides contract calls, essentially transactions that are executed by
@@ -35,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -42,6 +43,8 @@ import (
 	"github.com/ethereum/go-ethereum/eth/gasestimator"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/merkeldag"
+	"github.com/ethereum/go-ethereum/merkeldag/mdagdb"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -664,15 +667,58 @@ func (s *BlockChainAPI) GetSecurityLevel(ctx context.Context, address common.Add
 	return hexutil.Uint64(b), state.Error()
 }
 
-// Result structs for GetProof
+// GetInterest returns the amount of wei the given address could claim from the
+// interest it accrued up to the given block.
+func (s *BlockChainAPI) GetInterest(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	interest := core.ClaimableInterest(s.b.ChainConfig(), state, address, header.Number)
+	return (*hexutil.Big)(interest.ToBig()), state.Error()
+}
+
+// ExtendedAccountResult is the result of a GetAccountExtended operation.
+type ExtendedAccountResult struct {
+	Address          common.Address `json:"address"`
+	Balance          *hexutil.Big   `json:"balance"`
+	Interest         *hexutil.Big   `json:"interest"`
+	LastAccrualBlock hexutil.Uint64 `json:"lastAccrualBlock"`
+	SecurityLevel    hexutil.Uint64 `json:"securityLevel"`
+}
+
+// GetAccountExtended returns the balance, the interest in wei claimable at the
+// given block, the block interest was last accrued at and the security level of
+// the given address, as stored in the state of the given block.
+func (s *BlockChainAPI) GetAccountExtended(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*ExtendedAccountResult, error) {
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	lastAccrual := state.GetInterestBlock(address)
+	interest := core.ClaimableInterest(s.b.ChainConfig(), state, address, header.Number)
+	return &ExtendedAccountResult{
+		Address:          address,
+		Balance:          (*hexutil.Big)(state.GetBalance(address).ToBig()),
+		Interest:         (*hexutil.Big)(interest.ToBig()),
+		LastAccrualBlock: hexutil.Uint64(lastAccrual),
+		SecurityLevel:    hexutil.Uint64(state.GetSecurityLevel(address)),
+	}, state.Error()
+}
+
+// Result structs for GetProof. The interest is given as stored in the account,
+// i.e. as a fixed point number with the precision of the chain's interest model.
 type AccountResult struct {
-	Address      common.Address  `json:"address"`
-	AccountProof []string        `json:"accountProof"`
-	Balance      *hexutil.Big    `json:"balance"`
-	CodeHash     common.Hash     `json:"codeHash"`
-	Nonce        hexutil.Uint64  `json:"nonce"`
-	StorageHash  common.Hash     `json:"storageHash"`
-	StorageProof []StorageResult `json:"storageProof"`
+	Address          common.Address  `json:"address"`
+	AccountProof     []string        `json:"accountProof"`
+	Balance          *hexutil.Big    `json:"balance"`
+	CodeHash         common.Hash     `json:"codeHash"`
+	Nonce            hexutil.Uint64  `json:"nonce"`
+	StorageHash      common.Hash     `json:"storageHash"`
+	StorageProof     []StorageResult `json:"storageProof"`
+	SecurityLevel    hexutil.Uint64  `json:"securityLevel"`
+	Interest         *hexutil.Big    `json:"interest"`
+	LastAccrualBlock hexutil.Uint64  `json:"lastAccrualBlock"`
 }
 
 type StorageResult struct {
@@ -761,16 +807,90 @@ func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, st
 	}
 	balance := statedb.GetBalance(address).ToBig()
 	return &AccountResult{
-		Address:      address,
-		AccountProof: accountProof,
-		Balance:      (*hexutil.Big)(balance),
-		CodeHash:     codeHash,
-		Nonce:        hexutil.Uint64(statedb.GetNonce(address)),
-		StorageHash:  storageRoot,
-		StorageProof: storageProof,
+		Address:          address,
+		AccountProof:     accountProof,
+		Balance:          (*hexutil.Big)(balance),
+		CodeHash:         codeHash,
+		Nonce:            hexutil.Uint64(statedb.GetNonce(address)),
+		StorageHash:      storageRoot,
+		StorageProof:     storageProof,
+		SecurityLevel:    hexutil.Uint64(statedb.GetSecurityLevel(address)),
+		Interest:         (*hexutil.Big)(statedb.GetInterest(address).ToBig()),
+		LastAccrualBlock: hexutil.Uint64(statedb.GetInterestBlock(address)),
 	}, statedb.Error()
 }
 
+// TaintLeafProof proves a leaf of the tainted-address DAG against its root.
+type TaintLeafProof struct {
+	Index    hexutil.Uint64  `json:"index"`
+	Data     hexutil.Bytes   `json:"data"`
+	Siblings []hexutil.Bytes `json:"siblings"` // From the leaf up to the root
+}
+
+func newTaintLeafProof(proof *merkeldag.Proof) *TaintLeafProof {
+	if proof == nil {
+		return nil
+	}
+	siblings := make([]hexutil.Bytes, len(proof.Siblings))
+	for i, sibling := range proof.Siblings {
+		siblings[i] = sibling
+	}
+	return &TaintLeafProof{
+		Index:    hexutil.Uint64(proof.Index),
+		Data:     proof.Data,
+		Siblings: siblings,
+	}
+}
+
+// TaintProofResult is the result of a GetTaintProof operation. Tainted
+// addresses come with the proof of their leaf, the others with the proofs of
+// the adjacent leaves below and above the address in the sorted DAG. Neither
+// is given if the root is empty, i.e. no address was tainted yet.
+type TaintProofResult struct {
+	Address common.Address  `json:"address"`
+	Root    hexutil.Bytes   `json:"root"`
+	Tainted bool            `json:"tainted"`
+	Proof   *TaintLeafProof `json:"proof,omitempty"`
+	Lower   *TaintLeafProof `json:"lower,omitempty"`
+	Upper   *TaintLeafProof `json:"upper,omitempty"`
+}
+
+// GetTaintProof returns the proof that the given address is or isn't in the
+// tainted-address DAG the given block commits to.
+func (s *BlockChainAPI) GetTaintProof(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*TaintProofResult, error) {
+	header, err := s.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	result := &TaintProofResult{Address: address, Root: header.Tainted}
+	if len(header.Tainted) == 0 {
+		return result, nil
+	}
+	// Only the nodes on the searched and proven paths are read from the database
+	dag, err := mdagdb.NewMerkleDAGDB(s.b.ChainDb()).LoadVersion(header.Tainted)
+	if err != nil {
+		return nil, err
+	}
+	index, ok, err := dag.Search(address.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		proof, err := dag.Prove(index)
+		if err != nil {
+			return nil, err
+		}
+		result.Tainted, result.Proof = true, newTaintLeafProof(proof)
+		return result, nil
+	}
+	proof, err := dag.ProveAbsence(address.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	result.Lower, result.Upper = newTaintLeafProof(proof.Lower), newTaintLeafProof(proof.Upper)
+	return result, nil
+}
+
 // decodeHash parses a hex-encoded 32-byte hash. The input may optionally
 // be prefixed by 0x and can have a byte length up to 32.
 func decodeHash(s string) (h common.Hash, inputLength int, err error) {
@@ -953,6 +1073,59 @@ func (s *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.
 	return result, nil
 }
 
+// VoucherArgs names a voucher of a fee currency contract to quote the rate of.
+type VoucherArgs struct {
+	Currency common.Address `json:"currency"`
+	Voucher  string         `json:"voucher"`
+}
+
+// GetVoucherRates returns the voucher rate snapshot of the given block, i.e. the
+// rates its transactions converted voucher fees at, along with the rates of the
+// requested vouchers, quoted on the state the block started from. For the
+// pending block, the vouchers of the latest block's snapshot and the requested
+// ones are quoted on the latest state, as the next block will convert fees at.
+func (s *BlockChainAPI) GetVoucherRates(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, vouchers *[]VoucherArgs) ([]types.VoucherRate, error) {
+	var requested []types.VoucherRate
+	if vouchers != nil {
+		for _, v := range *vouchers {
+			requested = append(requested, types.VoucherRate{Currency: v.Currency, Voucher: v.Voucher})
+		}
+	}
+	if number, ok := blockNrOrHash.Number(); ok && number == rpc.PendingBlockNumber {
+		state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+		if state == nil || err != nil {
+			return nil, err
+		}
+		blockCtx := core.NewEVMBlockContext(header, NewChainContext(ctx, s.b), nil)
+		blockCtx.BlockNumber = new(big.Int).Add(header.Number, common.Big1)
+
+		rates := rawdb.ReadVoucherRates(s.b.ChainDb(), header.Hash(), header.Number.Uint64())
+		return core.NewVoucherRates(s.b.ChainConfig(), blockCtx, state).Snapshot(append(rates, requested...)), nil
+	}
+	header, err := s.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	rates := rawdb.ReadVoucherRates(s.b.ChainDb(), header.Hash(), header.Number.Uint64())
+	if len(requested) > 0 {
+		// The genesis block has no parent, its rates are those of its own state
+		start := rpc.BlockNumberOrHashWithHash(header.Hash(), false)
+		if header.Number.Sign() > 0 {
+			start = rpc.BlockNumberOrHashWithHash(header.ParentHash, false)
+		}
+		state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, start)
+		if state == nil || err != nil {
+			return nil, err
+		}
+		blockCtx := core.NewEVMBlockContext(header, NewChainContext(ctx, s.b), nil)
+		rates = core.NewVoucherRates(s.b.ChainConfig(), blockCtx, state).Snapshot(append(rates, requested...))
+	}
+	if rates == nil {
+		rates = []types.VoucherRate{}
+	}
+	return rates, nil
+}
+
 // OverrideAccount indicates the overriding fields of account during the execution
 // of a message call.
 // Note, state and stateDiff can't be specified at the same time. If state is
@@ -1163,13 +1336,13 @@ func (s *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrO
 	}
 	result, err := DoCall(ctx, s.b, args, *blockNrOrHash, overrides, blockOverrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
 	if err != nil {
-		return nil, err
+		return nil, wrapSecurityLevelError(err)
 	}
 	// If the result contains a revert reason, try to unpack and return it.
 	if len(result.Revert()) > 0 {
 		return nil, newRevertError(result.Revert())
 	}
-	return result.Return(), result.Err
+	return result.Return(), wrapSecurityLevelError(result.Err)
 }
 
 // DoEstimateGas returns the lowest possible gas limit that allows the transaction to run
@@ -1204,7 +1377,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 		if len(revert) > 0 {
 			return 0, newRevertError(revert)
 		}
-		return 0, err
+		return 0, wrapSecurityLevelError(err)
 	}
 	return hexutil.Uint64(estimate), nil
 }
@@ -1263,6 +1436,9 @@ func RPCMarshalHeader(head *types.Header) map[string]interface{} {
 	if head.ParentBeaconRoot != nil {
 		result["parentBeaconBlockRoot"] = head.ParentBeaconRoot
 	}
+	if len(head.Tainted) > 0 {
+		result["tainted"] = hexutil.Bytes(head.Tainted)
+	}
 	return result
 }
 
@@ -1343,6 +1519,8 @@ type RPCTransaction struct {
 	R                   *hexutil.Big      `json:"r"`
 	S                   *hexutil.Big      `json:"s"`
 	YParity             *hexutil.Uint64   `json:"yParity,omitempty"`
+	FeeCurrency         *common.Address   `json:"feeCurrency,omitempty"`
+	Voucher             string            `json:"voucher,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1417,6 +1595,23 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		}
 		result.MaxFeePerBlobGas = (*hexutil.Big)(tx.BlobGasFeeCap())
 		result.BlobVersionedHashes = tx.BlobHashes()
+
+	case types.VoucherTxType:
+		al := tx.AccessList()
+		yparity := hexutil.Uint64(v.Sign())
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.YParity = &yparity
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		// if the transaction has been mined, compute the effective gas price
+		if baseFee != nil && blockHash != (common.Hash{}) {
+			result.GasPrice = (*hexutil.Big)(effectiveGasPrice(tx, baseFee))
+		} else {
+			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
+		}
+		result.FeeCurrency = tx.FeeCurrency()
+		result.Voucher = tx.Voucher()
 	}
 	return result
 }
@@ -1739,6 +1934,13 @@ func marshalReceipt(receipt *types.Receipt, blockHash common.Hash, blockNumber u
 		fields["blobGasUsed"] = hexutil.Uint64(receipt.BlobGasUsed)
 		fields["blobGasPrice"] = (*hexutil.Big)(receipt.BlobGasPrice)
 	}
+	if tx.Type() == types.VoucherTxType {
+		fields["feeSettlement"] = receipt.Settlement
+	}
+	if receipt.InterestFee != nil {
+		fields["interestFee"] = (*hexutil.Big)(receipt.InterestFee)
+		fields["balanceFee"] = (*hexutil.Big)(receipt.BalanceFee)
+	}
 
 	// If the ContractAddress is 20 0x0 bytes, assume it is not a contract creation
 	if receipt.ContractAddress != (common.Address{}) {
@@ -2088,6 +2290,20 @@ func (s *DebugAPI) GetRawTransaction(ctx context.Context, hash common.Hash) (hex
 	return tx.MarshalBinary()
 }
 
+// SecurityLevelHistory returns the security level changes made to the given
+// account through the governance module on the canonical chain, oldest first.
+func (api *DebugAPI) SecurityLevelHistory(ctx context.Context, address common.Address) ([]types.SecurityLevelChange, error) {
+	db := api.b.ChainDb()
+
+	history := make([]types.SecurityLevelChange, 0)
+	for _, change := range rawdb.ReadSecurityLevelHistory(db, address) {
+		if rawdb.ReadCanonicalHash(db, change.BlockNumber) == change.BlockHash {
+			history = append(history, change)
+		}
+	}
+	return history, nil
+}
+
 // PrintBlock retrieves a block and returns its pretty printed form.
 func (api *DebugAPI) PrintBlock(ctx context.Context, number uint64) (string, error) {
 	block, _ := api.b.BlockByNumber(ctx, rpc.BlockNumber(number))
@@ -48,6 +48,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/blocktest"
+	"github.com/ethereum/go-ethereum/merkeldag"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/holiman/uint256"
@@ -1862,3 +1863,82 @@ func testRPCResponseWithFile(t *testing.T, testid int, result interface{}, rpc s
 	}
 	require.JSONEqf(t, string(want), string(data), "test %d: json not match, want: %s, have: %s", testid, string(want), string(data))
 }
+
+func TestGetTaintProof(t *testing.T) {
+	t.Parallel()
+
+	var (
+		acc     = newAccounts(1)[0]
+		config  = *params.TestChainConfig
+		genesis = &core.Genesis{
+			Config: &config,
+			Alloc:  core.GenesisAlloc{acc.addr: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(&config)
+	)
+	config.Security = &params.SecurityConfig{Admins: []common.Address{acc.addr}}
+	// Taint two addresses in the first block
+	data := []byte{0x0d, 0x03, 0x02}
+	data = append(data, common.Address{0xaa}.Bytes()...)
+	data = append(data, common.Address{0xcc}.Bytes()...)
+
+	backend := newTestBackend(t, 1, genesis, ethash.NewFaker(), func(i int, b *core.BlockGen) {
+		tx, _ := types.SignNewTx(acc.key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(acc.addr),
+			GasPrice: b.BaseFee(),
+			Gas:      100000,
+			To:       &common.Address{0xdd},
+			Data:     data,
+		})
+		b.AddTx(tx)
+	})
+	api := NewBlockChainAPI(backend)
+	root := backend.chain.CurrentBlock().Tainted
+
+	toProof := func(p *TaintLeafProof) *merkeldag.Proof {
+		if p == nil {
+			return nil
+		}
+		siblings := make([][]byte, len(p.Siblings))
+		for i, sibling := range p.Siblings {
+			siblings[i] = sibling
+		}
+		return &merkeldag.Proof{Index: uint64(p.Index), Data: p.Data, Siblings: siblings}
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	// Tainted addresses are proven by their leaf
+	result, err := api.GetTaintProof(context.Background(), common.Address{0xcc}, latest)
+	if err != nil {
+		t.Fatalf("failed to get taint proof: %v", err)
+	}
+	if !result.Tainted || result.Proof == nil || !bytes.Equal(result.Root, root) {
+		t.Fatalf("tainted address not proven: %+v", result)
+	}
+	if err := toProof(result.Proof).Verify(root); err != nil {
+		t.Errorf("membership proof doesn't verify: %v", err)
+	}
+	if have := common.BytesToAddress(result.Proof.Data); have != (common.Address{0xcc}) {
+		t.Errorf("membership proof for wrong address: %v", have)
+	}
+	// Others by the adjacent leaves
+	result, err = api.GetTaintProof(context.Background(), common.Address{0xbb}, latest)
+	if err != nil {
+		t.Fatalf("failed to get taint proof: %v", err)
+	}
+	if result.Tainted || result.Proof != nil {
+		t.Fatalf("untainted address proven tainted: %+v", result)
+	}
+	absence := &merkeldag.AbsenceProof{Lower: toProof(result.Lower), Upper: toProof(result.Upper)}
+	if err := absence.Verify(root, common.Address{0xbb}.Bytes()); err != nil {
+		t.Errorf("non-membership proof doesn't verify: %v", err)
+	}
+	// Nothing to prove before any address was tainted
+	result, err = api.GetTaintProof(context.Background(), common.Address{0xaa}, rpc.BlockNumberOrHashWithNumber(0))
+	if err != nil {
+		t.Fatalf("failed to get taint proof: %v", err)
+	}
+	if result.Tainted || len(result.Root) != 0 || result.Proof != nil || result.Lower != nil || result.Upper != nil {
+		t.Errorf("proof against the empty root: %+v", result)
+	}
+}
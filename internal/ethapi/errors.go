@@ -17,9 +17,11 @@
 package ethapi
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/vm"
 )
@@ -56,6 +58,49 @@ func newRevertError(revert []byte) *revertError {
 	}
 }
 
+// securityLevelError is an API error that indicates a call violating the rules
+// of the security levels, with the levels of the accounts involved as data.
+type securityLevelError struct {
+	error
+	data securityLevelErrorData
+}
+
+type securityLevelErrorData struct {
+	Caller      common.Address `json:"caller"`
+	CallerLevel hexutil.Uint64 `json:"callerLevel"`
+	Callee      common.Address `json:"callee"`
+	CalleeLevel hexutil.Uint64 `json:"calleeLevel"`
+}
+
+// ErrorCode returns the JSON error code for a rejected call.
+// See: https://github.com/ethereum/wiki/wiki/JSON-RPC-Error-Codes-Improvement-Proposal
+func (e *securityLevelError) ErrorCode() int {
+	return -32003
+}
+
+// ErrorData returns the accounts involved and their levels.
+func (e *securityLevelError) ErrorData() interface{} {
+	return e.data
+}
+
+// wrapSecurityLevelError turns security level violations into securityLevelError,
+// leaving other errors untouched.
+func wrapSecurityLevelError(err error) error {
+	var violation *vm.SecurityLevelError
+	if !errors.As(err, &violation) {
+		return err
+	}
+	return &securityLevelError{
+		error: err,
+		data: securityLevelErrorData{
+			Caller:      violation.Caller,
+			CallerLevel: hexutil.Uint64(violation.CallerLevel),
+			Callee:      violation.Callee,
+			CalleeLevel: hexutil.Uint64(violation.CalleeLevel),
+		},
+	}
+}
+
 // TxIndexingError is an API error that indicates the transaction indexing is not
 // fully finished yet with JSON error code and a binary data blob.
 type TxIndexingError struct{}
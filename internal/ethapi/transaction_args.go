@@ -68,6 +68,10 @@ type TransactionArgs struct {
 	SignatureData  *hexutil.Bytes  `json:"signatureData,omitempty"`
 	PublicKey      *hexutil.Bytes  `json:"publicKey,omitempty"`
 	PublicKeyIndex *hexutil.Uint64 `json:"publicKeyIndex,omitempty"`
+
+	// Introduced by VoucherTxType
+	FeeCurrency *common.Address `json:"feeCurrency,omitempty"`
+	Voucher     *string         `json:"voucher,omitempty"`
 }
 
 // from retrieves the transaction sender address.
@@ -116,6 +120,14 @@ func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend) error {
 	if args.To == nil && len(args.data()) == 0 {
 		return errors.New(`contract creation without any data provided`)
 	}
+	if args.FeeCurrency != nil {
+		if args.Voucher == nil || *args.Voucher == "" {
+			return errors.New(`voucher transactions need the "voucher" paying the fee`)
+		}
+		if args.GasPrice != nil || args.BlobHashes != nil {
+			return errors.New(`voucher transactions only support "maxFeePerGas" and "maxPriorityFeePerGas"`)
+		}
+	}
 	// Estimate the gas usage if necessary.
 	if args.Gas == nil {
 		// These fields are immutable during the estimation, safe to
@@ -130,6 +142,8 @@ func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend) error {
 			Value:                args.Value,
 			Data:                 (*hexutil.Bytes)(&data),
 			AccessList:           args.AccessList,
+			FeeCurrency:          args.FeeCurrency,
+			Voucher:              args.Voucher,
 		}
 		latestBlockNr := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
 		estimated, err := DoEstimateGas(ctx, b, callArgs, latestBlockNr, nil, b.RPCGasCap())
@@ -352,6 +366,10 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (*
 	if args.PublicKeyIndex != nil {
 		publicKeyIndex = uint64(*args.PublicKeyIndex)
 	}
+	var voucher string
+	if args.Voucher != nil {
+		voucher = *args.Voucher
+	}
 
 	msg := &core.Message{
 		From:              addr,
@@ -371,6 +389,8 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (*
 		SignatureData:     signatureData,
 		PublicKey:         publicKey,
 		PublicKeyIndex:    publicKeyIndex,
+		FeeCurrency:       args.FeeCurrency,
+		Voucher:           voucher,
 	}
 	return msg, nil
 }
@@ -452,6 +472,24 @@ func (args *TransactionArgs) toTransaction() *types.Transaction {
 			BlobHashes: args.BlobHashes,
 			BlobFeeCap: uint256.MustFromBig((*big.Int)(args.BlobFeeCap)),
 		}
+	case args.FeeCurrency != nil:
+		al := types.AccessList{}
+		if args.AccessList != nil {
+			al = *args.AccessList
+		}
+		data = &types.VoucherTx{
+			To:          args.To,
+			ChainID:     (*big.Int)(args.ChainID),
+			Nonce:       uint64(*args.Nonce),
+			Gas:         uint64(*args.Gas),
+			GasFeeCap:   (*big.Int)(args.MaxFeePerGas),
+			GasTipCap:   (*big.Int)(args.MaxPriorityFeePerGas),
+			Value:       (*big.Int)(args.Value),
+			Data:        args.data(),
+			AccessList:  al,
+			FeeCurrency: *args.FeeCurrency,
+			Voucher:     *args.Voucher,
+		}
 	case args.MaxFeePerGas != nil:
 		al := types.AccessList{}
 		if args.AccessList != nil {
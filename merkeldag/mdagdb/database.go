@@ -3,24 +3,46 @@ package mdagdb
 import (
 	"bytes"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/merkeldag"
 )
 
+var (
+	nodePrefix    = []byte("mdag-node-")    // nodePrefix + hash -> 序列化的节点
+	versionPrefix = []byte("mdag-version-") // versionPrefix + 根哈希 -> 该版本的当前索引
+)
+
+// nodeKey 返回节点在数据库中的键
+func nodeKey(hash []byte) []byte {
+	return append(append([]byte{}, nodePrefix...), hash...)
+}
+
+// versionKey 返回根哈希对应版本在数据库中的键
+func versionKey(root []byte) []byte {
+	return append(append([]byte{}, versionPrefix...), root...)
+}
+
+// nodeCacheSize 是缓存的节点数，足够缓存最大的 DAG 的所有节点
+const nodeCacheSize = 2 << merkeldag.MaxTreeDepth
+
+// MerkleDAGDB 保存 DAG 的节点和版本。DAG 的节点在访问时才从数据库加载，
+// 节点不会再修改，最近加载或保存的节点按哈希缓存，缓存中的节点都已在数据库中
 type MerkleDAGDB struct {
 	db        ethdb.Database
 	mu        sync.RWMutex
-	nodeCache map[string]*merkeldag.Node
+	nodeCache *lru.Cache[string, *merkeldag.Node]
 }
 
 // NewMerkleDAGDB 创建新的数据库实例
 func NewMerkleDAGDB(db ethdb.Database) *MerkleDAGDB {
 	return &MerkleDAGDB{
 		db:        db,
-		nodeCache: make(map[string]*merkeldag.Node),
+		nodeCache: lru.NewCache[string, *merkeldag.Node](nodeCacheSize),
 	}
 }
 
@@ -56,15 +78,16 @@ func (mdb *MerkleDAGDB) SaveDAG(dag *merkeldag.MerkelDAG) error {
 	return mdb.saveNode(dag.GetRoot())
 }
 
-// saveNode 递归保存节点
+// saveNode 递归保存节点，只有哈希的节点和缓存中的节点已在数据库中，共享的
+// 空子树不需要保存
 func (mdb *MerkleDAGDB) saveNode(node *merkeldag.Node) error {
-	if node == nil {
+	if node == nil || node.GetData() == nil || node.IsEmptySubtree() {
 		return nil
 	}
 
 	// 检查节点是否已存在
 	hashStr := string(node.GetHash())
-	if _, exists := mdb.nodeCache[hashStr]; exists {
+	if mdb.nodeCache.Contains(hashStr) {
 		return nil
 	}
 
@@ -94,11 +117,11 @@ func (mdb *MerkleDAGDB) saveNode(node *merkeldag.Node) error {
 		return err
 	}
 
-	if err := mdb.db.Put(node.GetHash(), buf.Bytes()); err != nil {
+	if err := mdb.db.Put(nodeKey(node.GetHash()), buf.Bytes()); err != nil {
 		return err
 	}
 
-	mdb.nodeCache[hashStr] = node
+	mdb.nodeCache.Add(hashStr, node)
 	return nil
 }
 
@@ -107,24 +130,15 @@ func (mdb *MerkleDAGDB) LoadDAG() (*merkeldag.MerkelDAG, error) {
 	mdb.mu.Lock()
 	defer mdb.mu.Unlock()
 
-	// 清空缓存
-	mdb.nodeCache = make(map[string]*merkeldag.Node)
-
 	// 加载当前索引
 	index, err := mdb.loadIndex()
 	if err != nil {
 		return nil, err
 	}
 
-	// 创建新的 DAG
-	dag, err := merkeldag.NewMerkelDAG()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create new DAG: %v", err)
-	}
-
 	// 如果索引为0，说明是空树
 	if index == 0 {
-		return dag, nil
+		return merkeldag.NewMerkelDAG()
 	}
 
 	// 加载根节点哈希
@@ -133,72 +147,88 @@ func (mdb *MerkleDAGDB) LoadDAG() (*merkeldag.MerkelDAG, error) {
 		return nil, fmt.Errorf("failed to load root hash: %v", err)
 	}
 
-	// 递归加载根节点及其所有子节点
-	root, err := mdb.loadNode(rootHash)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load root node: %v", err)
-	}
-
-	// 设置 DAG 的状态
-	dag.SetRoot(root)
-	if err := dag.SetCurrentIndex(index); err != nil {
-		return nil, fmt.Errorf("failed to set current index: %v", err)
-	}
-
-	return dag, nil
+	// 节点在访问时才加载
+	return merkeldag.OpenMerkelDAG(merkeldag.NewHashNode(rootHash), index, mdb)
 }
 
-// loadNode 递归加载节点
-func (mdb *MerkleDAGDB) loadNode(hash []byte) (*merkeldag.Node, error) {
-	if hash == nil {
-		return nil, nil
-	}
-
-	// 检查缓存
-	hashStr := string(hash)
-	if node, exists := mdb.nodeCache[hashStr]; exists {
+// ResolveNode 按哈希加载节点，子节点只有哈希，在访问时再加载。加载的节点
+// 会校验哈希
+func (mdb *MerkleDAGDB) ResolveNode(hash []byte) (*merkeldag.Node, error) {
+	if node, ok := mdb.nodeCache.Get(string(hash)); ok {
 		return node, nil
 	}
-
-	// 从数据库加载
-	data, err := mdb.db.Get(hash)
+	data, err := mdb.db.Get(nodeKey(hash))
 	if err != nil {
-		return nil, fmt.Errorf("failed to load node data: %v", err)
+		return nil, fmt.Errorf("failed to load node %x: %v", hash, err)
 	}
 
 	var sNode serialNode
 	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&sNode); err != nil {
 		return nil, fmt.Errorf("failed to decode node: %v", err)
 	}
+	if sNode.Data == nil {
+		sNode.Data = []byte{} // gob 不区分空数据和 nil
+	}
 
-	// 创建新节点
+	// 创建新节点，子节点只有哈希
 	node, err := merkeldag.NewNode(sNode.Data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create node: %v", err)
 	}
+	if sNode.Left != nil {
+		node.SetLeft(merkeldag.NewHashNode(sNode.Left))
+	}
+	if sNode.Right != nil {
+		node.SetRight(merkeldag.NewHashNode(sNode.Right))
+	}
+	if !bytes.Equal(node.GetHash(), hash) {
+		return nil, fmt.Errorf("node %x corrupted, hash %x", hash, node.GetHash())
+	}
+	mdb.nodeCache.Add(string(hash), node)
+	return node, nil
+}
 
-	// 添加到缓存（在加载子节点之前，防止循环引用）
-	mdb.nodeCache[hashStr] = node
+// SaveVersion 保存 DAG 的当前版本，之后可按根哈希加载。各版本共享
+// 相同的节点，只有新节点会写入数据库
+func (mdb *MerkleDAGDB) SaveVersion(dag *merkeldag.MerkelDAG) error {
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
 
-	// 递归加载左子节点
-	if sNode.Left != nil {
-		left, err := mdb.loadNode(sNode.Left)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load left child: %v", err)
-		}
-		node.SetLeft(left)
+	if dag == nil || dag.GetRoot() == nil {
+		return errors.New("dag is nil")
+	}
+	if err := mdb.saveNode(dag.GetRoot()); err != nil {
+		return err
 	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(dag.GetCurrentIndex()); err != nil {
+		return err
+	}
+	return mdb.db.Put(versionKey(dag.GetRoot().GetHash()), buf.Bytes())
+}
 
-	// 递归加载右子节点
-	if sNode.Right != nil {
-		right, err := mdb.loadNode(sNode.Right)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load right child: %v", err)
-		}
-		node.SetRight(right)
+// LoadVersion 按根哈希打开 DAG 的某个版本，空的根哈希表示空的 DAG。节点在
+// 访问时才加载，修改 DAG 时复制被修改的节点，不影响已保存的版本
+func (mdb *MerkleDAGDB) LoadVersion(root []byte) (*merkeldag.MerkelDAG, error) {
+	if len(root) == 0 {
+		return merkeldag.NewMerkelDAG()
 	}
+	mdb.mu.Lock()
+	defer mdb.mu.Unlock()
 
-	return node, nil
+	data, err := mdb.db.Get(versionKey(root))
+	if err != nil {
+		return nil, fmt.Errorf("unknown dag version %x", root)
+	}
+	var index uint64
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&index); err != nil {
+		return nil, err
+	}
+	dag, err := merkeldag.OpenMerkelDAG(merkeldag.NewHashNode(root), index, mdb)
+	if err != nil {
+		return nil, fmt.Errorf("dag version %x corrupted: %v", root, err)
+	}
+	return dag, nil
 }
 
 // 保存当前索引
@@ -226,7 +256,5 @@ func (mdb *MerkleDAGDB) loadIndex() (uint64, error) {
 
 // ClearCache 清除缓存
 func (mdb *MerkleDAGDB) ClearCache() {
-	mdb.mu.Lock()
-	defer mdb.mu.Unlock()
-	mdb.nodeCache = make(map[string]*merkeldag.Node)
+	mdb.nodeCache.Purge()
 }
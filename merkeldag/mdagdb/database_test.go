@@ -2,9 +2,11 @@ package mdagdb
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/merkeldag"
 )
 
@@ -156,3 +158,129 @@ func TestMerkleDAGDB_LoadNonExistentDAG(t *testing.T) {
 		t.Error("Loading non-existent DAG should return DAG with index 0")
 	}
 }
+
+func TestMerkleDAGDB_SaveAndLoadVersion(t *testing.T) {
+	memDB := rawdb.NewMemoryDatabase()
+	dagDB := NewMerkleDAGDB(memDB)
+
+	// 空的根哈希对应空的 DAG
+	dag, err := dagDB.LoadVersion(nil)
+	if err != nil {
+		t.Fatalf("Failed to load empty version: %v", err)
+	}
+	if !dag.IsEmpty() {
+		t.Error("Empty root should load an empty DAG")
+	}
+
+	// 依次保存两个版本
+	var roots [][]byte
+	for _, data := range [][]byte{[]byte("data1"), []byte("data2")} {
+		if err := dag.Insert(data); err != nil {
+			t.Fatalf("Failed to insert data: %v", err)
+		}
+		if err := dagDB.SaveVersion(dag); err != nil {
+			t.Fatalf("Failed to save version: %v", err)
+		}
+		roots = append(roots, dag.GetRoot().GetHash())
+	}
+
+	// 各版本相互独立，旧版本不受之后插入的影响
+	for i, root := range roots {
+		loaded, err := dagDB.LoadVersion(root)
+		if err != nil {
+			t.Fatalf("Failed to load version %d: %v", i, err)
+		}
+		if loaded.GetCurrentIndex() != uint64(i+1) {
+			t.Errorf("Version %d index mismatch: got %d, want %d", i, loaded.GetCurrentIndex(), i+1)
+		}
+		if !bytes.Equal(loaded.GetRoot().GetHash(), root) {
+			t.Errorf("Version %d root hash mismatch", i)
+		}
+		if _, ok := loaded.IndexOf([]byte("data2")); ok != (i == 1) {
+			t.Errorf("Version %d membership of data2 mismatch: got %v", i, ok)
+		}
+	}
+
+	// 在旧版本上插入不影响已保存的新版本
+	old, _ := dagDB.LoadVersion(roots[0])
+	if err := old.Insert([]byte("data3")); err != nil {
+		t.Fatalf("Failed to insert data: %v", err)
+	}
+	if loaded, err := dagDB.LoadVersion(roots[1]); err != nil || !bytes.Equal(loaded.GetRoot().GetHash(), roots[1]) {
+		t.Errorf("Version 1 changed after inserting into version 0: %v", err)
+	}
+
+	// 未知的根哈希
+	if _, err := dagDB.LoadVersion([]byte("unknown")); err == nil {
+		t.Error("Loading an unknown version should fail")
+	}
+}
+
+// countingDB 统计从数据库读取的节点数
+type countingDB struct {
+	ethdb.Database
+	reads int
+}
+
+func (db *countingDB) Get(key []byte) ([]byte, error) {
+	if bytes.HasPrefix(key, nodePrefix) {
+		db.reads++
+	}
+	return db.Database.Get(key)
+}
+
+func TestMerkleDAGDB_LoadVersionLazily(t *testing.T) {
+	memDB := rawdb.NewMemoryDatabase()
+	dag, _ := merkeldag.NewMerkelDAG()
+	for i := 0; i < 100; i++ {
+		if err := dag.Insert([]byte(fmt.Sprintf("data%03d", i))); err != nil {
+			t.Fatalf("Failed to insert data: %v", err)
+		}
+	}
+	if err := NewMerkleDAGDB(memDB).SaveVersion(dag); err != nil {
+		t.Fatalf("Failed to save version: %v", err)
+	}
+	root := dag.GetRoot().GetHash()
+
+	// 打开版本和证明一个叶子节点只加载经过的节点
+	db := &countingDB{Database: memDB}
+	dagDB := NewMerkleDAGDB(db)
+	loaded, err := dagDB.LoadVersion(root)
+	if err != nil {
+		t.Fatalf("Failed to load version: %v", err)
+	}
+	proof, err := loaded.Prove(42)
+	if err != nil {
+		t.Fatalf("Failed to prove leaf: %v", err)
+	}
+	if err := proof.Verify(root); err != nil || string(proof.Data) != "data042" {
+		t.Errorf("Proof of leaf 42 invalid: %v", err)
+	}
+	if max := 2 * (merkeldag.TreeDepth + 1); db.reads > max {
+		t.Errorf("Loaded %d nodes, want at most %d", db.reads, max)
+	}
+
+	// 缓存的节点在 DAG 之间共享，修改一个 DAG 不影响另一个
+	other, _ := dagDB.LoadVersion(root)
+	if err := loaded.Insert([]byte("data100")); err != nil {
+		t.Fatalf("Failed to insert data: %v", err)
+	}
+	if !bytes.Equal(other.GetRoot().GetHash(), root) {
+		t.Error("Inserting into a loaded version changed another one")
+	}
+	if proof, err := other.Prove(100); err != nil || !proof.Empty() || proof.Verify(root) != nil {
+		t.Errorf("Leaf 100 of the unchanged version not empty: %v", err)
+	}
+
+	// 只有新的节点会保存
+	reads := db.reads
+	if err := dagDB.SaveVersion(loaded); err != nil {
+		t.Fatalf("Failed to save version: %v", err)
+	}
+	if reopened, err := NewMerkleDAGDB(memDB).LoadVersion(loaded.GetRoot().GetHash()); err != nil || reopened.GetCurrentIndex() != 101 {
+		t.Errorf("Failed to reload the new version: %v", err)
+	}
+	if db.reads != reads {
+		t.Errorf("Saving read %d nodes", db.reads-reads)
+	}
+}
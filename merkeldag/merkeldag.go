@@ -5,12 +5,24 @@ import (
 	"fmt"
 )
 
-const TreeDepth = 8
-const MaxLeafNodes = 1 << TreeDepth // 2^18 个叶子节点
+// DAG 从 TreeDepth 层开始，叶子节点用完时深度加一，最多增长到 MaxTreeDepth 层。
+// 有序插入要右移之后所有的叶子节点，在 2^16 个叶子节点的 DAG 中间插入一个数据
+// 约需 60ms（节点已缓存）到 3.5s（节点都在数据库中），2^18 个叶子节点时从数据库
+// 加载需要 26s，所以 DAG 最多 2^16 个叶子节点
+const (
+	TreeDepth    = 8                 // 新建 DAG 的深度，2^8 个叶子节点
+	MaxTreeDepth = 16                // DAG 的最大深度
+	MaxLeafNodes = 1 << MaxTreeDepth // 2^16 个叶子节点
+)
+
+// emptyLeafData 是空叶子节点的数据
+var emptyLeafData = []byte("empty")
 
 type MerkelDAG struct {
 	root         *Node
-	currentIndex uint64 // 当前已使用的索引位置
+	depth        int          // 树的深度
+	currentIndex uint64       // 当前已使用的索引位置
+	resolver     NodeResolver // 加载只有哈希的节点，完全在内存中的 DAG 为 nil
 }
 
 // NewMerkelDAG 创建一个新的 Merkle 树
@@ -28,49 +40,74 @@ func NewMerkelDAG() (*MerkelDAG, error) {
 
 	return &MerkelDAG{
 		root:         root,
+		depth:        TreeDepth,
 		currentIndex: 0,
 	}, nil
 }
 
-// initializeEmptyTree 从根节点开始初始化空的树结构
-func InitializeEmptyTree(node *Node, depth int) error {
-	if node == nil {
-		return errors.New("node cannot be nil")
-	}
+// RestoreMerkelDAG 用已有的根节点和已使用的索引恢复 DAG，索引可以等于
+// 叶子节点数，即 DAG 已满
+func RestoreMerkelDAG(root *Node, index uint64) (*MerkelDAG, error) {
+	return OpenMerkelDAG(root, index, nil)
+}
 
-	if depth == 0 {
-		// 叶子节点，计算哈希
-		node.updateHash()
-		return nil
+// OpenMerkelDAG 用根节点和已使用的索引打开 DAG，只有哈希的节点在访问时由
+// resolver 加载，不会一次加载整个 DAG
+func OpenMerkelDAG(root *Node, index uint64, resolver NodeResolver) (*MerkelDAG, error) {
+	if root == nil {
+		return nil, errors.New("root cannot be nil")
 	}
-
-	// 创建子节点
-	left, err := NewNode([]byte("empty"))
+	depth, err := depthOf(root, resolver)
 	if err != nil {
-		return fmt.Errorf("failed to create left node: %v", err)
+		return nil, err
 	}
-	right, err := NewNode([]byte("empty"))
-	if err != nil {
-		return fmt.Errorf("failed to create right node: %v", err)
+	if depth > MaxTreeDepth || index > uint64(1)<<depth {
+		return nil, errors.New("index exceeds maximum leaf nodes")
 	}
+	return &MerkelDAG{
+		root:         root,
+		depth:        depth,
+		currentIndex: index,
+		resolver:     resolver,
+	}, nil
+}
 
-	// 设置节点关系
-	node.left = left
-	node.right = right
-	left.prev = node
-	right.prev = node
+// emptyNodes 是各层的空子树，第 i 个是深度为 i 的空子树。空子树在所有 DAG 之间
+// 共享，DAG 的空的部分不占用内存也不写入数据库
+var emptyNodes, emptyHashes = func() ([MaxTreeDepth + 1]*Node, map[string]*Node) {
+	var (
+		nodes  [MaxTreeDepth + 1]*Node
+		hashes = make(map[string]*Node)
+	)
+	nodes[0], _ = NewNode(emptyLeafData)
+	for i := 1; i <= MaxTreeDepth; i++ {
+		nodes[i] = &Node{left: nodes[i-1], right: nodes[i-1], data: emptyLeafData}
+		nodes[i].updateHash()
+	}
+	for _, node := range nodes {
+		hashes[string(node.hash)] = node
+	}
+	return nodes, hashes
+}()
+
+// IsEmptySubtree 检查节点是否是共享的空子树
+func (n *Node) IsEmptySubtree() bool {
+	return n != nil && emptyHashes[string(n.hash)] == n
+}
 
-	// 递归初始化子树
-	if err := InitializeEmptyTree(left, depth-1); err != nil {
-		return err
+// InitializeEmptyTree 把节点初始化为深度为 depth 的空树的根节点，子树是共享的
+// 空子树
+func InitializeEmptyTree(node *Node, depth int) error {
+	if node == nil {
+		return errors.New("node cannot be nil")
 	}
-	if err := InitializeEmptyTree(right, depth-1); err != nil {
-		return err
+	if depth < 0 || depth > MaxTreeDepth {
+		return fmt.Errorf("invalid depth %d", depth)
+	}
+	if depth > 0 {
+		node.left, node.right = emptyNodes[depth-1], emptyNodes[depth-1]
 	}
-
-	// 计算当前节点的哈希
 	node.updateHash()
-
 	return nil
 }
 
@@ -80,12 +117,11 @@ func (m *MerkelDAG) Insert(data []byte) error {
 		return errors.New("dag is nil")
 	}
 
-	if m.currentIndex >= MaxLeafNodes {
-		return errors.New("merkle tree is full")
+	if err := m.reserve(1); err != nil {
+		return err
 	}
-
-	// 直接使用 insertAtIndex，因为树结构已经存在
-	newRoot, err := insertAtIndex(m.root, data, m.currentIndex)
+	// 复制被修改的路径，之前的版本不变
+	newRoot, err := update(m.root, m.depth, m.currentIndex, [][]byte{data}, m.resolver)
 	if err != nil {
 		return err
 	}
@@ -95,6 +131,33 @@ func (m *MerkelDAG) Insert(data []byte) error {
 	return nil
 }
 
+// reserve 确保还有 n 个空的叶子节点可以插入，叶子节点不够时增长 DAG
+func (m *MerkelDAG) reserve(n uint64) error {
+	if n > MaxLeafNodes-m.currentIndex {
+		return errors.New("merkle tree is full")
+	}
+	for m.currentIndex+n > m.Capacity() {
+		m.grow()
+	}
+	return nil
+}
+
+// grow 把 DAG 的深度加一：原来的树成为新根节点的左子树，右子树是共享的
+// 空子树，已有叶子节点的索引不变
+func (m *MerkelDAG) grow() {
+	root := &Node{left: m.root, right: emptyNodes[m.depth], data: []byte("root")}
+	root.updateHash()
+	m.root, m.depth = root, m.depth+1
+}
+
+// Capacity 获取 DAG 当前深度下的叶子节点数
+func (m *MerkelDAG) Capacity() uint64 {
+	if m == nil {
+		return 0
+	}
+	return uint64(1) << m.depth
+}
+
 // GetRoot 获取 DAG 的根节点
 func (m *MerkelDAG) GetRoot() *Node {
 	if m == nil {
@@ -111,12 +174,14 @@ func (m *MerkelDAG) GetCurrentIndex() uint64 {
 	return m.currentIndex
 }
 
-// SetRoot 设置根节点
+// SetRoot 设置根节点，根节点必须完全在内存中
 func (m *MerkelDAG) SetRoot(root *Node) {
 	if m == nil {
 		return
 	}
 	m.root = root
+	m.depth, _ = depthOf(root, nil)
+	m.resolver = nil
 }
 
 // SetCurrentIndex 设置当前索引
@@ -124,7 +189,7 @@ func (m *MerkelDAG) SetCurrentIndex(index uint64) error {
 	if m == nil {
 		return errors.New("dag is nil")
 	}
-	if index >= MaxLeafNodes {
+	if index >= m.Capacity() {
 		return errors.New("index exceeds maximum leaf nodes")
 	}
 	m.currentIndex = index
@@ -133,5 +198,38 @@ func (m *MerkelDAG) SetCurrentIndex(index uint64) error {
 
 // IsEmpty 检查 DAG 是否为空
 func (m *MerkelDAG) IsEmpty() bool {
-	return m == nil || m.root == nil
+	return m == nil || m.root == nil || m.currentIndex == 0
+}
+
+// IsFull 检查 DAG 的叶子节点是否已用完
+func (m *MerkelDAG) IsFull() bool {
+	return m != nil && m.currentIndex >= MaxLeafNodes
+}
+
+// Copy 返回 DAG 的副本。节点不会修改，副本与原来的 DAG 共享所有节点，之后的
+// 修改互不影响
+func (m *MerkelDAG) Copy() *MerkelDAG {
+	if m == nil {
+		return nil
+	}
+	cpy := *m
+	return &cpy
+}
+
+// IndexOf 返回数据所在叶子节点的索引，依次比较已使用的叶子节点，有序 DAG
+// 应使用 Search
+func (m *MerkelDAG) IndexOf(data []byte) (uint64, bool) {
+	if m.IsEmpty() {
+		return 0, false
+	}
+	var leaves [][]byte
+	if err := collectLeaves(m.root, m.depth, 0, m.currentIndex, m.resolver, &leaves); err != nil {
+		return 0, false
+	}
+	for i, leaf := range leaves {
+		if string(leaf) == string(data) {
+			return uint64(i), true
+		}
+	}
+	return 0, false
 }
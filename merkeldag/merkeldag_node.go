@@ -3,17 +3,23 @@ package merkeldag
 import (
 	"crypto/sha256"
 	"errors"
+	"fmt"
 )
 
-// Node 表示 Merkle 树中的一个节点
+// Node 表示 Merkle 树中的一个节点。节点加入 DAG 后不再修改，可以在 DAG 的各个
+// 版本之间以及相同的子树之间共享，修改时复制从根节点到叶子节点的路径
 type Node struct {
 	left  *Node
 	right *Node
-	prev  *Node
 	data  []byte
 	hash  []byte
 }
 
+// NodeResolver 按哈希加载节点，节点的子节点可以只有哈希，在访问时再加载
+type NodeResolver interface {
+	ResolveNode(hash []byte) (*Node, error)
+}
+
 // NewNode 创建一个新的 Merkle 节点
 func NewNode(data []byte) (*Node, error) {
 	if data == nil {
@@ -24,7 +30,6 @@ func NewNode(data []byte) (*Node, error) {
 		data:  data,
 		left:  nil,
 		right: nil,
-		prev:  nil,
 	}
 
 	// 立即计算初始哈希值
@@ -34,6 +39,30 @@ func NewNode(data []byte) (*Node, error) {
 	return node, nil
 }
 
+// NewHashNode 创建一个只有哈希的节点，访问时由 NodeResolver 加载
+func NewHashNode(hash []byte) *Node {
+	return &Node{hash: hash}
+}
+
+// isHashNode 检查节点是否只有哈希，需要加载
+func (n *Node) isHashNode() bool {
+	return n != nil && n.data == nil && n.left == nil && n.right == nil
+}
+
+// resolve 返回节点的内容，只有哈希的节点是空子树或者由 resolver 加载
+func resolve(n *Node, resolver NodeResolver) (*Node, error) {
+	if !n.isHashNode() {
+		return n, nil
+	}
+	if empty, ok := emptyHashes[string(n.hash)]; ok {
+		return empty, nil
+	}
+	if resolver == nil {
+		return nil, fmt.Errorf("missing node %x", n.hash)
+	}
+	return resolver.ResolveNode(n.hash)
+}
+
 // calculateHash 计算节点的哈希值
 func (n *Node) calculateHash() []byte {
 	if n == nil {
@@ -59,23 +88,19 @@ func (n *Node) calculateHash() []byte {
 	return hash[:]
 }
 
-// updateHash 更新节点及其所有父节点的哈希值
+// updateHash 更新节点的哈希值
 func (n *Node) updateHash() {
 	if n == nil {
 		return
 	}
-
 	n.hash = n.calculateHash()
-	if n.prev != nil {
-		n.prev.updateHash()
-	}
 }
 
-// InsertNode 在 Merkle 树中插入新节点
+// InsertNode 在 Merkle 树中插入新节点，返回新的根节点，原来的树不变
 func InsertNode(root *Node, data []byte, index uint64) (*Node, error) {
 	if root == nil {
-		root, err := NewNode([]byte("root"))
-		if err != nil {
+		var err error
+		if root, err = NewNode([]byte("root")); err != nil {
 			return nil, err
 		}
 		// 初始化完整的空树结构
@@ -84,76 +109,134 @@ func InsertNode(root *Node, data []byte, index uint64) (*Node, error) {
 		}
 		// 树已经在 InitializeEmptyTree 中更新了哈希
 	}
-
+	depth, err := depthOf(root, nil)
+	if err != nil {
+		return nil, err
+	}
+	if index >= uint64(1)<<depth {
+		return nil, errors.New("index exceeds maximum leaf nodes")
+	}
 	// 插入叶子节点
-	return insertAtIndex(root, data, index)
+	return update(root, depth, index, [][]byte{data}, nil)
 }
 
-// insertAtIndex 在指定索引位置插入节点
-func insertAtIndex(root *Node, data []byte, index uint64) (*Node, error) {
-	if root == nil {
-		return nil, errors.New("root cannot be nil")
+// update 把以 node 为根、深度为 depth 的子树中从 start 开始的叶子节点依次替换为
+// leaves，返回新的子树。只复制被修改的路径上的节点，其他节点与原来的子树共享，
+// 原来的子树不变
+func update(node *Node, depth int, start uint64, leaves [][]byte, resolver NodeResolver) (*Node, error) {
+	if len(leaves) == 0 {
+		return node, nil
 	}
-
-	// 创建新的叶子节点
-	newNode, err := NewNode(data)
+	if depth == 0 {
+		return NewNode(leaves[0])
+	}
+	node, err := resolve(node, resolver)
 	if err != nil {
 		return nil, err
 	}
+	if node == nil || node.left == nil || node.right == nil {
+		return nil, errors.New("invalid tree structure")
+	}
+	var (
+		half  = uint64(1) << uint(depth-1)
+		left  = node.left
+		right = node.right
+	)
+	if start < half {
+		n := half - start
+		if n > uint64(len(leaves)) {
+			n = uint64(len(leaves))
+		}
+		if left, err = update(left, depth-1, start, leaves[:n], resolver); err != nil {
+			return nil, err
+		}
+		leaves, start = leaves[n:], half
+	}
+	if right, err = update(right, depth-1, start-half, leaves, resolver); err != nil {
+		return nil, err
+	}
+	copied := &Node{left: left, right: right, data: node.data}
+	copied.updateHash()
+	return copied, nil
+}
 
-	// 找到目标叶子节点的路径
-	current := root
-	// 使用固定的8层深度，从最高位开始遍历
-	path := make([]*Node, TreeDepth+1) // +1 包含根节点
-	path[0] = current
-
-	for i := 0; i < TreeDepth; i++ {
-		if current == nil {
+// leafAt 返回以 node 为根、深度为 depth 的子树中指定索引的叶子节点
+func leafAt(node *Node, depth int, index uint64, resolver NodeResolver) (*Node, error) {
+	for bit := depth - 1; bit >= 0; bit-- {
+		var err error
+		if node, err = resolve(node, resolver); err != nil {
+			return nil, err
+		}
+		if node == nil {
 			return nil, errors.New("invalid tree structure")
 		}
-
-		// 使用index的第(TreeDepth-1-i)位决定走左边还是右边
-		if (index & (1 << uint(TreeDepth-1-i))) == 0 {
-			current = current.left
+		// 从最高位开始，索引的每一位决定走左边还是右边
+		if index&(1<<uint(bit)) == 0 {
+			node = node.left
 		} else {
-			current = current.right
+			node = node.right
 		}
-		path[i+1] = current
-	}
-
-	// 替换叶子节点
-	parent := path[TreeDepth-1]
-	if parent.left == current {
-		parent.left = newNode
-	} else {
-		parent.right = newNode
 	}
-	newNode.prev = parent
-
-	// 从叶子节点开始，自底向上更新哈希值
-	for i := TreeDepth - 1; i >= 0; i-- {
-		path[i].updateHash()
+	if node == nil {
+		return nil, errors.New("invalid tree structure")
 	}
-
-	return root, nil
+	return resolve(node, resolver)
 }
 
-// GetRoot 获取树的根节点
-func GetRoot(node *Node) *Node {
+// collectLeaves 按顺序收集以 node 为根、深度为 depth 的子树中索引在 [from, to)
+// 之间的叶子节点的数据
+func collectLeaves(node *Node, depth int, from, to uint64, resolver NodeResolver, leaves *[][]byte) error {
+	if from >= to {
+		return nil
+	}
+	node, err := resolve(node, resolver)
+	if err != nil {
+		return err
+	}
 	if node == nil {
+		return errors.New("invalid tree structure")
+	}
+	if depth == 0 {
+		*leaves = append(*leaves, node.data)
 		return nil
 	}
+	half := uint64(1) << uint(depth-1)
+	if from < half {
+		end := to
+		if end > half {
+			end = half
+		}
+		if err := collectLeaves(node.left, depth-1, from, end, resolver, leaves); err != nil {
+			return err
+		}
+		from = half
+	}
+	if to > half {
+		return collectLeaves(node.right, depth-1, from-half, to-half, resolver, leaves)
+	}
+	return nil
+}
 
-	current := node
-	for current.prev != nil {
-		current = current.prev
+// depthOf 返回以 node 为根的完全二叉树的深度，由最左边的路径决定
+func depthOf(node *Node, resolver NodeResolver) (int, error) {
+	depth := 0
+	for {
+		var err error
+		if node, err = resolve(node, resolver); err != nil {
+			return 0, err
+		}
+		if node == nil || node.left == nil {
+			return depth, nil
+		}
+		node = node.left
+		depth++
 	}
-	return current
 }
 
-// Verify 验证节点的哈希值是否正确
+// Verify 验证节点的哈希值是否正确，只有哈希的节点不会加载，共享的空子树
+// 不需要验证
 func (n *Node) Verify() bool {
-	if n == nil {
+	if n == nil || n.isHashNode() || n.IsEmptySubtree() {
 		return true
 	}
 
@@ -165,7 +248,8 @@ func (n *Node) Verify() bool {
 	return n.left.Verify() && n.right.Verify()
 }
 
-// GetLeafNodes 获取所有叶子节点
+// GetLeafNodes 获取所有叶子节点，包括空的叶子节点，只能用于完全在内存中的
+// 小树
 func (n *Node) GetLeafNodes() []*Node {
 	leaves := make([]*Node, 0)
 	n.collectLeaves(&leaves)
@@ -216,22 +300,12 @@ func (n *Node) GetRight() *Node {
 	return n.right
 }
 
-func (n *Node) GetPrev() *Node {
-	if n == nil {
-		return nil
-	}
-	return n.prev
-}
-
-// Setter 方法
+// Setter 方法，只能用于还没有加入 DAG 的节点
 func (n *Node) SetLeft(left *Node) {
 	if n == nil {
 		return
 	}
 	n.left = left
-	if left != nil {
-		left.prev = n
-	}
 	n.updateHash()
 }
 
@@ -240,9 +314,6 @@ func (n *Node) SetRight(right *Node) {
 		return
 	}
 	n.right = right
-	if right != nil {
-		right.prev = n
-	}
 	n.updateHash()
 }
 
@@ -84,12 +84,12 @@ func TestMerkelDAG_SetCurrentIndex(t *testing.T) {
 		},
 		{
 			name:    "max index",
-			index:   MaxLeafNodes - 1,
+			index:   1<<TreeDepth - 1,
 			wantErr: false,
 		},
 		{
 			name:    "overflow index",
-			index:   MaxLeafNodes,
+			index:   1 << TreeDepth,
 			wantErr: true,
 		},
 	}
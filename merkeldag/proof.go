@@ -0,0 +1,253 @@
+package merkeldag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Proof 证明某个叶子节点属于根哈希对应的 DAG
+type Proof struct {
+	Index    uint64   // 叶子节点的索引
+	Data     []byte   // 叶子节点的数据
+	Siblings [][]byte // 从叶子节点到根节点路径上的兄弟节点哈希，自底向上
+}
+
+// Empty 检查证明的叶子节点是否为空
+func (p *Proof) Empty() bool {
+	return bytes.Equal(p.Data, emptyLeafData)
+}
+
+// Verify 检查证明是否与给定的根哈希一致
+func (p *Proof) Verify(root []byte) error {
+	if p == nil {
+		return errors.New("proof is nil")
+	}
+	if len(p.Siblings) < TreeDepth || len(p.Siblings) > MaxTreeDepth {
+		return fmt.Errorf("invalid proof length %d, want %d to %d", len(p.Siblings), TreeDepth, MaxTreeDepth)
+	}
+	if p.Index >= uint64(1)<<len(p.Siblings) {
+		return errors.New("index exceeds maximum leaf nodes")
+	}
+	hash := sha256.Sum256(p.Data)
+	for i, sibling := range p.Siblings {
+		// 索引的第 i 位决定当前节点是左子节点还是右子节点
+		if p.Index&(1<<uint(i)) == 0 {
+			hash = sha256.Sum256(append(hash[:], sibling...))
+		} else {
+			hash = sha256.Sum256(append(append([]byte{}, sibling...), hash[:]...))
+		}
+	}
+	if !bytes.Equal(hash[:], root) {
+		return errors.New("root hash mismatch")
+	}
+	return nil
+}
+
+// Prove 生成指定索引叶子节点的证明，空叶子节点也可以证明
+func (m *MerkelDAG) Prove(index uint64) (*Proof, error) {
+	if m == nil || m.root == nil {
+		return nil, errors.New("dag is nil")
+	}
+	depth := m.depth
+	if index >= uint64(1)<<depth {
+		return nil, errors.New("index exceeds maximum leaf nodes")
+	}
+	siblings := make([][]byte, depth)
+
+	current := m.root
+	for i := 0; i < depth; i++ {
+		var err error
+		if current, err = resolve(current, m.resolver); err != nil {
+			return nil, err
+		}
+		if current == nil {
+			return nil, errors.New("invalid tree structure")
+		}
+		// 与 leafAt 相同，从最高位开始决定走向
+		bit := depth - 1 - i
+		if index&(1<<uint(bit)) == 0 {
+			siblings[bit] = current.right.GetHash()
+			current = current.left
+		} else {
+			siblings[bit] = current.left.GetHash()
+			current = current.right
+		}
+	}
+	current, err := resolve(current, m.resolver)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, errors.New("invalid tree structure")
+	}
+	return &Proof{
+		Index:    index,
+		Data:     append([]byte{}, current.data...),
+		Siblings: siblings,
+	}, nil
+}
+
+// Search 在有序 DAG 中二分查找数据，返回数据所在的叶子节点的索引，数据不存在时
+// 返回应插入的位置。每次比较只加载从根节点到一个叶子节点的路径
+func (m *MerkelDAG) Search(data []byte) (uint64, bool, error) {
+	if m == nil || m.root == nil {
+		return 0, false, errors.New("dag is nil")
+	}
+	lo, hi := uint64(0), m.currentIndex
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		leaf, err := leafAt(m.root, m.depth, mid, m.resolver)
+		if err != nil {
+			return 0, false, err
+		}
+		switch cmp := bytes.Compare(leaf.data, data); {
+		case cmp == 0:
+			return mid, true, nil
+		case cmp < 0:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return lo, false, nil
+}
+
+// InsertSorted 按字节序插入新数据，使已使用的叶子节点保持有序，之后的数据依次
+// 右移。多个数据一起插入时，第一个插入位置之后的叶子节点只重写一次。有序 DAG
+// 可以证明数据不存在，只能用于所有数据都由 InsertSorted 插入的 DAG。插入失败时
+// DAG 不变
+func (m *MerkelDAG) InsertSorted(data ...[]byte) error {
+	if m == nil {
+		return errors.New("dag is nil")
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	sorted := make([][]byte, len(data))
+	copy(sorted, data)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	var pos uint64
+	for i, item := range sorted {
+		if item == nil {
+			return errors.New("data cannot be nil")
+		}
+		if bytes.Equal(item, emptyLeafData) {
+			return errors.New("data is reserved for empty leaves")
+		}
+		if i > 0 && bytes.Equal(item, sorted[i-1]) {
+			return errors.New("data already exists")
+		}
+		index, ok, err := m.Search(item)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return errors.New("data already exists")
+		}
+		if i == 0 {
+			pos = index
+		}
+	}
+	// 在副本上修改，失败时 DAG 不变
+	next := m.Copy()
+	if err := next.reserve(uint64(len(sorted))); err != nil {
+		return err
+	}
+	var leaves [][]byte
+	if err := collectLeaves(next.root, next.depth, pos, next.currentIndex, next.resolver, &leaves); err != nil {
+		return err
+	}
+	// 合并新数据和之后右移的数据，一次写入，只复制被修改的节点
+	merged := make([][]byte, 0, len(leaves)+len(sorted))
+	for len(leaves) > 0 || len(sorted) > 0 {
+		if len(sorted) == 0 || (len(leaves) > 0 && bytes.Compare(leaves[0], sorted[0]) < 0) {
+			merged, leaves = append(merged, leaves[0]), leaves[1:]
+		} else {
+			merged, sorted = append(merged, sorted[0]), sorted[1:]
+		}
+	}
+	root, err := update(next.root, next.depth, pos, merged, next.resolver)
+	if err != nil {
+		return err
+	}
+	next.root = root
+	next.currentIndex += uint64(len(data))
+	*m = *next
+	return nil
+}
+
+// AbsenceProof 证明数据不在有序 DAG 中：Lower 是小于数据的最大叶子节点，
+// Upper 是紧随其后的叶子节点，大于数据或者为空。数据小于所有叶子节点时
+// Lower 为空，DAG 已满且数据大于所有叶子节点时 Upper 为空
+type AbsenceProof struct {
+	Lower *Proof
+	Upper *Proof
+}
+
+// ProveAbsence 生成数据不在有序 DAG 中的证明
+func (m *MerkelDAG) ProveAbsence(data []byte) (*AbsenceProof, error) {
+	if m == nil || m.root == nil {
+		return nil, errors.New("dag is nil")
+	}
+	pos, ok, err := m.Search(data)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, errors.New("data exists")
+	}
+	proof := new(AbsenceProof)
+	if pos > 0 {
+		if proof.Lower, err = m.Prove(pos - 1); err != nil {
+			return nil, err
+		}
+	}
+	if pos < m.Capacity() {
+		if proof.Upper, err = m.Prove(pos); err != nil {
+			return nil, err
+		}
+	}
+	return proof, nil
+}
+
+// Verify 检查证明是否与给定的根哈希一致，并且证明了数据不在有序 DAG 中
+func (p *AbsenceProof) Verify(root []byte, data []byte) error {
+	if p == nil || (p.Lower == nil && p.Upper == nil) {
+		return errors.New("proof is empty")
+	}
+	if p.Lower != nil {
+		if err := p.Lower.Verify(root); err != nil {
+			return fmt.Errorf("invalid lower proof: %v", err)
+		}
+		if p.Lower.Empty() || bytes.Compare(p.Lower.Data, data) >= 0 {
+			return errors.New("lower leaf not below data")
+		}
+	}
+	if p.Upper != nil {
+		if err := p.Upper.Verify(root); err != nil {
+			return fmt.Errorf("invalid upper proof: %v", err)
+		}
+		if !p.Upper.Empty() && bytes.Compare(p.Upper.Data, data) <= 0 {
+			return errors.New("upper leaf not above data")
+		}
+	}
+	// 两个叶子节点必须相邻，缺少的一侧必须是 DAG 的边界
+	switch {
+	case p.Lower == nil:
+		if p.Upper.Index != 0 {
+			return errors.New("upper leaf not the first leaf")
+		}
+	case p.Upper == nil:
+		if p.Lower.Index != uint64(1)<<len(p.Lower.Siblings)-1 {
+			return errors.New("lower leaf not the last leaf")
+		}
+	case p.Upper.Index != p.Lower.Index+1:
+		return errors.New("leaves not adjacent")
+	}
+	return nil
+}
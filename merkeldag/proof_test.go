@@ -0,0 +1,249 @@
+package merkeldag
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMerkelDAG_Prove(t *testing.T) {
+	dag, err := NewMerkelDAG()
+	if err != nil {
+		t.Fatalf("Failed to create DAG: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := dag.Insert([]byte(fmt.Sprintf("data%d", i))); err != nil {
+			t.Fatalf("Failed to insert data: %v", err)
+		}
+	}
+	root := dag.GetRoot().GetHash()
+
+	// 已使用和空的叶子节点都可以证明
+	for _, index := range []uint64{0, 1, 2, 3, dag.Capacity() - 1} {
+		proof, err := dag.Prove(index)
+		if err != nil {
+			t.Fatalf("Prove(%d) error = %v", index, err)
+		}
+		if err := proof.Verify(root); err != nil {
+			t.Errorf("Proof of leaf %d doesn't verify: %v", index, err)
+		}
+		if proof.Empty() != (index >= 3) {
+			t.Errorf("Proof of leaf %d empty = %v", index, proof.Empty())
+		}
+	}
+	// 篡改的证明不能通过验证
+	proof, _ := dag.Prove(1)
+	proof.Data = []byte("data0")
+	if err := proof.Verify(root); err == nil {
+		t.Error("Proof with altered data verifies")
+	}
+	proof, _ = dag.Prove(1)
+	proof.Index = 0
+	if err := proof.Verify(root); err == nil {
+		t.Error("Proof with altered index verifies")
+	}
+	if _, err := dag.Prove(dag.Capacity()); err == nil {
+		t.Error("Prove() should fail beyond the last leaf")
+	}
+}
+
+func TestMerkelDAG_InsertSorted(t *testing.T) {
+	dag, err := NewMerkelDAG()
+	if err != nil {
+		t.Fatalf("Failed to create DAG: %v", err)
+	}
+	for _, data := range []string{"c", "a", "d", "b"} {
+		if err := dag.InsertSorted([]byte(data)); err != nil {
+			t.Fatalf("InsertSorted(%s) error = %v", data, err)
+		}
+	}
+	if err := dag.InsertSorted([]byte("b")); err == nil {
+		t.Error("InsertSorted() should fail for existing data")
+	}
+	if err := dag.InsertSorted(emptyLeafData); err == nil {
+		t.Error("InsertSorted() should fail for the empty leaf data")
+	}
+	// 插入顺序不影响结果
+	want, _ := NewMerkelDAG()
+	for _, data := range []string{"a", "b", "c", "d"} {
+		want.Insert([]byte(data))
+	}
+	if !bytes.Equal(dag.GetRoot().GetHash(), want.GetRoot().GetHash()) {
+		t.Error("Sorted insertion root mismatch")
+	}
+	if !dag.GetRoot().Verify() {
+		t.Error("Sorted DAG doesn't verify")
+	}
+	if index, ok := dag.IndexOf([]byte("c")); !ok || index != 2 {
+		t.Errorf("IndexOf(c) = %d, %v, want 2, true", index, ok)
+	}
+}
+
+func TestMerkelDAG_ProveAbsence(t *testing.T) {
+	dag, err := NewMerkelDAG()
+	if err != nil {
+		t.Fatalf("Failed to create DAG: %v", err)
+	}
+	for _, data := range []string{"b", "d"} {
+		if err := dag.InsertSorted([]byte(data)); err != nil {
+			t.Fatalf("InsertSorted(%s) error = %v", data, err)
+		}
+	}
+	root := dag.GetRoot().GetHash()
+
+	tests := []struct {
+		data         string
+		lower, upper bool
+	}{
+		{data: "a", upper: true},              // 小于所有数据
+		{data: "c", lower: true, upper: true}, // 两个数据之间
+		{data: "e", lower: true, upper: true}, // 大于所有数据，后面是空叶子节点
+	}
+	for _, tt := range tests {
+		proof, err := dag.ProveAbsence([]byte(tt.data))
+		if err != nil {
+			t.Fatalf("ProveAbsence(%s) error = %v", tt.data, err)
+		}
+		if (proof.Lower != nil) != tt.lower || (proof.Upper != nil) != tt.upper {
+			t.Errorf("ProveAbsence(%s) bounds = %v, %v", tt.data, proof.Lower != nil, proof.Upper != nil)
+		}
+		if err := proof.Verify(root, []byte(tt.data)); err != nil {
+			t.Errorf("Absence proof of %s doesn't verify: %v", tt.data, err)
+		}
+	}
+	if _, err := dag.ProveAbsence([]byte("b")); err == nil {
+		t.Error("ProveAbsence() should fail for existing data")
+	}
+	// 证明不能用于其它数据
+	proof, _ := dag.ProveAbsence([]byte("c"))
+	for _, data := range []string{"b", "d", "e"} {
+		if err := proof.Verify(root, []byte(data)); err == nil {
+			t.Errorf("Absence proof of c verifies for %s", data)
+		}
+	}
+	// 不相邻的叶子节点不能证明数据不存在
+	lower, _ := dag.Prove(0)
+	upper, _ := dag.Prove(2)
+	if err := (&AbsenceProof{Lower: lower, Upper: upper}).Verify(root, []byte("e")); err == nil {
+		t.Error("Absence proof with non-adjacent leaves verifies")
+	}
+	upper, _ = dag.Prove(1)
+	if err := (&AbsenceProof{Upper: upper}).Verify(root, []byte("c")); err == nil {
+		t.Error("Absence proof without lower bound verifies for an inner leaf")
+	}
+}
+
+func TestMerkelDAG_Grow(t *testing.T) {
+	dag, err := NewMerkelDAG()
+	if err != nil {
+		t.Fatalf("Failed to create DAG: %v", err)
+	}
+	full := int(dag.Capacity())
+	for i := 0; i < full; i++ {
+		if err := dag.InsertSorted([]byte(fmt.Sprintf("data%04d", i))); err != nil {
+			t.Fatalf("InsertSorted(%d) error = %v", i, err)
+		}
+	}
+	// 已满的 DAG 可以证明大于所有数据的数据不存在
+	proof, err := dag.ProveAbsence([]byte("data9999"))
+	if err != nil || proof.Upper != nil {
+		t.Fatalf("ProveAbsence() of full DAG = %v, %v", proof, err)
+	}
+	if err := proof.Verify(dag.GetRoot().GetHash(), []byte("data9999")); err != nil {
+		t.Errorf("Absence proof of full DAG doesn't verify: %v", err)
+	}
+	// 超出容量的数据使 DAG 增长一层，已有的叶子节点不变
+	if err := dag.InsertSorted([]byte("data9999")); err != nil {
+		t.Fatalf("InsertSorted() beyond capacity error = %v", err)
+	}
+	if have, want := dag.Capacity(), uint64(2*full); have != want {
+		t.Errorf("Capacity() = %d, want %d", have, want)
+	}
+	if have := dag.GetCurrentIndex(); have != uint64(full+1) {
+		t.Errorf("GetCurrentIndex() = %d, want %d", have, full+1)
+	}
+	if !dag.GetRoot().Verify() {
+		t.Error("Grown DAG doesn't verify")
+	}
+	root := dag.GetRoot().GetHash()
+	for _, index := range []uint64{0, uint64(full), dag.Capacity() - 1} {
+		proof, err := dag.Prove(index)
+		if err != nil {
+			t.Fatalf("Prove(%d) error = %v", index, err)
+		}
+		if len(proof.Siblings) != TreeDepth+1 {
+			t.Errorf("Proof of leaf %d length = %d, want %d", index, len(proof.Siblings), TreeDepth+1)
+		}
+		if err := proof.Verify(root); err != nil {
+			t.Errorf("Proof of leaf %d doesn't verify: %v", index, err)
+		}
+	}
+	if index, ok := dag.IndexOf([]byte("data9999")); !ok || index != uint64(full) {
+		t.Errorf("IndexOf(data9999) = %d, %v, want %d, true", index, ok, full)
+	}
+	proof, err = dag.ProveAbsence([]byte("dataA"))
+	if err != nil || proof.Upper == nil || !proof.Upper.Empty() {
+		t.Fatalf("ProveAbsence() after growth = %v, %v", proof, err)
+	}
+	if err := proof.Verify(root, []byte("dataA")); err != nil {
+		t.Errorf("Absence proof after growth doesn't verify: %v", err)
+	}
+}
+
+func TestMerkelDAG_InsertSortedBatch(t *testing.T) {
+	dag, err := NewMerkelDAG()
+	if err != nil {
+		t.Fatalf("Failed to create DAG: %v", err)
+	}
+	want, _ := NewMerkelDAG()
+	var batch [][]byte
+	for i := 0; i < 3*int(dag.Capacity()); i += 3 {
+		if err := want.InsertSorted([]byte(fmt.Sprintf("data%04d", i))); err != nil {
+			t.Fatalf("InsertSorted(%d) error = %v", i, err)
+		}
+		batch = append(batch, []byte(fmt.Sprintf("data%04d", i)))
+	}
+	if err := dag.InsertSorted(batch...); err != nil {
+		t.Fatalf("InsertSorted() error = %v", err)
+	}
+	// 之后一次插入的数据使 DAG 增长，与逐个插入的结果相同
+	batch = [][]byte{[]byte("data0001"), []byte("data9999"), []byte("data0500")}
+	for _, data := range batch {
+		if err := want.InsertSorted(data); err != nil {
+			t.Fatalf("InsertSorted(%s) error = %v", data, err)
+		}
+	}
+	before := dag.Copy()
+	if err := dag.InsertSorted(batch...); err != nil {
+		t.Fatalf("InsertSorted() error = %v", err)
+	}
+	if !bytes.Equal(dag.GetRoot().GetHash(), want.GetRoot().GetHash()) || dag.GetCurrentIndex() != want.GetCurrentIndex() {
+		t.Error("Batch insertion doesn't match single insertions")
+	}
+	if before.GetCurrentIndex() != dag.GetCurrentIndex()-3 || before.Capacity() != dag.Capacity()/2 {
+		t.Error("Insertion changed the copy of the DAG")
+	}
+	// 失败的插入不改变 DAG
+	root := dag.GetRoot().GetHash()
+	if err := dag.InsertSorted([]byte("data0002"), []byte("data0003")); err == nil {
+		t.Error("InsertSorted() should fail for existing data")
+	}
+	if err := dag.InsertSorted([]byte("dataB"), []byte("dataB")); err == nil {
+		t.Error("InsertSorted() should fail for duplicate data")
+	}
+	if !bytes.Equal(dag.GetRoot().GetHash(), root) {
+		t.Error("Failed insertion changed the DAG")
+	}
+	// 二分查找返回数据的索引或者应插入的位置
+	for _, tt := range []struct {
+		data  string
+		index uint64
+		ok    bool
+	}{
+		{"data0000", 0, true}, {"data0001", 1, true}, {"data0002", 2, false}, {"data9999", 258, true}, {"dataB", 259, false},
+	} {
+		if index, ok, err := dag.Search([]byte(tt.data)); err != nil || index != tt.index || ok != tt.ok {
+			t.Errorf("Search(%s) = %d, %v, %v, want %d, %v", tt.data, index, ok, err, tt.index, tt.ok)
+		}
+	}
+}
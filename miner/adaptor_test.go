@@ -7,48 +7,117 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
+// The PoW difficulty, price and gas of a block derive from its parent header and
+// the parameters in effect, see core.CalcPowParams, and the moving averages from
+// its transactions, see core.CalcPowAverages. These tests drive the derivation
+// over simulated chains of headers.
+
+// adaptorChain is a chain of simulated headers, the grandparents the price
+// derivation looks up.
+type adaptorChain map[common.Hash]*types.Header
+
+func (c adaptorChain) Config() *params.ChainConfig                   { return params.TestChainConfig }
+func (c adaptorChain) CurrentHeader() *types.Header                  { return nil }
+func (c adaptorChain) GetHeaderByNumber(number uint64) *types.Header { return nil }
+func (c adaptorChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	return c[hash]
+}
+func (c adaptorChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return c[hash]
+}
+func (c adaptorChain) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+
+// next derives the header of the block after parent, with the given gas limit
+// and transactions.
+func (c adaptorChain) next(pow *params.PowParams, parent *types.Header, gasLimit uint64, txs types.Transactions) *types.Header {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   gasLimit,
+	}
+	header.PowDifficulty, header.PowPrice, header.PowGas = core.CalcPowParams(c, pow, parent)
+
+	ratio, usage := core.CalcPowAverages(pow, parent, header, txs)
+	header.AvgRatioNumerator, header.AvgRatioDenominator = ratio.Numerator, ratio.Denominator
+	header.AvgGasNumerator, header.AvgGasDenominator = usage.Numerator, usage.Denominator
+	c[header.Hash()] = header
+	return header
+}
+
+// adaptorTxs returns total transactions of the given gas each, the first pows
+// of them PoW transactions.
+func adaptorTxs(pows, total int, gas uint64) types.Transactions {
+	txs := make(types.Transactions, total)
+	for i := range txs {
+		if i < pows {
+			txs[i] = types.NewTx(&types.PowTx{Gas: gas, Value: new(big.Int)})
+		} else {
+			txs[i] = types.NewTx(&types.LegacyTx{Gas: gas, Value: new(big.Int)})
+		}
+	}
+	return txs
+}
+
+// gasParams returns the parameters of the gas tests.
+func gasParams(minGas, maxGas, initialGas uint64, alpha *common.Rational) *params.PowParams {
+	pow := params.DefaultPowParams()
+	pow.MinPowGas, pow.MaxPowGas, pow.InitialGas, pow.Alpha = minGas, maxGas, initialGas, alpha
+	return pow
+}
+
+// powParams returns the parameters of the difficulty and price tests.
+func powParams(targetPowRatio, alpha, fMin, fMax *common.Rational, initialDiff *big.Int, kp, ki *common.Rational, minPrice, maxPrice *big.Int) *params.PowParams {
+	pow := params.DefaultPowParams()
+	pow.TargetPowRatio, pow.Alpha, pow.Fmin, pow.Fmax = targetPowRatio, alpha, fMin, fMax
+	pow.Difficulty, pow.Kp, pow.Ki = initialDiff, kp, ki
+	pow.MinPrice, pow.MaxPrice = minPrice, maxPrice
+	return pow
+}
+
 // Test GasAdaptor
 
 func TestGasAdaptorNormal(t *testing.T) {
 	// Initialize with normal values
-	ga := NewGasAdaptor(1000, 10000, 5000, common.NewRational(3, 10))
+	pow := gasParams(1000, 10000, 5000, common.NewRational(3, 10))
+	chain := make(adaptorChain)
+	parent := &types.Header{Number: new(big.Int), AvgGasNumerator: 5000, AvgGasDenominator: 5000} // Simulating 1.0 ratio
 
 	// Test normal adjustment
-	currentRatio := common.NewRational(6000, 5000)   // Simulating 1.2 ratio
-	parentAvgRatio := common.NewRational(5000, 5000) // Simulating 1.0 ratio
-
-	newGas, newAvgRatio, newAvgDenom := ga.AdjustGas(currentRatio, parentAvgRatio)
+	header := chain.next(pow, parent, 5000, adaptorTxs(0, 1, 6000)) // Simulating 1.2 ratio
 
 	// Check if results are within expected range
-	if newGas < 1000 || newGas > 10000 {
-		t.Errorf("newGas out of range: got %d, want between %d and %d", newGas, 1000, 10000)
+	if header.PowGas < 1000 || header.PowGas > 10000 {
+		t.Errorf("newGas out of range: got %d, want between %d and %d", header.PowGas, 1000, 10000)
 	}
 
-	if newAvgRatio == 0 || newAvgDenom == 0 {
+	if header.AvgGasNumerator == 0 || header.AvgGasDenominator == 0 {
 		t.Error("average values should not be zero")
 	}
 }
 
 func TestGasAdaptorEdgeCases(t *testing.T) {
-	// Test min gas limit
-	ga := NewGasAdaptor(1000, 10000, 5000, common.NewRational(3, 10))
-	newGas, _, _ := ga.AdjustGas(
-		common.NewRational(100, 1000),  // Very low ratio
-		common.NewRational(1000, 1000), // Normal ratio
-	)
-	if newGas != 1000 {
-		t.Errorf("expected min gas %d, got %d", 1000, newGas)
+	// Test min gas limit: the gas shrinks by a tenth per block of low usage
+	pow := gasParams(1000, 10000, 5000, common.NewRational(3, 10))
+	chain := make(adaptorChain)
+	header := &types.Header{Number: new(big.Int), AvgGasNumerator: 1000, AvgGasDenominator: 1000} // Normal ratio
+	for i := 0; i < 50; i++ {
+		header = chain.next(pow, header, 10000, adaptorTxs(0, 1, 1000)) // Very low ratio
+	}
+	if header.PowGas != 1000 {
+		t.Errorf("expected min gas %d, got %d", 1000, header.PowGas)
 	}
 
-	// Test max gas limit
-	newGas, _, _ = ga.AdjustGas(
-		common.NewRational(20000, 1000), // Very high ratio
-		common.NewRational(1000, 1000),  // Normal ratio
-	)
-	if newGas != 10000 {
-		t.Errorf("expected max gas %d, got %d", 10000, newGas)
+	// Test max gas limit: the gas grows by a tenth per block of high usage
+	for i := 0; i < 50; i++ {
+		header = chain.next(pow, header, 1000, adaptorTxs(0, 1, 20000)) // Very high ratio
+	}
+	if header.PowGas != 10000 {
+		t.Errorf("expected max gas %d, got %d", 10000, header.PowGas)
 	}
 }
 
@@ -58,7 +127,7 @@ func TestPoWAdaptorNormal(t *testing.T) {
 	minPrice := big.NewInt(100)
 	maxPrice := big.NewInt(10000)
 
-	pa := NewPoWAdaptor(
+	pow := powParams(
 		common.NewRational(3, 10),  // targetPowRatio
 		common.NewRational(2, 10),  // alpha
 		common.NewRational(8, 10),  // fMin
@@ -69,27 +138,26 @@ func TestPoWAdaptorNormal(t *testing.T) {
 		minPrice,
 		maxPrice,
 	)
+	chain := make(adaptorChain)
 
 	// Test normal adjustment
-	currentPowRatio := common.NewRational(4, 10) // Current PoW ratio
-	parentAvgRatio := common.NewRational(3, 10)  // Parent average ratio
-	parentPrice := big.NewInt(1000)              // Parent price
-
-	newDiff, newPrice, newAvgRatio, newAvgDenom := pa.AdjustParameters(
-		currentPowRatio,
-		parentAvgRatio,
-		parentPrice,
-	)
+	parent := &types.Header{
+		Number:              new(big.Int),
+		AvgRatioNumerator:   3, // Parent average ratio
+		AvgRatioDenominator: 10,
+		PowPrice:            big.NewInt(1000), // Parent price
+	}
+	header := chain.next(pow, parent, 10*params.TxGas, adaptorTxs(4, 10, params.TxGas)) // Current PoW ratio
 
-	if newDiff.Cmp(big.NewInt(0)) <= 0 {
+	if header.PowDifficulty.Cmp(big.NewInt(0)) <= 0 {
 		t.Error("new difficulty should be positive")
 	}
 
-	if newPrice.Cmp(minPrice) < 0 || newPrice.Cmp(maxPrice) > 0 {
+	if header.PowPrice.Cmp(minPrice) < 0 || header.PowPrice.Cmp(maxPrice) > 0 {
 		t.Error("new price out of valid range")
 	}
 
-	if newAvgRatio == 0 || newAvgDenom == 0 {
+	if header.AvgRatioNumerator == 0 || header.AvgRatioDenominator == 0 {
 		t.Error("average values should not be zero")
 	}
 }
@@ -99,7 +167,7 @@ func TestPoWAdaptorEdgeCases(t *testing.T) {
 	minPrice := big.NewInt(100)
 	maxPrice := big.NewInt(10000)
 
-	pa := NewPoWAdaptor(
+	pow := powParams(
 		common.NewRational(3, 10),  // targetPowRatio
 		common.NewRational(2, 10),  // alpha
 		common.NewRational(8, 10),  // fMin
@@ -110,95 +178,92 @@ func TestPoWAdaptorEdgeCases(t *testing.T) {
 		minPrice,
 		maxPrice,
 	)
+	chain := make(adaptorChain)
 
 	// Test min price boundary
-	parentPrice := big.NewInt(100)
-	_, newPrice, _, _ := pa.AdjustParameters(
-		common.NewRational(1, 10), // very low ratio to push price down
-		common.NewRational(1, 10),
-		parentPrice,
-	)
+	parent := &types.Header{Number: new(big.Int), AvgRatioNumerator: 1, AvgRatioDenominator: 10, PowPrice: big.NewInt(100)}
+	header := chain.next(pow, parent, 10*params.TxGas, adaptorTxs(1, 10, params.TxGas)) // very low ratio
+	header = chain.next(pow, header, 10*params.TxGas, adaptorTxs(1, 10, params.TxGas))
 
-	if newPrice.Cmp(minPrice) < 0 {
+	if header.PowPrice.Cmp(minPrice) < 0 {
 		t.Error("price should not go below minimum")
 	}
 
 	// Test max price boundary
-	parentPrice = big.NewInt(9000)
-	_, newPrice, _, _ = pa.AdjustParameters(
-		common.NewRational(9, 10), // very high ratio to push price up
-		common.NewRational(9, 10),
-		parentPrice,
-	)
+	parent = &types.Header{Number: new(big.Int), AvgRatioNumerator: 9, AvgRatioDenominator: 10, PowPrice: big.NewInt(9000)}
+	header = chain.next(pow, parent, 10*params.TxGas, adaptorTxs(9, 10, params.TxGas)) // very high ratio
+	header = chain.next(pow, header, 10*params.TxGas, adaptorTxs(9, 10, params.TxGas))
 
-	if newPrice.Cmp(maxPrice) > 0 {
+	if header.PowPrice.Cmp(maxPrice) > 0 {
 		t.Error("price should not go above maximum")
 	}
 }
 
+// The derivation doesn't panic on parameters the old adaptor refused, it keeps
+// the fields of the header usable instead.
 func TestPoWAdaptorPanics(t *testing.T) {
 	testCases := []struct {
 		name string
-		fn   func()
+		pow  *params.PowParams
 	}{
 		{
 			name: "zero price",
-			fn: func() {
-				NewPoWAdaptor(
-					common.NewRational(3, 10),  // targetPowRatio
-					common.NewRational(2, 10),  // alpha
-					common.NewRational(8, 10),  // fMin
-					common.NewRational(12, 10), // fMax
-					big.NewInt(100),            // initialDiff
-					common.NewRational(1, 10),  // kp
-					common.NewRational(1, 100), // ki
-					big.NewInt(0),              // zero minPrice
-					big.NewInt(10000),          // maxPrice
-				)
-			},
+			pow: powParams(
+				common.NewRational(3, 10),  // targetPowRatio
+				common.NewRational(2, 10),  // alpha
+				common.NewRational(8, 10),  // fMin
+				common.NewRational(12, 10), // fMax
+				big.NewInt(100),            // initialDiff
+				common.NewRational(1, 10),  // kp
+				common.NewRational(1, 100), // ki
+				big.NewInt(0),              // zero minPrice
+				big.NewInt(10000),          // maxPrice
+			),
 		},
 		{
 			name: "min price greater than max price",
-			fn: func() {
-				NewPoWAdaptor(
-					common.NewRational(3, 10),  // targetPowRatio
-					common.NewRational(2, 10),  // alpha
-					common.NewRational(8, 10),  // fMin
-					common.NewRational(12, 10), // fMax
-					big.NewInt(100),            // initialDiff
-					common.NewRational(1, 10),  // kp
-					common.NewRational(1, 100), // ki
-					big.NewInt(100000),         // minPrice > maxPrice
-					big.NewInt(100),            // maxPrice
-				)
-			},
+			pow: powParams(
+				common.NewRational(3, 10),  // targetPowRatio
+				common.NewRational(2, 10),  // alpha
+				common.NewRational(8, 10),  // fMin
+				common.NewRational(12, 10), // fMax
+				big.NewInt(100),            // initialDiff
+				common.NewRational(1, 10),  // kp
+				common.NewRational(1, 100), // ki
+				big.NewInt(100000),         // minPrice > maxPrice
+				big.NewInt(100),            // maxPrice
+			),
 		},
 		{
 			name: "zero initial difficulty",
-			fn: func() {
-				NewPoWAdaptor(
-					common.NewRational(3, 10),  // targetPowRatio
-					common.NewRational(2, 10),  // alpha
-					common.NewRational(8, 10),  // fMin
-					common.NewRational(12, 10), // fMax
-					big.NewInt(0),              // zero initialDiff
-					common.NewRational(1, 10),  // kp
-					common.NewRational(1, 100), // ki
-					big.NewInt(100),            // minPrice
-					big.NewInt(100000),         // maxPrice
-				)
-			},
+			pow: powParams(
+				common.NewRational(3, 10),  // targetPowRatio
+				common.NewRational(2, 10),  // alpha
+				common.NewRational(8, 10),  // fMin
+				common.NewRational(12, 10), // fMax
+				big.NewInt(0),              // zero initialDiff
+				common.NewRational(1, 10),  // kp
+				common.NewRational(1, 100), // ki
+				big.NewInt(100),            // minPrice
+				big.NewInt(100000),         // maxPrice
+			),
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			defer func() {
-				if r := recover(); r == nil {
-					t.Errorf("expected panic for test case: %s", tc.name)
+				if r := recover(); r != nil {
+					t.Errorf("unexpected panic for test case: %s: %v", tc.name, r)
 				}
 			}()
-			tc.fn()
+			header := make(adaptorChain).next(tc.pow, &types.Header{Number: new(big.Int)}, params.TxGas, adaptorTxs(0, 1, params.TxGas))
+			if header.PowDifficulty.Sign() <= 0 {
+				t.Errorf("difficulty should be positive, got %v", header.PowDifficulty)
+			}
+			if header.PowPrice.Cmp(tc.pow.MaxPrice) > 0 {
+				t.Errorf("price should not go above maximum, got %v", header.PowPrice)
+			}
 		})
 	}
 }
@@ -209,7 +274,7 @@ func TestPoWAdaptorContinuousBlocks(t *testing.T) {
 	minPrice := big.NewInt(100)
 	maxPrice := big.NewInt(10000)
 
-	pa := NewPoWAdaptor(
+	pow := powParams(
 		common.NewRational(3, 10),  // targetPowRatio = 0.3
 		common.NewRational(2, 10),  // alpha = 0.2
 		common.NewRational(8, 10),  // fMin = 0.8
@@ -232,19 +297,30 @@ func TestPoWAdaptorContinuousBlocks(t *testing.T) {
 	metrics := make([]blockMetrics, 0)
 
 	// Initial values
-	currentDiff := initialDiff
 	currentPrice := big.NewInt(0).Add(
 		minPrice,
 		maxPrice,
 	)
 	currentPrice.Div(currentPrice, big.NewInt(2))
-	avgRatio := common.NewRational(3, 10)
+	var (
+		chain  = make(adaptorChain)
+		header = &types.Header{
+			Number:              new(big.Int),
+			PowDifficulty:       initialDiff,
+			PowPrice:            currentPrice,
+			AvgRatioNumerator:   3,
+			AvgRatioDenominator: 10,
+		}
+		random = rand.New(rand.NewSource(1))
+	)
+	chain[header.Hash()] = header
 
 	// Simulate 1000 blocks
 	numBlocks := 1000
 
-	// Helper to calculate simulated PoW ratio based on difficulty
-	simulatePowRatio := func(diff *big.Int, price *big.Int) *common.Rational {
+	// Helper to calculate simulated PoW transaction count out of 100 based on
+	// difficulty
+	simulatePowRatio := func(diff *big.Int, price *big.Int) int {
 		// Simulate miners responding to difficulty/price
 		// Higher difficulty -> lower ratio
 		// Higher price -> higher ratio
@@ -255,7 +331,7 @@ func TestPoWAdaptorContinuousBlocks(t *testing.T) {
 		ratio := baseRatio + diffEffect + priceEffect
 
 		// Add some random noise
-		ratio += (rand.Float64() - 0.5) * 0.1
+		ratio += (random.Float64() - 0.5) * 0.1
 
 		// Clamp between 0 and 1
 		if ratio < 0 {
@@ -265,32 +341,21 @@ func TestPoWAdaptorContinuousBlocks(t *testing.T) {
 			ratio = 1
 		}
 
-		return common.NewRational(uint64(ratio*1000), 1000)
+		return int(ratio * 100)
 	}
 
 	for i := 0; i < numBlocks; i++ {
-		// Simulate current block's PoW ratio
-		currentRatio := simulatePowRatio(currentDiff, currentPrice)
-
-		// Get new parameters
-		newDiff, newPrice, newAvgRatio, newAvgDenom := pa.AdjustParameters(
-			currentRatio,
-			avgRatio,
-			currentPrice,
-		)
+		// Derive the parameters of the block, then simulate its PoW ratio
+		pows := simulatePowRatio(header.PowDifficulty, header.PowPrice)
+		header = chain.next(pow, header, 100*params.TxGas, adaptorTxs(pows, 100, params.TxGas))
 
 		// Store metrics
 		metrics = append(metrics, blockMetrics{
-			difficulty: newDiff,
-			price:      newPrice,
-			powRatio:   float64(currentRatio.Numerator) / float64(currentRatio.Denominator),
-			avgRatio:   float64(newAvgRatio) / float64(newAvgDenom),
+			difficulty: header.PowDifficulty,
+			price:      header.PowPrice,
+			powRatio:   float64(pows) / 100,
+			avgRatio:   float64(header.AvgRatioNumerator) / float64(header.AvgRatioDenominator),
 		})
-
-		// Update for next block
-		currentDiff = newDiff
-		currentPrice = newPrice
-		avgRatio = common.NewRational(newAvgRatio, newAvgDenom)
 	}
 
 	// Analyze results
@@ -345,7 +410,7 @@ func TestGasAdaptorContinuousBlocks(t *testing.T) {
 	maxGas := uint64(30000000)
 	initialGas := uint64(15000000)
 
-	ga := NewGasAdaptor(
+	pow := gasParams(
 		minGas,
 		maxGas,
 		initialGas,
@@ -361,45 +426,45 @@ func TestGasAdaptorContinuousBlocks(t *testing.T) {
 	metrics := make([]blockMetrics, 0)
 
 	// Initial values
-	currentGas := initialGas
-	avgRatio := common.NewRational(15000000, 15000000) // Initial target
+	var (
+		chain  = make(adaptorChain)
+		header = &types.Header{
+			Number:            new(big.Int),
+			PowGas:            initialGas,
+			AvgGasNumerator:   15000000, // Initial target
+			AvgGasDenominator: 15000000,
+		}
+		random = rand.New(rand.NewSource(1))
+	)
+	chain[header.Hash()] = header
 
 	// Simulate 1000 blocks
 	numBlocks := 1000
 
 	// Helper to calculate simulated gas usage based on current gas limit
-	simulateGasUsage := func(gasLimit uint64) *common.Rational {
+	simulateGasUsage := func(gasLimit uint64) uint64 {
 		// Simulate blocks using around 50% of gas limit with some random variation
 		baseUsage := float64(gasLimit) * 0.5
-		variation := float64(gasLimit) * 0.2 * (rand.Float64() - 0.5)
+		variation := float64(gasLimit) * 0.2 * (random.Float64() - 0.5)
 		usage := uint64(baseUsage + variation)
 
 		if usage > gasLimit {
 			usage = gasLimit
 		}
 
-		return common.NewRational(usage, gasLimit)
+		return usage
 	}
 
 	for i := 0; i < numBlocks; i++ {
-		// Simulate current block's gas usage
-		currentRatio := simulateGasUsage(currentGas)
-
-		// Get new parameters
-		newGas, newAvgRatio, newAvgDenom := ga.AdjustGas(
-			currentRatio,
-			avgRatio,
-		)
+		// Simulate the gas usage of the block against the PoW gas of its parent
+		gasLimit := header.PowGas
+		header = chain.next(pow, header, gasLimit, adaptorTxs(0, 1, simulateGasUsage(gasLimit)))
 
 		// Store metrics
 		metrics = append(metrics, blockMetrics{
-			gas:      newGas,
-			avgRatio: float64(newAvgRatio) / float64(newAvgDenom),
+			gas:      header.PowGas,
+			avgRatio: float64(header.AvgGasNumerator) / float64(header.AvgGasDenominator),
 		})
-
-		// Update for next block
-		currentGas = newGas
-		avgRatio = common.NewRational(newAvgRatio, newAvgDenom)
 	}
 
 	// Analyze results
@@ -4,9 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"net"
 	"sync"
-	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
@@ -36,8 +34,8 @@ type CoinMixerMonitor struct {
 	chainConfig *params.ChainConfig
 	nonceLock   sync.Mutex
 
-	serving atomic.Bool
-	server  *grpc.Server
+	server *serverLink // server the transfer layer connects to
+	pb.UnimplementedCoinMixerMonitorServer
 
 	// transfer消息订阅
 	transferCh  chan *TransferMessage
@@ -48,7 +46,7 @@ type CoinMixerMonitor struct {
 	mixerEventCh chan types.Log
 }
 
-func NewCoinMixerMonitor(eth Backend, config *params.ChainConfig, mux *event.TypeMux) *CoinMixerMonitor {
+func NewCoinMixerMonitor(eth Backend, config *params.ChainConfig, mux *event.TypeMux, links *grpcLinks, endpoint string) *CoinMixerMonitor {
 	m := &CoinMixerMonitor{
 		mux:          mux,
 		eth:          eth,
@@ -56,32 +54,24 @@ func NewCoinMixerMonitor(eth Backend, config *params.ChainConfig, mux *event.Typ
 		transferCh:   make(chan *TransferMessage, 10),
 		quit:         make(chan struct{}),
 		mixerEventCh: make(chan types.Log),
-		server:       grpc.NewServer(),
 	}
+	m.server = links.serve("coinmixer", endpoint, func(s *grpc.Server) {
+		pb.RegisterCoinMixerMonitorServer(s, m)
+	})
 
 	return m
 }
 
 func (m *CoinMixerMonitor) start() {
-	if !m.serving.Load() {
-		// !!! 这一段应该进入配置文件
-		listen, err := net.Listen("tcp", "127.0.0.1:9294") // will be included in config
-		if err != nil {
-			fmt.Println(err)
-			panic("coin mixer monitor cannot listen!")
-		}
-		m.serving.Store(true)
-		go m.server.Serve(listen)
-	}
+	m.server.start()
 	go m.bindCoinMixer()
 	go m.loop()
 }
 
 func (m *CoinMixerMonitor) Stop() {
-	m.server.Stop()
+	m.server.stop()
 	close(m.quit)
 	m.transferSub.Unsubscribe()
-	m.serving.Store(false)
 }
 
 func (m *CoinMixerMonitor) UTXODeposit(ctx context.Context, req *pb.UTXODepositRequest) (*pb.Empty, error) {
@@ -0,0 +1,323 @@
+package miner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/proto/pb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Cross-shard transactions are transactions whose recipient belongs to another
+// shard than their sender, see params.ShardingConfig. The source shard executes
+// them like any other, locking their value at its cross-shard address. A relay
+// process fetches the receipt of the transaction with its proofs from a source
+// executor, and submits it to a destination executor. The destination executor
+// checks the proofs against the roots a quorum of the source executors agree on,
+// and pays the value to the recipient from its etherbase account with a payout
+// transaction, which the destination shard records in its state so that the
+// transfer is only paid out once. Payouts are sent again until recorded.
+
+// crossShardTransfer identifies a cross-shard transaction by its source shard
+// and hash.
+type crossShardTransfer struct {
+	shard uint64
+	hash  common.Hash
+}
+
+// proofList collects the trie nodes of a proof.
+type proofList [][]byte
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (l *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+// deriveProof builds the trie of a list the way types.DeriveSha does, and
+// returns the item at index with its proof against the root of the trie.
+func deriveProof(list types.DerivableList, index uint64) ([]byte, [][]byte, common.Hash, error) {
+	var (
+		tr   = trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+		buf  = new(bytes.Buffer)
+		item []byte
+	)
+	for i := 0; i < list.Len(); i++ {
+		buf.Reset()
+		list.EncodeIndex(i, buf)
+		value := common.CopyBytes(buf.Bytes())
+		if uint64(i) == index {
+			item = value
+		}
+		if err := tr.Update(rlp.AppendUint64(nil, uint64(i)), value); err != nil {
+			return nil, nil, common.Hash{}, err
+		}
+	}
+	if item == nil {
+		return nil, nil, common.Hash{}, fmt.Errorf("index %d out of range", index)
+	}
+	var proof proofList
+	if err := tr.Prove(rlp.AppendUint64(nil, index), &proof); err != nil {
+		return nil, nil, common.Hash{}, err
+	}
+	return item, proof, tr.Hash(), nil
+}
+
+// verifyItemProof checks that item is at index in the list the root is of.
+func verifyItemProof(root common.Hash, index uint64, item []byte, proof [][]byte) error {
+	db := memorydb.New()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	value, err := trie.VerifyProof(root, rlp.AppendUint64(nil, index), db)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(value, item) {
+		return errors.New("item mismatch")
+	}
+	return nil
+}
+
+// verifyCrossShardReceipt checks the proofs of a cross-shard receipt against the
+// transactions and receipts roots of its block, and returns the transaction and
+// its receipt.
+func verifyCrossShardReceipt(txRoot, receiptRoot common.Hash, r *pb.CrossShardReceipt) (*types.Transaction, *types.Receipt, error) {
+	if err := verifyItemProof(txRoot, r.Index, r.Tx, r.TxProof); err != nil {
+		return nil, nil, fmt.Errorf("invalid transaction proof: %v", err)
+	}
+	if err := verifyItemProof(receiptRoot, r.Index, r.Receipt, r.ReceiptProof); err != nil {
+		return nil, nil, fmt.Errorf("invalid receipt proof: %v", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(r.Tx); err != nil {
+		return nil, nil, err
+	}
+	receipt := new(types.Receipt)
+	if err := receipt.UnmarshalBinary(r.Receipt); err != nil {
+		return nil, nil, err
+	}
+	return tx, receipt, nil
+}
+
+// crossShardReceipt returns the receipt of a cross-shard transaction executed
+// by the local shard, with its proofs.
+func (e *executor) crossShardReceipt(hash common.Hash) (*pb.CrossShardReceipt, error) {
+	tx, blockHash, number, index := rawdb.ReadTransaction(e.eth.ChainDb(), hash)
+	if tx == nil {
+		return nil, errors.New("transaction not found")
+	}
+	if tx.To() == nil || !e.chainConfig.Sharding.IsForeign(*tx.To()) {
+		return nil, errors.New("not a cross-shard transaction")
+	}
+	block := e.eth.BlockChain().GetBlock(blockHash, number)
+	receipts := e.eth.BlockChain().GetReceiptsByHash(blockHash)
+	if block == nil || receipts == nil {
+		return nil, errors.New("block not found")
+	}
+	txData, txProof, txRoot, err := deriveProof(block.Transactions(), index)
+	if err != nil {
+		return nil, err
+	}
+	receiptData, receiptProof, receiptRoot, err := deriveProof(receipts, index)
+	if err != nil {
+		return nil, err
+	}
+	if txRoot != block.TxHash() || receiptRoot != block.ReceiptHash() {
+		return nil, errors.New("block roots mismatch")
+	}
+	return &pb.CrossShardReceipt{
+		SourceShard:  e.networkId,
+		BlockNumber:  number,
+		BlockHash:    blockHash.Bytes(),
+		Index:        index,
+		Tx:           txData,
+		Receipt:      receiptData,
+		TxProof:      txProof,
+		ReceiptProof: receiptProof,
+	}, nil
+}
+
+// sourceRoots returns the transactions and receipts roots of a block of another
+// shard, as served by a quorum of the executors of the shard: more than two
+// thirds of them. Executors only serve the blocks of the batches committed by
+// the consensus layer, whose roots every executor of the shard computes alike,
+// so that a single executor can't make up a block.
+func (e *executor) sourceRoots(ctx context.Context, shard uint64, number uint64) (common.Hash, common.Hash, error) {
+	clients := e.shardClients[shard]
+	if shard == e.networkId || len(clients) == 0 {
+		return common.Hash{}, common.Hash{}, fmt.Errorf("no executor for source shard %d", shard)
+	}
+	var (
+		quorum = len(clients)*2/3 + 1
+		votes  = make(map[[2]common.Hash]int)
+	)
+	for i, client := range clients {
+		reply, err := client.GetHeader(ctx, &pb.HeaderRequest{Number: number})
+		if err != nil {
+			log.Debug("Failed to retrieve source header", "shard", shard, "executor", i, "number", number, "err", err)
+			continue
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(reply.Header, header); err != nil || header.Number == nil || header.Number.Uint64() != number {
+			log.Debug("Invalid source header", "shard", shard, "executor", i, "number", number, "err", err)
+			continue
+		}
+		roots := [2]common.Hash{header.TxHash, header.ReceiptHash}
+		if votes[roots]++; votes[roots] >= quorum {
+			return roots[0], roots[1], nil
+		}
+	}
+	return common.Hash{}, common.Hash{}, fmt.Errorf("no quorum of %d executors of shard %d for block %d", quorum, shard, number)
+}
+
+// submitCrossShardReceipt checks a cross-shard receipt against the roots of its
+// block in the source shard and pays the value to the recipient, unless it was
+// paid already. It returns the hash of the payout transaction.
+func (e *executor) submitCrossShardReceipt(ctx context.Context, r *pb.CrossShardReceipt) (common.Hash, error) {
+	sharding := e.chainConfig.Sharding
+	if sharding == nil {
+		return common.Hash{}, errors.New("chain not sharded")
+	}
+	txRoot, receiptRoot, err := e.sourceRoots(ctx, r.SourceShard, r.BlockNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, receipt, err := verifyCrossShardReceipt(txRoot, receiptRoot, r)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if shard := sharding.ShardOf(from); shard != r.SourceShard {
+		return common.Hash{}, fmt.Errorf("sender in shard %d, not source shard %d", shard, r.SourceShard)
+	}
+	if tx.To() == nil || sharding.IsForeign(*tx.To()) {
+		return common.Hash{}, errors.New("recipient not in the local shard")
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return common.Hash{}, errors.New("transaction failed in the source shard")
+	}
+	e.payoutLock.Lock()
+	defer e.payoutLock.Unlock()
+
+	transfer := crossShardTransfer{shard: r.SourceShard, hash: tx.Hash()}
+	statedb, err := e.eth.BlockChain().State()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if core.CrossShardPaid(e.chainConfig, statedb, e.etherbase(), transfer.shard, transfer.hash) {
+		return rawdb.ReadCrossShardPayout(e.eth.ChainDb(), transfer.shard, transfer.hash), nil
+	}
+	// Payouts not recorded yet are sent again if they left the pool, the state
+	// rejecting all but the first one included
+	if payout := e.payouts[transfer]; payout != nil && e.eth.TxPool().Has(payout.Hash()) {
+		return payout.Hash(), nil
+	}
+	payout, err := e.payCrossShard(transfer, *tx.To(), tx.Value())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Paid out cross-shard transaction", "shard", transfer.shard, "hash", transfer.hash, "payout", payout.Hash())
+	return payout.Hash(), nil
+}
+
+// retryCrossShardPayouts sends the payouts again that left the pool without
+// being recorded in the state, and forgets the recorded ones.
+func (e *executor) retryCrossShardPayouts() {
+	e.payoutLock.Lock()
+	defer e.payoutLock.Unlock()
+
+	if len(e.payouts) == 0 {
+		return
+	}
+	statedb, err := e.eth.BlockChain().State()
+	if err != nil {
+		log.Warn("Failed to check cross-shard payouts", "err", err)
+		return
+	}
+	for transfer, payout := range e.payouts {
+		switch {
+		case core.CrossShardPaid(e.chainConfig, statedb, e.etherbase(), transfer.shard, transfer.hash):
+			delete(e.payouts, transfer)
+		case !e.eth.TxPool().Has(payout.Hash()):
+			retry, err := e.payCrossShard(transfer, *payout.To(), payout.Value())
+			if err != nil {
+				log.Warn("Failed to retry cross-shard payout", "shard", transfer.shard, "hash", transfer.hash, "err", err)
+				continue
+			}
+			log.Info("Retried cross-shard payout", "shard", transfer.shard, "hash", transfer.hash, "payout", retry.Hash())
+		}
+	}
+}
+
+// payCrossShard signs a payout of a cross-shard transfer from the etherbase to
+// the recipient, and adds it to the pool to be sent to the consensus layer. The
+// payout is tracked until recorded in the state. The lock of the payouts must be
+// held.
+func (e *executor) payCrossShard(transfer crossShardTransfer, to common.Address, value *big.Int) (*types.Transaction, error) {
+	data := core.CrossShardPayoutData(transfer.shard, transfer.hash)
+	gas, err := core.IntrinsicGas(data, nil, false, true, true, true)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := e.submitTx(to, value, gas+params.SstoreSetGasEIP2200, data)
+	if err != nil {
+		return nil, err
+	}
+	e.payouts[transfer] = signed
+	rawdb.WriteCrossShardPayout(e.eth.ChainDb(), transfer.shard, transfer.hash, signed.Hash())
+	return signed, nil
+}
+
+// GetHeader returns a canonical header, for other shards to check the
+// cross-shard receipts of the local shard against. Only the headers of blocks
+// executing a batch committed by the consensus layer are served.
+func (es *executorServer) GetHeader(ctx context.Context, req *pb.HeaderRequest) (*pb.HeaderReply, error) {
+	header := es.executorPtr.eth.BlockChain().GetHeaderByNumber(req.GetNumber())
+	if header == nil {
+		return nil, fmt.Errorf("header %d not found", req.GetNumber())
+	}
+	if rawdb.ReadBlockExecBatch(es.executorPtr.eth.ChainDb(), header.Hash()) == nil {
+		return nil, fmt.Errorf("block %d not of a committed batch", req.GetNumber())
+	}
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.HeaderReply{Header: data}, nil
+}
+
+// GetCrossShardReceipt returns the receipt of a cross-shard transaction of the
+// local shard with its proofs, for a relay to submit to the destination shard.
+func (es *executorServer) GetCrossShardReceipt(ctx context.Context, req *pb.CrossShardReceiptRequest) (*pb.CrossShardReceipt, error) {
+	return es.executorPtr.crossShardReceipt(common.BytesToHash(req.GetTxHash()))
+}
+
+// SubmitCrossShardReceipt pays out a cross-shard transaction of another shard.
+// Submitting a receipt again returns the same payout.
+func (es *executorServer) SubmitCrossShardReceipt(ctx context.Context, r *pb.CrossShardReceipt) (*pb.CrossShardPayout, error) {
+	hash, err := es.executorPtr.submitCrossShardReceipt(ctx, r)
+	if err != nil {
+		log.Warn("Rejected cross-shard receipt", "shard", r.GetSourceShard(), "block", r.GetBlockNumber(), "err", err)
+		return nil, err
+	}
+	return &pb.CrossShardPayout{TxHash: hash.Bytes()}, nil
+}
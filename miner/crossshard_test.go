@@ -0,0 +1,92 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/proto/pb"
+	"github.com/ethereum/go-ethereum/trie"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCrossShardReceiptProof(t *testing.T) {
+	var (
+		signer   = types.LatestSigner(params.TestChainConfig)
+		txs      types.Transactions
+		receipts types.Receipts
+	)
+	for i := 0; i < 20; i++ {
+		tx := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &testUserAddress,
+			Value:    big.NewInt(int64(1000 + i)),
+			Gas:      21000,
+			GasPrice: big.NewInt(1),
+		})
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Logs:              []*types.Log{},
+		})
+	}
+	header := &types.Header{
+		Number:      big.NewInt(7),
+		TxHash:      types.DeriveSha(txs, trie.NewStackTrie(nil)),
+		ReceiptHash: types.DeriveSha(receipts, trie.NewStackTrie(nil)),
+	}
+	const index = 13
+	txData, txProof, txRoot, err := deriveProof(txs, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiptData, receiptProof, receiptRoot, err := deriveProof(receipts, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txRoot != header.TxHash || receiptRoot != header.ReceiptHash {
+		t.Fatal("derived roots differ from the header")
+	}
+	r := &pb.CrossShardReceipt{
+		SourceShard:  2,
+		BlockNumber:  7,
+		BlockHash:    header.Hash().Bytes(),
+		Index:        index,
+		Tx:           txData,
+		Receipt:      receiptData,
+		TxProof:      txProof,
+		ReceiptProof: receiptProof,
+	}
+	tx, receipt, err := verifyCrossShardReceipt(header.TxHash, header.ReceiptHash, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Hash() != txs[index].Hash() || receipt.CumulativeGasUsed != receipts[index].CumulativeGasUsed {
+		t.Fatal("wrong transaction or receipt returned")
+	}
+	// Tampering with any part of the receipt is rejected
+	tampered := proto.Clone(r).(*pb.CrossShardReceipt)
+	tampered.Index = index - 1
+	if _, _, err := verifyCrossShardReceipt(header.TxHash, header.ReceiptHash, tampered); err == nil {
+		t.Error("receipt at wrong index accepted")
+	}
+	tampered = proto.Clone(r).(*pb.CrossShardReceipt)
+	tampered.Tx = common.CopyBytes(txs[index-1].Hash().Bytes())
+	if _, _, err := verifyCrossShardReceipt(header.TxHash, header.ReceiptHash, tampered); err == nil {
+		t.Error("wrong transaction accepted")
+	}
+	tampered = proto.Clone(r).(*pb.CrossShardReceipt)
+	tampered.Receipt = common.CopyBytes(r.Tx)
+	if _, _, err := verifyCrossShardReceipt(header.TxHash, header.ReceiptHash, tampered); err == nil {
+		t.Error("wrong receipt accepted")
+	}
+	if _, _, err := verifyCrossShardReceipt(header.ReceiptHash, header.TxHash, r); err == nil {
+		t.Error("receipt of other roots accepted")
+	}
+	if _, _, _, err := deriveProof(txs, uint64(len(txs))); err == nil {
+		t.Error("proof of missing item derived")
+	}
+}
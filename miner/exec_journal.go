@@ -0,0 +1,123 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxExecBatchesAhead is the number of batches the consensus layer may commit
+// ahead of the next one to execute, waiting for the batches in between.
+const maxExecBatchesAhead = 1024
+
+var (
+	errExecBatchTooFarAhead = errors.New("execution batch too far ahead")
+	errUnsequencedExecBatch = errors.New("execution batch without height")
+)
+
+// execJournal is the write-ahead journal of the batches committed by the
+// consensus layer. Batches are persisted before the commit is acknowledged and
+// removed once executed, so the batches pending at a crash are executed on
+// restart.
+//
+// Batches are executed in consensus height order, starting after the last
+// executed batch, or after the batch executed by the chain head if none was
+// executed yet. Batches committed ahead of the next one to execute wait for the
+// missing ones, batches at or below the last executed height, or already
+// pending, are duplicates and dropped. Batches without a height are sequenced in
+// commit order, until the consensus layer sends heights: from then on they are
+// rejected, as their heights could collide with the sequenced ones.
+type execJournal struct {
+	db ethdb.Database
+
+	mu        sync.Mutex
+	executed  uint64 // Height of the last executed batch
+	last      uint64 // Highest height journaled or executed
+	sequenced bool   // Whether the consensus layer sent heights
+}
+
+// newExecJournal opens the journal, keeping the batches pending in db. The
+// sequence starts from the consensus height of the batch executed by head,
+// unless a later batch was executed already.
+func newExecJournal(db ethdb.Database, head *types.Header) *execJournal {
+	j := &execJournal{
+		db:        db,
+		sequenced: rawdb.ReadExecSequenced(db),
+	}
+	if executed := rawdb.ReadExecutedBatch(db); executed != nil {
+		j.executed = *executed
+	}
+	if head != nil {
+		if height := rawdb.ReadBlockExecBatch(db, head.Hash()); height != nil && *height > j.executed {
+			j.executed = *height
+		}
+	}
+	j.last = j.executed
+	if heights := rawdb.ReadExecBatchHeights(db); len(heights) > 0 && heights[len(heights)-1] > j.last {
+		j.last = heights[len(heights)-1]
+	}
+	return j
+}
+
+// commit persists a batch, returning whether it's new. Batches without a height
+// are given the next one, unless the consensus layer sent heights before.
+func (j *execJournal) commit(batch *types.ExecBatch) (bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if batch.Height == 0 {
+		if j.sequenced {
+			return false, errUnsequencedExecBatch
+		}
+		batch.Height = j.last + 1
+	} else if !j.sequenced {
+		rawdb.WriteExecSequenced(j.db)
+		j.sequenced = true
+	}
+	if batch.Height <= j.executed {
+		return false, nil
+	}
+	if batch.Height > j.executed+maxExecBatchesAhead {
+		return false, fmt.Errorf("%w: height %d, last executed %d", errExecBatchTooFarAhead, batch.Height, j.executed)
+	}
+	if rawdb.HasExecBatch(j.db, batch.Height) {
+		return false, nil
+	}
+	rawdb.WriteExecBatch(j.db, batch)
+	if batch.Height > j.last {
+		j.last = batch.Height
+	}
+	return true, nil
+}
+
+// next returns the next batch to execute, nil if it wasn't committed yet.
+func (j *execJournal) next() *types.ExecBatch {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return rawdb.ReadExecBatch(j.db, j.executed+1)
+}
+
+// done marks a batch returned by next as executed, removing it from the journal.
+// The block the batch produced, if any, is linked to the height of the batch.
+func (j *execJournal) done(height uint64, block common.Hash) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	batch := j.db.NewBatch()
+	rawdb.WriteExecutedBatch(batch, height)
+	rawdb.DeleteExecBatch(batch, height)
+	if block != (common.Hash{}) {
+		rawdb.WriteBlockExecBatch(batch, block, height)
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to mark execution batch as executed", "height", height, "err", err)
+	}
+	j.executed = height
+}
@@ -0,0 +1,156 @@
+package miner
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newTestExecBatch(height uint64) *types.ExecBatch {
+	return &types.ExecBatch{Height: height, Timestamp: 1, RandomNumber: big.NewInt(1)}
+}
+
+func TestExecJournalOrder(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	journal := newExecJournal(db, nil)
+
+	// Batches ahead wait for the missing ones, from the start of the sequence
+	for _, height := range []uint64{2, 4} {
+		if fresh, err := journal.commit(newTestExecBatch(height)); !fresh || err != nil {
+			t.Fatalf("batch %d not journaled: %v", height, err)
+		}
+	}
+	if batch := journal.next(); batch != nil {
+		t.Fatalf("batch %d executed before batch 1", batch.Height)
+	}
+	if fresh, err := journal.commit(newTestExecBatch(1)); !fresh || err != nil {
+		t.Fatalf("batch 1 not journaled: %v", err)
+	}
+	for _, height := range []uint64{1, 2} {
+		batch := journal.next()
+		if batch == nil || batch.Height != height {
+			t.Fatalf("wrong batch: have %v, want %d", batch, height)
+		}
+		journal.done(height, common.Hash{})
+	}
+	if batch := journal.next(); batch != nil {
+		t.Fatalf("batch %d executed before batch 3", batch.Height)
+	}
+	if fresh, err := journal.commit(newTestExecBatch(3)); !fresh || err != nil {
+		t.Fatalf("batch 3 not journaled: %v", err)
+	}
+	for _, height := range []uint64{3, 4} {
+		batch := journal.next()
+		if batch == nil || batch.Height != height {
+			t.Fatalf("wrong batch: have %v, want %d", batch, height)
+		}
+		journal.done(height, common.Hash{})
+	}
+	if batch := journal.next(); batch != nil {
+		t.Fatalf("unexpected batch %d", batch.Height)
+	}
+}
+
+func TestExecJournalUnsequenced(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	journal := newExecJournal(db, nil)
+
+	// Without heights from the consensus layer, batches go in commit order
+	for want := uint64(1); want <= 2; want++ {
+		batch := newTestExecBatch(0)
+		if fresh, err := journal.commit(batch); !fresh || err != nil || batch.Height != want {
+			t.Fatalf("unsequenced batch not journaled at %d: height %d, err %v", want, batch.Height, err)
+		}
+	}
+	// Once heights were sent, batches without one are rejected, also after a restart
+	if fresh, err := journal.commit(newTestExecBatch(3)); !fresh || err != nil {
+		t.Fatalf("batch 3 not journaled: %v", err)
+	}
+	if _, err := journal.commit(newTestExecBatch(0)); !errors.Is(err, errUnsequencedExecBatch) {
+		t.Fatalf("wrong error for unsequenced batch: %v", err)
+	}
+	journal = newExecJournal(db, nil)
+	if _, err := journal.commit(newTestExecBatch(0)); !errors.Is(err, errUnsequencedExecBatch) {
+		t.Fatalf("wrong error for unsequenced batch after restart: %v", err)
+	}
+}
+
+func TestExecJournalDuplicates(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	journal := newExecJournal(db, nil)
+
+	if fresh, _ := journal.commit(newTestExecBatch(1)); !fresh {
+		t.Fatal("batch 1 not journaled")
+	}
+	if fresh, _ := journal.commit(newTestExecBatch(1)); fresh {
+		t.Fatal("pending batch journaled twice")
+	}
+	journal.done(1, common.Hash{})
+	if fresh, _ := journal.commit(newTestExecBatch(1)); fresh {
+		t.Fatal("executed batch journaled again")
+	}
+	if _, err := journal.commit(newTestExecBatch(2 + maxExecBatchesAhead)); !errors.Is(err, errExecBatchTooFarAhead) {
+		t.Fatalf("wrong error for batch too far ahead: %v", err)
+	}
+}
+
+func TestExecJournalRestart(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	journal := newExecJournal(db, nil)
+
+	tx := types.NewTransaction(0, testUserAddress, big.NewInt(1000), 21000, big.NewInt(1), nil)
+	for height := uint64(1); height <= 3; height++ {
+		batch := newTestExecBatch(height)
+		batch.Txs = types.Transactions{tx}
+		journal.commit(batch)
+	}
+	journal.done(1, common.Hash{})
+
+	// Reopen the journal, the pending batches are kept
+	journal = newExecJournal(db, nil)
+	batch := journal.next()
+	if batch == nil || batch.Height != 2 {
+		t.Fatalf("wrong batch after restart: %v", batch)
+	}
+	if len(batch.Txs) != 1 || batch.Txs[0].Hash() != tx.Hash() {
+		t.Fatal("batch transactions not kept")
+	}
+	if fresh, _ := journal.commit(newTestExecBatch(1)); fresh {
+		t.Fatal("executed batch journaled again after restart")
+	}
+	if heights := rawdb.ReadExecBatchHeights(db); len(heights) != 2 || heights[0] != 2 || heights[1] != 3 {
+		t.Fatalf("wrong pending batches: %v", heights)
+	}
+}
+
+func TestExecJournalHeadBase(t *testing.T) {
+	var (
+		db   = rawdb.NewMemoryDatabase()
+		head = &types.Header{Number: big.NewInt(7)}
+	)
+	// The blocks of executed batches are linked to their heights
+	journal := newExecJournal(db, nil)
+	journal.commit(newTestExecBatch(1))
+	journal.done(1, head.Hash())
+	if height := rawdb.ReadBlockExecBatch(db, head.Hash()); height == nil || *height != 1 {
+		t.Fatalf("block not linked to its batch: %v", height)
+	}
+	// Without an executed batch, the sequence starts after the one of the head
+	db = rawdb.NewMemoryDatabase()
+	rawdb.WriteBlockExecBatch(db, head.Hash(), 10)
+
+	journal = newExecJournal(db, head)
+	if fresh, _ := journal.commit(newTestExecBatch(10)); fresh {
+		t.Fatal("batch of the head journaled again")
+	}
+	if fresh, err := journal.commit(newTestExecBatch(11)); !fresh || err != nil {
+		t.Fatalf("batch 11 not journaled: %v", err)
+	}
+	if batch := journal.next(); batch == nil || batch.Height != 11 {
+		t.Fatalf("wrong batch after the head: %v", batch)
+	}
+}
@@ -0,0 +1,102 @@
+package miner
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/proto/pb"
+)
+
+// execReportsLimit is the number of reports kept for the consensus layer to
+// catch up on, e.g. after reconnecting.
+const execReportsLimit = 256
+
+// errExecReportsBehind is returned to the subscribers dropped for not keeping up
+// with the reports.
+var errExecReportsBehind = errors.New("execution reports subscriber fell behind")
+
+// execReports keeps the reports of the last executed batches, and feeds the new
+// ones to the consensus layer. Reports of the batches executed before the node
+// started aren't available, their blocks are in the chain.
+//
+// Reports are sent without blocking execution: subscribers whose channel is
+// full are dropped, and catch up from the kept reports when subscribing again.
+type execReports struct {
+	mu      sync.Mutex
+	reports []*pb.ExecResult // Last reports, in height order
+	subs    map[*execReportsSub]struct{}
+}
+
+// execReportsSub is a subscription to the new reports.
+type execReportsSub struct {
+	reports *execReports
+	ch      chan<- *pb.ExecResult
+	err     chan error
+	once    sync.Once
+}
+
+// add keeps a new report and sends it to the subscribers.
+func (r *execReports) add(report *pb.ExecResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reports = append(r.reports, report)
+	if len(r.reports) > execReportsLimit {
+		r.reports = r.reports[len(r.reports)-execReportsLimit:]
+	}
+	for sub := range r.subs {
+		select {
+		case sub.ch <- report:
+		default:
+			r.drop(sub, errExecReportsBehind)
+		}
+	}
+}
+
+// subscribe subscribes to the new reports, and returns the reports kept from
+// the given height on. The reports added afterwards are sent on ch.
+func (r *execReports) subscribe(from uint64, ch chan<- *pb.ExecResult) (event.Subscription, []*pb.ExecResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub := &execReportsSub{reports: r, ch: ch, err: make(chan error, 1)}
+	if r.subs == nil {
+		r.subs = make(map[*execReportsSub]struct{})
+	}
+	r.subs[sub] = struct{}{}
+
+	var reports []*pb.ExecResult
+	for _, report := range r.reports {
+		if report.Height >= from {
+			reports = append(reports, report)
+		}
+	}
+	return sub, reports
+}
+
+// drop removes a subscription, ending it with err if not nil. The lock must be
+// held.
+func (r *execReports) drop(sub *execReportsSub, err error) {
+	delete(r.subs, sub)
+	sub.once.Do(func() {
+		if err != nil {
+			sub.err <- err
+		}
+		close(sub.err)
+	})
+}
+
+// Unsubscribe implements event.Subscription.
+func (s *execReportsSub) Unsubscribe() {
+	s.reports.mu.Lock()
+	defer s.reports.mu.Unlock()
+
+	s.reports.drop(s, nil)
+}
+
+// Err implements event.Subscription, the error is errExecReportsBehind if the
+// subscriber was dropped.
+func (s *execReportsSub) Err() <-chan error {
+	return s.err
+}
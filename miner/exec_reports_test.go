@@ -0,0 +1,98 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/proto/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestExecReportsLimit(t *testing.T) {
+	var reports execReports
+	for height := uint64(1); height <= execReportsLimit+10; height++ {
+		reports.add(&pb.ExecResult{Height: height})
+	}
+	sub, kept := reports.subscribe(0, make(chan *pb.ExecResult))
+	defer sub.Unsubscribe()
+
+	if len(kept) != execReportsLimit || kept[0].Height != 11 {
+		t.Fatalf("wrong reports kept: have %d from %d, want %d from 11", len(kept), kept[0].Height, execReportsLimit)
+	}
+	sub, kept = reports.subscribe(execReportsLimit+5, make(chan *pb.ExecResult))
+	defer sub.Unsubscribe()
+
+	if len(kept) != 6 || kept[0].Height != execReportsLimit+5 {
+		t.Fatalf("wrong reports from height: %v", kept)
+	}
+}
+
+func TestExecReportsSlowSubscriber(t *testing.T) {
+	var reports execReports
+	slow, fast := make(chan *pb.ExecResult, 1), make(chan *pb.ExecResult, 2)
+	slowSub, _ := reports.subscribe(0, slow)
+	fastSub, _ := reports.subscribe(0, fast)
+	defer fastSub.Unsubscribe()
+
+	// Full subscribers are dropped instead of blocking the reports
+	reports.add(&pb.ExecResult{Height: 1})
+	reports.add(&pb.ExecResult{Height: 2})
+	if err := <-slowSub.Err(); !errors.Is(err, errExecReportsBehind) {
+		t.Fatalf("wrong error for slow subscriber: %v", err)
+	}
+	slowSub.Unsubscribe()
+	if len(fast) != 2 {
+		t.Fatalf("wrong reports sent: have %d, want 2", len(fast))
+	}
+	select {
+	case err := <-fastSub.Err():
+		t.Fatalf("subscriber keeping up dropped: %v", err)
+	default:
+	}
+}
+
+func TestExecutionReportsStream(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := new(executor)
+	server := grpc.NewServer()
+	pb.RegisterExecutorServer(server, &executorServer{executorPtr: e})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	e.reports.add(&pb.ExecResult{Height: 1})
+	e.reports.add(&pb.ExecResult{Height: 2, BlockNumber: 5, Txs: []*pb.TxResult{{Status: pb.TxStatus_TX_REVERTED, GasUsed: 21000}}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := pb.NewExecutorClient(conn).ExecutionReports(ctx, &pb.ExecReportsRequest{FromHeight: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Kept reports come first, then the new ones
+	report, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Height != 2 || report.BlockNumber != 5 || len(report.Txs) != 1 || report.Txs[0].Status != pb.TxStatus_TX_REVERTED {
+		t.Fatalf("wrong kept report: %v", report)
+	}
+	go e.reports.add(&pb.ExecResult{Height: 3})
+	if report, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if report.Height != 3 {
+		t.Fatalf("wrong new report height: have %d, want 3", report.Height)
+	}
+}
@@ -5,30 +5,43 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"net"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/merkeldag"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/proto/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 )
 
 const txMaxSize = 4 * 32 * 1024 // 128KB
 const ContributionContractAddr = "0x67fbF000Fc60CBE25D9658D83C5C2506ca323Fdd"
 
+// forwardedShardKey marks the batches forwarded by the executor of another shard,
+// which aren't forwarded again.
+const forwardedShardKey = "x-forwarded-shard"
+
+var (
+	errTxReplayProtected = errors.New("replay protected transaction before EIP155")
+	errTxNotFromLeader   = errors.New("contribution transaction not from the leader")
+	errMissingEtherbase  = errors.New("refusing to mine without etherbase")
+)
+
 // environment is the worker's current environment and holds all
 // information of the sealing block generation.
 type executor_env struct {
@@ -38,6 +51,7 @@ type executor_env struct {
 	gasPool  *core.GasPool  // available gas used to pack transactions
 	coinbase common.Address
 	header   *types.Header
+	pow      *params.PowParams // PoW economics parameters in effect at the block
 
 	// 最后执行的结束后的结果，有多少tx被包括，他们的收据是什么
 	// 打包区块使用
@@ -45,6 +59,24 @@ type executor_env struct {
 	tcount      int // 成功执行的交易数量
 	txs         types.Transactions
 	receipts    []*types.Receipt
+	results     []*pb.TxResult       // 收到的每笔交易的执行结果，按收到的顺序
+	taint       *merkeldag.MerkelDAG // 区块的污点地址 DAG，锁定地址前为 nil
+}
+
+// include records the result of a transaction included in the block.
+func (env *executor_env) include(tx *types.Transaction, receipt *types.Receipt) *pb.TxResult {
+	status := pb.TxStatus_TX_SUCCESS
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = pb.TxStatus_TX_REVERTED
+	}
+	result := &pb.TxResult{Hash: tx.Hash().Bytes(), Status: status, GasUsed: receipt.GasUsed}
+	env.results = append(env.results, result)
+	return result
+}
+
+// reject records the result of a transaction left out of the block.
+func (env *executor_env) reject(tx *types.Transaction, status pb.TxStatus, err error) {
+	env.results = append(env.results, &pb.TxResult{Hash: tx.Hash().Bytes(), Status: status, Error: err.Error()})
 }
 
 // copy creates a deep copy of environment.
@@ -56,6 +88,7 @@ func (env *executor_env) copy() *executor_env {
 		coinbase: env.coinbase,
 		header:   types.CopyHeader(env.header),
 		receipts: copyReceipts(env.receipts),
+		taint:    env.taint, // DAG 建好后不会再修改
 	}
 	if env.gasPool != nil {
 		gasPool := *env.gasPool
@@ -66,14 +99,6 @@ func (env *executor_env) copy() *executor_env {
 	return cpy
 }
 
-type execReq struct {
-	timestamp    int64
-	txs          types.Transactions
-	leader       common.Address
-	randomNumber *big.Int
-	incentive    []byte
-}
-
 type executorServer struct {
 	executorPtr                    *executor
 	pb.UnimplementedExecutorServer // indicated executor can be a grpc server
@@ -84,8 +109,7 @@ type executorServer struct {
 // }
 
 // Receive txs from consensus layer
-func (es *executorServer) CommitBlock(ctx context.Context, pbBlock *pb.ExecBlock) (*pb.Empty, error) {
-	fmt.Println("get commit block")
+func (es *executorServer) CommitBlock(ctx context.Context, pbBlock *pb.ExecBlock) (*pb.CommitResult, error) {
 	// set leader
 	randomNuber := new(big.Int).SetUint64(pbBlock.GetRandomNumber())
 
@@ -93,7 +117,7 @@ func (es *executorServer) CommitBlock(ctx context.Context, pbBlock *pb.ExecBlock
 	IncentiveData, err := DecodeIncentive(incentiveBytes)
 	if err != nil {
 		log.Warn("decode incentive failed", "err", err)
-		return &pb.Empty{}, nil
+		return &pb.CommitResult{}, nil
 	}
 	// es.executorPtr.currenLeader = common.HexToAddress(IncentiveData.Leader)
 	// log.Info("get leader", "leader", es.executorPtr.currenLeader)
@@ -108,18 +132,32 @@ func (es *executorServer) CommitBlock(ctx context.Context, pbBlock *pb.ExecBlock
 	sharding, err := hexutil.DecodeUint64(string(pbBlock.ShardingName))
 	if err != nil {
 		log.Warn("get sharding failed", "sharding", pbBlock.ShardingName)
-		return &pb.Empty{}, nil
+		return &pb.CommitResult{}, nil
 	}
 
 	if sharding != es.executorPtr.networkId {
-		// if sharding check failed, return an error
-		log.Warn("get another sharding transactions", "sharding", sharding, "networkId", es.executorPtr.networkId)
-		return &pb.Empty{}, nil
+		// Forward the batches of the other shards to their executors, once
+		clients := es.executorPtr.shardClients[sharding]
+		if len(clients) == 0 {
+			log.Warn("get another sharding transactions", "sharding", sharding, "networkId", es.executorPtr.networkId)
+			return &pb.CommitResult{}, fmt.Errorf("no executor for shard %d", sharding)
+		}
+		if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(forwardedShardKey)) != 0 {
+			return &pb.CommitResult{}, fmt.Errorf("batch of shard %d forwarded to shard %d", sharding, es.executorPtr.networkId)
+		}
+		log.Debug("Forwarding batch to its shard", "sharding", sharding, "height", pbBlock.GetHeight())
+		ctx = metadata.AppendToOutgoingContext(ctx, forwardedShardKey, hexutil.EncodeUint64(es.executorPtr.networkId))
+		for _, client := range clients[:len(clients)-1] {
+			if result, err := client.CommitBlock(ctx, pbBlock); err == nil {
+				return result, nil
+			}
+		}
+		return clients[len(clients)-1].CommitBlock(ctx, pbBlock)
 	}
 
 	pbtxs := pbBlock.GetTxs()
-	if len(pbtxs) == 0 {
-		return &pb.Empty{}, nil
+	if len(pbtxs) == 0 && pbBlock.GetHeight() == 0 {
+		return &pb.CommitResult{}, nil
 	}
 	var errs []error = make([]error, 0)
 	var txs types.Transactions = make(types.Transactions, 0)
@@ -142,25 +180,70 @@ func (es *executorServer) CommitBlock(ctx context.Context, pbBlock *pb.ExecBlock
 
 	log.Info("get commited tx from consensus", "txs len:", txs.Len())
 
-	// Receive txs from consensus layer
-	if txs.Len() != 0 {
-		// construct a execReq
-		execreq := &execReq{
-			timestamp:    time.Now().Unix(),
-			txs:          txs,
-			randomNumber: randomNuber,
-			leader:       common.HexToAddress(IncentiveData.Leader),
-			incentive:    incentiveBytes,
+	// Journal the batch before acknowledging it. Empty batches are journaled too
+	// if sequenced, so that the batches after them don't wait for them. The
+	// result of the batch is reported once executed, see ExecutionReports.
+	result := new(pb.CommitResult)
+	if txs.Len() != 0 || pbBlock.GetHeight() != 0 {
+		batch := &types.ExecBatch{
+			Height:       pbBlock.GetHeight(),
+			Timestamp:    uint64(time.Now().Unix()),
+			Txs:          txs,
+			Leader:       common.HexToAddress(IncentiveData.Leader),
+			RandomNumber: randomNuber,
+			Incentive:    incentiveBytes,
+		}
+		fresh, err := es.executorPtr.journal.commit(batch)
+		if err != nil {
+			log.Warn("Failed to journal execution batch", "height", batch.Height, "err", err)
+			return result, err
+		}
+		result.Height = batch.Height
+		if !fresh {
+			log.Debug("Dropped duplicate execution batch", "height", batch.Height)
+			result.Duplicate = true
+			return result, nil
+		}
+		select {
+		case es.executorPtr.execCh <- struct{}{}:
+		default:
 		}
-		es.executorPtr.execCh <- execreq
 	}
 
 	// Check if there are protobuf errors in the consensus block
 	if len(errs) != 0 {
 		errStr := fmt.Sprintf("There are %d errors in the block", len(errs))
-		return &pb.Empty{}, fmt.Errorf(errStr)
+		return result, fmt.Errorf(errStr)
+	}
+	return result, nil
+}
+
+// ExecutionReports streams the results of the executed batches to the consensus
+// layer, from the given height on.
+func (es *executorServer) ExecutionReports(req *pb.ExecReportsRequest, stream grpc.ServerStreamingServer[pb.ExecResult]) error {
+	// Streams that fall more than the kept reports behind are ended, the
+	// consensus layer catches up by asking for the reports again
+	ch := make(chan *pb.ExecResult, execReportsLimit)
+	sub, reports := es.executorPtr.reports.subscribe(req.GetFromHeight(), ch)
+	defer sub.Unsubscribe()
+
+	for _, report := range reports {
+		if err := stream.Send(report); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case report := <-ch:
+			if err := stream.Send(report); err != nil {
+				return err
+			}
+		case err := <-sub.Err():
+			return err
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
 	}
-	return &pb.Empty{}, nil
 }
 
 func (es *executorServer) VerifyTx(ctx context.Context, pTx *pb.Transaction) (*pb.Result, error) {
@@ -268,7 +351,6 @@ type executor struct {
 
 	running atomic.Bool // a functional judge
 	syncing atomic.Bool // The indicator whether the node is still syncing.
-	serving atomic.Bool
 
 	startCh chan struct{} // ...
 	exitCh  chan struct{} // ...
@@ -279,9 +361,12 @@ type executor struct {
 	mux *event.TypeMux
 
 	newWorkCh  chan *newWorkReq // to launch a new batch to consensus
-	execCh     chan *execReq    // received from consensus, and go to execute
+	execCh     chan struct{}    // signals batches journaled by consensus, to execute
 	offChainCh chan bool        //  communicate with WASM
 
+	journal *execJournal // batches received from consensus, until executed
+	reports execReports  // results of the executed batches, for consensus
+
 	mu           sync.RWMutex   // The lock used to protect the coinbase
 	coinbase     common.Address // yeah, baby
 	currenLeader common.Address // consensus leader
@@ -296,18 +381,19 @@ type executor struct {
 	execClient *executorClient
 
 	// server to consensus layer
-	server *grpc.Server // server pointer to the running server
+	server *serverLink // server the consensus layer connects to
 
-	// difficultyAdaptor *DifficultyAdaptor
-	// priceAdaptor      *PriceAdaptor
-	powAdaptor *PoWAdaptor
-	gasAdaptor *GasAdaptor
+	// routing to the other shards
+	router       *shardRouter
+	shardClients map[uint64][]pb.ExecutorClient            // executors of the other shards
+	payoutLock   sync.Mutex                                // protects the cross-shard payouts
+	payouts      map[crossShardTransfer]*types.Transaction // payouts until recorded in the state
 
 	planPool *core.PlanPool
 }
 
 // newExecutor creates a new executor.
-func newExecutor(config *Config, chainConfig *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, isLocalBlock func(header *types.Header) bool, init bool, consensusCli pb.P2PClient, transferCli pb.TransferGRPCClient, dciClient pb.DciExectorClient) *executor {
+func newExecutor(config *Config, chainConfig *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, isLocalBlock func(header *types.Header) bool, init bool, links *grpcLinks, consensusCli pb.P2PClient, transferCli pb.TransferGRPCClient, dciClient pb.DciExectorClient) *executor {
 	executor := &executor{
 		config:      config,
 		chainConfig: chainConfig,
@@ -339,28 +425,10 @@ func newExecutor(config *Config, chainConfig *params.ChainConfig, engine consens
 		resubmitIntervalCh: make(chan time.Duration),
 
 		newWorkCh:  make(chan *newWorkReq),
-		execCh:     make(chan *execReq),
+		execCh:     make(chan struct{}, 1),
 		offChainCh: make(chan bool),
-
-		// TODO: 有问题 Params需要被plan改动，不然重启之后就没有用了
-		gasAdaptor: NewGasAdaptor(
-			params.MinPowGas,  // minGas: 最小 gas 限制
-			params.MaxPowGas,  // maxGas: 最大 gas 限制，与 DefaultConfig.GasCeil 保持一致
-			params.InitialGas, // initialGas: 初始 gas 限制
-			params.Alpha,      // alpha: EMA 平滑因子为 0.2
-		),
-		powAdaptor: NewPoWAdaptor(
-			params.TargetPowRatio,    // targetPowRatio: 目标 PoW 交易比例为 30%
-			params.Alpha,             // alpha: EMA 平滑因子为 0.2
-			params.Fmin,              // fMin: 最小调整因子为 0.8
-			params.Fmax,              // fMax: 最大调整因子为 1.2
-			params.InitialDifficulty, // initialDifficulty: 初始难度
-			params.Kp,                // kp: 比例调节系数为 0.1
-			params.Ki,                // ki: 积分调节系数为 0.01
-			params.MinPrice,          // minPrice: 最小价格
-			params.MaxPrice,          // maxPrice: 最大价格
-		),
-		planPool: core.NewPlanPool(),
+		journal:    newExecJournal(eth.ChainDb(), eth.BlockChain().CurrentBlock()),
+		planPool:   core.NewPlanPool(),
 	}
 	// TODO: 草率开始聆听
 	StartPlanClient(executor.planPool, 22324)
@@ -384,13 +452,31 @@ func newExecutor(config *Config, chainConfig *params.ChainConfig, engine consens
 		dciClient:       dciClient,
 	}
 
+	// Connect to the executors of the other shards
+	router, err := newShardRouter(executor.networkId, config.Shards)
+	if err != nil {
+		log.Crit("Invalid shard routes", "err", err)
+	}
+	if sharding := chainConfig.Sharding; sharding != nil {
+		if err := router.checkSharding(sharding); err != nil {
+			log.Crit("Shard routes disagree with the chain", "err", err)
+		}
+	}
+	executor.router = router
+	executor.shardClients = make(map[uint64][]pb.ExecutorClient)
+	for shard, endpoints := range router.executors() {
+		for i, endpoint := range endpoints {
+			link := links.dial(fmt.Sprintf("shard-%d-%d", shard, i), endpoint)
+			executor.shardClients[shard] = append(executor.shardClients[shard], pb.NewExecutorClient(link.conn))
+		}
+	}
+	executor.payouts = make(map[crossShardTransfer]*types.Transaction)
+
 	// Register the grpc server
 	executorServer := executorServer{executorPtr: executor}
-	s := grpc.NewServer()
-	pb.RegisterExecutorServer(s, &executorServer)
-	executor.server = s // then we can handle the server
-
-	executor.serving.Store(false)
+	executor.server = links.serve("executor", config.GRPC.Executor, func(s *grpc.Server) {
+		pb.RegisterExecutorServer(s, &executorServer)
+	})
 
 	// start loop
 	executor.wg.Add(3)
@@ -413,16 +499,7 @@ func (e *executor) isRunning() bool {
 // start sets the running status as 1 and triggers new work submitting.
 func (e *executor) start() {
 	e.running.Store(true)
-	if !e.serving.Load() {
-		// !!! 这一段应该进入配置文件
-		listen, err := net.Listen("tcp", "127.0.0.1:9876") // will be included in config
-		if err != nil {
-			fmt.Println(err)
-			panic("cannot listen!")
-		}
-		e.serving.Store(true)
-		go e.server.Serve(listen)
-	}
+	e.server.start()
 
 	e.startCh <- struct{}{}
 }
@@ -436,7 +513,7 @@ func (e *executor) stop() {
 // Note the worker does not support being closed multiple times.
 func (e *executor) close() {
 	e.running.Store(false)
-	e.server.Stop()
+	e.server.stop()
 	close(e.exitCh)
 	e.wg.Wait()
 }
@@ -593,47 +670,16 @@ func (e *executor) prepareWork(genParams *generateParams) (*executor_env, error)
 		timestamp = parent.Time + 1
 	}
 
-	// TODO: Havn't test yet.
-	nextPlanHeight := e.planPool.GetMinHeight()
-	if parent.Number.Uint64()+1 == nextPlanHeight {
-		e.ExecuteAndRemovePlan(nextPlanHeight)
-	}
-
-	newPoWPrice := big.NewInt(0)
-	newGas := uint64(0)
-	newGasNumerator := uint64(0)
-	newGasDenominator := uint64(0)
-	newRatioNumerator := uint64(0)
-	newRatioDenominator := uint64(0)
-
-	if genParams.isExecution {
-		// Adjust PoW parameters
-		_, newPoWPrice, newRatioNumerator, newRatioDenominator = e.powAdaptor.AdjustParameters(
-			genParams.currentRatio,
-			common.NewRational(parent.AvgRatioNumerator, parent.AvgRatioDenominator),
-			parent.PowPrice,
-		)
-
-		// Adjust gas parameters
-		newGas, newGasNumerator, newGasDenominator = e.gasAdaptor.AdjustGas(
-			genParams.currentGasRatio,
-			common.NewRational(parent.AvgGasNumerator, parent.AvgGasDenominator),
-		)
-	}
+	// Plans must be recorded in the state before the blocks they apply to
+	e.schedulePlans(parent.Number.Uint64() + 1)
 
 	header := &types.Header{
-		ParentHash:          parent.Hash(),
-		Number:              new(big.Int).Add(parent.Number, common.Big1),
-		GasLimit:            core.CalcGasLimit(parent.GasLimit, e.config.GasCeil),
-		Time:                timestamp,
-		Coinbase:            genParams.coinbase,
-		Difficulty:          big.NewInt(1),
-		PowPrice:            newPoWPrice,
-		PowGas:              newGas,
-		AvgGasNumerator:     newGasNumerator,
-		AvgGasDenominator:   newGasDenominator,
-		AvgRatioNumerator:   newRatioNumerator,
-		AvgRatioDenominator: newRatioDenominator,
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   core.CalcGasLimit(parent.GasLimit, e.config.GasCeil),
+		Time:       timestamp,
+		Coinbase:   genParams.coinbase,
+		Difficulty: big.NewInt(1),
 		// random
 		RandomNumber: genParams.currentRandomNumber,
 		// consensus info
@@ -659,6 +705,12 @@ func (e *executor) prepareWork(genParams *generateParams) (*executor_env, error)
 		return nil, err
 	}
 
+	// PoW parameters of the block, from the plans recorded in the state of the
+	// parent, the averages are updated once executed
+	env.pow = core.PowParamsAt(env.state, header.Number.Uint64())
+	if genParams.isExecution {
+		header.PowDifficulty, header.PowPrice, header.PowGas = core.CalcPowParams(e.eth.BlockChain(), env.pow, parent)
+	}
 	return env, nil
 }
 
@@ -671,8 +723,11 @@ func (e *executor) makeEnv(parent *types.Header, header *types.Header, coinbase
 		return nil, err
 	}
 	state.StartPrefetcher("miner")
+	core.PrepareState(e.chainConfig, state, header.Number)
 
-	// Note the passed coinbase may be different with header.Coinbase.
+	// The block keeps the tainted-address DAG of its parent until it locks an
+	// address. Note the passed coinbase may be different with header.Coinbase.
+	header.Tainted = parent.Tainted
 	env := &executor_env{
 		signer:   types.MakeSigner(e.chainConfig, header.Number, header.Time),
 		state:    state,
@@ -800,8 +855,9 @@ func (e *executor) sendTransactions(env *executor_env, txs *transactionsByPriceA
 			continue
 		}
 
-		// sendTx to consensus
-		_, err := e.execClient.sendTx(tx, e.networkId)
+		// sendTx to consensus, for the shard of the sender to execute
+		from, _ := types.Sender(env.signer, tx)
+		_, err := e.execClient.sendTx(tx, e.router.shardOf(from))
 		// fmt.Println("to", tx.To(), "value", tx.Value(), "nonce", tx.Nonce())
 		if err != nil {
 			log.Trace("Failed to send transaction", "hash", ltx.Hash, "err", err)
@@ -819,60 +875,105 @@ func (e *executor) sendTransactions(env *executor_env, txs *transactionsByPriceA
 func (e *executor) executionLoop() {
 	defer e.wg.Done()
 
+	// Execute the batches left pending by the last run first
+	e.executeJournal(true)
 	for {
 		select {
-		case req := <-e.execCh:
-			// fmt.Println("executionLoop get a execCh and start execute txs")
-			e.executeNewTxBatch(req.timestamp, req.txs, req.leader, req.randomNumber, req.incentive)
+		case <-e.execCh:
+			e.executeJournal(false)
+			e.retryCrossShardPayouts()
 		case <-e.exitCh:
 			return
 		}
 	}
 }
 
-func (e *executor) executeNewTxBatch(timestamp int64, txs types.Transactions, leader common.Address, randomNumber *big.Int, incentiveData []byte) {
-	var coinbase common.Address
-	if e.isRunning() {
-		coinbase = e.etherbase()
-		if coinbase == (common.Address{}) {
-			log.Error("Refusing to mine without etherbase")
-			return
+// executeJournal executes the journaled batches in order, until the next one is
+// missing. Batches that fail to produce a block are reported with all their
+// transactions rejected and marked executed, so the later batches don't wait on
+// them; only a missing etherbase stops the execution, the batch being retried on
+// the next commit. When replaying the batches of the last run, the first one is skipped if its
+// block was written already, just before a crash.
+func (e *executor) executeJournal(replay bool) {
+	for batch := e.journal.next(); batch != nil; batch = e.journal.next() {
+		var block common.Hash
+		if replay && e.batchIncluded(batch) {
+			log.Info("Skipping execution batch included already", "height", batch.Height)
+		} else {
+			// Empty batches don't produce blocks, but are reported anyway
+			result := new(pb.ExecResult)
+			if len(batch.Txs) != 0 {
+				var err error
+				if result, err = e.executeNewTxBatch(int64(batch.Timestamp), batch.Txs, batch.Leader, batch.RandomNumber, batch.Incentive); err != nil {
+					if errors.Is(err, errMissingEtherbase) {
+						log.Warn("Deferring execution batch", "height", batch.Height, "err", err)
+						return
+					}
+					log.Error("Failed to execute batch, rejecting it", "height", batch.Height, "err", err)
+					result = rejectedExecResult(batch.Txs, err)
+				}
+			}
+			result.Height = batch.Height
+			e.reports.add(result)
+			block = common.BytesToHash(result.BlockHash)
 		}
+		replay = false
+		e.journal.done(batch.Height, block)
 	}
+}
 
-	// Count PoW transactions and total gas
-	powCount := uint64(0)
-	totalGas := uint64(0)
-	txCount := uint64(len(txs))
+// rejectedExecResult reports a batch that failed to produce a block, with all
+// its transactions rejected with the error.
+func rejectedExecResult(txs types.Transactions, err error) *pb.ExecResult {
+	result := &pb.ExecResult{Txs: make([]*pb.TxResult, len(txs))}
+	for i, tx := range txs {
+		result.Txs[i] = &pb.TxResult{Hash: tx.Hash().Bytes(), Status: pb.TxStatus_TX_REJECTED, Error: err.Error()}
+	}
+	return result
+}
 
-	if txCount > 0 {
-		for _, tx := range txs {
-			if tx.Type() == types.PowTxType {
-				powCount++
-			}
-			totalGas += tx.Gas()
+// batchIncluded reports whether any transaction of the batch is in the chain.
+func (e *executor) batchIncluded(batch *types.ExecBatch) bool {
+	for _, tx := range batch.Txs {
+		if rawdb.ReadTxLookupEntry(e.eth.ChainDb(), tx.Hash()) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// executeNewTxBatch executes a batch of transactions and seals the block, and
+// reports the result of the batch.
+func (e *executor) executeNewTxBatch(timestamp int64, txs types.Transactions, leader common.Address, randomNumber *big.Int, incentiveData []byte) (*pb.ExecResult, error) {
+	var coinbase common.Address
+	if e.isRunning() {
+		coinbase = e.etherbase()
+		if coinbase == (common.Address{}) {
+			return nil, errMissingEtherbase
 		}
 	}
 
 	work, err := e.prepareWork(&generateParams{
-		timestamp: uint64(timestamp),
-		coinbase:  coinbase,
-		currentRatio: &common.Rational{
-			Numerator:   powCount,
-			Denominator: txCount,
-		},
-		currentGasRatio: &common.Rational{
-			Numerator:   totalGas,
-			Denominator: txCount,
-		},
+		timestamp:   uint64(timestamp),
+		coinbase:    coinbase,
 		isExecution: true,
 	})
 	if err != nil {
-		return
+		return nil, err
 	}
 
 	e.executeTransactions(work, txs)
-	e.writeToChain(work)
+	block, err := e.writeToChain(work)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ExecResult{
+		BlockNumber:  block.NumberU64(),
+		BlockHash:    block.Hash().Bytes(),
+		StateRoot:    block.Root().Bytes(),
+		ReceiptsRoot: block.ReceiptHash().Bytes(),
+		Txs:          work.results,
+	}, nil
 }
 
 // 串行地执行交易，会返回一个Logs，或许以后会有用
@@ -886,15 +987,19 @@ func (e *executor) executeTransactions(env *executor_env, txs types.Transactions
 	// fmt.Println("start exec,txs len:", len((txs)))
 	log.Info("start execute transactions", "receive init txs len:", txs.Len())
 	env.initTxcount = txs.Len()
-	for _, tx := range txs {
+	for i, tx := range txs {
 		// If we don't have enough gas for any further transactions then we're done.
 		if env.gasPool.Gas() < params.TxGas {
 			log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", params.TxGas)
+			for _, tx := range txs[i:] {
+				env.reject(tx, pb.TxStatus_TX_SKIPPED, core.ErrGasLimitReached)
+			}
 			break
 		}
 		// If we don't have enough space for the next transaction, skip.
 		if env.gasPool.Gas() < tx.Gas() {
 			log.Trace("Not enough gas left for transaction", "hash", tx.Hash(), "left", env.gasPool.Gas(), "needed", tx.Gas())
+			env.reject(tx, pb.TxStatus_TX_SKIPPED, core.ErrGasLimitReached)
 			continue
 		}
 		// Transaction seems to fit, pull it up from the pooltinue
@@ -902,6 +1007,7 @@ func (e *executor) executeTransactions(env *executor_env, txs types.Transactions
 		// phase, start ignoring the sender until we do.
 		if tx.Protected() && !e.chainConfig.IsEIP155(env.header.Number) {
 			log.Trace("Ignoring replay protected transaction", "hash", tx.Hash(), "eip155", e.chainConfig.EIP155Block)
+			env.reject(tx, pb.TxStatus_TX_REJECTED, errTxReplayProtected)
 			continue
 		}
 
@@ -909,24 +1015,27 @@ func (e *executor) executeTransactions(env *executor_env, txs types.Transactions
 		if tx.To() != nil {
 			if tx.To().Hex() == ContributionContractAddr && from != e.currenLeader {
 				log.Trace("Ignoring contribution transaction because it is not from current leader", "hash", tx.Hash(), "leader", e.currenLeader)
+				env.reject(tx, pb.TxStatus_TX_REJECTED, errTxNotFromLeader)
 				continue
 			}
 		}
 
-		// TODO: How to transimit rate from govern.
-		env.state.SetInterestRate(1) // 1% interest rate
-
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 		logs, err := e.executeTransaction(env, tx)
 		switch {
 		case errors.Is(err, core.ErrNonceTooLow):
 			// New head notification data race between the transaction pool and miner, shift
 			log.Trace("Skipping transaction with low nonce", "hash", tx.Hash, "sender", from, "nonce", tx.Nonce())
+			env.reject(tx, pb.TxStatus_TX_REJECTED, err)
 			continue
 
 		case errors.Is(err, nil):
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			coalescedLogs = append(coalescedLogs, logs...)
+			result := env.include(tx, env.receipts[len(env.receipts)-1])
+			if sharding := e.chainConfig.Sharding; tx.To() != nil && result.Status == pb.TxStatus_TX_SUCCESS && sharding.IsForeign(*tx.To()) {
+				result.DestinationShard = sharding.ShardOf(*tx.To())
+			}
 			continue
 
 		default:
@@ -934,6 +1043,7 @@ func (e *executor) executeTransactions(env *executor_env, txs types.Transactions
 			// the same sender because of `nonce-too-high` clause.
 			log.Debug("Transaction failed, account skipped", "hash", tx.Hash, "err", err)
 			log.Error("Transaction failed, account skipped", "hash", tx.Hash, "err", err)
+			env.reject(tx, pb.TxStatus_TX_REJECTED, err)
 			continue
 		}
 	}
@@ -1002,23 +1112,47 @@ func (e *executor) applyTransaction(env *executor_env, tx *types.Transaction) (*
 	var (
 		snap = env.state.Snapshot()
 		gp   = env.gasPool.Gas()
+		used = env.header.GasUsed
 	)
 	receipt, err := core.ApplyTransaction(e.chainConfig, e.eth.BlockChain(), &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *e.eth.BlockChain().GetVMConfig())
+	taint := env.taint
+	if err == nil && len(receipt.Tainted) > 0 {
+		// Locks the tainted-address DAG can't record would invalidate the block.
+		// The DAG of the parent is opened on the first lock.
+		if taint == nil {
+			taint, err = e.eth.BlockChain().TaintDAG(env.header.Tainted)
+		}
+		if err == nil {
+			taint, err = core.AddTainted(taint, receipt.Tainted)
+		}
+	}
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
+		env.header.GasUsed = used
+		return receipt, err
+	}
+	if taint != env.taint {
+		env.taint, env.header.Tainted = taint, taint.GetRoot().GetHash()
 	}
-	return receipt, err
+	return receipt, nil
 }
 
-func (e *executor) writeToChain(env *executor_env) error {
+func (e *executor) writeToChain(env *executor_env) (*types.Block, error) {
 	// 插入header的新数据
 	env.header.CommitTxLength = uint64(env.initTxcount)
+	parent := e.eth.BlockChain().GetHeader(env.header.ParentHash, env.header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, errors.New("missing parent")
+	}
+	ratio, usage := core.CalcPowAverages(env.pow, parent, env.header, env.txs)
+	env.header.AvgRatioNumerator, env.header.AvgRatioDenominator = ratio.Numerator, ratio.Denominator
+	env.header.AvgGasNumerator, env.header.AvgGasDenominator = usage.Numerator, usage.Denominator
 
 	// 组装一个区块
 	block, err := e.engine.FinalizeAndAssemble(e.eth.BlockChain(), env.header, env.state, env.txs, nil, env.receipts, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	var (
 		receipts = make([]*types.Receipt, len(env.receipts))
@@ -1046,10 +1180,10 @@ func (e *executor) writeToChain(env *executor_env) error {
 		logs = append(logs, receipt.Logs...)
 	}
 	// Commit block and state to database.
-	_, err = e.eth.BlockChain().WriteBlockAndSetHead(block, receipts, logs, env.state, true)
+	_, err = e.eth.BlockChain().WriteBlockAndSetHead(block, receipts, logs, env.state, env.taint, true)
 	if err != nil {
 		log.Error("Failed writing block to chain", "err", err)
-		return err
+		return nil, err
 	}
 
 	// fmt.Println(e.eth.BlockChain().CurrentBlock().Number)
@@ -1060,7 +1194,7 @@ func (e *executor) writeToChain(env *executor_env) error {
 	// emit broadcast
 	e.mux.Post(core.NewMinedBlockEvent{Block: block, NetworkID: e.networkId})
 
-	return nil
+	return block, nil
 }
 
 func isTokenTransition(tx *types.Transaction) bool {
@@ -1074,37 +1208,67 @@ func isTokenTransition(tx *types.Transaction) bool {
 	return false
 }
 
-func (e *executor) ExecuteAndRemovePlan(height uint64) error {
-	// Get merged plan from pool
-	mergedPlan := e.planPool.MergePlans(height)
-	if mergedPlan == nil {
-		return nil
+// schedulePlans sends the plans received from the consensus layer in plan
+// transactions of the etherbase, which must hold the plan role of the
+// governance module. The parameters of the blocks derive from the plans these
+// record in the state. Plans for blocks from number on can't be scheduled
+// anymore and are dropped.
+func (e *executor) schedulePlans(number uint64) {
+	for {
+		height := e.planPool.GetMinHeight()
+		plans := e.planPool.GetPlansByHeight(height)
+		if len(plans) == 0 {
+			return
+		}
+		plan := e.planPool.MergePlans(height)
+		for _, p := range plans {
+			e.planPool.RemovePlan(p.ID)
+		}
+		if height <= number {
+			log.Warn("Dropping plan of past block", "height", height, "number", number)
+			continue
+		}
+		data, err := core.PowPlanData(plan)
+		if err != nil {
+			log.Error("Failed to encode plan", "height", height, "err", err)
+			continue
+		}
+		gas, err := core.IntrinsicGas(data, nil, false, true, true, true)
+		if err != nil {
+			log.Error("Failed to compute plan gas", "height", height, "err", err)
+			continue
+		}
+		tx, err := e.submitTx(params.PowPlanAddress, new(big.Int), gas, data)
+		if err != nil {
+			log.Warn("Failed to send plan", "height", height, "err", err)
+			continue
+		}
+		log.Info("Sent plan", "height", height, "tx", tx.Hash())
 	}
+}
 
-	// Update parameters
-	e.planPool.UpdateParams(mergedPlan)
-
-	// Recreate adaptors with new parameters
-	e.powAdaptor = NewPoWAdaptor(
-		params.TargetPowRatio,
-		params.Alpha,
-		params.Fmin,
-		params.Fmax,
-		params.InitialDifficulty,
-		params.Kp,
-		params.Ki,
-		params.MinPrice,
-		params.MaxPrice,
-	)
-
-	e.gasAdaptor = NewGasAdaptor(
-		params.MinPowGas,
-		params.MaxPowGas,
-		params.InitialGas,
-		params.Alpha,
-	)
-
-	e.planPool.RemovePlan(height)
-
-	return nil
+// submitTx signs a transaction of the etherbase and adds it to the pool, to be
+// sent to the consensus layer.
+func (e *executor) submitTx(to common.Address, value *big.Int, gas uint64, data []byte) (*types.Transaction, error) {
+	account := accounts.Account{Address: e.etherbase()}
+	wallet, err := e.eth.AccountManager().Find(account)
+	if err != nil {
+		return nil, fmt.Errorf("etherbase wallet not found: %v", err)
+	}
+	gasPrice := new(big.Int)
+	if e.opts.MinTip != nil {
+		gasPrice.Set(e.opts.MinTip)
+	}
+	if baseFee := e.eth.BlockChain().CurrentHeader().BaseFee; baseFee != nil {
+		gasPrice.Add(gasPrice, baseFee)
+	}
+	tx := types.NewTransaction(e.eth.TxPool().Nonce(account.Address), to, value, gas, gasPrice, data)
+	signed, err := wallet.SignTx(account, tx, e.chainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if errs := e.eth.TxPool().Add([]*types.Transaction{signed}, true, true); errs[0] != nil {
+		return nil, errs[0]
+	}
+	return signed, nil
 }
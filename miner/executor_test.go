@@ -103,6 +103,7 @@ type testWorkerBackend struct {
 
 func (b *testWorkerBackend) BlockChain() *core.BlockChain      { return b.chain }
 func (b *testWorkerBackend) TxPool() *txpool.TxPool            { return b.txPool }
+func (b *testWorkerBackend) ChainDb() ethdb.Database           { return b.db }
 func (b *testWorkerBackend) NetworkId() uint64                 { return 1 }
 func (b *testWorkerBackend) AccountManager() *accounts.Manager { return nil }
 
@@ -192,7 +193,8 @@ func newTestExecutor(chainConfig *params.ChainConfig, engine consensus.Engine, d
 
 	dciClient := pb.NewDciExectorClient(conn2)
 	transferClient := pb.NewTransferGRPCClient(conn1)
-	e := newExecutor(testConfig, chainConfig, engine, backend, new(event.TypeMux), nil, false, p2pClient, transferClient, dciClient)
+	links, _ := newGRPCLinks(&testConfig.GRPC)
+	e := newExecutor(testConfig, chainConfig, engine, backend, new(event.TypeMux), nil, false, links, p2pClient, transferClient, dciClient)
 	e.coinbase = testBankAddress
 	return e, backend
 }
@@ -206,7 +208,8 @@ func newTestPotExecutor(chainConfig *params.ChainConfig, engine consensus.Engine
 	}
 
 	potExcutorClient := pb.NewPoTExecutorClient(conn)
-	p := newPoter(backend, potExcutorClient)
+	links, _ := newGRPCLinks(&testConfig.GRPC)
+	p := newPoter(backend, links, testConfig.GRPC.Poter, potExcutorClient)
 
 	return p
 }
@@ -0,0 +1,314 @@
+package miner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// GRPCConfig is the configuration of the gRPC links between the executor node
+// and the external layers.
+type GRPCConfig struct {
+	Consensus string // Endpoint of the consensus layer
+	PoT       string // Endpoint of the PoT layer
+	Transfer  string // Endpoint of the transfer service
+	DCI       string // Endpoint of the DCI layer
+
+	Executor  string // Listen address of the executor server
+	Poter     string // Listen address of the PoT executor server
+	CoinMixer string // Listen address of the coin mixer monitor server
+
+	// TLS is enabled by setting a certificate and key. It is mutual if a CA is
+	// set too: the other side must then present a certificate signed by it.
+	CertFile string `toml:",omitempty"`
+	KeyFile  string `toml:",omitempty"`
+	CAFile   string `toml:",omitempty"`
+
+	Keepalive  time.Duration // Interval of the keepalive pings, zero disables them
+	MaxBackoff time.Duration // Maximum delay between reconnection attempts
+}
+
+// DefaultGRPCConfig contains the default settings of the gRPC links, with every
+// external layer on the local host.
+var DefaultGRPCConfig = GRPCConfig{
+	Consensus:  "127.0.0.1:9080",
+	PoT:        "127.0.0.1:9081",
+	Transfer:   "127.0.0.1:1145",
+	DCI:        "127.0.0.1:9866",
+	Executor:   "127.0.0.1:9876",
+	Poter:      "127.0.0.1:9808",
+	CoinMixer:  "127.0.0.1:9294",
+	MaxBackoff: 10 * time.Second,
+}
+
+// tlsConfig loads the TLS configuration of the links, nil if TLS is disabled.
+// The same configuration serves both sides of the links.
+func (c *GRPCConfig) tlsConfig() (*tls.Config, error) {
+	if c.CertFile == "" && c.KeyFile == "" {
+		if c.CAFile != "" {
+			return nil, fmt.Errorf("gRPC CA %s set without a certificate", c.CAFile)
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load gRPC certificate: %v", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gRPC CA: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in gRPC CA %s", c.CAFile)
+		}
+		config.RootCAs = pool
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// dialOptions returns the options to dial the external layers with. Lost
+// connections are reestablished with exponential backoff.
+func (c *GRPCConfig) dialOptions(tlsConfig *tls.Config) []grpc.DialOption {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	backoffConfig := backoff.DefaultConfig
+	if c.MaxBackoff > 0 {
+		backoffConfig.MaxDelay = c.MaxBackoff
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: backoffConfig}),
+	}
+	if c.Keepalive > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                c.Keepalive,
+			Timeout:             c.Keepalive,
+			PermitWithoutStream: true,
+		}))
+	}
+	return opts
+}
+
+// serverOptions returns the options of the servers the external layers connect to.
+func (c *GRPCConfig) serverOptions(tlsConfig *tls.Config) []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if c.Keepalive > 0 {
+		opts = append(opts,
+			grpc.KeepaliveParams(keepalive.ServerParameters{Time: c.Keepalive, Timeout: c.Keepalive}),
+			grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: c.Keepalive / 2, PermitWithoutStream: true}),
+		)
+	}
+	return opts
+}
+
+// LinkStatus reports the health of a gRPC link to an external layer.
+type LinkStatus struct {
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+	Server   bool   `json:"server"` // Whether the external layer connects to us
+	TLS      bool   `json:"tls"`
+	State    string `json:"state"` // Connectivity state of clients, serving or stopped for servers
+	Error    string `json:"error,omitempty"`
+}
+
+// grpcLinks creates the links between the executor node and the external
+// layers, sharing the TLS and keepalive settings, and keeps track of them for
+// health reporting.
+type grpcLinks struct {
+	config    *GRPCConfig
+	tlsConfig *tls.Config
+
+	mu      sync.Mutex
+	clients []*clientLink
+	servers []*serverLink
+}
+
+// newGRPCLinks loads the TLS configuration of the links, if enabled.
+func newGRPCLinks(config *GRPCConfig) (*grpcLinks, error) {
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &grpcLinks{config: config, tlsConfig: tlsConfig}, nil
+}
+
+// dial dials the external layer at endpoint. Dialing doesn't wait for the
+// connection, which is established in the background and reestablished when
+// lost.
+func (g *grpcLinks) dial(name, endpoint string) *clientLink {
+	link := &clientLink{name: name, endpoint: endpoint, tls: g.tlsConfig != nil}
+	if link.conn, link.err = grpc.Dial(endpoint, g.config.dialOptions(g.tlsConfig)...); link.err != nil {
+		log.Error("Failed to dial external layer", "name", name, "endpoint", endpoint, "err", link.err)
+	}
+	g.mu.Lock()
+	g.clients = append(g.clients, link)
+	g.mu.Unlock()
+	return link
+}
+
+// serve creates the server the external layers connect to at endpoint, serving
+// the services register registers once started.
+func (g *grpcLinks) serve(name, endpoint string, register func(*grpc.Server)) *serverLink {
+	link := &serverLink{
+		name:     name,
+		endpoint: endpoint,
+		tls:      g.tlsConfig != nil,
+		opts:     g.config.serverOptions(g.tlsConfig),
+		register: register,
+	}
+	g.mu.Lock()
+	g.servers = append(g.servers, link)
+	g.mu.Unlock()
+	return link
+}
+
+// status reports the health of all links, clients first.
+func (g *grpcLinks) status() []LinkStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	status := make([]LinkStatus, 0, len(g.clients)+len(g.servers))
+	for _, link := range g.clients {
+		status = append(status, link.status())
+	}
+	for _, link := range g.servers {
+		status = append(status, link.status())
+	}
+	return status
+}
+
+// close closes the connections to the external layers. Servers are stopped by
+// their owners.
+func (g *grpcLinks) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, link := range g.clients {
+		link.close()
+	}
+}
+
+// clientLink is a connection to an external layer.
+type clientLink struct {
+	name     string
+	endpoint string
+	tls      bool
+	conn     *grpc.ClientConn
+	err      error // Dial error, the link is unusable if set
+}
+
+func (l *clientLink) close() {
+	if l.conn != nil {
+		l.conn.Close()
+	}
+}
+
+func (l *clientLink) status() LinkStatus {
+	status := LinkStatus{Name: l.name, Endpoint: l.endpoint, TLS: l.tls}
+	if l.err != nil {
+		status.State, status.Error = "failed", l.err.Error()
+	} else {
+		status.State = l.conn.GetState().String()
+	}
+	return status
+}
+
+// serverLink is a server the external layers connect to. A fresh gRPC server is
+// created every time it's started, so that it can be restarted after a stop.
+type serverLink struct {
+	name     string
+	endpoint string
+	tls      bool
+	opts     []grpc.ServerOption
+	register func(*grpc.Server) // Registers the services on a new server
+
+	mu       sync.Mutex
+	server   *grpc.Server // Running server, nil if stopped
+	listener net.Listener // Listener of the running server
+	err      error        // Last listen or serve error
+}
+
+// start listens on the endpoint and serves, unless the server is running
+// already. Failures to listen, e.g. on a busy port, are logged and reported in
+// the status of the link; starting again retries.
+func (l *serverLink) start() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.server != nil {
+		return
+	}
+	listener, err := net.Listen("tcp", l.endpoint)
+	if err != nil {
+		log.Error("Failed to start gRPC server", "name", l.name, "endpoint", l.endpoint, "err", err)
+		l.err = err
+		return
+	}
+	server := grpc.NewServer(l.opts...)
+	l.register(server)
+	l.server, l.listener, l.err = server, listener, nil
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Error("gRPC server failed", "name", l.name, "endpoint", l.endpoint, "err", err)
+
+			l.mu.Lock()
+			if l.server == server {
+				l.server, l.listener, l.err = nil, nil, err
+			}
+			l.mu.Unlock()
+		}
+	}()
+}
+
+// stop stops the server, closing all its connections. The listener is closed
+// here too, as the server only closes it once serving, so that the endpoint is
+// free when stop returns.
+func (l *serverLink) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.server != nil {
+		l.server.Stop()
+		l.listener.Close()
+		l.server, l.listener = nil, nil
+	}
+}
+
+func (l *serverLink) status() LinkStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status := LinkStatus{Name: l.name, Endpoint: l.endpoint, Server: true, TLS: l.tls, State: "stopped"}
+	if l.server != nil {
+		status.State = "serving"
+	}
+	if l.err != nil {
+		status.Error = l.err.Error()
+	}
+	return status
+}
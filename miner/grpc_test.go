@@ -0,0 +1,73 @@
+package miner
+
+import (
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func TestServerLinkBusyPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	links, err := newGRPCLinks(&GRPCConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	busy := links.serve("busy", listener.Addr().String(), func(*grpc.Server) {})
+	busy.start()
+	if status := busy.status(); status.State != "stopped" || status.Error == "" {
+		t.Fatalf("busy port not reported: %+v", status)
+	}
+	// Once the port is free, starting again serves.
+	listener.Close()
+	busy.start()
+	if status := busy.status(); status.State != "serving" || status.Error != "" {
+		t.Fatalf("server not started: %+v", status)
+	}
+	busy.stop()
+	if status := busy.status(); status.State != "stopped" {
+		t.Fatalf("server not stopped: %+v", status)
+	}
+	// Stopped servers can be restarted.
+	busy.start()
+	defer busy.stop()
+	if status := busy.status(); status.State != "serving" {
+		t.Fatalf("server not restarted: %+v", status)
+	}
+}
+
+func TestGRPCLinksStatus(t *testing.T) {
+	links, err := newGRPCLinks(&GRPCConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer links.close()
+
+	links.dial("consensus", "127.0.0.1:1")
+	links.serve("executor", "127.0.0.1:0", func(*grpc.Server) {})
+
+	status := links.status()
+	if len(status) != 2 {
+		t.Fatalf("wrong number of links: have %d, want 2", len(status))
+	}
+	if status[0].Name != "consensus" || status[0].Server || status[0].TLS || status[0].Error != "" {
+		t.Errorf("wrong client status: %+v", status[0])
+	}
+	if status[1].Name != "executor" || !status[1].Server || status[1].State != "stopped" {
+		t.Errorf("wrong server status: %+v", status[1])
+	}
+}
+
+func TestGRPCConfigTLS(t *testing.T) {
+	if _, err := newGRPCLinks(&GRPCConfig{CAFile: "ca.pem"}); err == nil {
+		t.Error("CA without certificate accepted")
+	}
+	if _, err := newGRPCLinks(&GRPCConfig{CertFile: "missing.pem", KeyFile: "missing.key"}); err == nil {
+		t.Error("missing certificate accepted")
+	}
+}
@@ -32,12 +32,11 @@ import (
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/proto/pb"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // Backend wraps all methods required for mining. Only full node is capable
@@ -46,6 +45,7 @@ type Backend interface {
 	AccountManager() *accounts.Manager
 	BlockChain() *core.BlockChain
 	TxPool() *txpool.TxPool
+	ChainDb() ethdb.Database
 	NetworkId() uint64
 }
 
@@ -59,6 +59,8 @@ type Config struct {
 	Recommit          time.Duration  // The time interval for miner to re-create mining work.
 	Sharding          []byte
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
+	GRPC              GRPCConfig    // Links to the external layers
+	Shards            []ShardRoute  `toml:",omitempty"` // Shards of the accounts, all local if empty
 }
 
 // DefaultConfig contains default settings for miner.
@@ -73,6 +75,7 @@ var DefaultConfig = Config{
 	// run 3 rounds.
 	Recommit:          2 * time.Second,
 	NewPayloadTimeout: 2 * time.Second,
+	GRPC:              DefaultGRPCConfig,
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -87,49 +90,34 @@ type Miner struct {
 	executor         *executor
 	poter            *poter
 	coinMixerMonitor *CoinMixerMonitor
+	links            *grpcLinks // Links to the external layers
 
 	wg sync.WaitGroup
 }
 
 func New(eth Backend, config *Config, chainConfig *params.ChainConfig, mux *event.TypeMux, engine consensus.Engine, isLocalBlock func(header *types.Header) bool) *Miner {
-	// consensus client
-	conn, err := grpc.Dial("127.0.0.1:9080", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	links, err := newGRPCLinks(&config.GRPC)
 	if err != nil {
-		fmt.Println(err)
+		log.Crit("Invalid gRPC configuration", "err", err)
 	}
-	p2pClient := pb.NewP2PClient(conn)
-
-	// POT Client
-	conn1, err := grpc.Dial("127.0.0.1:9081", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		fmt.Println(err)
-	}
-	potClient := pb.NewPoTExecutorClient(conn1)
-
-	// Transfer Client
-	conn2, err := grpc.Dial("127.0.0.1:1145", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		fmt.Println(err)
-	}
-	transferClient := pb.NewTransferGRPCClient(conn2)
-
-	conn3, err := grpc.Dial("127.0.0.1:9866", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		fmt.Println(err)
-	}
-	dciClient := pb.NewDciExectorClient(conn3)
+	// Clients of the external layers
+	p2pClient := pb.NewP2PClient(links.dial("consensus", config.GRPC.Consensus).conn)
+	potClient := pb.NewPoTExecutorClient(links.dial("pot", config.GRPC.PoT).conn)
+	transferClient := pb.NewTransferGRPCClient(links.dial("transfer", config.GRPC.Transfer).conn)
+	dciClient := pb.NewDciExectorClient(links.dial("dci", config.GRPC.DCI).conn)
 
 	miner := &Miner{
 		mux:     mux,
 		eth:     eth,
 		engine:  engine,
+		links:   links,
 		exitCh:  make(chan struct{}),
 		startCh: make(chan struct{}),
 		stopCh:  make(chan struct{}),
 		// worker:   newWorker(config, chainConfig, engine, eth, mux, isLocalBlock, true),
-		executor:         newExecutor(config, chainConfig, engine, eth, mux, isLocalBlock, false, p2pClient, transferClient, dciClient),
-		poter:            newPoter(eth, potClient),
-		coinMixerMonitor: NewCoinMixerMonitor(eth, chainConfig, mux),
+		executor:         newExecutor(config, chainConfig, engine, eth, mux, isLocalBlock, false, links, p2pClient, transferClient, dciClient),
+		poter:            newPoter(eth, links, config.GRPC.Poter, potClient),
+		coinMixerMonitor: NewCoinMixerMonitor(eth, chainConfig, mux, links, config.GRPC.CoinMixer),
 	}
 	miner.wg.Add(1)
 	go miner.update()
@@ -215,6 +203,7 @@ func (miner *Miner) update() {
 			miner.executor.close()
 			miner.poter.close()
 			miner.coinMixerMonitor.Stop()
+			miner.links.close()
 			return
 		}
 	}
@@ -233,6 +222,11 @@ func (miner *Miner) Close() {
 	miner.wg.Wait()
 }
 
+// Links reports the health of the gRPC links to the external layers.
+func (miner *Miner) Links() []LinkStatus {
+	return miner.links.status()
+}
+
 func (miner *Miner) Mining() bool {
 	// return miner.worker.isRunning()
 	return miner.executor.isRunning()
@@ -2,9 +2,6 @@ package miner
 
 import (
 	"context"
-	"fmt"
-	"net"
-	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -17,50 +14,32 @@ type poter struct {
 	chain *core.BlockChain
 
 	// running atomic.Bool // a functional judge
-	serving   atomic.Bool
 	networkId uint64
 
 	poterClient pb.PoTExecutorClient
 
-	server *grpc.Server // server pointer to the running server
+	server *serverLink // server the PoT layer connects to
 	pb.UnimplementedPoTExecutorServer
 }
 
-func newPoter(eth Backend, cli pb.PoTExecutorClient) *poter {
+func newPoter(eth Backend, links *grpcLinks, endpoint string, cli pb.PoTExecutorClient) *poter {
 	poter := &poter{
 		eth:   eth,
 		chain: eth.BlockChain(),
 		// running: atomic.Bool{},
-		serving:   atomic.Bool{},
 		networkId: eth.NetworkId(),
-
-		server: grpc.NewServer(),
 	}
 	poter.poterClient = cli
 	// Register the grpc server
-	s := grpc.NewServer()
-	pb.RegisterPoTExecutorServer(s, poter)
-	poter.server = s // then we can handle the server
-
-	poter.serving.Store(false)
-
+	poter.server = links.serve("poter", endpoint, func(s *grpc.Server) {
+		pb.RegisterPoTExecutorServer(s, poter)
+	})
 	return poter
 }
 
 // start sets the running status as 1 and triggers new work submitting.
 func (p *poter) start() {
-
-	if !p.serving.Load() {
-		// !!! 这一段应该进入配置文件
-		listen, err := net.Listen("tcp", "127.0.0.1:9808") // will be included in config
-		if err != nil {
-			fmt.Println(err)
-			panic("cannot listen!")
-		}
-		p.serving.Store(true)
-		go p.server.Serve(listen)
-	}
-
+	p.server.start()
 }
 
 // func (p *poter) isRunning() bool {
@@ -68,8 +47,7 @@ func (p *poter) start() {
 // }
 
 func (p *poter) close() {
-	p.server.Stop()
-	p.serving.Store(false)
+	p.server.stop()
 }
 
 func (p *poter) GetTxs(ctx context.Context, getTxRq *pb.GetTxRequest) (*pb.GetTxResponse, error) {
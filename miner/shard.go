@@ -0,0 +1,103 @@
+package miner
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ShardRoute assigns the accounts of an address range to a shard. Transactions
+// are executed by the shard of their sender.
+type ShardRoute struct {
+	Shard    uint64         // Network ID of the shard
+	From     common.Address // First address of the range
+	To       common.Address // Last address of the range, inclusive
+	Executor string         `toml:",omitempty"` // gRPC endpoints of the executors of the shard, comma separated
+}
+
+// ParseShardRoute parses a shard route given as <shard>:<from>-<to>, optionally
+// followed by @<executor endpoints>.
+func ParseShardRoute(s string) (ShardRoute, error) {
+	var route ShardRoute
+	s, route.Executor, _ = strings.Cut(s, "@")
+	shard, addrs, ok := strings.Cut(s, ":")
+	if !ok {
+		return route, fmt.Errorf("invalid shard route %q, want <shard>:<from>-<to>[@<executor>]", s)
+	}
+	from, to, ok := strings.Cut(addrs, "-")
+	if !ok || !common.IsHexAddress(from) || !common.IsHexAddress(to) {
+		return route, fmt.Errorf("invalid address range %q", addrs)
+	}
+	var err error
+	if route.Shard, err = strconv.ParseUint(shard, 0, 64); err != nil {
+		return route, fmt.Errorf("invalid shard %q: %v", shard, err)
+	}
+	route.From, route.To = common.HexToAddress(from), common.HexToAddress(to)
+	return route, nil
+}
+
+// shardRouter maps accounts to shards. Accounts out of every range belong to
+// the local shard, so that without routes all transactions are executed
+// locally.
+type shardRouter struct {
+	local  uint64
+	routes []ShardRoute // Sorted by range
+}
+
+// newShardRouter checks that the ranges of the routes don't overlap.
+func newShardRouter(local uint64, routes []ShardRoute) (*shardRouter, error) {
+	sorted := append([]ShardRoute{}, routes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].From.Bytes(), sorted[j].From.Bytes()) < 0
+	})
+	for i, route := range sorted {
+		if bytes.Compare(route.From.Bytes(), route.To.Bytes()) > 0 {
+			return nil, fmt.Errorf("empty address range %x-%x of shard %d", route.From, route.To, route.Shard)
+		}
+		if i > 0 && bytes.Compare(sorted[i-1].To.Bytes(), route.From.Bytes()) >= 0 {
+			return nil, fmt.Errorf("address ranges of shards %d and %d overlap", sorted[i-1].Shard, route.Shard)
+		}
+	}
+	return &shardRouter{local: local, routes: sorted}, nil
+}
+
+// shardOf returns the shard of an account.
+func (r *shardRouter) shardOf(addr common.Address) uint64 {
+	i := sort.Search(len(r.routes), func(i int) bool {
+		return bytes.Compare(r.routes[i].To.Bytes(), addr.Bytes()) >= 0
+	})
+	if i < len(r.routes) && bytes.Compare(r.routes[i].From.Bytes(), addr.Bytes()) <= 0 {
+		return r.routes[i].Shard
+	}
+	return r.local
+}
+
+// executors returns the executor endpoints of the other shards.
+func (r *shardRouter) executors() map[uint64][]string {
+	endpoints := make(map[uint64][]string)
+	for _, route := range r.routes {
+		if route.Shard != r.local && route.Executor != "" {
+			endpoints[route.Shard] = append(endpoints[route.Shard], strings.Split(route.Executor, ",")...)
+		}
+	}
+	return endpoints
+}
+
+// checkSharding checks that the routes agree with the sharding of the chain,
+// which cross-shard transfers are locked and paid out by.
+func (r *shardRouter) checkSharding(sharding *params.ShardingConfig) error {
+	if sharding.Shard != r.local {
+		return fmt.Errorf("chain of shard %d, executor of shard %d", sharding.Shard, r.local)
+	}
+	for _, route := range r.routes {
+		if sharding.ShardOf(route.From) != route.Shard || sharding.ShardOf(route.To) != route.Shard {
+			return fmt.Errorf("address range %x-%x of shard %d not in the shard on chain", route.From, route.To, route.Shard)
+		}
+	}
+	return nil
+}
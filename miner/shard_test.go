@@ -0,0 +1,85 @@
+package miner
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestParseShardRoute(t *testing.T) {
+	route, err := ParseShardRoute("2:0x0000000000000000000000000000000000000000-0x7fffffffffffffffffffffffffffffffffffffff@127.0.0.1:9002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ShardRoute{
+		Shard:    2,
+		To:       common.HexToAddress("0x7fffffffffffffffffffffffffffffffffffffff"),
+		Executor: "127.0.0.1:9002",
+	}
+	if route != want {
+		t.Fatalf("wrong route: have %+v, want %+v", route, want)
+	}
+	for _, s := range []string{
+		"",
+		"2",
+		"x:0x0000000000000000000000000000000000000000-0x0000000000000000000000000000000000000001",
+		"2:0x0000000000000000000000000000000000000000",
+		"2:0x00-0x01",
+	} {
+		if _, err := ParseShardRoute(s); err == nil {
+			t.Errorf("route %q parsed", s)
+		}
+	}
+}
+
+func TestShardRouter(t *testing.T) {
+	var (
+		a = common.HexToAddress("0x1000000000000000000000000000000000000000")
+		b = common.HexToAddress("0x1fffffffffffffffffffffffffffffffffffffff")
+		c = common.HexToAddress("0x2000000000000000000000000000000000000000")
+		d = common.HexToAddress("0x2fffffffffffffffffffffffffffffffffffffff")
+	)
+	router, err := newShardRouter(1, []ShardRoute{
+		{Shard: 3, From: c, To: d, Executor: "127.0.0.1:9003,127.0.0.1:9013"},
+		{Shard: 2, From: a, To: b},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		addr  common.Address
+		shard uint64
+	}{
+		{common.Address{}, 1},
+		{a, 2},
+		{common.HexToAddress("0x1800000000000000000000000000000000000000"), 2},
+		{b, 2},
+		{c, 3},
+		{d, 3},
+		{common.HexToAddress("0xff00000000000000000000000000000000000000"), 1},
+	}
+	for _, tt := range tests {
+		if shard := router.shardOf(tt.addr); shard != tt.shard {
+			t.Errorf("wrong shard of %x: have %d, want %d", tt.addr, shard, tt.shard)
+		}
+	}
+	if executors := router.executors(); len(executors) != 1 || len(executors[3]) != 2 || executors[3][1] != "127.0.0.1:9013" {
+		t.Errorf("wrong executors: %v", executors)
+	}
+	if _, err := newShardRouter(1, []ShardRoute{{Shard: 2, From: a, To: c}, {Shard: 3, From: b, To: d}}); err == nil {
+		t.Error("overlapping ranges accepted")
+	}
+	if _, err := newShardRouter(1, []ShardRoute{{Shard: 2, From: b, To: a}}); err == nil {
+		t.Error("empty range accepted")
+	}
+	// Routes must agree with the sharding of the chain
+	sharding := &params.ShardingConfig{Shard: 1, Ranges: []params.ShardRange{{Shard: 2, From: a, To: b}, {Shard: 3, From: c, To: d}}}
+	if err := router.checkSharding(sharding); err != nil {
+		t.Errorf("matching sharding rejected: %v", err)
+	}
+	sharding.Ranges = sharding.Ranges[:1]
+	if err := router.checkSharding(sharding); err == nil {
+		t.Error("route of a local range accepted")
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/merkeldag"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 )
@@ -93,6 +94,7 @@ type environment struct {
 	receipts []*types.Receipt
 	sidecars []*types.BlobTxSidecar
 	blobs    int
+	taint    *merkeldag.MerkelDAG // Tainted-address DAG of the block, nil until changed
 }
 
 // copy creates a deep copy of environment.
@@ -104,6 +106,7 @@ func (env *environment) copy() *environment {
 		coinbase: env.coinbase,
 		header:   types.CopyHeader(env.header),
 		receipts: copyReceipts(env.receipts),
+		taint:    env.taint, // DAGs aren't changed once built
 	}
 	if env.gasPool != nil {
 		gasPool := *env.gasPool
@@ -132,6 +135,7 @@ func (env *environment) discard() {
 type task struct {
 	receipts  []*types.Receipt
 	state     *state.StateDB
+	taint     *merkeldag.MerkelDAG // Tainted-address DAG of the block, nil if unchanged
 	block     *types.Block
 	createdAt time.Time
 }
@@ -686,7 +690,7 @@ func (w *worker) resultLoop() {
 				logs = append(logs, receipt.Logs...)
 			}
 			// Commit block and state to database.
-			_, err := w.chain.WriteBlockAndSetHead(block, receipts, logs, task.state, true)
+			_, err := w.chain.WriteBlockAndSetHead(block, receipts, logs, task.state, task.taint, true)
 			if err != nil {
 				log.Error("Failed writing block to chain", "err", err)
 				continue
@@ -712,8 +716,11 @@ func (w *worker) makeEnv(parent *types.Header, header *types.Header, coinbase co
 		return nil, err
 	}
 	state.StartPrefetcher("miner")
+	core.PrepareState(w.chainConfig, state, header.Number)
 
-	// Note the passed coinbase may be different with header.Coinbase.
+	// The block keeps the tainted-address DAG of its parent until it locks an
+	// address. Note the passed coinbase may be different with header.Coinbase.
+	header.Tainted = parent.Tainted
 	env := &environment{
 		signer:   types.MakeSigner(w.chainConfig, header.Number, header.Time),
 		state:    state,
@@ -783,13 +790,30 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction) (*typ
 	var (
 		snap = env.state.Snapshot()
 		gp   = env.gasPool.Gas()
+		used = env.header.GasUsed
 	)
 	receipt, err := core.ApplyTransaction(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *w.chain.GetVMConfig())
+	taint := env.taint
+	if err == nil && len(receipt.Tainted) > 0 {
+		// Locks the tainted-address DAG can't record would invalidate the block.
+		// The DAG of the parent is opened on the first lock.
+		if taint == nil {
+			taint, err = w.chain.TaintDAG(env.header.Tainted)
+		}
+		if err == nil {
+			taint, err = core.AddTainted(taint, receipt.Tainted)
+		}
+	}
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
+		env.header.GasUsed = used
+		return receipt, err
+	}
+	if taint != env.taint {
+		env.taint, env.header.Tainted = taint, taint.GetRoot().GetHash()
 	}
-	return receipt, err
+	return receipt, nil
 }
 
 func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAndNonce, interrupt *atomic.Int32) error {
@@ -898,10 +922,6 @@ type generateParams struct {
 	noTxs       bool              // Flag whether an empty block without any transaction is expected
 
 	isExecution bool
-	// The PoW transaction ratio of the current block
-	// if currentPowRatio < 0, if means we do not refer to this field
-	currentRatio    *common.Rational
-	currentGasRatio *common.Rational
 
 	// current random number for current block
 	currentRandomNumber *big.Int
@@ -1139,7 +1159,7 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 		// If we're post merge, just ignore
 		if !w.isTTDReached(block.Header()) {
 			select {
-			case w.taskCh <- &task{receipts: env.receipts, state: env.state, block: block, createdAt: time.Now()}:
+			case w.taskCh <- &task{receipts: env.receipts, state: env.state, taint: env.taint, block: block, createdAt: time.Now()}:
 				fees := totalFees(block, env.receipts)
 				feesInEther := new(big.Float).Quo(new(big.Float).SetInt(fees), big.NewFloat(params.Ether))
 				log.Info("Commit new sealing work", "number", block.Number(), "sealhash", w.engine.SealHash(block.Header()),
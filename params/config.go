@@ -17,7 +17,10 @@
 package params
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -364,6 +367,34 @@ type ChainConfig struct {
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
+
+	// Upgraded crypto algorithms served by codestorage (nil = disabled)
+	CryptoUpgrade *CryptoUpgradeConfig `json:"cryptoUpgrade,omitempty"`
+
+	// Fee currencies and source of the voucher exchange rates (nil = the predeployed
+	// voucher contract, at the rates set in it)
+	Voucher *VoucherConfig `json:"voucher,omitempty"`
+
+	// Interest model of account balances (nil = no interest accrues)
+	Interest *InterestConfig `json:"interest,omitempty"`
+
+	// Governance of account security levels (nil = no governance module)
+	Security *SecurityConfig `json:"security,omitempty"`
+
+	// Block from which accounts with a non default security level aren't empty,
+	// so that locks survive the clearing of empty accounts (nil = no fork)
+	SecurityLevelBlock *big.Int `json:"securityLevelBlock,omitempty"`
+
+	// Block from which the call policy charges its storage reads to the frames
+	// it decides on below the top one of a transaction (nil = no fork)
+	SecurityPolicyGasBlock *big.Int `json:"securityPolicyGasBlock,omitempty"`
+
+	// Block from which headers must carry the PoW economics fields derived from
+	// their parent and its state (nil = no fork, the fields aren't checked)
+	PowEconomicsBlock *big.Int `json:"powEconomicsBlock,omitempty"`
+
+	// Shards of the accounts, each its own chain (nil = all accounts local)
+	Sharding *ShardingConfig `json:"sharding,omitempty"`
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -385,6 +416,339 @@ func (c *CliqueConfig) String() string {
 	return "clique"
 }
 
+// DefaultCodeStorageAddress is where codestorage lives if the chain config
+// doesn't say otherwise.
+var DefaultCodeStorageAddress = common.BytesToAddress([]byte{67})
+
+// CryptoUpgradeConfig is the configuration of upgraded crypto algorithms. Calls
+// of callFunc on codestorage are run in the algorithm sandbox, and a code
+// package is only loaded once Threshold distinct governors signed it.
+type CryptoUpgradeConfig struct {
+	Address   *common.Address  `json:"address,omitempty"` // Address of codestorage (nil = DefaultCodeStorageAddress)
+	Governors []common.Address `json:"governors"`         // Keys allowed to approve code packages
+	Threshold uint64           `json:"threshold"`         // Number of governor signatures required
+}
+
+// CodeStorage returns the address of the codestorage contract.
+func (c *CryptoUpgradeConfig) CodeStorage() common.Address {
+	if c != nil && c.Address != nil {
+		return *c.Address
+	}
+	return DefaultCodeStorageAddress
+}
+
+// Security levels of accounts range up to MaxSecurityLevel, new accounts start
+// at DefaultSecurityLevel and level zero means the account is locked.
+const (
+	DefaultSecurityLevel = 1
+	MaxSecurityLevel     = 5
+)
+
+// DefaultSecurityGovernanceAddress is where the security level governance module
+// lives if the chain config doesn't say otherwise.
+var DefaultSecurityGovernanceAddress = common.BytesToAddress([]byte{70})
+
+// SecurityConfig is the configuration of the security level governance module.
+// Admins hold every right of the module, and may grant rights to others.
+type SecurityConfig struct {
+	Address *common.Address  `json:"address,omitempty"` // Address of the governance module (nil = DefaultSecurityGovernanceAddress)
+	Admins  []common.Address `json:"admins"`            // Accounts holding every right from genesis on
+	Policy  *SecurityPolicy  `json:"policy,omitempty"`  // Call policy from genesis on, amended by the module (nil = default policy)
+}
+
+// Governance returns the address of the security level governance module.
+func (c *SecurityConfig) Governance() common.Address {
+	if c != nil && c.Address != nil {
+		return *c.Address
+	}
+	return DefaultSecurityGovernanceAddress
+}
+
+// IsAdmin reports whether the config makes addr an admin of the module.
+func (c *SecurityConfig) IsAdmin(addr common.Address) bool {
+	if c == nil {
+		return false
+	}
+	for _, admin := range c.Admins {
+		if admin == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// CallPolicy returns the call policy the chain starts with.
+func (c *SecurityConfig) CallPolicy() *SecurityPolicy {
+	if c == nil {
+		return nil
+	}
+	return c.Policy
+}
+
+// SecurityPolicy is the call policy matrix deciding which call frames the
+// security levels of the accounts involved let through. Rules override the
+// default rule for pairs of caller and callee levels, which lets callers only
+// call callees of at least their own level. The pairs of accounts allowed to
+// call each other do so whatever their levels. Callers may not transfer more
+// than the value limit of their level per call frame, and accounts below
+// CreateLevel may not create contracts. The nil policy is the default rule.
+type SecurityPolicy struct {
+	Rules       []SecurityRule       `json:"rules,omitempty"`       // Rules overriding the default rule
+	Allow       []SecurityPair       `json:"allow,omitempty"`       // Accounts calling each other whatever their levels
+	ValueLimits []SecurityValueLimit `json:"valueLimits,omitempty"` // Most value callers may transfer per call frame
+	CreateLevel uint64               `json:"createLevel,omitempty"` // Lowest level allowed to create contracts
+}
+
+// SecurityRule decides whether accounts at the Caller level may call accounts
+// at the Callee level.
+type SecurityRule struct {
+	Caller uint64 `json:"caller"`
+	Callee uint64 `json:"callee"`
+	Allow  bool   `json:"allow"`
+}
+
+// SecurityPair is a pair of accounts of the call policy allow-list.
+type SecurityPair struct {
+	Caller common.Address `json:"caller"`
+	Callee common.Address `json:"callee"`
+}
+
+// SecurityValueLimit is the most value accounts at a level may transfer per
+// call frame.
+type SecurityValueLimit struct {
+	Level uint64   `json:"level"`
+	Limit *big.Int `json:"limit"`
+}
+
+// LevelAllowed reports whether the policy lets accounts at the caller level
+// call accounts at the callee level.
+func (p *SecurityPolicy) LevelAllowed(caller, callee uint64) bool {
+	if p != nil {
+		for _, rule := range p.Rules {
+			if rule.Caller == caller && rule.Callee == callee {
+				return rule.Allow
+			}
+		}
+	}
+	return caller <= callee
+}
+
+// PairAllowed reports whether the policy allow-lists caller calling callee.
+func (p *SecurityPolicy) PairAllowed(caller, callee common.Address) bool {
+	if p != nil {
+		for _, pair := range p.Allow {
+			if pair.Caller == caller && pair.Callee == callee {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValueLimit returns the most value accounts at level may transfer per call
+// frame, nil if there's no limit.
+func (p *SecurityPolicy) ValueLimit(level uint64) *big.Int {
+	if p != nil {
+		for _, limit := range p.ValueLimits {
+			if limit.Level == level {
+				return limit.Limit
+			}
+		}
+	}
+	return nil
+}
+
+// MinCreateLevel returns the lowest level allowed to create contracts.
+func (p *SecurityPolicy) MinCreateLevel() uint64 {
+	if p == nil {
+		return 0
+	}
+	return p.CreateLevel
+}
+
+// validate checks that the policy only names valid security levels.
+func (p *SecurityPolicy) validate() error {
+	for _, rule := range p.Rules {
+		if rule.Caller > MaxSecurityLevel || rule.Callee > MaxSecurityLevel {
+			return fmt.Errorf("security policy rule for levels %d -> %d exceeds level %d", rule.Caller, rule.Callee, MaxSecurityLevel)
+		}
+	}
+	for _, limit := range p.ValueLimits {
+		if limit.Level > MaxSecurityLevel {
+			return fmt.Errorf("security policy value limit for level %d exceeds level %d", limit.Level, MaxSecurityLevel)
+		}
+		if limit.Limit == nil || limit.Limit.Sign() < 0 {
+			return fmt.Errorf("invalid security policy value limit for level %d", limit.Level)
+		}
+	}
+	if p.CreateLevel > MaxSecurityLevel {
+		return fmt.Errorf("security policy create level %d exceeds level %d", p.CreateLevel, MaxSecurityLevel)
+	}
+	return nil
+}
+
+// DefaultVoucherAddress is where the multi voucher contract is predeployed.
+var DefaultVoucherAddress = common.BytesToAddress([]byte{68})
+
+// VoucherConfig is the configuration of voucher fee payment. Transactions pay
+// their fees with the vouchers of the predeployed contract or of the listed fee
+// currencies, no other contract is ever called to look up balances or rates.
+// Without an oracle, fees are converted at the rates governance set in the
+// voucher contracts; with one, at the rates the oracle's pool averaged over
+// Window.
+type VoucherConfig struct {
+	Currencies []common.Address `json:"currencies,omitempty"` // Fee currency contracts besides the predeployed one, with its storage layout
+	Oracle     *common.Address  `json:"oracle,omitempty"`     // Pool oracle quoting time-weighted rates (nil = governance-set rates)
+	Window     uint32           `json:"window,omitempty"`     // Seconds the time-weighted rates are averaged over
+}
+
+// IsFeeCurrency reports whether transactions may pay their fees with the
+// vouchers of the contract at addr.
+func (c *VoucherConfig) IsFeeCurrency(addr common.Address) bool {
+	if addr == DefaultVoucherAddress {
+		return true
+	}
+	if c == nil {
+		return false
+	}
+	for _, currency := range c.Currencies {
+		if currency == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxInterestPrecision is the most decimals interest rates may be given with.
+const MaxInterestPrecision = 36
+
+// DefaultInterestClaimAddress is where accrued interest is claimed at if the
+// chain config doesn't say otherwise.
+var DefaultInterestClaimAddress = common.BytesToAddress([]byte{69})
+
+// InterestConfig is the interest model of account balances. Interest compounds
+// once every Period blocks, at the rate of the schedule in effect at the last
+// block of the period. Rates and accrued interest are fixed point numbers with
+// Precision decimals. Accounts move their accrued interest into their balance
+// by calling the claim address.
+type InterestConfig struct {
+	Schedule  []InterestRate  `json:"schedule"`          // Rates ordered by activation block
+	Period    uint64          `json:"period"`            // Number of blocks per compounding period
+	Precision uint8           `json:"precision"`         // Decimals of the rates and of accrued interest
+	Address   *common.Address `json:"address,omitempty"` // Address interest is claimed at (nil = DefaultInterestClaimAddress)
+}
+
+// ClaimAddress returns the address accounts claim their accrued interest at.
+func (c *InterestConfig) ClaimAddress() common.Address {
+	if c.Address != nil {
+		return *c.Address
+	}
+	return DefaultInterestClaimAddress
+}
+
+// InterestRate is a rate of the interest schedule.
+type InterestRate struct {
+	Block uint64 `json:"block"` // First block the rate is in effect at
+	Rate  uint64 `json:"rate"`  // Interest per period, in units of 10^-Precision
+}
+
+// Scale returns the fixed point scale of rates and accrued interest, i.e. the
+// representation of one.
+func (c *InterestConfig) Scale() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.Precision)), nil)
+}
+
+// RateAt returns the rate in effect at the given block, and the block the next
+// rate of the schedule comes into effect at, math.MaxUint64 if there's none.
+// No interest accrues before the first rate of the schedule.
+func (c *InterestConfig) RateAt(number uint64) (rate uint64, next uint64) {
+	next = math.MaxUint64
+	for i := len(c.Schedule) - 1; i >= 0; i-- {
+		if c.Schedule[i].Block <= number {
+			return c.Schedule[i].Rate, next
+		}
+		next = c.Schedule[i].Block
+	}
+	return 0, next
+}
+
+// validate checks that the interest model is well formed.
+func (c *InterestConfig) validate() error {
+	if c.Period == 0 {
+		return errors.New("interest compounding period must be positive")
+	}
+	if c.Precision > MaxInterestPrecision {
+		return fmt.Errorf("interest precision %d exceeds %d decimals", c.Precision, MaxInterestPrecision)
+	}
+	for i := 1; i < len(c.Schedule); i++ {
+		if c.Schedule[i-1].Block >= c.Schedule[i].Block {
+			return fmt.Errorf("unsupported interest schedule ordering: rate at block %d, but next at block %d",
+				c.Schedule[i-1].Block, c.Schedule[i].Block)
+		}
+	}
+	return nil
+}
+
+// DefaultCrossShardAddress is where cross-shard transfers are locked and paid
+// out if the chain config doesn't say otherwise.
+var DefaultCrossShardAddress = common.BytesToAddress([]byte{71})
+
+// ShardingConfig splits the accounts among shards by address range. The value
+// of transactions to accounts of another shard is locked at the cross-shard
+// address of the source shard, and paid out in the destination shard by payout
+// transactions, each recorded in the storage of the cross-shard address there.
+type ShardingConfig struct {
+	Shard   uint64          `json:"shard"`             // Shard of the chain
+	Ranges  []ShardRange    `json:"ranges"`            // Accounts of the shards, accounts out of every range are local
+	Address *common.Address `json:"address,omitempty"` // Address of the locked value and payout records (nil = DefaultCrossShardAddress)
+}
+
+// ShardRange assigns the accounts of an address range to a shard.
+type ShardRange struct {
+	Shard uint64         `json:"shard"`
+	From  common.Address `json:"from"` // First address of the range
+	To    common.Address `json:"to"`   // Last address of the range, inclusive
+}
+
+// CrossShard returns the address cross-shard transfers are locked and paid out
+// at.
+func (c *ShardingConfig) CrossShard() common.Address {
+	if c != nil && c.Address != nil {
+		return *c.Address
+	}
+	return DefaultCrossShardAddress
+}
+
+// ShardOf returns the shard of an account.
+func (c *ShardingConfig) ShardOf(addr common.Address) uint64 {
+	for _, r := range c.Ranges {
+		if bytes.Compare(r.From.Bytes(), addr.Bytes()) <= 0 && bytes.Compare(addr.Bytes(), r.To.Bytes()) <= 0 {
+			return r.Shard
+		}
+	}
+	return c.Shard
+}
+
+// IsForeign reports whether an account belongs to another shard than the chain.
+func (c *ShardingConfig) IsForeign(addr common.Address) bool {
+	return c != nil && c.ShardOf(addr) != c.Shard
+}
+
+// validate checks that the address ranges are well formed and don't overlap.
+func (c *ShardingConfig) validate() error {
+	for i, r := range c.Ranges {
+		if bytes.Compare(r.From.Bytes(), r.To.Bytes()) > 0 {
+			return fmt.Errorf("empty address range %x-%x of shard %d", r.From, r.To, r.Shard)
+		}
+		for _, other := range c.Ranges[:i] {
+			if bytes.Compare(r.From.Bytes(), other.To.Bytes()) <= 0 && bytes.Compare(other.From.Bytes(), r.To.Bytes()) <= 0 {
+				return fmt.Errorf("address ranges of shards %d and %d overlap", other.Shard, r.Shard)
+			}
+		}
+	}
+	return nil
+}
+
 // Description returns a human-readable description of ChainConfig.
 func (c *ChainConfig) Description() string {
 	var banner string
@@ -549,6 +913,24 @@ func (c *ChainConfig) IsGrayGlacier(num *big.Int) bool {
 	return isBlockForked(c.GrayGlacierBlock, num)
 }
 
+// IsSecurityLevel returns whether num is either equal to the security level fork
+// block or greater.
+func (c *ChainConfig) IsSecurityLevel(num *big.Int) bool {
+	return isBlockForked(c.SecurityLevelBlock, num)
+}
+
+// IsSecurityPolicyGas returns whether num is either equal to the security
+// policy gas fork block or greater.
+func (c *ChainConfig) IsSecurityPolicyGas(num *big.Int) bool {
+	return isBlockForked(c.SecurityPolicyGasBlock, num)
+}
+
+// IsPowEconomics returns whether num is either equal to the PoW economics fork
+// block or greater.
+func (c *ChainConfig) IsPowEconomics(num *big.Int) bool {
+	return isBlockForked(c.PowEconomicsBlock, num)
+}
+
 // IsTerminalPoWBlock returns whether the given block is the last block of PoW stage.
 func (c *ChainConfig) IsTerminalPoWBlock(parentTotalDiff *big.Int, totalDiff *big.Int) bool {
 	if c.TerminalTotalDifficulty == nil {
@@ -667,6 +1049,22 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	// Interest rates are scheduled by block like forks
+	if c.Interest != nil {
+		if err := c.Interest.validate(); err != nil {
+			return err
+		}
+	}
+	if policy := c.Security.CallPolicy(); policy != nil {
+		if err := policy.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Sharding != nil {
+		if err := c.Sharding.validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -726,6 +1124,15 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkBlockIncompatible(c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock, headNumber) {
 		return newBlockCompatError("Merge netsplit fork block", c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock)
 	}
+	if isForkBlockIncompatible(c.SecurityLevelBlock, newcfg.SecurityLevelBlock, headNumber) {
+		return newBlockCompatError("Security level fork block", c.SecurityLevelBlock, newcfg.SecurityLevelBlock)
+	}
+	if isForkBlockIncompatible(c.SecurityPolicyGasBlock, newcfg.SecurityPolicyGasBlock, headNumber) {
+		return newBlockCompatError("Security policy gas fork block", c.SecurityPolicyGasBlock, newcfg.SecurityPolicyGasBlock)
+	}
+	if isForkBlockIncompatible(c.PowEconomicsBlock, newcfg.PowEconomicsBlock, headNumber) {
+		return newBlockCompatError("PoW economics fork block", c.PowEconomicsBlock, newcfg.PowEconomicsBlock)
+	}
 	if isForkTimestampIncompatible(c.ShanghaiTime, newcfg.ShanghaiTime, headTimestamp) {
 		return newTimestampCompatError("Shanghai fork timestamp", c.ShanghaiTime, newcfg.ShanghaiTime)
 	}
@@ -89,10 +89,12 @@ const (
 	SelfdestructRefundGas uint64 = 24000 // Refunded following a selfdestruct operation.
 	MemoryGas             uint64 = 3     // Times the address of the (highest referenced byte in memory + 1). NOTE: referencing happens on read, write and in instructions such as RETURN and CALL.
 
-	TxDataNonZeroGasFrontier  uint64 = 68   // Per byte of data attached to a transaction that is not equal to zero. NOTE: Not payable on data of calls between transactions.
-	TxDataNonZeroGasEIP2028   uint64 = 16   // Per byte of non zero data attached to a transaction after EIP 2028 (part in Istanbul)
-	TxAccessListAddressGas    uint64 = 2400 // Per address specified in EIP 2930 access list
-	TxAccessListStorageKeyGas uint64 = 1900 // Per storage key specified in EIP 2930 access list
+	TxDataNonZeroGasFrontier  uint64 = 68    // Per byte of data attached to a transaction that is not equal to zero. NOTE: Not payable on data of calls between transactions.
+	TxDataNonZeroGasEIP2028   uint64 = 16    // Per byte of non zero data attached to a transaction after EIP 2028 (part in Istanbul)
+	TxAccessListAddressGas    uint64 = 2400  // Per address specified in EIP 2930 access list
+	TxAccessListStorageKeyGas uint64 = 1900  // Per storage key specified in EIP 2930 access list
+	TxVoucherSettlementGas    uint64 = 30000 // Per transaction paying its fee with a voucher, reserved for settling the fee
+	VoucherLookupGas          uint64 = 50000 // Allowance of the voucher balance and rate lookups, which are free of charge
 
 	// These have been changed during the course of the chain
 	CallGasFrontier              uint64 = 40  // Once per CALL operation & message call transaction.
@@ -141,6 +143,9 @@ const (
 	IdentityBaseGas     uint64 = 15   // Base price for a data copy operation
 	IdentityPerWordGas  uint64 = 3    // Per-work price for a data copy operation
 
+	InterestClaimGas      uint64 = 9000  // Gas needed to move accrued interest into the balance
+	SecurityGovernanceGas uint64 = 20000 // Gas needed per call of the security level governance module
+
 	Bn256AddGasByzantium             uint64 = 500    // Byzantium gas needed for an elliptic curve addition
 	Bn256AddGasIstanbul              uint64 = 150    // Gas needed for an elliptic curve addition
 	Bn256ScalarMulGasByzantium       uint64 = 40000  // Byzantium gas needed for an elliptic curve scalar multiplication
@@ -190,17 +195,55 @@ var (
 	SystemAddress common.Address = common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffffe")
 
 	// newly added params here
-	ModHeight         uint64 = 100
-	InitialDifficulty        = big.NewInt(100)
-	TargetPowRatio           = common.NewRational(3, 10)
-	MinPowGas         uint64 = 1000000
-	InitialGas        uint64 = 10000000
-	MaxPowGas         uint64 = 100000000
-	MinPrice                 = big.NewInt(100)
-	MaxPrice                 = big.NewInt(10000)
-	Alpha                    = common.NewRational(1, 2)
-	Fmin                     = common.NewRational(4, 5)
-	Fmax                     = common.NewRational(6, 5)
-	Kp                       = common.NewRational(1, 10)
-	Ki                       = common.NewRational(1, 100)
+	ModHeight uint64 = 100
+
+	// PowPlanAddress is where the plans of the PoW economics parameters are
+	// recorded.
+	PowPlanAddress = common.BytesToAddress([]byte{72})
+)
+
+// Defaults of the PoW economics parameters, in effect until plans replace them.
+const (
+	InitialPowDifficulty        = 100
+	MinPowGas            uint64 = 1000000
+	InitialGas           uint64 = 10000000
+	MaxPowGas            uint64 = 100000000
+	MinPowPrice                 = 100
+	MaxPowPrice                 = 10000
 )
+
+// PowParams are the parameters of the PoW economics, deciding the PoW difficulty,
+// price and gas of blocks.
+type PowParams struct {
+	Difficulty     *big.Int         // Difficulty of the first block with PoW economics
+	TargetPowRatio *common.Rational // Ratio of PoW transactions the price steers to
+	MinPowGas      uint64           // Least gas of PoW transactions
+	MaxPowGas      uint64           // Most gas of PoW transactions
+	InitialGas     uint64           // Gas of PoW transactions of the first block with PoW economics
+	MinPrice       *big.Int         // Lowest price of PoW transactions
+	MaxPrice       *big.Int         // Highest price of PoW transactions
+	Alpha          *common.Rational // Smoothing factor of the moving averages
+	Fmin           *common.Rational // Factor of the difficulty below target
+	Fmax           *common.Rational // Factor of the difficulty above target
+	Kp             *common.Rational // Proportional gain of the price
+	Ki             *common.Rational // Integral gain of the price
+}
+
+// DefaultPowParams returns the PoW economics parameters in effect until plans
+// replace them.
+func DefaultPowParams() *PowParams {
+	return &PowParams{
+		Difficulty:     big.NewInt(InitialPowDifficulty),
+		TargetPowRatio: common.NewRational(3, 10),
+		MinPowGas:      MinPowGas,
+		MaxPowGas:      MaxPowGas,
+		InitialGas:     InitialGas,
+		MinPrice:       big.NewInt(MinPowPrice),
+		MaxPrice:       big.NewInt(MaxPowPrice),
+		Alpha:          common.NewRational(1, 2),
+		Fmin:           common.NewRational(4, 5),
+		Fmax:           common.NewRational(6, 5),
+		Kp:             common.NewRational(1, 10),
+		Ki:             common.NewRational(1, 100),
+	}
+}
@@ -19,16 +19,24 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Executor_CommitBlock_FullMethodName = "/pb.Executor/CommitBlock"
-	Executor_VerifyTx_FullMethodName    = "/pb.Executor/VerifyTx"
+	Executor_CommitBlock_FullMethodName             = "/pb.Executor/CommitBlock"
+	Executor_VerifyTx_FullMethodName                = "/pb.Executor/VerifyTx"
+	Executor_ExecutionReports_FullMethodName        = "/pb.Executor/ExecutionReports"
+	Executor_GetHeader_FullMethodName               = "/pb.Executor/GetHeader"
+	Executor_GetCrossShardReceipt_FullMethodName    = "/pb.Executor/GetCrossShardReceipt"
+	Executor_SubmitCrossShardReceipt_FullMethodName = "/pb.Executor/SubmitCrossShardReceipt"
 )
 
 // ExecutorClient is the client API for Executor service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ExecutorClient interface {
-	CommitBlock(ctx context.Context, in *ExecBlock, opts ...grpc.CallOption) (*Empty, error)
+	CommitBlock(ctx context.Context, in *ExecBlock, opts ...grpc.CallOption) (*CommitResult, error)
 	VerifyTx(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*Result, error)
+	ExecutionReports(ctx context.Context, in *ExecReportsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecResult], error)
+	GetHeader(ctx context.Context, in *HeaderRequest, opts ...grpc.CallOption) (*HeaderReply, error)
+	GetCrossShardReceipt(ctx context.Context, in *CrossShardReceiptRequest, opts ...grpc.CallOption) (*CrossShardReceipt, error)
+	SubmitCrossShardReceipt(ctx context.Context, in *CrossShardReceipt, opts ...grpc.CallOption) (*CrossShardPayout, error)
 }
 
 type executorClient struct {
@@ -39,9 +47,9 @@ func NewExecutorClient(cc grpc.ClientConnInterface) ExecutorClient {
 	return &executorClient{cc}
 }
 
-func (c *executorClient) CommitBlock(ctx context.Context, in *ExecBlock, opts ...grpc.CallOption) (*Empty, error) {
+func (c *executorClient) CommitBlock(ctx context.Context, in *ExecBlock, opts ...grpc.CallOption) (*CommitResult, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Empty)
+	out := new(CommitResult)
 	err := c.cc.Invoke(ctx, Executor_CommitBlock_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -59,12 +67,65 @@ func (c *executorClient) VerifyTx(ctx context.Context, in *Transaction, opts ...
 	return out, nil
 }
 
+func (c *executorClient) ExecutionReports(ctx context.Context, in *ExecReportsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Executor_ServiceDesc.Streams[0], Executor_ExecutionReports_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecReportsRequest, ExecResult]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Executor_ExecutionReportsClient = grpc.ServerStreamingClient[ExecResult]
+
+func (c *executorClient) GetHeader(ctx context.Context, in *HeaderRequest, opts ...grpc.CallOption) (*HeaderReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeaderReply)
+	err := c.cc.Invoke(ctx, Executor_GetHeader_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executorClient) GetCrossShardReceipt(ctx context.Context, in *CrossShardReceiptRequest, opts ...grpc.CallOption) (*CrossShardReceipt, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CrossShardReceipt)
+	err := c.cc.Invoke(ctx, Executor_GetCrossShardReceipt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executorClient) SubmitCrossShardReceipt(ctx context.Context, in *CrossShardReceipt, opts ...grpc.CallOption) (*CrossShardPayout, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CrossShardPayout)
+	err := c.cc.Invoke(ctx, Executor_SubmitCrossShardReceipt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ExecutorServer is the server API for Executor service.
 // All implementations must embed UnimplementedExecutorServer
 // for forward compatibility.
 type ExecutorServer interface {
-	CommitBlock(context.Context, *ExecBlock) (*Empty, error)
+	CommitBlock(context.Context, *ExecBlock) (*CommitResult, error)
 	VerifyTx(context.Context, *Transaction) (*Result, error)
+	ExecutionReports(*ExecReportsRequest, grpc.ServerStreamingServer[ExecResult]) error
+	GetHeader(context.Context, *HeaderRequest) (*HeaderReply, error)
+	GetCrossShardReceipt(context.Context, *CrossShardReceiptRequest) (*CrossShardReceipt, error)
+	SubmitCrossShardReceipt(context.Context, *CrossShardReceipt) (*CrossShardPayout, error)
 	mustEmbedUnimplementedExecutorServer()
 }
 
@@ -75,12 +136,24 @@ type ExecutorServer interface {
 // pointer dereference when methods are called.
 type UnimplementedExecutorServer struct{}
 
-func (UnimplementedExecutorServer) CommitBlock(context.Context, *ExecBlock) (*Empty, error) {
+func (UnimplementedExecutorServer) CommitBlock(context.Context, *ExecBlock) (*CommitResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CommitBlock not implemented")
 }
 func (UnimplementedExecutorServer) VerifyTx(context.Context, *Transaction) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method VerifyTx not implemented")
 }
+func (UnimplementedExecutorServer) ExecutionReports(*ExecReportsRequest, grpc.ServerStreamingServer[ExecResult]) error {
+	return status.Errorf(codes.Unimplemented, "method ExecutionReports not implemented")
+}
+func (UnimplementedExecutorServer) GetHeader(context.Context, *HeaderRequest) (*HeaderReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeader not implemented")
+}
+func (UnimplementedExecutorServer) GetCrossShardReceipt(context.Context, *CrossShardReceiptRequest) (*CrossShardReceipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrossShardReceipt not implemented")
+}
+func (UnimplementedExecutorServer) SubmitCrossShardReceipt(context.Context, *CrossShardReceipt) (*CrossShardPayout, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitCrossShardReceipt not implemented")
+}
 func (UnimplementedExecutorServer) mustEmbedUnimplementedExecutorServer() {}
 func (UnimplementedExecutorServer) testEmbeddedByValue()                  {}
 
@@ -138,6 +211,71 @@ func _Executor_VerifyTx_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Executor_ExecutionReports_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecReportsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecutorServer).ExecutionReports(m, &grpc.GenericServerStream[ExecReportsRequest, ExecResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Executor_ExecutionReportsServer = grpc.ServerStreamingServer[ExecResult]
+
+func _Executor_GetHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).GetHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Executor_GetHeader_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).GetHeader(ctx, req.(*HeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Executor_GetCrossShardReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CrossShardReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).GetCrossShardReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Executor_GetCrossShardReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).GetCrossShardReceipt(ctx, req.(*CrossShardReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Executor_SubmitCrossShardReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CrossShardReceipt)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).SubmitCrossShardReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Executor_SubmitCrossShardReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).SubmitCrossShardReceipt(ctx, req.(*CrossShardReceipt))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Executor_ServiceDesc is the grpc.ServiceDesc for Executor service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,7 +291,25 @@ var Executor_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "VerifyTx",
 			Handler:    _Executor_VerifyTx_Handler,
 		},
+		{
+			MethodName: "GetHeader",
+			Handler:    _Executor_GetHeader_Handler,
+		},
+		{
+			MethodName: "GetCrossShardReceipt",
+			Handler:    _Executor_GetCrossShardReceipt_Handler,
+		},
+		{
+			MethodName: "SubmitCrossShardReceipt",
+			Handler:    _Executor_SubmitCrossShardReceipt_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecutionReports",
+			Handler:       _Executor_ExecutionReports_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "executor.proto",
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/params"
@@ -39,6 +40,7 @@ var (
 
 // Implements the interface of miner.Backend
 type TestBackend struct {
+	db      ethdb.Database
 	bc      *core.BlockChain
 	txpool  *txpool.TxPool
 	genesis *core.Genesis
@@ -48,6 +50,7 @@ type TestBackend struct {
 func (m *TestBackend) NetworkId() uint64            { return 1 }
 func (m *TestBackend) BlockChain() *core.BlockChain { return m.bc }
 func (m *TestBackend) TxPool() *txpool.TxPool       { return m.txpool }
+func (m *TestBackend) ChainDb() ethdb.Database      { return m.db }
 func (m *TestBackend) AccountManager() *accounts.Manager {
 	return accounts.NewManager(&accounts.Config{})
 }
@@ -107,10 +110,11 @@ func (m *TestBackend) CreateMiner() *miner.Miner {
 }
 
 func newTestBackend() *TestBackend {
-	bc := newBlockChain()
+	db, bc := newBlockChain()
 	txpool := newTxPool(bc)
 	genesis := genesisBlock()
 	return &TestBackend{
+		db:      db,
 		bc:      bc,
 		txpool:  txpool,
 		genesis: genesis,
@@ -133,15 +137,15 @@ func genesisBlock() *core.Genesis {
 		BaseFee:    big.NewInt(params.InitialBaseFee),
 		Difficulty: big.NewInt(1),
 		Alloc: map[common.Address]core.GenesisAccount{
-			bankAddress:     {Balance: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(9))},
-			userAddress:     {Balance: big.NewInt(1000000)},
-			contractAddress: {Code: common.FromHex(deployedBytecode)},
+			bankAddress: {Balance: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(9))},
+			userAddress: {Balance: big.NewInt(1000000)},
 		},
+		Voucher: &core.GenesisVoucher{},
 	}
 }
 
 // Create new blockchain to test
-func newBlockChain() *core.BlockChain {
+func newBlockChain() (ethdb.Database, *core.BlockChain) {
 	database := rawdb.NewMemoryDatabase()
 	triedb := trie.NewDatabase(database, nil)
 	genesis := genesisBlock()
@@ -155,7 +159,7 @@ func newBlockChain() *core.BlockChain {
 	if err != nil {
 		fmt.Printf("can't create new chain %v\n", err)
 	}
-	return bc
+	return database, bc
 }
 
 // Create new txpool basing blockchain
@@ -174,6 +178,9 @@ func newEVM(bc *core.BlockChain) *vm.EVM {
 	if err != nil {
 		fmt.Printf("failed to get stateDB: %v\n", err)
 	}
+	// Warm the accounts touched by the voucher calls
+	rules := bc.Config().Rules(header.Number, true, header.Time)
+	stateDB.Prepare(rules, bankAddress, header.Coinbase, &contractAddress, vm.ActivePrecompiles(rules), nil)
 	// Create EVM
 	blockContext := core.NewEVMBlockContext(header, bc, nil)
 	evm := vm.NewEVM(blockContext, vm.TxContext{}, stateDB, bc.Config(), vm.Config{})
@@ -12,22 +12,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/voucher"
-	"github.com/holiman/uint256"
 	// "google.golang.org/grpc"
 )
 
-func TestDataStruct(t *testing.T) {
-	prefix := []byte{0x0A, 0x0D, 0x03}
-	data := append(prefix, bankAddress.Bytes()...)
-	data = append(data, userAddress.Bytes()...)
-	fmt.Printf("hex.EncodeToString(data): %v\n", hex.EncodeToString(data))
-	// Data's legitimacy check
-	if len(data) == 43 && data[0] == 0x0A && data[1] == 0x0D && data[2] == 0x03 {
-	} else {
-		fmt.Println("Tx don't use non-native tokens")
-	}
-}
-
 // Test voucher contract in EVM
 func TestVoucherWithEVM(t *testing.T) {
 	backend := newTestBackend()
@@ -38,34 +25,33 @@ func TestVoucherWithEVM(t *testing.T) {
 	// Create EVM instance to call contract
 	evm := newEVM(backend.bc)
 	var (
-		err            error
-		flag           bool
+		contract       = voucher.NewEVMBackend(evm, bankAddress, 1_000_000)
 		voucherName    = "BitCoin"
-		balance        = new(big.Int)
 		conversionRate = big.NewInt(2)
 	)
 
-	// Create new voucher
-	_, err = voucher.CreateVoucher.Execute(evm, nil, &bankAddress, uint256.NewInt(0), voucherName, conversionRate)
+	currency, err := voucher.NewMultiVoucher(voucher.VoucherAddress, contract)
 	if err != nil {
-		t.Fail()
+		t.Fatal(err)
+	}
+	// Create new voucher
+	if _, err := currency.CreateVoucher(contract.TransactOpts(nil), voucherName, conversionRate); err != nil {
+		t.Fatalf("failed to create voucher: %v", err)
 	}
 	// Bank buy voucher, value=1000 convert to 2000 BitCoin voucher
-	_, err = voucher.Buy.Execute(evm, nil, &bankAddress, uint256.NewInt(1000), voucherName)
-	if err != nil {
-		t.Fail()
+	if _, err := currency.Buy(contract.TransactOpts(big.NewInt(1000)), voucherName); err != nil {
+		t.Fatalf("failed to buy voucher: %v", err)
 	}
 	// Bank use voucher
-	_, err = voucher.Use.Execute(evm, &flag, &bankAddress, uint256.NewInt(0), voucherName, big.NewInt(1000))
-	if err != nil {
-		t.Fail()
+	if _, err := currency.Use(contract.TransactOpts(nil), voucherName, big.NewInt(1000)); err != nil {
+		t.Fatalf("failed to use voucher: %v", err)
 	}
 	// Look up balance of user
-	_, err = voucher.BalanceOf.Execute(evm, &balance, &bankAddress, uint256.NewInt(0), voucherName, bankAddress)
-	fmt.Printf("Account balance: %v\n", balance)
+	balance, err := currency.BalanceOf(contract.CallOpts(), voucherName, bankAddress)
 	if err != nil {
-		t.Fail()
+		t.Fatalf("failed to retrieve balance: %v", err)
 	}
+	fmt.Printf("Account balance: %v\n", balance)
 }
 
 func NewTx(bc *core.BlockChain, Nonce int, to *common.Address, value *big.Int, data []byte) *types.Transaction {
@@ -111,13 +97,18 @@ func TestVoucherWithTx(t *testing.T) {
 	}
 	tx2 := NewTx(backend.bc, 1, VoucherAddress, valueAmount, input)
 
-	// Construct type of 03 data: identifier + contractAddress + TokenName
-	input2 := []byte{0x0A, 0x0D, 0x03}
-
-	var result [20]byte
-	copy(result[:], []byte(tokenName))
-	input2 = append(input2, result[:]...)
-	tx3 := NewTx(backend.bc, 2, &userAddress, big.NewInt(0), input2)
+	// Pay the gas of a transfer with the voucher
+	tx3 := types.MustSignNewTx(bankKey, types.LatestSigner(backend.bc.Config()), &types.VoucherTx{
+		ChainID:     backend.bc.Config().ChainID,
+		Nonce:       2,
+		To:          &userAddress,
+		Value:       big.NewInt(0),
+		Gas:         63696,
+		GasTipCap:   big.NewInt(0),
+		GasFeeCap:   big.NewInt(params.InitialBaseFee),
+		FeeCurrency: *VoucherAddress,
+		Voucher:     tokenName,
+	})
 
 	backend.AddTx(tx1)
 	backend.AddTx(tx2)
@@ -0,0 +1,152 @@
+package voucher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/uint256"
+)
+
+var errNoFilters = errors.New("logs can't be filtered on the evm")
+
+// EVMBackend runs the calls of the generated bindings directly on an EVM, with
+// caller as the sender and at most gas per call. Transactions are executed as
+// plain calls on the same EVM, they are never signed nor sent anywhere.
+type EVMBackend struct {
+	evm    *vm.EVM
+	caller common.Address
+	gas    uint64
+	used   uint64
+}
+
+// NewEVMBackend creates a backend running the calls of caller on evm.
+func NewEVMBackend(evm *vm.EVM, caller common.Address, gas uint64) *EVMBackend {
+	return &EVMBackend{evm: evm, caller: caller, gas: gas}
+}
+
+// GasUsed returns the gas used by all the calls run on the backend, also by the
+// failed ones.
+func (b *EVMBackend) GasUsed() uint64 {
+	return b.used
+}
+
+// CallOpts returns the options of the calls of the bindings.
+func (b *EVMBackend) CallOpts() *bind.CallOpts {
+	return &bind.CallOpts{From: b.caller}
+}
+
+// TransactOpts returns the options of the transactions of the bindings, sending
+// value along.
+func (b *EVMBackend) TransactOpts(value *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:     b.caller,
+		Nonce:    new(big.Int),
+		Value:    value,
+		GasPrice: new(big.Int),
+		GasLimit: b.gas,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}
+}
+
+// run executes a call of the contract at to, as a static call if value is nil.
+func (b *EVMBackend) run(to common.Address, input []byte, value *big.Int) ([]byte, error) {
+	var (
+		output    []byte
+		gasRemain uint64
+		err       error
+	)
+	if value == nil {
+		output, gasRemain, err = b.evm.StaticCall(vm.AccountRef(b.caller), to, input, b.gas)
+	} else {
+		amount, overflow := uint256.FromBig(value)
+		if overflow {
+			return nil, fmt.Errorf("value %v exceeds 256 bits", value)
+		}
+		output, gasRemain, err = b.evm.Call(vm.AccountRef(b.caller), to, input, b.gas, amount)
+	}
+	b.used += b.gas - gasRemain
+	if err != nil {
+		log.Debug("Voucher contract call failed", "contract", to, "err", err, "output", hexutil.Encode(output))
+		return nil, fmt.Errorf("call of %v: %w", to, err)
+	}
+	return output, nil
+}
+
+// CodeAt implements bind.ContractCaller, returning the code on the EVM state.
+func (b *EVMBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return b.evm.StateDB.GetCode(contract), nil
+}
+
+// CallContract implements bind.ContractCaller, running a static call.
+func (b *EVMBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To == nil {
+		return nil, errors.New("contract creation not supported")
+	}
+	return b.run(*call.To, call.Data, nil)
+}
+
+// SendTransaction implements bind.ContractTransactor, running the transaction as
+// a call of the caller.
+func (b *EVMBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if tx.To() == nil {
+		return errors.New("contract creation not supported")
+	}
+	_, err := b.run(*tx.To(), tx.Data(), tx.Value())
+	return err
+}
+
+// PendingCodeAt implements bind.ContractTransactor.
+func (b *EVMBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return b.CodeAt(ctx, account, nil)
+}
+
+// HeaderByNumber implements bind.ContractTransactor, the options of the backend
+// never need a header.
+func (b *EVMBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: new(big.Int).Set(b.evm.Context.BlockNumber)}, nil
+}
+
+// PendingNonceAt implements bind.ContractTransactor, calls need no nonce.
+func (b *EVMBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 0, nil
+}
+
+// SuggestGasPrice implements bind.ContractTransactor, calls are free.
+func (b *EVMBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return new(big.Int), nil
+}
+
+// SuggestGasTipCap implements bind.ContractTransactor, calls are free.
+func (b *EVMBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return new(big.Int), nil
+}
+
+// EstimateGas implements bind.ContractTransactor, calls get the gas of the
+// backend.
+func (b *EVMBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return b.gas, nil
+}
+
+// FilterLogs implements bind.ContractFilterer, logs stay in the EVM state.
+func (b *EVMBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return nil, errNoFilters
+}
+
+// SubscribeFilterLogs implements bind.ContractFilterer, logs stay in the EVM
+// state.
+func (b *EVMBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errNoFilters
+}
+
+var _ bind.ContractBackend = (*EVMBackend)(nil)
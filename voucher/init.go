@@ -1,208 +1,36 @@
 package voucher
 
-// Used to solve some problems caused by abi coding
 import (
+	_ "embed"
 	"fmt"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
 )
 
+// The bindings are generated from the abi and creation code of the artifact,
+// extracted to abi/multivoucher.abi and abi/multivoucher.bin.
+//go:generate go run ../cmd/abigen --abi abi/multivoucher.abi --bin abi/multivoucher.bin --pkg voucher --type MultiVoucher --out multivoucher.go
+//go:generate go run ../cmd/abigen --abi abi/rateoracle.abi --pkg voucher --type RateOracle --out rateoracle.go
+
+// Hardhat artifact of the multi voucher contract
+//
+//go:embed abi/mutivoucher.json
+var multiVoucherArtifact []byte
+
 var (
-	// Method declaration
-	BalanceOf     *BoundMethod
-	Buy           *BoundMethod
-	Use           *BoundMethod
-	CreateVoucher *BoundMethod
 	// Fixed address
-	VoucherAddress = common.BytesToAddress([]byte{68})
-	voucherABIjson = `[
-    {
-      "anonymous": false,
-      "inputs": [
-        {
-          "indexed": false,
-          "internalType": "string",
-          "name": "name",
-          "type": "string"
-        },
-        {
-          "indexed": false,
-          "internalType": "uint256",
-          "name": "conversionRate",
-          "type": "uint256"
-        }
-      ],
-      "name": "VoucherCreated",
-      "type": "event"
-    },
-    {
-      "anonymous": false,
-      "inputs": [
-        {
-          "indexed": false,
-          "internalType": "address",
-          "name": "buyer",
-          "type": "address"
-        },
-        {
-          "indexed": false,
-          "internalType": "string",
-          "name": "name",
-          "type": "string"
-        },
-        {
-          "indexed": false,
-          "internalType": "uint256",
-          "name": "amount",
-          "type": "uint256"
-        }
-      ],
-      "name": "VoucherPurchased",
-      "type": "event"
-    },
-    {
-      "anonymous": false,
-      "inputs": [
-        {
-          "indexed": false,
-          "internalType": "address",
-          "name": "user",
-          "type": "address"
-        },
-        {
-          "indexed": false,
-          "internalType": "string",
-          "name": "name",
-          "type": "string"
-        },
-        {
-          "indexed": false,
-          "internalType": "uint256",
-          "name": "amount",
-          "type": "uint256"
-        }
-      ],
-      "name": "VoucherUsed",
-      "type": "event"
-    },
-    {
-      "inputs": [
-        {
-          "internalType": "string",
-          "name": "name",
-          "type": "string"
-        },
-        {
-          "internalType": "address",
-          "name": "user",
-          "type": "address"
-        }
-      ],
-      "name": "balanceOf",
-      "outputs": [
-        {
-          "internalType": "uint256",
-          "name": "",
-          "type": "uint256"
-        }
-      ],
-      "stateMutability": "view",
-      "type": "function"
-    },
-    {
-      "inputs": [
-        {
-          "internalType": "string",
-          "name": "name",
-          "type": "string"
-        }
-      ],
-      "name": "buy",
-      "outputs": [],
-      "stateMutability": "payable",
-      "type": "function"
-    },
-    {
-      "inputs": [
-        {
-          "internalType": "string",
-          "name": "name",
-          "type": "string"
-        },
-        {
-          "internalType": "uint256",
-          "name": "conversionRate",
-          "type": "uint256"
-        }
-      ],
-      "name": "createVoucher",
-      "outputs": [],
-      "stateMutability": "nonpayable",
-      "type": "function"
-    },
-    {
-      "inputs": [],
-      "name": "decimals",
-      "outputs": [
-        {
-          "internalType": "uint256",
-          "name": "",
-          "type": "uint256"
-        }
-      ],
-      "stateMutability": "view",
-      "type": "function"
-    },
-    {
-      "inputs": [
-        {
-          "internalType": "string",
-          "name": "name",
-          "type": "string"
-        }
-      ],
-      "name": "getVoucherInfo",
-      "outputs": [
-        {
-          "internalType": "uint256",
-          "name": "conversionRate",
-          "type": "uint256"
-        }
-      ],
-      "stateMutability": "view",
-      "type": "function"
-    },
-    {
-      "inputs": [
-        {
-          "internalType": "string",
-          "name": "name",
-          "type": "string"
-        },
-        {
-          "internalType": "uint256",
-          "name": "amount",
-          "type": "uint256"
-        }
-      ],
-      "name": "use",
-      "outputs": [],
-      "stateMutability": "nonpayable",
-      "type": "function"
-    }
-  ]`
+	VoucherAddress = params.DefaultVoucherAddress
+
+	// Abi and deployed code of the multi voucher contract
+	VoucherABI, VoucherCode = mustParseArtifact(multiVoucherArtifact)
 )
 
-func init() {
-	gas := uint64(100000000)
-	voucherABI, err := abi.JSON(strings.NewReader(voucherABIjson))
+func mustParseArtifact(artifact []byte) (*abi.ABI, []byte) {
+	parsed, _, deployed, err := abi.ParseHardhatContract(artifact)
 	if err != nil {
-		fmt.Printf("Load voucher abi err: %v\n", err)
+		panic(fmt.Sprintf("invalid multi voucher artifact: %v", err))
 	}
-	BalanceOf = NewBoundMethod(&VoucherAddress, &voucherABI, "balanceOf", true, gas)
-	Use = NewBoundMethod(&VoucherAddress, &voucherABI, "use", false, gas)
-	Buy = NewBoundMethod(&VoucherAddress, &voucherABI, "buy", false, gas)
-	CreateVoucher = NewBoundMethod(&VoucherAddress, &voucherABI, "createVoucher", false, gas)
+	return parsed, common.FromHex(deployed)
 }
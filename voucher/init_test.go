@@ -5,11 +5,12 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestABI(t *testing.T) {
-	fmt.Printf("BalanceOf: %v\n", BalanceOf)
+	fmt.Printf("balanceOf: %v\n", VoucherABI.Methods["balanceOf"])
 
 	fmt.Printf("VoucherAddress: %v\n", VoucherAddress)
 
@@ -58,3 +59,27 @@ func TestToken(t *testing.T) {
 	}
 
 }
+
+// Tests that the generated bindings match the artifact the contract is
+// predeployed from.
+func TestBindingsMatchArtifact(t *testing.T) {
+	parsed, bytecode, _, err := abi.ParseHardhatContract(multiVoucherArtifact)
+	if err != nil {
+		t.Fatalf("failed to parse artifact: %v", err)
+	}
+	if MultiVoucherMetaData.Bin != bytecode {
+		t.Errorf("creation code of the bindings differs from the artifact, run go generate")
+	}
+	generated, err := MultiVoucherMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("failed to parse generated abi: %v", err)
+	}
+	for name, method := range parsed.Methods {
+		if generated.Methods[name].Sig != method.Sig {
+			t.Errorf("method %s differs from the artifact, run go generate", name)
+		}
+	}
+	if len(generated.Methods) != len(parsed.Methods) || len(generated.Events) != len(parsed.Events) {
+		t.Errorf("abi of the bindings differs from the artifact, run go generate")
+	}
+}
@@ -0,0 +1,83 @@
+package voucher
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// errZeroRate is returned if a rate source quotes a voucher as worthless, fees
+// can't be converted at such a rate.
+var errZeroRate = errors.New("zero voucher rate")
+
+// RateSource quotes the exchange rate of vouchers, i.e. how many units of a
+// voucher one unit of the native token buys.
+type RateSource interface {
+	// Rate returns the rate of the named voucher of the fee currency contract,
+	// as seen by the state of the given EVM.
+	Rate(evm *vm.EVM, currency common.Address, name string) (*big.Int, error)
+}
+
+// NewRateSource creates the rate source configured for the chain: the pool
+// oracle if there is one, the voucher contracts themselves otherwise.
+func NewRateSource(config *params.VoucherConfig) RateSource {
+	if config == nil || config.Oracle == nil {
+		return ContractRates{}
+	}
+	return NewPoolRates(*config.Oracle, config.Window)
+}
+
+// ContractRates quotes vouchers at the conversion rate governance set when
+// creating them in their fee currency contract.
+type ContractRates struct{}
+
+// Rate implements RateSource, reading the conversion rate of the voucher.
+func (ContractRates) Rate(evm *vm.EVM, currency common.Address, name string) (*big.Int, error) {
+	backend := NewEVMBackend(evm, common.Address{}, params.VoucherLookupGas)
+	contract, err := NewMultiVoucherCaller(currency, backend)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := contract.GetVoucherInfo(backend.CallOpts(), name)
+	if err != nil {
+		return nil, err
+	}
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q of %v", errZeroRate, name, currency)
+	}
+	return rate, nil
+}
+
+// PoolRates quotes vouchers at the time-weighted average rate of an on-chain
+// pool, as reported by its oracle.
+type PoolRates struct {
+	oracle common.Address
+	window uint32
+}
+
+// NewPoolRates creates a rate source consulting the oracle deployed at address
+// for rates averaged over window seconds.
+func NewPoolRates(oracle common.Address, window uint32) *PoolRates {
+	return &PoolRates{oracle: oracle, window: window}
+}
+
+// Rate implements RateSource, consulting the oracle.
+func (p *PoolRates) Rate(evm *vm.EVM, currency common.Address, name string) (*big.Int, error) {
+	backend := NewEVMBackend(evm, common.Address{}, params.VoucherLookupGas)
+	oracle, err := NewRateOracleCaller(p.oracle, backend)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := oracle.Consult(backend.CallOpts(), currency, name, p.window)
+	if err != nil {
+		return nil, err
+	}
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q of %v", errZeroRate, name, currency)
+	}
+	return rate, nil
+}
@@ -0,0 +1,240 @@
+// Package settlement implements the settle method of the fee currency
+// contracts, through which the fee of a transaction paying with a voucher is
+// settled once it executed: the payer is debited its fee net of the refund of
+// the unused gas and the coinbase is credited the tip, in a single call metered
+// like the storage accesses it makes. The multi voucher contract has no way to
+// credit vouchers without a payment, so the node handles the method itself on
+// every fee currency of the chain, for the system address only.
+package settlement
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// Abi of the settle method and of the event of the multi voucher contract
+// announcing the debit.
+const settlementABI = `[
+{"inputs":[{"name":"name","type":"string"},{"name":"payer","type":"address"},{"name":"coinbase","type":"address"},{"name":"fee","type":"uint256"},{"name":"refund","type":"uint256"},{"name":"tip","type":"uint256"}],"name":"settle","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"anonymous":false,"inputs":[{"indexed":false,"name":"user","type":"address"},{"indexed":false,"name":"name","type":"string"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"VoucherUsed","type":"event"}
+]`
+
+// SettlementABI is the abi of the settle method.
+var SettlementABI = mustParseABI(settlementABI)
+
+// vouchersSlot is the slot of the mapping(string => Voucher) vouchers of the
+// multi voucher contract. Every voucher is a struct of its conversion rate
+// followed by the mapping of its balances.
+var vouchersSlot = common.BigToHash(big.NewInt(1))
+
+var (
+	// ErrOutOfGas is returned when a settlement runs out of gas.
+	ErrOutOfGas = errors.New("out of gas")
+
+	// ErrUnauthorized is returned if anyone but the system address settles.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrInsufficientVoucher is returned if the payer can't cover its fee.
+	ErrInsufficientVoucher = errors.New("insufficient voucher balance")
+
+	// errInvalidAmounts is returned if the refund or the tip exceed the fee.
+	errInvalidAmounts = errors.New("invalid settlement amounts")
+
+	// errInvalidInput is returned for input not encoding a settle call.
+	errInvalidInput = errors.New("invalid settlement input")
+)
+
+// StateDB is the state settlements run on. Storage accesses are charged like
+// SLOAD and SSTORE under EIP-2929.
+type StateDB interface {
+	GetState(common.Address, common.Hash) common.Hash
+	SetState(common.Address, common.Hash, common.Hash)
+	SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool)
+	AddSlotToAccessList(addr common.Address, slot common.Hash)
+	AddLog(*types.Log)
+}
+
+// RateSlot returns the storage slot holding the conversion rate of the named
+// voucher.
+func RateSlot(name string) common.Hash {
+	return crypto.Keccak256Hash([]byte(name), vouchersSlot[:])
+}
+
+// BalanceSlot returns the storage slot holding how much of the named voucher
+// user holds.
+func BalanceSlot(name string, user common.Address) common.Hash {
+	balances := common.BigToHash(new(big.Int).Add(RateSlot(name).Big(), common.Big1))
+	return crypto.Keccak256Hash(common.BytesToHash(user[:]).Bytes(), balances[:])
+}
+
+// IsSettlement reports whether a call of addr with input settles a fee, i.e.
+// whether addr is a fee currency of the chain and input selects settle.
+func IsSettlement(config *params.VoucherConfig, addr common.Address, input []byte) bool {
+	if len(input) < 4 || !config.IsFeeCurrency(addr) {
+		return false
+	}
+	return bytes.Equal(input[:4], SettlementABI.Methods["settle"].ID)
+}
+
+// Settle returns the input of a settle call.
+func Settle(name string, payer, coinbase common.Address, fee, refund, tip *big.Int) ([]byte, error) {
+	return SettlementABI.Pack("settle", name, payer, coinbase, fee, refund, tip)
+}
+
+// Run executes a settle call of the fee currency by caller with the given gas.
+// It returns the remaining gas. Running out of gas returns ErrOutOfGas and
+// consumes all gas, any other error returns the abi encoded revert reason along
+// with it and leaves the state untouched.
+func Run(statedb StateDB, currency common.Address, caller common.Address, input []byte, gas uint64, number uint64) ([]byte, uint64, error) {
+	m := &meter{state: statedb, currency: currency, gas: gas}
+	if err := m.settle(caller, input, number); err != nil {
+		if errors.Is(err, ErrOutOfGas) {
+			return nil, 0, err
+		}
+		return revertReason(err), m.gas, err
+	}
+	return nil, m.gas, nil
+}
+
+// meter runs a settlement, charging its storage accesses and logs.
+type meter struct {
+	state    StateDB
+	currency common.Address
+	gas      uint64
+}
+
+// charge consumes cost gas.
+func (m *meter) charge(cost uint64) error {
+	if m.gas < cost {
+		m.gas = 0
+		return ErrOutOfGas
+	}
+	m.gas -= cost
+	return nil
+}
+
+// load returns a slot of the currency, charging a cold or warm storage read.
+func (m *meter) load(slot common.Hash) (*uint256.Int, error) {
+	cost := params.WarmStorageReadCostEIP2929
+	if _, warm := m.state.SlotInAccessList(m.currency, slot); !warm {
+		m.state.AddSlotToAccessList(m.currency, slot)
+		cost = params.ColdSloadCostEIP2929
+	}
+	if err := m.charge(cost); err != nil {
+		return nil, err
+	}
+	value := m.state.GetState(m.currency, slot)
+	return new(uint256.Int).SetBytes(value[:]), nil
+}
+
+// writeCost returns the gas of overwriting previous with value in a slot read
+// before.
+func writeCost(previous, value *uint256.Int) uint64 {
+	if previous.IsZero() && !value.IsZero() {
+		return params.SstoreSetGasEIP2200
+	}
+	return params.SstoreResetGasEIP2200 - params.ColdSloadCostEIP2929
+}
+
+// settle debits the payer its fee net of the refund and credits the tip to the
+// coinbase. Everything is checked and charged before the first write, so failed
+// settlements leave the state untouched.
+func (m *meter) settle(caller common.Address, input []byte, number uint64) error {
+	if caller != params.SystemAddress {
+		return ErrUnauthorized
+	}
+	args, err := SettlementABI.Methods["settle"].Inputs.Unpack(input[4:])
+	if err != nil || len(args) != 6 {
+		return errInvalidInput
+	}
+	name, ok1 := args[0].(string)
+	payer, ok2 := args[1].(common.Address)
+	coinbase, ok3 := args[2].(common.Address)
+	fee, ok4 := args[3].(*big.Int)
+	refund, ok5 := args[4].(*big.Int)
+	tip, ok6 := args[5].(*big.Int)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 {
+		return errInvalidInput
+	}
+	if refund.Cmp(fee) > 0 {
+		return errInvalidAmounts
+	}
+	charge := new(big.Int).Sub(fee, refund)
+	if tip.Cmp(charge) > 0 {
+		return errInvalidAmounts
+	}
+	var (
+		amount, _ = uint256.FromBig(charge)
+		tipped, _ = uint256.FromBig(tip)
+		payerSlot = BalanceSlot(name, payer)
+		coinSlot  = BalanceSlot(name, coinbase)
+	)
+	// The coinbase paying for its own transaction keeps the tip
+	if coinbase == payer {
+		amount.Sub(amount, tipped)
+		tipped.Clear()
+	}
+	balance, err := m.load(payerSlot)
+	if err != nil {
+		return err
+	}
+	if balance.Lt(amount) {
+		return ErrInsufficientVoucher
+	}
+	debited := new(uint256.Int).Sub(balance, amount)
+	cost := writeCost(balance, debited)
+
+	var credited, previous *uint256.Int
+	if !tipped.IsZero() {
+		if previous, err = m.load(coinSlot); err != nil {
+			return err
+		}
+		var overflow bool
+		if credited, overflow = new(uint256.Int).AddOverflow(previous, tipped); overflow {
+			return errInvalidAmounts
+		}
+		cost += writeCost(previous, credited)
+	}
+	data, err := SettlementABI.Events["VoucherUsed"].Inputs.Pack(payer, name, charge)
+	if err != nil {
+		return err
+	}
+	cost += params.LogGas + params.LogTopicGas + params.LogDataGas*uint64(len(data))
+	if err := m.charge(cost); err != nil {
+		return err
+	}
+	m.state.SetState(m.currency, payerSlot, debited.Bytes32())
+	if credited != nil {
+		m.state.SetState(m.currency, coinSlot, credited.Bytes32())
+	}
+	m.state.AddLog(&types.Log{
+		Address:     m.currency,
+		Topics:      []common.Hash{SettlementABI.Events["VoucherUsed"].ID},
+		Data:        data,
+		BlockNumber: number,
+	})
+	return nil
+}
+
+func revertReason(err error) []byte {
+	str, _ := abi.NewType("string", "", nil)
+	data, _ := abi.Arguments{{Type: str}}.Pack(err.Error())
+	return append(crypto.Keccak256([]byte("Error(string)"))[:4], data...)
+}
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,57 @@
+package settlement
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that settlements debit the payer the fee net of the refund, credit the
+// tip to the coinbase, and leave the state untouched when they fail.
+func TestSettle(t *testing.T) {
+	var (
+		currency = params.DefaultVoucherAddress
+		payer    = common.Address{0xaa}
+		coinbase = common.Address{0xcb}
+	)
+	for i, tt := range []struct {
+		caller           common.Address
+		fee, refund, tip int64
+		gas              uint64
+		err              error
+		payer, coinbase  int64
+	}{
+		{caller: params.SystemAddress, fee: 100, refund: 30, tip: 10, gas: params.TxVoucherSettlementGas, payer: 930, coinbase: 10},
+		{caller: params.SystemAddress, fee: 100, refund: 100, gas: params.TxVoucherSettlementGas, payer: 1000},
+		{caller: params.SystemAddress, fee: 2000, refund: 500, gas: params.TxVoucherSettlementGas, err: ErrInsufficientVoucher, payer: 1000},
+		{caller: params.SystemAddress, fee: 100, refund: 30, tip: 80, gas: params.TxVoucherSettlementGas, err: errInvalidAmounts, payer: 1000},
+		{caller: params.SystemAddress, fee: 100, refund: 30, tip: 10, gas: 10000, err: ErrOutOfGas, payer: 1000},
+		{caller: payer, fee: 100, gas: params.TxVoucherSettlementGas, err: ErrUnauthorized, payer: 1000},
+	} {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetState(currency, BalanceSlot("BitCoin", payer), common.BigToHash(big.NewInt(1000)))
+
+		input, err := Settle("BitCoin", payer, coinbase, big.NewInt(tt.fee), big.NewInt(tt.refund), big.NewInt(tt.tip))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := Run(statedb, currency, tt.caller, input, tt.gas, 1); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if have := statedb.GetState(currency, BalanceSlot("BitCoin", payer)).Big().Int64(); have != tt.payer {
+			t.Errorf("test %d: payer balance mismatch: have %d, want %d", i, have, tt.payer)
+		}
+		if have := statedb.GetState(currency, BalanceSlot("BitCoin", coinbase)).Big().Int64(); have != tt.coinbase {
+			t.Errorf("test %d: coinbase balance mismatch: have %d, want %d", i, have, tt.coinbase)
+		}
+		if logs := statedb.Logs(); (tt.err == nil) != (len(logs) == 1) {
+			t.Errorf("test %d: have %d logs", i, len(logs))
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package voucher
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/voucher/settlement"
+)
+
+// Storage layout of the multi voucher contract, used to predeploy it with some
+// vouchers already created, as its constructor and methods are never run then.
+// Every voucher is a struct of its conversion rate followed by the mapping of
+// its balances.
+var decimalsSlot = common.BigToHash(big.NewInt(0)) // uint256 decimals
+
+// DefaultDecimals is the value the constructor of the contract assigns to
+// decimals, the factor between a voucher unit and what a buyer is credited.
+var DefaultDecimals = big.NewInt(1e18)
+
+// DecimalsSlot returns the storage slot holding decimals.
+func DecimalsSlot() common.Hash {
+	return decimalsSlot
+}
+
+// RateSlot returns the storage slot holding the conversion rate of the named
+// voucher.
+func RateSlot(name string) common.Hash {
+	return settlement.RateSlot(name)
+}
+
+// BalanceSlot returns the storage slot holding how much of the named voucher
+// user holds.
+func BalanceSlot(name string, user common.Address) common.Hash {
+	return settlement.BalanceSlot(name, user)
+}
+
+// SettleInput returns the input of a settle call of a fee currency.
+func SettleInput(name string, payer, coinbase common.Address, fee, refund, tip *big.Int) ([]byte, error) {
+	return settlement.Settle(name, payer, coinbase, fee, refund, tip)
+}
+
+// Settle settles the fee of a voucher transaction with a single settle call of
+// the fee currency by the system address, metered against gas: payer is debited
+// fee net of refund and coinbase is credited tip. It returns the gas used.
+func Settle(evm *vm.EVM, currency common.Address, name string, payer, coinbase common.Address, fee, refund, tip *big.Int, gas uint64) (uint64, error) {
+	input, err := SettleInput(name, payer, coinbase, fee, refund, tip)
+	if err != nil {
+		return 0, err
+	}
+	backend := NewEVMBackend(evm, params.SystemAddress, gas)
+	_, err = backend.run(currency, input, new(big.Int))
+	return backend.GasUsed(), err
+}